@@ -23,9 +23,11 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/cache"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/fuse"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/kv"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/ufs"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/vfs"
 )
 
 func BasicFlags() []cli.Flag {
@@ -127,11 +129,61 @@ func CacheFlags(fuseConf *fuse.FuseConfig) []cli.Flag {
 			Value: 200 * 1024 * 1024,
 			Usage: "size of read-ahead data",
 		},
+		&cli.Int64Flag{
+			Name:  "data-cache-size",
+			Value: 0,
+			Usage: "max size of data cache in MiB, 0 means limited by free space of cache disk",
+		},
+		&cli.StringFlag{
+			Name:  "data-cache-evict-policy",
+			Value: cache.EvictLRU,
+			Usage: "data cache evict policy, e.g. lru, lfu, size",
+		},
+		&cli.Float64Flag{
+			Name:  "data-cache-high-watermark",
+			Value: cache.DefaultHighWatermark,
+			Usage: "data cache starts evicting when usage exceeds this ratio of cache size",
+		},
+		&cli.Float64Flag{
+			Name:  "data-cache-low-watermark",
+			Value: cache.DefaultLowWatermark,
+			Usage: "data cache stops evicting when usage falls below this ratio of cache size",
+		},
+		&cli.BoolFlag{
+			Name:  "write-back",
+			Value: false,
+			Usage: "write new files to local dir first and upload them to ufs in background",
+		},
+		&cli.StringFlag{
+			Name:  "write-back-dir",
+			Value: "/var/cache/pfs-cache-dir/write-back",
+			Usage: "local dir of files waiting for upload in write-back mode",
+		},
+		&cli.DurationFlag{
+			Name:  "write-back-timeout",
+			Value: vfs.DefaultWriteBackTimeout,
+			Usage: "max time fsync waits for upload in write-back mode",
+		},
+		&cli.IntFlag{
+			Name:  "write-back-retry",
+			Value: vfs.DefaultWriteBackRetry,
+			Usage: "retry times of a failed upload in write-back mode",
+		},
+		&cli.IntFlag{
+			Name:  "write-back-concurrency",
+			Value: vfs.DefaultWriteBackConcurrency,
+			Usage: "number of files uploaded concurrently in write-back mode",
+		},
 		&cli.BoolFlag{
 			Name:  "clean-cache",
 			Value: false,
 			Usage: "clean cache dir after mount process ends",
 		},
+		&cli.BoolFlag{
+			Name:  "cache-persistent",
+			Value: false,
+			Usage: "reuse disk meta cache and data cache after remount, entries are revalidated by mtime and size",
+		},
 	}
 }
 
@@ -40,7 +40,7 @@ func TestCacheFlags(t *testing.T) {
 			args: args{
 				fuseConf: fuse.FuseConf,
 			},
-			want: 12,
+			want: 22,
 		},
 	}
 	for _, tt := range tests {
@@ -150,7 +150,9 @@ func setup(c *cli.Context) error {
 		}()
 	}
 
-	if c.Bool("clean-cache") {
+	if c.Bool("clean-cache") && c.Bool("cache-persistent") {
+		log.Warnf("clean-cache is ignored since cache-persistent is set")
+	} else if c.Bool("clean-cache") {
 		if c.String("meta-cache-path") != "" {
 			cleanCacheInfo.CachePaths = append(cleanCacheInfo.CachePaths, c.String("meta-cache-path"))
 		}
@@ -379,23 +381,37 @@ func InitVFS(c *cli.Context, registry *prometheus.Registry) error {
 		EntryCacheExpire: c.Duration("entry-cache-expire"),
 		PathCacheExpire:  c.Duration("path-cache-expire"),
 		Config: kv.Config{
-			FsID:      fsMeta.ID,
-			Driver:    c.String("meta-cache-driver"),
-			CachePath: c.String("meta-cache-path"),
+			FsID:       fsMeta.ID,
+			Driver:     c.String("meta-cache-driver"),
+			CachePath:  c.String("meta-cache-path"),
+			Persistent: c.Bool("cache-persistent"),
 		},
 	}
 	d := cache.Config{
-		BlockSize:    c.Int("block-size"),
-		MaxReadAhead: c.Int("data-read-ahead-size"),
-		Expire:       c.Duration("data-cache-expire"),
+		BlockSize:     c.Int("block-size"),
+		MaxReadAhead:  c.Int("data-read-ahead-size"),
+		Expire:        c.Duration("data-cache-expire"),
+		CacheSize:     c.Int64("data-cache-size") * 1024 * 1024,
+		EvictPolicy:   c.String("data-cache-evict-policy"),
+		HighWatermark: c.Float64("data-cache-high-watermark"),
+		LowWatermark:  c.Float64("data-cache-low-watermark"),
 		Config: kv.Config{
-			CachePath: c.String("data-cache-path"),
+			CachePath:  c.String("data-cache-path"),
+			Persistent: c.Bool("cache-persistent"),
 		},
 	}
 	vfsOptions := []vfs.Option{
 		vfs.WithDataCacheConfig(d),
 		vfs.WithMetaConfig(m),
 	}
+	if c.Bool("write-back") {
+		vfsOptions = append(vfsOptions, vfs.WithWriteBackConfig(vfs.WriteBackConfig{
+			Dir:         c.String("write-back-dir"),
+			Timeout:     c.Duration("write-back-timeout"),
+			Retry:       c.Int("write-back-retry"),
+			Concurrency: c.Int("write-back-concurrency"),
+		}))
+	}
 	if !fuse.FuseConf.RawOwner {
 		vfsOptions = append(vfsOptions, vfs.WithOwner(
 			uint32(fuse.FuseConf.Uid),
@@ -45,6 +45,11 @@ func CacheWorkerFlags() []cli.Flag {
 			Value: "",
 			Usage: "podCachePath",
 		},
+		&cli.StringFlag{
+			Name:  "podMountPoint",
+			Value: "",
+			Usage: "mount point of file system in pod, paths of warmup tasks are warmed up and ufs health is probed on it",
+		},
 		&cli.StringFlag{
 			Name:  "server",
 			Value: "",
@@ -108,6 +108,15 @@ func act(c *cli.Context) error {
 		location_awareness.PatchCacheStatsLoop(k8sClient, podNamespace, podName, podCachePath)
 	}()
 
+	if podMountPoint := c.String("podMountPoint"); podMountPoint != "" {
+		go func() {
+			location_awareness.WarmupLoop(k8sClient, podNamespace, podName, podMountPoint)
+		}()
+		go func() {
+			location_awareness.UfsHealthLoop(k8sClient, podNamespace, podName, podMountPoint)
+		}()
+	}
+
 	stopSig := make(chan os.Signal, 1)
 	signal.Notify(stopSig, syscall.SIGTERM, syscall.SIGINT)
 	sig := <-stopSig
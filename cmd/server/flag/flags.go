@@ -87,6 +87,18 @@ func StorageFlags(dbConf *config.StorageConfig) []cli.Flag {
 			Usage:       "db idle transaction timeout in milliseconds",
 			Destination: &dbConf.IdleTransactionTimeoutInMilliseconds,
 		},
+		&cli.StringFlag{
+			Name:        "db-ssl-mode",
+			Value:       dbConf.SSLMode,
+			Usage:       "ssl mode of postgres, disable by default",
+			Destination: &dbConf.SSLMode,
+		},
+		&cli.BoolFlag{
+			Name:        "db-migrate-on-start",
+			Value:       dbConf.MigrateOnStart,
+			Usage:       "apply database migrations when server starts",
+			Destination: &dbConf.MigrateOnStart,
+		},
 	}
 }
 
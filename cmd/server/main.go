@@ -20,10 +20,12 @@ import (
 	jobCtrl "github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/job"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/pipeline"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/queue"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/middleware"
 	router "github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/v1"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/secret"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/metrics"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
@@ -72,6 +74,7 @@ func Main(args []string) error {
 		EnableBashCompletion: true,
 		Flags:                flag.ExpandFlags(compoundFlags),
 		Action:               act,
+		Commands:             []*cli.Command{migrateCommand()},
 	}
 	return app.Run(args)
 }
@@ -191,19 +194,33 @@ func setup() {
 
 	log.Infof("The final server config is: %s ", config.PrettyFormat(ServerConf))
 
+	// secrets stored in plaintext are encrypted by migration, so secret must be initialized before storage
+	if err = secret.Init(ServerConf.Secret.Config); err != nil {
+		log.Errorf("init secret err: %v", err)
+		gracefullyExit(err)
+	}
+
 	dbConf := &ServerConf.Storage
 	if err := driver.InitStorage(&config.StorageConfig{
-		Driver:   dbConf.Driver,
-		Host:     dbConf.Host,
-		Port:     dbConf.Port,
-		User:     dbConf.User,
-		Password: dbConf.Password,
-		Database: dbConf.Database,
+		Driver:                  dbConf.Driver,
+		Host:                    dbConf.Host,
+		Port:                    dbConf.Port,
+		User:                    dbConf.User,
+		Password:                dbConf.Password,
+		Database:                dbConf.Database,
+		ConnectTimeoutInSeconds: dbConf.ConnectTimeoutInSeconds,
+		SSLMode:                 dbConf.SSLMode,
+		MigrateOnStart:          dbConf.MigrateOnStart,
 	}, ServerConf.Log.Level); err != nil {
 		log.Errorf("init database err: %v", err)
 		gracefullyExit(err)
 	}
 
+	if err = middleware.InitJWT(&ServerConf.ApiServer.JWT); err != nil {
+		log.Errorf("init jwt keys err: %v", err)
+		gracefullyExit(err)
+	}
+
 	if err = driver.InitCache(ServerConf.Log.Level); err != nil {
 		log.Errorf("init cache err: %v", err)
 		gracefullyExit(err)
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/secret"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/driver"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/migration"
+)
+
+// migrateCommand manages schema of database configured by config file and db flags
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "migrate schema of database",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "up",
+				Usage: "apply migrations which are not applied",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "apply migrations until the version, all migrations are applied if not set",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					migrator, err := newMigrator()
+					if err != nil {
+						return err
+					}
+					count, err := migrator.Up(c.String("to"))
+					fmt.Printf("%d migrations are applied\n", count)
+					return err
+				},
+			},
+			{
+				Name:  "down",
+				Usage: "revert migrations applied",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "steps",
+						Value: 1,
+						Usage: "number of migrations to revert",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					migrator, err := newMigrator()
+					if err != nil {
+						return err
+					}
+					count, err := migrator.Down(c.Int("steps"))
+					fmt.Printf("%d migrations are reverted\n", count)
+					return err
+				},
+			},
+			{
+				Name:  "status",
+				Usage: "show migrations and whether they are applied",
+				Action: func(c *cli.Context) error {
+					migrator, err := newMigrator()
+					if err != nil {
+						return err
+					}
+					statuses, err := migrator.Status()
+					if err != nil {
+						return err
+					}
+					w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+					fmt.Fprintln(w, "VERSION\tAPPLIED AT\tDESCRIPTION")
+					for _, status := range statuses {
+						appliedAt := "pending"
+						if status.Applied {
+							appliedAt = status.AppliedAt.Format(time.RFC3339)
+						}
+						fmt.Fprintf(w, "%s\t%s\t%s\n", status.Version, appliedAt, status.Description)
+					}
+					return w.Flush()
+				},
+			},
+		},
+	}
+}
+
+func newMigrator() (*migration.Migrator, error) {
+	// migration encrypts secrets stored in plaintext with secret key provider
+	if err := secret.Init(ServerConf.Secret.Config); err != nil {
+		return nil, err
+	}
+	db := driver.OpenDB(&ServerConf.Storage, ServerConf.Log.Level)
+	if db == nil {
+		return nil, fmt.Errorf("open database of driver %s failed", ServerConf.Storage.Driver)
+	}
+	return migration.New(db), nil
+}
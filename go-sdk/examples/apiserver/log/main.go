@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleFlow/go-sdk/service"
+	v1 "github.com/PaddlePaddle/PaddleFlow/go-sdk/service/apiserver/v1"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/core"
+)
+
+func main() {
+	config := &core.PaddleFlowClientConfiguration{
+		Host:                       "",
+		Port:                       8999,
+		ConnectionTimeoutInSeconds: 1,
+	}
+	pfClient, err := service.NewForClient(config)
+	if err != nil {
+		panic(err)
+	}
+	data, err := pfClient.APIV1().User().Login(context.TODO(), &v1.LoginInfo{
+		UserName: "",
+		Password: "",
+	})
+	if err != nil {
+		panic(err)
+	}
+	token := data.Authorization
+
+	jobID := "job-xxx"
+	clusterName := "default-cluster"
+
+	// get log tail of job
+	logInfo, err := pfClient.APIV1().Log().GetJobLog(context.TODO(), &v1.GetJobLogRequest{
+		JobID:        jobID,
+		ClusterName:  clusterName,
+		ReadFromTail: true,
+		LineLimit:    100,
+	}, token)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("job log %v\n", logInfo)
+
+	// follow job log until job finished
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Minute)
+	defer cancel()
+	err = pfClient.APIV1().Log().StreamJobLog(ctx, &v1.StreamJobLogRequest{
+		JobID:       jobID,
+		ClusterName: clusterName,
+	}, token, func(taskID, line string) {
+		fmt.Printf("[%s] %s\n", taskID, line)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// watch jobs updated
+	watcher, err := pfClient.APIV1().Job().Watch(ctx, &v1.WatchJobOptions{}, token)
+	if err != nil {
+		panic(err)
+	}
+	for job := range watcher.ResultChan() {
+		fmt.Printf("job %s is %s\n", job.ID, job.Status)
+	}
+	fmt.Printf("watch stopped, err: %v\n", watcher.Err())
+}
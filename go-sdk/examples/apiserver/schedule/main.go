@@ -72,6 +72,20 @@ func Stop(pfClient *service.PaddleFlowClient, token string, scheduleID string) {
 	}
 }
 
+func Pause(pfClient *service.PaddleFlowClient, token string, scheduleID string) {
+	err := pfClient.APIV1().Schedule().Pause(context.TODO(), scheduleID, token)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func Resume(pfClient *service.PaddleFlowClient, token string, scheduleID string) {
+	err := pfClient.APIV1().Schedule().Resume(context.TODO(), scheduleID, token)
+	if err != nil {
+		panic(err)
+	}
+}
+
 func Delete(pfClient *service.PaddleFlowClient, token string, scheduleID string) {
 	err := pfClient.APIV1().Schedule().Delete(context.TODO(), scheduleID, token)
 	if err != nil {
@@ -109,6 +123,9 @@ func main() {
 	jsonGet, _ := json.Marshal(resGet)
 	fmt.Println(string(jsonGet))
 
+	Pause(pfClient, token, "schedule-000001")
+	Resume(pfClient, token, "schedule-000001")
+
 	Stop(pfClient, token, "schedule-000001")
 	resGet = Get(pfClient, token, &v1.GetScheduleRequest{
 		ScheduleID: "schedule-000001",
@@ -30,6 +30,12 @@ type APIV1Interface interface {
 	RunGetter
 	PipelineGetter
 	ScheduleGetter
+	LogGetter
+	StatisticsGetter
+	GrantGetter
+	LinkGetter
+	FileSystemCacheGetter
+	KubernetesObjectGetter
 }
 
 // APIV1Client is used to interact with features provided by the group.
@@ -73,6 +79,30 @@ func (c *APIV1Client) Schedule() ScheduleInterface {
 	return newSchedule(c)
 }
 
+func (c *APIV1Client) Log() LogInterface {
+	return newLogs(c)
+}
+
+func (c *APIV1Client) Statistics() StatisticsInterface {
+	return newStatistics(c)
+}
+
+func (c *APIV1Client) Grant() GrantInterface {
+	return newGrant(c)
+}
+
+func (c *APIV1Client) Link() LinkInterface {
+	return newLink(c)
+}
+
+func (c *APIV1Client) FileSystemCache() FileSystemCacheInterface {
+	return newFileSystemCache(c)
+}
+
+func (c *APIV1Client) KubernetesObject() KubernetesObjectInterface {
+	return newKubernetesObject(c)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *APIV1Client) RESTClient() *core.PaddleFlowClient {
@@ -120,7 +120,7 @@ type UpdateClusterResponse struct {
 func (c *cluster) Create(ctx context.Context, request *CreateClusterRequest,
 	token string) (result *CreateClusterResponse, err error) {
 	result = &CreateClusterResponse{}
-	err = core.NewRequestBuilder(c.client).
+	err = core.NewRequestBuilder(c.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(ClusterApi).
 		WithMethod(http.POST).
@@ -133,7 +133,7 @@ func (c *cluster) Create(ctx context.Context, request *CreateClusterRequest,
 func (c *cluster) Get(ctx context.Context, clusterName,
 	token string) (result *GetClusterResponse, err error) {
 	result = &GetClusterResponse{}
-	err = core.NewRequestBuilder(c.client).
+	err = core.NewRequestBuilder(c.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(ClusterApi + "/" + clusterName).
 		WithMethod(http.GET).
@@ -148,7 +148,7 @@ func (c *cluster) Get(ctx context.Context, clusterName,
 func (c *cluster) List(ctx context.Context, request *ListClusterRequest,
 	token string) (result *ListClusterResponse, err error) {
 	result = &ListClusterResponse{}
-	err = core.NewRequestBuilder(c.client).
+	err = core.NewRequestBuilder(c.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(ClusterApi).
 		WithMethod(http.GET).
@@ -164,10 +164,23 @@ func (c *cluster) List(ctx context.Context, request *ListClusterRequest,
 	return
 }
 
+// ListAll iterates clusters of all pages from request.Marker
+func (c *cluster) ListAll(ctx context.Context, request *ListClusterRequest, token string) *core.Iterator[ClusterInfo] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[ClusterInfo], error) {
+		pageRequest.Marker = marker
+		result, err := c.List(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[ClusterInfo]{}, err
+		}
+		return markerPage(result.ClusterList, result.MarkerInfo), nil
+	})
+}
+
 func (c *cluster) Update(ctx context.Context, clusterName string, request *UpdateClusterRequest,
 	token string) (result *UpdateClusterResponse, err error) {
 	result = &UpdateClusterResponse{}
-	err = core.NewRequestBuilder(c.client).
+	err = core.NewRequestBuilder(c.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(ClusterApi + "/" + clusterName).
 		WithMethod(http.PUT).
@@ -178,7 +191,7 @@ func (c *cluster) Update(ctx context.Context, clusterName string, request *Updat
 }
 
 func (c *cluster) Delete(ctx context.Context, clusterName, token string) (err error) {
-	err = core.NewRequestBuilder(c.client).
+	err = core.NewRequestBuilder(c.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(ClusterApi + "/" + clusterName).
 		WithMethod(http.DELETE).
@@ -194,6 +207,7 @@ type ClusterInterface interface {
 	Create(ctx context.Context, request *CreateClusterRequest, token string) (*CreateClusterResponse, error)
 	Get(ctx context.Context, clusterName string, token string) (*GetClusterResponse, error)
 	List(ctx context.Context, request *ListClusterRequest, token string) (*ListClusterResponse, error)
+	ListAll(ctx context.Context, request *ListClusterRequest, token string) *core.Iterator[ClusterInfo]
 	Update(ctx context.Context, clusterName string, request *UpdateClusterRequest, token string) (*UpdateClusterResponse, error)
 	Delete(ctx context.Context, clusterName string, token string) error
 }
@@ -94,7 +94,7 @@ type ListFlavourResponse struct {
 func (f *flavour) Create(ctx context.Context, request *CreateFlavourRequest,
 	token string) (result *CreateFlavourResponse, err error) {
 	result = &CreateFlavourResponse{}
-	err = core.NewRequestBuilder(f.client).
+	err = core.NewRequestBuilder(f.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(FlavourAPI).
 		WithMethod(http.POST).
@@ -107,7 +107,7 @@ func (f *flavour) Create(ctx context.Context, request *CreateFlavourRequest,
 func (f *flavour) Update(ctx context.Context, request *UpdateFlavourRequest,
 	token string) (result *UpdateFlavourResponse, err error) {
 	result = &UpdateFlavourResponse{}
-	err = core.NewRequestBuilder(f.client).
+	err = core.NewRequestBuilder(f.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(FlavourAPI + "/" + request.Name).
 		WithMethod(http.PUT).
@@ -120,7 +120,7 @@ func (f *flavour) Update(ctx context.Context, request *UpdateFlavourRequest,
 func (f *flavour) Get(ctx context.Context, name string,
 	token string) (result *Flavour, err error) {
 	result = &Flavour{}
-	err = core.NewRequestBuilder(f.client).
+	err = core.NewRequestBuilder(f.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(FlavourAPI + "/" + name).
 		WithMethod(http.GET).
@@ -135,7 +135,7 @@ func (f *flavour) Get(ctx context.Context, name string,
 func (f *flavour) List(ctx context.Context, request *ListFlavourRequest,
 	token string) (result *ListFlavourResponse, err error) {
 	result = &ListFlavourResponse{}
-	err = core.NewRequestBuilder(f.client).
+	err = core.NewRequestBuilder(f.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(FlavourAPI).
 		WithMethod(http.GET).
@@ -150,9 +150,22 @@ func (f *flavour) List(ctx context.Context, request *ListFlavourRequest,
 	return
 }
 
+// ListAll iterates flavours of all pages from request.Marker
+func (f *flavour) ListAll(ctx context.Context, request *ListFlavourRequest, token string) *core.Iterator[Flavour] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[Flavour], error) {
+		pageRequest.Marker = marker
+		result, err := f.List(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[Flavour]{}, err
+		}
+		return markerPage(result.FlavourList, result.MarkerInfo), nil
+	})
+}
+
 func (f *flavour) Delete(ctx context.Context, name string,
 	token string) (err error) {
-	err = core.NewRequestBuilder(f.client).
+	err = core.NewRequestBuilder(f.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(FlavourAPI + "/" + name).
 		WithMethod(http.DELETE).
@@ -169,6 +182,7 @@ type FlavourInterface interface {
 	Update(ctx context.Context, request *UpdateFlavourRequest, token string) (*UpdateFlavourResponse, error)
 	Get(ctx context.Context, name string, token string) (*Flavour, error)
 	List(ctx context.Context, request *ListFlavourRequest, token string) (*ListFlavourResponse, error)
+	ListAll(ctx context.Context, request *ListFlavourRequest, token string) *core.Iterator[Flavour]
 	Delete(ctx context.Context, name string, token string) error
 }
 
@@ -68,7 +68,7 @@ type DeleteFileSystemRequest struct {
 func (f *fileSystem) Create(ctx context.Context, request *CreateFileSystemRequest,
 	token string) (result *CreateFileSystemResponse, err error) {
 	result = &CreateFileSystemResponse{}
-	err = core.NewRequestBuilder(f.client).
+	err = core.NewRequestBuilder(f.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(FsApi).
 		WithMethod(http.POST).
@@ -81,7 +81,7 @@ func (f *fileSystem) Create(ctx context.Context, request *CreateFileSystemReques
 func (f *fileSystem) Get(ctx context.Context, request *GetFileSystemRequest,
 	token string) (result *GetFileSystemResponse, err error) {
 	result = &GetFileSystemResponse{}
-	err = core.NewRequestBuilder(f.client).
+	err = core.NewRequestBuilder(f.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(FsApi+"/"+request.FsName).
 		WithQueryParam(KeyUsername, request.Username).
@@ -95,7 +95,7 @@ func (f *fileSystem) Get(ctx context.Context, request *GetFileSystemRequest,
 }
 
 func (f *fileSystem) Delete(ctx context.Context, request *DeleteFileSystemRequest, token string) (err error) {
-	err = core.NewRequestBuilder(f.client).
+	err = core.NewRequestBuilder(f.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(FsApi+"/"+request.FsName).
 		WithQueryParam(KeyUsername, request.Username).
@@ -0,0 +1,188 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/core"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/util/http"
+)
+
+const (
+	FsCacheApi = Prefix + "/fsCache"
+)
+
+type fsCache struct {
+	client *core.PaddleFlowClient
+}
+
+type ResourceLimit struct {
+	CpuLimit    string `json:"cpuLimit"`
+	MemoryLimit string `json:"memoryLimit"`
+}
+
+type CreateFileSystemCacheRequest struct {
+	Username            string                 `json:"username"`
+	FsName              string                 `json:"fsName"`
+	CacheDir            string                 `json:"cacheDir"`
+	Quota               int                    `json:"quota"`
+	MetaDriver          string                 `json:"metaDriver"`
+	BlockSize           int                    `json:"blockSize"`
+	Debug               bool                   `json:"debug"`
+	CleanCache          bool                   `json:"cleanCache"`
+	Resource            ResourceLimit          `json:"resource"`
+	NodeTaintToleration map[string]interface{} `json:"nodeTaintToleration"`
+	ExtraConfig         map[string]string      `json:"extraConfig"`
+}
+
+type FileSystemCacheResponse struct {
+	CacheDir            string                 `json:"cacheDir"`
+	Quota               int                    `json:"quota"`
+	MetaDriver          string                 `json:"metaDriver"`
+	BlockSize           int                    `json:"blockSize"`
+	CleanCache          bool                   `json:"cleanCache"`
+	Resource            ResourceLimit          `json:"resource"`
+	NodeTaintToleration map[string]interface{} `json:"nodeTaintToleration"`
+	ExtraConfig         map[string]string      `json:"extraConfig"`
+	FsName              string                 `json:"fsName"`
+	Username            string                 `json:"username"`
+	CreateTime          string                 `json:"createTime"`
+	UpdateTime          string                 `json:"updateTime,omitempty"`
+}
+
+type WarmupFileSystemCacheRequest struct {
+	FsName   string   `json:"-"`
+	Username string   `json:"username"`
+	Paths    []string `json:"paths"`
+	Manifest string   `json:"manifest"`
+	Type     string   `json:"type"`
+	Threads  int      `json:"threads"`
+}
+
+type WarmupNode struct {
+	ClusterID string `json:"clusterID"`
+	NodeName  string `json:"nodename"`
+	PodName   string `json:"podName"`
+}
+
+type WarmupFileSystemCacheResponse struct {
+	WarmupID string       `json:"warmupID"`
+	Nodes    []WarmupNode `json:"nodes"`
+}
+
+type WarmupProgress struct {
+	ClusterID string `json:"clusterID"`
+	NodeName  string `json:"nodename"`
+	CacheDir  string `json:"cacheDir"`
+	UsedSize  int    `json:"usedSize"`
+	WarmupID  string `json:"warmupID"`
+	Status    string `json:"status"`
+	Total     int64  `json:"total"`
+	Done      int64  `json:"done"`
+	Failed    int64  `json:"failed"`
+	Bytes     int64  `json:"bytes"`
+	Message   string `json:"message,omitempty"`
+}
+
+type GetWarmupResponse struct {
+	FsName   string           `json:"fsName"`
+	Username string           `json:"username"`
+	Nodes    []WarmupProgress `json:"nodes"`
+}
+
+func (f *fsCache) Create(ctx context.Context, request *CreateFileSystemCacheRequest, token string) (err error) {
+	err = newRequestBuilderWithTokenHeader(ctx, f.client, token).
+		WithURL(FsCacheApi).
+		WithMethod(http.POST).
+		WithBody(request).
+		Do()
+	return
+}
+
+func (f *fsCache) Get(ctx context.Context, fsName, username,
+	token string) (result *FileSystemCacheResponse, err error) {
+	result = &FileSystemCacheResponse{}
+	err = newRequestBuilderWithTokenHeader(ctx, f.client, token).
+		WithURL(FsCacheApi+"/"+fsName).
+		WithMethod(http.GET).
+		WithQueryParamFilter(KeyUsername, username).
+		WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (f *fsCache) Delete(ctx context.Context, fsName, username, token string) (err error) {
+	err = newRequestBuilderWithTokenHeader(ctx, f.client, token).
+		WithURL(FsCacheApi+"/"+fsName).
+		WithMethod(http.DELETE).
+		WithQueryParamFilter(KeyUsername, username).
+		Do()
+	return
+}
+
+func (f *fsCache) Warmup(ctx context.Context, request *WarmupFileSystemCacheRequest,
+	token string) (result *WarmupFileSystemCacheResponse, err error) {
+	result = &WarmupFileSystemCacheResponse{}
+	err = newRequestBuilderWithTokenHeader(ctx, f.client, token).
+		WithURL(FsCacheApi + "/" + request.FsName + "/warmup").
+		WithMethod(http.POST).
+		WithBody(request).
+		WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (f *fsCache) GetWarmup(ctx context.Context, fsName, username,
+	token string) (result *GetWarmupResponse, err error) {
+	result = &GetWarmupResponse{}
+	err = newRequestBuilderWithTokenHeader(ctx, f.client, token).
+		WithURL(FsCacheApi+"/"+fsName+"/warmup").
+		WithMethod(http.GET).
+		WithQueryParamFilter(KeyUsername, username).
+		WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+type FileSystemCacheGetter interface {
+	FileSystemCache() FileSystemCacheInterface
+}
+
+type FileSystemCacheInterface interface {
+	Create(ctx context.Context, request *CreateFileSystemCacheRequest, token string) error
+	Get(ctx context.Context, fsName, username string, token string) (*FileSystemCacheResponse, error)
+	Delete(ctx context.Context, fsName, username string, token string) error
+	Warmup(ctx context.Context, request *WarmupFileSystemCacheRequest, token string) (*WarmupFileSystemCacheResponse, error)
+	GetWarmup(ctx context.Context, fsName, username string, token string) (*GetWarmupResponse, error)
+}
+
+// newFileSystemCache returns a fsCache.
+func newFileSystemCache(c *APIV1Client) *fsCache {
+	return &fsCache{
+		client: c.RESTClient(),
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/core"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/util/http"
+)
+
+const (
+	GrantApi = Prefix + "/grant"
+)
+
+type grant struct {
+	client *core.PaddleFlowClient
+}
+
+type CreateGrantRequest struct {
+	// one of UserName and GroupName is required
+	UserName     string `json:"userName,omitempty"`
+	GroupName    string `json:"groupName,omitempty"`
+	ResourceType string `json:"resourceType"` // queue, fs, pipeline or cluster
+	ResourceID   string `json:"resourceID"`
+	// Role is viewer, submitter or admin, default is submitter
+	Role string `json:"role,omitempty"`
+}
+
+type CreateGrantResponse struct {
+	GrantID string `json:"grantID"`
+}
+
+type DeleteGrantRequest struct {
+	UserName     string `json:"userName"`
+	GroupName    string `json:"groupName"`
+	ResourceType string `json:"resourceType"`
+	ResourceID   string `json:"resourceID"`
+}
+
+type ListGrantRequest struct {
+	UserName  string `json:"userName,omitempty"`
+	GroupName string `json:"groupName,omitempty"`
+	Marker    string `json:"marker"`
+	MaxKeys   int    `json:"maxKeys"`
+}
+
+type ListGrantResponse struct {
+	common.MarkerInfo
+	GrantList []Grant `json:"grantList"`
+}
+
+type Grant struct {
+	ID           string    `json:"grantID"`
+	UserName     string    `json:"userName"`
+	GroupName    string    `json:"groupName,omitempty"`
+	ResourceType string    `json:"resourceType"`
+	ResourceID   string    `json:"resourceID"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"createTime"`
+	UpdatedAt    time.Time `json:"updateTime,omitempty"`
+}
+
+func (g *grant) Create(ctx context.Context, request *CreateGrantRequest,
+	token string) (result *CreateGrantResponse, err error) {
+	result = &CreateGrantResponse{}
+	err = newRequestBuilderWithTokenHeader(ctx, g.client, token).
+		WithURL(GrantApi).
+		WithMethod(http.POST).
+		WithBody(request).
+		WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (g *grant) Delete(ctx context.Context, request *DeleteGrantRequest, token string) (err error) {
+	err = newRequestBuilderWithTokenHeader(ctx, g.client, token).
+		WithURL(GrantApi).
+		WithMethod(http.DELETE).
+		WithQueryParam(KeyUsername, request.UserName).
+		WithQueryParamFilter("groupName", request.GroupName).
+		WithQueryParam("resourceType", request.ResourceType).
+		WithQueryParam("resourceID", request.ResourceID).
+		Do()
+	return
+}
+
+func (g *grant) List(ctx context.Context, request *ListGrantRequest,
+	token string) (result *ListGrantResponse, err error) {
+	result = &ListGrantResponse{}
+	err = newRequestBuilderWithTokenHeader(ctx, g.client, token).
+		WithURL(GrantApi).
+		WithMethod(http.GET).
+		WithQueryParamFilter(KeyUsername, request.UserName).
+		WithQueryParamFilter("groupName", request.GroupName).
+		WithQueryParamFilter(KeyMarker, request.Marker).
+		WithQueryParamFilter(KeyMaxKeys, intFilter(request.MaxKeys)).
+		WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ListAll iterates grants of all pages from request.Marker
+func (g *grant) ListAll(ctx context.Context, request *ListGrantRequest, token string) *core.Iterator[Grant] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[Grant], error) {
+		pageRequest.Marker = marker
+		result, err := g.List(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[Grant]{}, err
+		}
+		return markerPage(result.GrantList, result.MarkerInfo), nil
+	})
+}
+
+type GrantGetter interface {
+	Grant() GrantInterface
+}
+
+type GrantInterface interface {
+	Create(ctx context.Context, request *CreateGrantRequest, token string) (*CreateGrantResponse, error)
+	Delete(ctx context.Context, request *DeleteGrantRequest, token string) error
+	List(ctx context.Context, request *ListGrantRequest, token string) (*ListGrantResponse, error)
+	ListAll(ctx context.Context, request *ListGrantRequest, token string) *core.Iterator[Grant]
+}
+
+// newGrant returns a grant.
+func newGrant(c *APIV1Client) *grant {
+	return &grant{
+		client: c.RESTClient(),
+	}
+}
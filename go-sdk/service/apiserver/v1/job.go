@@ -102,6 +102,9 @@ type MemberSpec struct {
 	JobSpec       `json:",inline"`
 	Role          string `json:"role"`
 	Replicas      int    `json:"replicas"`
+	// MinReplicas and MaxReplicas make the member elastic, which is supported by worker of pytorch and paddle job
+	MinReplicas int `json:"minReplicas,omitempty"`
+	MaxReplicas int `json:"maxReplicas,omitempty"`
 }
 
 type UpdateJobRequest struct {
@@ -109,6 +112,16 @@ type UpdateJobRequest struct {
 	Priority    string            `json:"priority"`
 	Labels      map[string]string `json:"labels"`
 	Annotations map[string]string `json:"annotations"`
+	// Replicas is the replicas of elastic member to scale, nil means not to update
+	Replicas *int `json:"replicas,omitempty"`
+}
+
+// ResubmitJobRequest convey request for resubmit a finished job, all fields are optional overrides
+type ResubmitJobRequest struct {
+	Queue    string            `json:"queue,omitempty"`
+	Priority string            `json:"priority,omitempty"`
+	Flavour  *schema.Flavour   `json:"flavour,omitempty"`
+	Env      map[string]string `json:"env,omitempty"`
 }
 
 // CreateJobResponse convey response for create job
@@ -119,6 +132,8 @@ type CreateJobResponse struct {
 type Member struct {
 	ID          string            `json:"id"`
 	Replicas    int               `json:"replicas"`
+	MinReplicas int               `json:"minReplicas,omitempty"`
+	MaxReplicas int               `json:"maxReplicas,omitempty"`
 	Role        schema.MemberRole `json:"role"`
 	schema.Conf `json:",inline"`
 }
@@ -183,7 +198,7 @@ type WorkflowRuntimeInfo struct {
 func (j *job) Create(ctx context.Context, single *CreateSingleJobRequest, distributed *CreateDisJobRequest,
 	wf *CreateWfJobRequest, token string) (result *CreateJobResponse, err error) {
 	result = &CreateJobResponse{}
-	requestClient := core.NewRequestBuilder(j.client).
+	requestClient := core.NewRequestBuilder(j.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithMethod(http.POST)
 	if single != nil {
@@ -204,7 +219,7 @@ func (j *job) Create(ctx context.Context, single *CreateSingleJobRequest, distri
 func (j *job) Get(ctx context.Context, jobID,
 	token string) (result *GetJobResponse, err error) {
 	result = &GetJobResponse{}
-	err = core.NewRequestBuilder(j.client).
+	err = core.NewRequestBuilder(j.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(JobApi + "/" + jobID).
 		WithMethod(http.GET).
@@ -219,7 +234,7 @@ func (j *job) Get(ctx context.Context, jobID,
 func (j *job) List(ctx context.Context, request *ListJobRequest,
 	token string) (result *ListJobResponse, err error) {
 	result = &ListJobResponse{}
-	requestClient := core.NewRequestBuilder(j.client).
+	requestClient := core.NewRequestBuilder(j.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(JobApi).
 		WithMethod(http.GET).
@@ -244,9 +259,22 @@ func (j *job) List(ctx context.Context, request *ListJobRequest,
 	return
 }
 
+// ListAll iterates jobs of all pages from request.Marker
+func (j *job) ListAll(ctx context.Context, request *ListJobRequest, token string) *core.Iterator[*GetJobResponse] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[*GetJobResponse], error) {
+		pageRequest.Marker = marker
+		result, err := j.List(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[*GetJobResponse]{}, err
+		}
+		return markerPage(result.JobList, result.MarkerInfo), nil
+	})
+}
+
 func (j *job) Update(ctx context.Context, jobID string, request *UpdateJobRequest,
 	token string) (err error) {
-	err = core.NewRequestBuilder(j.client).
+	err = core.NewRequestBuilder(j.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(JobApi+"/"+jobID).
 		WithQueryParam(KeyAction, "modify").
@@ -257,7 +285,7 @@ func (j *job) Update(ctx context.Context, jobID string, request *UpdateJobReques
 }
 
 func (j *job) Stop(ctx context.Context, jobID, token string) (err error) {
-	err = core.NewRequestBuilder(j.client).
+	err = core.NewRequestBuilder(j.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(JobApi+"/"+jobID).
 		WithQueryParam(KeyAction, "stop").
@@ -266,8 +294,27 @@ func (j *job) Stop(ctx context.Context, jobID, token string) (err error) {
 	return
 }
 
+func (j *job) Resubmit(ctx context.Context, jobID string, request *ResubmitJobRequest,
+	token string) (result *CreateJobResponse, err error) {
+	if request == nil {
+		request = &ResubmitJobRequest{}
+	}
+	result = &CreateJobResponse{}
+	err = core.NewRequestBuilder(j.client).WithContext(ctx).
+		WithHeader(common.HeaderKeyAuthorization, token).
+		WithURL(JobApi + "/" + jobID + "/resubmit").
+		WithMethod(http.POST).
+		WithBody(request).
+		WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
 func (j *job) Delete(ctx context.Context, jobID, token string) (err error) {
-	err = core.NewRequestBuilder(j.client).
+	err = core.NewRequestBuilder(j.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(JobApi + "/" + jobID).
 		WithMethod(http.DELETE).
@@ -284,9 +331,12 @@ type JobInterface interface {
 		wf *CreateWfJobRequest, token string) (*CreateJobResponse, error)
 	Get(ctx context.Context, jobID string, token string) (*GetJobResponse, error)
 	List(ctx context.Context, request *ListJobRequest, token string) (*ListJobResponse, error)
+	ListAll(ctx context.Context, request *ListJobRequest, token string) *core.Iterator[*GetJobResponse]
 	Update(ctx context.Context, jobID string, request *UpdateJobRequest, token string) error
 	Stop(ctx context.Context, jobID string, token string) error
 	Delete(ctx context.Context, jobID string, token string) error
+	Resubmit(ctx context.Context, jobID string, request *ResubmitJobRequest, token string) (*CreateJobResponse, error)
+	Watch(ctx context.Context, options *WatchJobOptions, token string) (*JobWatcher, error)
 }
 
 // newJob returns a job.
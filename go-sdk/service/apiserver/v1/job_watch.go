@@ -0,0 +1,162 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	gohttp "net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+)
+
+const (
+	JobWebsocketApi = Prefix + "/wsjob"
+
+	defaultHeartbeatInterval = 10 * time.Second
+	// server echoes heartbeat back, which is dropped by watcher
+	watchHeartbeat = "heartbeat"
+)
+
+type WatchJobOptions struct {
+	// ClientID identifies the connection in server, generated by server if empty
+	ClientID string
+	// HeartbeatInterval keeps connection alive, 10s by default
+	HeartbeatInterval time.Duration
+}
+
+// JobWatcher receives jobs updated after connected through the job websocket
+type JobWatcher struct {
+	conn      *websocket.Conn
+	result    chan *GetJobResponse
+	done      chan struct{}
+	stopOnce  sync.Once
+	writeLock sync.Mutex
+	err       error
+}
+
+// Watch connects to the job websocket of server, jobs updated are sent to ResultChan of the watcher.
+// the channel is closed when ctx is done, Stop is called or the connection is broken.
+func (j *job) Watch(ctx context.Context, options *WatchJobOptions, token string) (*JobWatcher, error) {
+	if options == nil {
+		options = &WatchJobOptions{}
+	}
+	interval := options.HeartbeatInterval
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	header := gohttp.Header{}
+	header.Set(common.HeaderKeyAuthorization, token)
+	if options.ClientID != "" {
+		header.Set(common.HeaderClientIDKey, options.ClientID)
+	}
+	url := fmt.Sprintf("ws://%s:%d%s", j.client.Config.Host, j.client.Config.Port, JobWebsocketApi)
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("connect to job websocket failed, status: %s, err: %v", resp.Status, err)
+		}
+		return nil, fmt.Errorf("connect to job websocket failed: %v", err)
+	}
+
+	w := &JobWatcher{
+		conn:   conn,
+		result: make(chan *GetJobResponse, 100),
+		done:   make(chan struct{}),
+	}
+	go w.readLoop()
+	go w.heartbeatLoop(ctx, interval)
+	return w, nil
+}
+
+// ResultChan returns the channel of jobs updated
+func (w *JobWatcher) ResultChan() <-chan *GetJobResponse {
+	return w.result
+}
+
+// Stop closes the connection, ResultChan is closed after that
+func (w *JobWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+		w.writeLock.Lock()
+		_ = w.conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		w.writeLock.Unlock()
+		w.conn.Close()
+	})
+}
+
+// Err returns the error which breaks the connection, it should be called after ResultChan is closed
+func (w *JobWatcher) Err() error {
+	return w.err
+}
+
+func (w *JobWatcher) readLoop() {
+	defer close(w.result)
+	defer w.Stop()
+	for {
+		_, data, err := w.conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-w.done:
+			default:
+				w.err = err
+			}
+			return
+		}
+		if string(data) == watchHeartbeat {
+			continue
+		}
+		jobInfo := &GetJobResponse{}
+		if err = json.Unmarshal(data, jobInfo); err != nil {
+			log.Warningf("unmarshal job from websocket message failed: %v", err)
+			continue
+		}
+		select {
+		case w.result <- jobInfo:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *JobWatcher) heartbeatLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			w.Stop()
+			return
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.writeLock.Lock()
+			err := w.conn.WriteMessage(websocket.TextMessage, []byte(watchHeartbeat))
+			w.writeLock.Unlock()
+			if err != nil {
+				log.Warningf("write heartbeat to job websocket failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+)
+
+func TestJobWatch(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	heartbeats := make(chan string, 10)
+	mux := http.NewServeMux()
+	mux.HandleFunc(JobWebsocketApi, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get(common.HeaderKeyAuthorization))
+		assert.Equal(t, "client-1", r.Header.Get(common.HeaderClientIDKey))
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer conn.Close()
+		for _, id := range []string{"job-1", "job-2"} {
+			data, _ := json.Marshal(GetJobResponse{
+				CreateSingleJobRequest: CreateSingleJobRequest{CommonJobInfo: CommonJobInfo{ID: id}},
+				Status:                 "running",
+			})
+			assert.Nil(t, conn.WriteMessage(websocket.TextMessage, data))
+		}
+		// echo heartbeat like server
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			heartbeats <- string(data)
+			if err = conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	watcher, err := newMockAPIV1Client(t, server).Job().Watch(ctx, &WatchJobOptions{
+		ClientID:          "client-1",
+		HeartbeatInterval: 10 * time.Millisecond,
+	}, "token")
+	assert.Nil(t, err)
+
+	var jobIDs []string
+	for i := 0; i < 2; i++ {
+		jobInfo := <-watcher.ResultChan()
+		jobIDs = append(jobIDs, jobInfo.ID)
+	}
+	assert.Equal(t, []string{"job-1", "job-2"}, jobIDs)
+	assert.Equal(t, watchHeartbeat, <-heartbeats)
+
+	// heartbeat echoed is not sent to result channel
+	cancel()
+	for jobInfo := range watcher.ResultChan() {
+		t.Errorf("unexpected job %s", jobInfo.ID)
+	}
+	assert.Nil(t, watcher.Err())
+}
@@ -0,0 +1,119 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/core"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/util/http"
+)
+
+type kubernetesObject struct {
+	client *core.PaddleFlowClient
+}
+
+// KubernetesObjectRequest locates a kubernetes object in cluster
+type KubernetesObjectRequest struct {
+	ClusterName string `json:"-"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Kind        string `json:"kind"`
+	APIVersion  string `json:"apiVersion"`
+}
+
+func kubernetesObjectApi(clusterName string) string {
+	return ClusterApi + "/" + clusterName + "/k8s/object"
+}
+
+// Create creates object in cluster, object is an unstructured kubernetes object with apiVersion, kind and metadata
+func (k *kubernetesObject) Create(ctx context.Context, clusterName string, object map[string]interface{},
+	token string) (result map[string]interface{}, err error) {
+	result = make(map[string]interface{})
+	err = newRequestBuilderWithTokenHeader(ctx, k.client, token).
+		WithURL(kubernetesObjectApi(clusterName)).
+		WithMethod(http.POST).
+		WithQueryParam(KeyAction, "create").
+		WithBody(object).
+		WithResult(&result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (k *kubernetesObject) Get(ctx context.Context, request *KubernetesObjectRequest,
+	token string) (result map[string]interface{}, err error) {
+	result = make(map[string]interface{})
+	err = newRequestBuilderWithTokenHeader(ctx, k.client, token).
+		WithURL(kubernetesObjectApi(request.ClusterName)).
+		WithMethod(http.GET).
+		WithQueryParam("name", request.Name).
+		WithQueryParamFilter("namespace", request.Namespace).
+		WithQueryParam("kind", request.Kind).
+		WithQueryParam("apiVersion", request.APIVersion).
+		WithResult(&result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (k *kubernetesObject) Update(ctx context.Context, clusterName string, object map[string]interface{},
+	token string) (result map[string]interface{}, err error) {
+	result = make(map[string]interface{})
+	err = newRequestBuilderWithTokenHeader(ctx, k.client, token).
+		WithURL(kubernetesObjectApi(clusterName)).
+		WithMethod(http.PUT).
+		WithBody(object).
+		WithResult(&result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (k *kubernetesObject) Delete(ctx context.Context, request *KubernetesObjectRequest, token string) (err error) {
+	err = newRequestBuilderWithTokenHeader(ctx, k.client, token).
+		WithURL(kubernetesObjectApi(request.ClusterName)).
+		WithMethod(http.POST).
+		WithQueryParam(KeyAction, "delete").
+		WithBody(request).
+		Do()
+	return
+}
+
+type KubernetesObjectGetter interface {
+	KubernetesObject() KubernetesObjectInterface
+}
+
+type KubernetesObjectInterface interface {
+	Create(ctx context.Context, clusterName string, object map[string]interface{}, token string) (map[string]interface{}, error)
+	Get(ctx context.Context, request *KubernetesObjectRequest, token string) (map[string]interface{}, error)
+	Update(ctx context.Context, clusterName string, object map[string]interface{}, token string) (map[string]interface{}, error)
+	Delete(ctx context.Context, request *KubernetesObjectRequest, token string) error
+}
+
+// newKubernetesObject returns a kubernetesObject.
+func newKubernetesObject(c *APIV1Client) *kubernetesObject {
+	return &kubernetesObject{
+		client: c.RESTClient(),
+	}
+}
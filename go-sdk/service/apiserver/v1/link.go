@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/core"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/util/http"
+)
+
+const (
+	LinkApi   = Prefix + "/link"
+	KeyFsPath = "fsPath"
+)
+
+type link struct {
+	client *core.PaddleFlowClient
+}
+
+type CreateLinkRequest struct {
+	FsName     string            `json:"fsName"`
+	Url        string            `json:"url"`
+	Properties map[string]string `json:"properties"`
+	Username   string            `json:"username"`
+	FsPath     string            `json:"fsPath"`
+}
+
+type DeleteLinkRequest struct {
+	FsName   string `json:"fsName"`
+	Username string `json:"username"`
+	FsPath   string `json:"fsPath"`
+}
+
+type ListLinkRequest struct {
+	FsName   string `json:"fsName"`
+	Username string `json:"username"`
+	FsPath   string `json:"fsPath,omitempty"`
+	Marker   string `json:"marker"`
+	MaxKeys  int    `json:"maxKeys"`
+}
+
+type ListLinkResponse struct {
+	Marker     string          `json:"marker"`
+	Truncated  bool            `json:"truncated"`
+	NextMarker string          `json:"nextMarker"`
+	LinkList   []*LinkResponse `json:"linkList"`
+}
+
+type LinkResponse struct {
+	FsName        string            `json:"fsName"`
+	FsPath        string            `json:"fsPath"`
+	ServerAddress string            `json:"serverAddress"`
+	Type          string            `json:"type"`
+	Username      string            `json:"username"`
+	SubPath       string            `json:"subPath"`
+	Properties    map[string]string `json:"properties"`
+}
+
+func (l *link) Create(ctx context.Context, request *CreateLinkRequest, token string) (err error) {
+	err = newRequestBuilderWithTokenHeader(ctx, l.client, token).
+		WithURL(LinkApi).
+		WithMethod(http.POST).
+		WithBody(request).
+		Do()
+	return
+}
+
+func (l *link) Delete(ctx context.Context, request *DeleteLinkRequest, token string) (err error) {
+	err = newRequestBuilderWithTokenHeader(ctx, l.client, token).
+		WithURL(LinkApi+"/"+request.FsName).
+		WithMethod(http.DELETE).
+		WithQueryParam(KeyFsPath, request.FsPath).
+		WithQueryParamFilter(KeyUsername, request.Username).
+		Do()
+	return
+}
+
+func (l *link) List(ctx context.Context, request *ListLinkRequest,
+	token string) (result *ListLinkResponse, err error) {
+	result = &ListLinkResponse{}
+	err = newRequestBuilderWithTokenHeader(ctx, l.client, token).
+		WithURL(LinkApi+"/"+request.FsName).
+		WithMethod(http.GET).
+		WithQueryParamFilter(KeyUsername, request.Username).
+		WithQueryParamFilter(KeyFsPath, request.FsPath).
+		WithQueryParamFilter(KeyMarker, request.Marker).
+		WithQueryParamFilter(KeyMaxKeys, intFilter(request.MaxKeys)).
+		WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ListAll iterates links of all pages from request.Marker
+func (l *link) ListAll(ctx context.Context, request *ListLinkRequest, token string) *core.Iterator[*LinkResponse] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[*LinkResponse], error) {
+		pageRequest.Marker = marker
+		result, err := l.List(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[*LinkResponse]{}, err
+		}
+		return core.Page[*LinkResponse]{Items: result.LinkList, NextMarker: result.NextMarker, IsTruncated: result.Truncated}, nil
+	})
+}
+
+type LinkGetter interface {
+	Link() LinkInterface
+}
+
+type LinkInterface interface {
+	Create(ctx context.Context, request *CreateLinkRequest, token string) error
+	Delete(ctx context.Context, request *DeleteLinkRequest, token string) error
+	List(ctx context.Context, request *ListLinkRequest, token string) (*ListLinkResponse, error)
+	ListAll(ctx context.Context, request *ListLinkRequest, token string) *core.Iterator[*LinkResponse]
+}
+
+// newLink returns a link.
+func newLink(c *APIV1Client) *link {
+	return &link{
+		client: c.RESTClient(),
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/core"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/util/http"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+)
+
+const (
+	logApi = Prefix + "/log"
+
+	defaultStreamInterval  = 3 * time.Second
+	defaultStreamLineLimit = 1000
+)
+
+type logs struct {
+	client *core.PaddleFlowClient
+	job    *job
+}
+
+type GetRunLogRequest struct {
+	RunID           string `json:"runID"`
+	JobID           string `json:"jobID,omitempty"`
+	PageNo          int    `json:"pageNo,omitempty"`
+	PageSize        int    `json:"pageSize,omitempty"`
+	LogFilePosition string `json:"logFilePosition,omitempty"` // begin or end
+}
+
+type GetRunLogResponse struct {
+	SubmitLog string              `json:"submitLog"`
+	RunLog    []schema.JobLogInfo `json:"runLog"`
+	RunID     string              `json:"runID"`
+}
+
+// GetJobLogRequest gets logs of paddleflow job by JobID, or logs and events of kubernetes pod/deploy by Name
+type GetJobLogRequest struct {
+	JobID        string `json:"jobID,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Namespace    string `json:"namespace,omitempty"`
+	ClusterName  string `json:"clusterName"`
+	ReadFromTail bool   `json:"readFromTail,omitempty"`
+	LineLimit    int    `json:"lineLimit,omitempty"`
+	SizeLimit    string `json:"sizeLimit,omitempty"` // such as 100M
+	Type         string `json:"type,omitempty"`      // single, distributed, workflow, deploy or pod
+	Framework    string `json:"framework,omitempty"`
+}
+
+// StreamJobLogRequest follows logs of a paddleflow job
+type StreamJobLogRequest struct {
+	JobID       string
+	ClusterName string
+	// Interval between two polls, 3s by default
+	Interval time.Duration
+	// LineLimit is lines of log tail got by each poll, new lines more than it between two polls are lost
+	LineLimit int
+}
+
+func (l *logs) GetRunLog(ctx context.Context, request *GetRunLogRequest,
+	token string) (result *GetRunLogResponse, err error) {
+	result = &GetRunLogResponse{}
+	err = newRequestBuilderWithTokenHeader(ctx, l.client, token).
+		WithURL(logApi+"/run/"+request.RunID).
+		WithMethod(http.GET).
+		WithQueryParamFilter("jobID", request.JobID).
+		WithQueryParamFilter("logFilePosition", request.LogFilePosition).
+		WithQueryParamFilter("pageNo", intFilter(request.PageNo)).
+		WithQueryParamFilter("pageSize", intFilter(request.PageSize)).
+		WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (l *logs) GetJobLog(ctx context.Context, request *GetJobLogRequest,
+	token string) (result *schema.JobLogInfo, err error) {
+	result = &schema.JobLogInfo{}
+	err = newRequestBuilderWithTokenHeader(ctx, l.client, token).
+		WithURL(logApi+"/job").
+		WithMethod(http.GET).
+		WithQueryParamFilter("jobID", request.JobID).
+		WithQueryParamFilter("name", request.Name).
+		WithQueryParamFilter("namespace", request.Namespace).
+		WithQueryParamFilter("clusterName", request.ClusterName).
+		WithQueryParam("readFromTail", strconv.FormatBool(request.ReadFromTail)).
+		WithQueryParamFilter("lineLimit", intFilter(request.LineLimit)).
+		WithQueryParamFilter("sizeLimit", request.SizeLimit).
+		WithQueryParamFilter("type", request.Type).
+		WithQueryParamFilter("framework", request.Framework).
+		WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// StreamJobLog polls log tail of each task of job, and calls handler with lines which are not seen before,
+// until the job is finished or ctx is done
+func (l *logs) StreamJobLog(ctx context.Context, request *StreamJobLogRequest, token string,
+	handler func(taskID, line string)) error {
+	interval := request.Interval
+	if interval <= 0 {
+		interval = defaultStreamInterval
+	}
+	lineLimit := request.LineLimit
+	if lineLimit <= 0 {
+		lineLimit = defaultStreamLineLimit
+	}
+	logRequest := &GetJobLogRequest{
+		JobID:        request.JobID,
+		ClusterName:  request.ClusterName,
+		ReadFromTail: true,
+		LineLimit:    lineLimit,
+	}
+	seen := make(map[string][]string)
+	poll := func() error {
+		logInfo, err := l.GetJobLog(ctx, logRequest, token)
+		if err != nil {
+			return err
+		}
+		for _, task := range logInfo.TaskList {
+			lines := splitLogLines(task.Info.LogContent)
+			for _, line := range newLogLines(seen[task.TaskID], lines) {
+				handler(task.TaskID, line)
+			}
+			seen[task.TaskID] = lines
+		}
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		jobInfo, err := l.job.Get(ctx, request.JobID, token)
+		if err != nil {
+			return err
+		}
+		if err = poll(); err != nil {
+			return err
+		}
+		// logs written before job finished have been got by the last poll
+		if schema.IsImmutableJobStatus(schema.JobStatus(jobInfo.Status)) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func splitLogLines(content string) []string {
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+// newLogLines returns lines of current tail after the longest overlap with previous tail
+func newLogLines(prev, cur []string) []string {
+	overlap := len(prev)
+	if overlap > len(cur) {
+		overlap = len(cur)
+	}
+	for ; overlap > 0; overlap-- {
+		if equalLines(prev[len(prev)-overlap:], cur[:overlap]) {
+			break
+		}
+	}
+	return cur[overlap:]
+}
+
+func equalLines(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func intFilter(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+type LogGetter interface {
+	Log() LogInterface
+}
+
+type LogInterface interface {
+	GetRunLog(ctx context.Context, request *GetRunLogRequest, token string) (*GetRunLogResponse, error)
+	GetJobLog(ctx context.Context, request *GetJobLogRequest, token string) (*schema.JobLogInfo, error)
+	StreamJobLog(ctx context.Context, request *StreamJobLogRequest, token string, handler func(taskID, line string)) error
+}
+
+// newLogs returns a logs.
+func newLogs(c *APIV1Client) *logs {
+	return &logs{
+		client: c.RESTClient(),
+		job:    newJob(c),
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/core"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+)
+
+func newMockAPIV1Client(t *testing.T, server *httptest.Server) *APIV1Client {
+	u, err := url.Parse(server.URL)
+	assert.Nil(t, err)
+	port, err := strconv.Atoi(u.Port())
+	assert.Nil(t, err)
+	client, err := NewForConfig(&core.PaddleFlowClientConfiguration{
+		Host:                       u.Hostname(),
+		Port:                       port,
+		ConnectionTimeoutInSeconds: 1,
+	})
+	assert.Nil(t, err)
+	return client
+}
+
+func TestNewLogLines(t *testing.T) {
+	testCases := []struct {
+		name string
+		prev []string
+		cur  []string
+		want []string
+	}{
+		{"first poll", nil, []string{"a", "b"}, []string{"a", "b"}},
+		{"no new lines", []string{"a", "b"}, []string{"a", "b"}, []string{}},
+		{"appended", []string{"a", "b"}, []string{"a", "b", "c"}, []string{"c"}},
+		{"tail moved", []string{"a", "b", "c"}, []string{"c", "d", "e"}, []string{"d", "e"}},
+		{"repeated lines", []string{"x", "x"}, []string{"x", "x", "x"}, []string{"x"}},
+		{"no overlap", []string{"a", "b"}, []string{"c", "d"}, []string{"c", "d"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newLogLines(tc.prev, tc.cur)
+			assert.Equal(t, len(tc.want), len(got))
+			for i := range tc.want {
+				assert.Equal(t, tc.want[i], got[i])
+			}
+		})
+	}
+}
+
+func TestStreamJobLog(t *testing.T) {
+	contents := []string{"line1\n", "line1\nline2\n", "line2\nline3\n"}
+	var polls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc(JobApi+"/job-1", func(w http.ResponseWriter, r *http.Request) {
+		status := schema.StatusJobRunning
+		if int(atomic.LoadInt32(&polls)) >= len(contents)-1 {
+			status = schema.StatusJobSucceeded
+		}
+		_ = json.NewEncoder(w).Encode(GetJobResponse{Status: string(status)})
+	})
+	mux.HandleFunc(logApi+"/job", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("readFromTail"))
+		content := contents[atomic.AddInt32(&polls, 1)-1]
+		_ = json.NewEncoder(w).Encode(schema.JobLogInfo{
+			JobID:    r.URL.Query().Get("jobID"),
+			TaskList: []schema.TaskLogInfo{{TaskID: "task-1", Info: schema.LogInfo{LogContent: content}}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var lines []string
+	err := newMockAPIV1Client(t, server).Log().StreamJobLog(context.TODO(), &StreamJobLogRequest{
+		JobID:    "job-1",
+		Interval: 10 * time.Millisecond,
+	}, "token", func(taskID, line string) {
+		assert.Equal(t, "task-1", taskID)
+		lines = append(lines, line)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"line1", "line2", "line3"}, lines)
+
+	// stopped by ctx
+	atomic.StoreInt32(&polls, 0)
+	contents = []string{"line1\n", "line1\n", "line1\n", "line1\n"}
+	ctx, cancel := context.WithTimeout(context.TODO(), 15*time.Millisecond)
+	defer cancel()
+	mux.HandleFunc(JobApi+"/job-2", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(GetJobResponse{Status: string(schema.StatusJobRunning)})
+	})
+	err = newMockAPIV1Client(t, server).Log().StreamJobLog(ctx, &StreamJobLogRequest{
+		JobID:    "job-2",
+		Interval: 10 * time.Millisecond,
+	}, "token", func(taskID, line string) {})
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -118,7 +118,7 @@ func newPipeline(c *APIV1Client) *pipeline {
 
 func (p *pipeline) Create(ctx context.Context, request *CreatePipelineRequest, token string) (result *CreatePipelineResponse, err error) {
 	result = &CreatePipelineResponse{}
-	err = newRequestBuilderWithTokenHeader(p.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, p.client, token).
 		WithURL(pipelineApi).
 		WithMethod(http.POST).
 		WithBody(request).
@@ -134,7 +134,7 @@ func (p *pipeline) Create(ctx context.Context, request *CreatePipelineRequest, t
 
 func (p *pipeline) Get(ctx context.Context, request *GetPipelineRequest, token string) (result *GetPipelineResponse, err error) {
 	result = &GetPipelineResponse{}
-	err = newRequestBuilderWithTokenHeader(p.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, p.client, token).
 		WithURL(pipelineApi+"/"+request.PipelineID).
 		WithMethod(http.GET).
 		WithResult(result).
@@ -153,7 +153,7 @@ func (p *pipeline) Get(ctx context.Context, request *GetPipelineRequest, token s
 func (p *pipeline) List(ctx context.Context, request *ListPipelineRequest,
 	token string) (result *ListPipelineResponse, err error) {
 	result = &ListPipelineResponse{}
-	err = newRequestBuilderWithTokenHeader(p.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, p.client, token).
 		WithMethod(http.GET).
 		WithResult(result).
 		WithURL(pipelineApi).
@@ -170,8 +170,21 @@ func (p *pipeline) List(ctx context.Context, request *ListPipelineRequest,
 	return
 }
 
+// ListAll iterates pipelines of all pages from request.Marker
+func (p *pipeline) ListAll(ctx context.Context, request *ListPipelineRequest, token string) *core.Iterator[PipelineBrief] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[PipelineBrief], error) {
+		pageRequest.Marker = marker
+		result, err := p.List(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[PipelineBrief]{}, err
+		}
+		return markerPage(result.PipelineList, result.MarkerInfo), nil
+	})
+}
+
 func (p *pipeline) Delete(ctx context.Context, pipelineID, token string) (err error) {
-	err = newRequestBuilderWithTokenHeader(p.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, p.client, token).
 		WithURL(pipelineApi + "/" + pipelineID).
 		WithMethod(http.DELETE).
 		Do()
@@ -186,7 +199,7 @@ func (p *pipeline) Delete(ctx context.Context, pipelineID, token string) (err er
 func (p *pipeline) Update(ctx context.Context, pipelineID string, request *UpdatePipelineRequest,
 	token string) (result *UpdatePipelineResponse, err error) {
 	result = &UpdatePipelineResponse{}
-	err = newRequestBuilderWithTokenHeader(p.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, p.client, token).
 		WithURL(pipelineApi + "/" + pipelineID).
 		WithMethod(http.POST).
 		WithBody(request).
@@ -200,7 +213,7 @@ func (p *pipeline) Update(ctx context.Context, pipelineID string, request *Updat
 
 func (p *pipeline) GetVersion(ctx context.Context, pipelineID, pipelineVersionID, token string) (result *GetPipelineVersionResponse, err error) {
 	result = &GetPipelineVersionResponse{}
-	err = newRequestBuilderWithTokenHeader(p.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, p.client, token).
 		WithURL(pipelineApi + "/" + pipelineID + "/" + pipelineVersionID).
 		WithMethod(http.GET).
 		WithResult(result).
@@ -214,7 +227,7 @@ func (p *pipeline) GetVersion(ctx context.Context, pipelineID, pipelineVersionID
 }
 
 func (p *pipeline) DeleteVersion(ctx context.Context, pipelineID, pipelineVersionID, token string) (err error) {
-	err = newRequestBuilderWithTokenHeader(p.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, p.client, token).
 		WithURL(pipelineApi + "/" + pipelineID + "/" + pipelineVersionID).
 		WithMethod(http.DELETE).
 		Do()
@@ -230,6 +243,7 @@ type PipelineInterface interface {
 	Create(ctx context.Context, request *CreatePipelineRequest, token string) (result *CreatePipelineResponse, err error)
 	Get(ctx context.Context, request *GetPipelineRequest, token string) (result *GetPipelineResponse, err error)
 	List(ctx context.Context, request *ListPipelineRequest, token string) (result *ListPipelineResponse, err error)
+	ListAll(ctx context.Context, request *ListPipelineRequest, token string) *core.Iterator[PipelineBrief]
 	Delete(ctx context.Context, pipelineID, token string) (err error)
 	Update(ctx context.Context, pipelineID string, request *UpdatePipelineRequest, token string) (result *UpdatePipelineResponse, err error)
 	GetVersion(ctx context.Context, pipelineID, pipelineVersionID, token string) (result *GetPipelineVersionResponse, err error)
@@ -109,7 +109,7 @@ type ListQueueResponse struct {
 func (q *queue) Create(ctx context.Context, request *CreateQueueRequest,
 	token string) (result *CreateQueueResponse, err error) {
 	result = &CreateQueueResponse{}
-	err = core.NewRequestBuilder(q.client).
+	err = core.NewRequestBuilder(q.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(QueueApi).
 		WithMethod(http.POST).
@@ -122,7 +122,7 @@ func (q *queue) Create(ctx context.Context, request *CreateQueueRequest,
 func (q *queue) Get(ctx context.Context, queueName,
 	token string) (result *GetQueueResponse, err error) {
 	result = &GetQueueResponse{}
-	err = core.NewRequestBuilder(q.client).
+	err = core.NewRequestBuilder(q.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(QueueApi + "/" + queueName).
 		WithMethod(http.GET).
@@ -137,7 +137,7 @@ func (q *queue) Get(ctx context.Context, queueName,
 func (q *queue) List(ctx context.Context, request *ListQueueRequest,
 	token string) (result *ListQueueResponse, err error) {
 	result = &ListQueueResponse{}
-	err = core.NewRequestBuilder(q.client).
+	err = core.NewRequestBuilder(q.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(QueueApi).
 		WithMethod(http.GET).
@@ -152,10 +152,23 @@ func (q *queue) List(ctx context.Context, request *ListQueueRequest,
 	return
 }
 
+// ListAll iterates queues of all pages from request.Marker
+func (q *queue) ListAll(ctx context.Context, request *ListQueueRequest, token string) *core.Iterator[Queue] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[Queue], error) {
+		pageRequest.Marker = marker
+		result, err := q.List(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[Queue]{}, err
+		}
+		return markerPage(result.QueueList, result.MarkerInfo), nil
+	})
+}
+
 func (q *queue) Update(ctx context.Context, queueName string, request *UpdateQueueRequest,
 	token string) (result *UpdateQueueResponse, err error) {
 	result = &UpdateQueueResponse{}
-	err = core.NewRequestBuilder(q.client).
+	err = core.NewRequestBuilder(q.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(QueueApi + "/" + queueName).
 		WithMethod(http.PUT).
@@ -166,7 +179,7 @@ func (q *queue) Update(ctx context.Context, queueName string, request *UpdateQue
 }
 
 func (q *queue) Delete(ctx context.Context, queueName, token string) (err error) {
-	err = core.NewRequestBuilder(q.client).
+	err = core.NewRequestBuilder(q.client).WithContext(ctx).
 		WithHeader(common.HeaderKeyAuthorization, token).
 		WithURL(QueueApi + "/" + queueName).
 		WithMethod(http.DELETE).
@@ -182,6 +195,7 @@ type QueueInterface interface {
 	Create(ctx context.Context, request *CreateQueueRequest, token string) (*CreateQueueResponse, error)
 	Get(ctx context.Context, queueName string, token string) (*GetQueueResponse, error)
 	List(ctx context.Context, request *ListQueueRequest, token string) (*ListQueueResponse, error)
+	ListAll(ctx context.Context, request *ListQueueRequest, token string) *core.Iterator[Queue]
 	Update(ctx context.Context, queueName string, request *UpdateQueueRequest, token string) (*UpdateQueueResponse, error)
 	Delete(ctx context.Context, queueName string, token string) error
 }
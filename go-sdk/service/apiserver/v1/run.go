@@ -197,6 +197,13 @@ type ListArtifactRequest struct {
 	Marker     string
 }
 
+type DeleteArtifactRequest struct {
+	UserName string // optional, only for root user
+	FsName   string
+	RunID    string
+	Path     string
+}
+
 type ListArtifactResponse struct {
 	common.MarkerInfo
 	ArtifactEventList []ArtifactEventBrief `json:"artifactEventList"`
@@ -213,8 +220,8 @@ func newRun(c *APIV1Client) *run {
 	}
 }
 
-func newRequestBuilderWithTokenHeader(cli *core.PaddleFlowClient, token string) *core.RequestBuilder {
-	builder := core.NewRequestBuilder(cli)
+func newRequestBuilderWithTokenHeader(ctx context.Context, cli *core.PaddleFlowClient, token string) *core.RequestBuilder {
+	builder := core.NewRequestBuilder(cli).WithContext(ctx)
 	builder.WithHeader(common.HeaderKeyAuthorization, token)
 	return builder
 }
@@ -222,7 +229,7 @@ func newRequestBuilderWithTokenHeader(cli *core.PaddleFlowClient, token string)
 func (r *run) Create(ctx context.Context, request *CreateRunRequest,
 	token string) (result *CreateRunResponse, err error) {
 	result = &CreateRunResponse{}
-	err = newRequestBuilderWithTokenHeader(r.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
 		WithURL(runApi).
 		WithMethod(http.POST).
 		WithBody(request).
@@ -239,7 +246,7 @@ func (r *run) Create(ctx context.Context, request *CreateRunRequest,
 func (r *run) Get(ctx context.Context, runID string, token string) (result *GetRunResponse, err error) {
 	// 由于GetResponse中的Runtime类型为接口，不能在直接传给WithResult，因此先用一个临时Map接收Response信息
 	result = &GetRunResponse{}
-	err = newRequestBuilderWithTokenHeader(r.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
 		WithMethod(http.GET).
 		WithURL(runApi + "/" + runID).
 		WithResult(result).
@@ -254,7 +261,7 @@ func (r *run) Get(ctx context.Context, runID string, token string) (result *GetR
 func (r *run) List(ctx context.Context, request *ListRunRequest, token string) (result *ListRunResponse, err error) {
 	result = &ListRunResponse{}
 
-	err = newRequestBuilderWithTokenHeader(r.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
 		WithMethod(http.GET).
 		WithResult(result).
 		WithURL(runApi).
@@ -275,12 +282,25 @@ func (r *run) List(ctx context.Context, request *ListRunRequest, token string) (
 	return
 }
 
+// ListAll iterates runs of all pages from request.Marker
+func (r *run) ListAll(ctx context.Context, request *ListRunRequest, token string) *core.Iterator[RunBrief] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[RunBrief], error) {
+		pageRequest.Marker = marker
+		result, err := r.List(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[RunBrief]{}, err
+		}
+		return markerPage(result.RunList, result.MarkerInfo), nil
+	})
+}
+
 func (r *run) Stop(ctx context.Context, StopForce bool, runID, token string) (err error) {
 	request := StopRequest{
 		StopForce: StopForce,
 	}
 
-	err = newRequestBuilderWithTokenHeader(r.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
 		WithURL(runApi+"/"+runID).
 		WithMethod(http.PUT).
 		WithQueryParam("action", "stop").
@@ -296,10 +316,12 @@ func (r *run) Stop(ctx context.Context, StopForce bool, runID, token string) (er
 
 func (r *run) Retry(ctx context.Context, runID string, token string) (result *RetryRunResponse, err error) {
 	result = &RetryRunResponse{}
-	err = newRequestBuilderWithTokenHeader(r.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
 		WithURL(runApi+"/"+runID).
 		WithMethod(http.PUT).
 		WithQueryParam("action", "retry").
+		// retry of run creates a new run, so it is not retried on failure
+		WithIdempotent(false).
 		WithResult(result).
 		Do()
 
@@ -310,7 +332,7 @@ func (r *run) Retry(ctx context.Context, runID string, token string) (result *Re
 }
 
 func (r *run) Delete(ctx context.Context, runID string, token string) (err error) {
-	err = newRequestBuilderWithTokenHeader(r.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
 		WithURL(runApi + "/" + runID).
 		WithMethod(http.DELETE).
 		Do()
@@ -325,7 +347,7 @@ func (r *run) Delete(ctx context.Context, runID string, token string) (err error
 func (r *run) ListRunCache(ctx context.Context, request *ListRunCacheRequest, token string) (result *ListRunCacheResponse, err error) {
 	result = &ListRunCacheResponse{}
 
-	err = newRequestBuilderWithTokenHeader(r.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
 		WithMethod(http.GET).
 		WithResult(result).
 		WithURL(runCacheApi).
@@ -343,10 +365,23 @@ func (r *run) ListRunCache(ctx context.Context, request *ListRunCacheRequest, to
 	return
 }
 
+// ListAllRunCache iterates run caches of all pages from request.Marker
+func (r *run) ListAllRunCache(ctx context.Context, request *ListRunCacheRequest, token string) *core.Iterator[RunCacheBrief] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[RunCacheBrief], error) {
+		pageRequest.Marker = marker
+		result, err := r.ListRunCache(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[RunCacheBrief]{}, err
+		}
+		return markerPage(result.RunCacheList, result.MarkerInfo), nil
+	})
+}
+
 func (r *run) GetRunCache(ctx context.Context, runCacheID string, token string) (result *GetRunCacheResponse, err error) {
 	result = &GetRunCacheResponse{}
 
-	err = newRequestBuilderWithTokenHeader(r.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
 		WithMethod(http.GET).
 		WithResult(result).
 		WithURL(runCacheApi + "/" + runCacheID).
@@ -360,7 +395,7 @@ func (r *run) GetRunCache(ctx context.Context, runCacheID string, token string)
 }
 
 func (r *run) DeleteRunCache(ctx context.Context, runCacheID string, token string) (err error) {
-	err = newRequestBuilderWithTokenHeader(r.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
 		WithMethod(http.DELETE).
 		WithURL(runCacheApi + "/" + runCacheID).
 		Do()
@@ -376,7 +411,7 @@ func (r *run) ListArtifact(ctx context.Context, request *ListArtifactRequest,
 	token string) (result *ListArtifactResponse, err error) {
 	result = &ListArtifactResponse{}
 
-	err = newRequestBuilderWithTokenHeader(r.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
 		WithMethod(http.GET).
 		WithResult(result).
 		WithURL(runArtifactApi).
@@ -396,19 +431,48 @@ func (r *run) ListArtifact(ctx context.Context, request *ListArtifactRequest,
 	return
 }
 
+// ListAllArtifact iterates artifacts of all pages from request.Marker
+func (r *run) ListAllArtifact(ctx context.Context, request *ListArtifactRequest, token string) *core.Iterator[ArtifactEventBrief] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[ArtifactEventBrief], error) {
+		pageRequest.Marker = marker
+		result, err := r.ListArtifact(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[ArtifactEventBrief]{}, err
+		}
+		return markerPage(result.ArtifactEventList, result.MarkerInfo), nil
+	})
+}
+
+func (r *run) DeleteArtifact(ctx context.Context, request *DeleteArtifactRequest, token string) (err error) {
+	err = newRequestBuilderWithTokenHeader(ctx, r.client, token).
+		WithMethod(http.DELETE).
+		WithURL(runArtifactApi).
+		WithQueryParamFilter("username", request.UserName).
+		WithQueryParam("fsname", request.FsName).
+		WithQueryParam("runID", request.RunID).
+		WithQueryParam("path", request.Path).
+		Do()
+	return
+}
+
 type RunInterface interface {
 	Create(ctx context.Context, request *CreateRunRequest, token string) (result *CreateRunResponse, err error)
 	Get(ctx context.Context, runID string, token string) (result *GetRunResponse, err error)
 	List(ctx context.Context, request *ListRunRequest, token string) (result *ListRunResponse, err error)
+	ListAll(ctx context.Context, request *ListRunRequest, token string) *core.Iterator[RunBrief]
 	Stop(ctx context.Context, StopForce bool, runID, token string) (err error)
 	Retry(ctx context.Context, runID string, token string) (result *RetryRunResponse, err error)
 	Delete(ctx context.Context, runID string, token string) (err error)
 
 	ListRunCache(ctx context.Context, request *ListRunCacheRequest, token string) (result *ListRunCacheResponse, err error)
+	ListAllRunCache(ctx context.Context, request *ListRunCacheRequest, token string) *core.Iterator[RunCacheBrief]
 	GetRunCache(ctx context.Context, runCacheID string, token string) (result *GetRunCacheResponse, err error)
 	DeleteRunCache(ctx context.Context, runCacheID string, token string) (err error)
 
 	ListArtifact(ctx context.Context, request *ListArtifactRequest, token string) (result *ListArtifactResponse, err error)
+	ListAllArtifact(ctx context.Context, request *ListArtifactRequest, token string) *core.Iterator[ArtifactEventBrief]
+	DeleteArtifact(ctx context.Context, request *DeleteArtifactRequest, token string) (err error)
 }
 
 type RunGetter interface {
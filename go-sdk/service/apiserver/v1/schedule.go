@@ -48,6 +48,16 @@ type CreateScheduleResponse struct {
 	ScheduleID string `json:"scheduleID"`
 }
 
+type BackfillScheduleRequest struct {
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+}
+
+type BackfillScheduleResponse struct {
+	ScheduleID string `json:"scheduleID"`
+	RunCount   int    `json:"runCount"`
+}
+
 type ScheduleBrief struct {
 	ID                string          `json:"scheduleID"`
 	Name              string          `json:"name"`
@@ -65,6 +75,8 @@ type ScheduleBrief struct {
 	NextRunTime       string          `json:"nextRunTime"`
 	Message           string          `json:"scheduleMsg"`
 	Status            string          `json:"status"`
+	BackfillNextTime  string          `json:"backfillNextTime"`
+	BackfillEndTime   string          `json:"backfillEndTime"`
 }
 
 type ScheduleOptions struct {
@@ -120,7 +132,7 @@ func newSchedule(c *APIV1Client) *schedule {
 
 func (s *schedule) Create(ctx context.Context, request *CreateScheduleRequest, token string) (result *CreateScheduleResponse, err error) {
 	result = &CreateScheduleResponse{}
-	err = newRequestBuilderWithTokenHeader(s.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, s.client, token).
 		WithURL(scheduleAPI).
 		WithMethod(http.POST).
 		WithBody(request).
@@ -136,7 +148,7 @@ func (s *schedule) Create(ctx context.Context, request *CreateScheduleRequest, t
 
 func (s *schedule) Get(ctx context.Context, request *GetScheduleRequest, token string) (result *GetScheduleResponse, err error) {
 	result = &GetScheduleResponse{}
-	err = newRequestBuilderWithTokenHeader(s.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, s.client, token).
 		WithMethod(http.GET).
 		WithURL(scheduleAPI+"/"+request.ScheduleID).
 		WithQueryParam("runFilter", strings.Join(request.RunFilter, ",")).
@@ -155,7 +167,7 @@ func (s *schedule) Get(ctx context.Context, request *GetScheduleRequest, token s
 func (s *schedule) List(ctx context.Context, request *ListScheduleRequest, token string) (result *ListScheduleResponse, err error) {
 	result = &ListScheduleResponse{}
 
-	err = newRequestBuilderWithTokenHeader(s.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, s.client, token).
 		WithMethod(http.GET).
 		WithResult(result).
 		WithURL(scheduleAPI).
@@ -176,8 +188,21 @@ func (s *schedule) List(ctx context.Context, request *ListScheduleRequest, token
 	return
 }
 
+// ListAll iterates schedules of all pages from request.Marker
+func (s *schedule) ListAll(ctx context.Context, request *ListScheduleRequest, token string) *core.Iterator[ScheduleBrief] {
+	pageRequest := *request
+	return core.NewIterator(ctx, request.Marker, func(ctx context.Context, marker string) (core.Page[ScheduleBrief], error) {
+		pageRequest.Marker = marker
+		result, err := s.List(ctx, &pageRequest, token)
+		if err != nil {
+			return core.Page[ScheduleBrief]{}, err
+		}
+		return markerPage(result.ScheduleList, result.MarkerInfo), nil
+	})
+}
+
 func (s *schedule) Stop(ctx context.Context, scheduleID string, token string) (err error) {
-	err = newRequestBuilderWithTokenHeader(s.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, s.client, token).
 		WithURL(scheduleAPI + "/" + scheduleID).
 		WithMethod(http.PUT).
 		Do()
@@ -189,8 +214,53 @@ func (s *schedule) Stop(ctx context.Context, scheduleID string, token string) (e
 	return
 }
 
+func (s *schedule) Pause(ctx context.Context, scheduleID string, token string) (err error) {
+	err = newRequestBuilderWithTokenHeader(ctx, s.client, token).
+		WithURL(scheduleAPI+"/"+scheduleID).
+		WithQueryParam("action", "pause").
+		WithMethod(http.PUT).
+		Do()
+
+	if err != nil {
+		return err
+	}
+
+	return
+}
+
+func (s *schedule) Resume(ctx context.Context, scheduleID string, token string) (err error) {
+	err = newRequestBuilderWithTokenHeader(ctx, s.client, token).
+		WithURL(scheduleAPI+"/"+scheduleID).
+		WithQueryParam("action", "resume").
+		WithMethod(http.PUT).
+		Do()
+
+	if err != nil {
+		return err
+	}
+
+	return
+}
+
+func (s *schedule) Backfill(ctx context.Context, scheduleID string, request *BackfillScheduleRequest,
+	token string) (result *BackfillScheduleResponse, err error) {
+	result = &BackfillScheduleResponse{}
+	err = newRequestBuilderWithTokenHeader(ctx, s.client, token).
+		WithURL(scheduleAPI + "/" + scheduleID + "/backfill").
+		WithMethod(http.POST).
+		WithBody(request).
+		WithResult(result).
+		Do()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
 func (s *schedule) Delete(ctx context.Context, scheduleID string, token string) (err error) {
-	err = newRequestBuilderWithTokenHeader(s.client, token).
+	err = newRequestBuilderWithTokenHeader(ctx, s.client, token).
 		WithURL(scheduleAPI + "/" + scheduleID).
 		WithMethod(http.DELETE).
 		Do()
@@ -206,7 +276,11 @@ type ScheduleInterface interface {
 	Create(ctx context.Context, request *CreateScheduleRequest, token string) (result *CreateScheduleResponse, err error)
 	Get(ctx context.Context, request *GetScheduleRequest, token string) (result *GetScheduleResponse, err error)
 	List(ctx context.Context, request *ListScheduleRequest, token string) (result *ListScheduleResponse, err error)
+	ListAll(ctx context.Context, request *ListScheduleRequest, token string) *core.Iterator[ScheduleBrief]
 	Stop(ctx context.Context, scheduleID string, token string) (err error)
+	Pause(ctx context.Context, scheduleID string, token string) (err error)
+	Resume(ctx context.Context, scheduleID string, token string) (err error)
+	Backfill(ctx context.Context, scheduleID string, request *BackfillScheduleRequest, token string) (result *BackfillScheduleResponse, err error)
 	Delete(ctx context.Context, scheduleID string, token string) (err error)
 }
 
@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/core"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/http/util/http"
+)
+
+const (
+	statisticsApi = Prefix + "/statistics"
+)
+
+type statistics struct {
+	client *core.PaddleFlowClient
+}
+
+type JobStatisticsResponse struct {
+	MetricsInfo map[string]string `json:"metricsInfo"`
+}
+
+// JobDetailStatisticsRequest queries metrics in [Start, End] with Step, all in seconds
+type JobDetailStatisticsRequest struct {
+	JobID string `json:"jobID"`
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+	Step  int64  `json:"step,omitempty"` // 60 by default
+}
+
+type JobDetailStatisticsResponse struct {
+	Result    []TaskStatistics `json:"result"`
+	Truncated bool             `json:"truncated"`
+}
+
+type TaskStatistics struct {
+	TaskName string       `json:"taskName"`
+	TaskInfo []MetricInfo `json:"taskInfo"`
+}
+
+type MetricInfo struct {
+	MetricName string       `json:"metric"`
+	Values     [][2]float64 `json:"values"`
+}
+
+func (s *statistics) GetJobStatistics(ctx context.Context, jobID,
+	token string) (result *JobStatisticsResponse, err error) {
+	result = &JobStatisticsResponse{}
+	err = newRequestBuilderWithTokenHeader(ctx, s.client, token).
+		WithURL(statisticsApi + "/job/" + jobID).
+		WithMethod(http.GET).
+		WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (s *statistics) GetJobDetailStatistics(ctx context.Context, request *JobDetailStatisticsRequest,
+	token string) (result *JobDetailStatisticsResponse, err error) {
+	result = &JobDetailStatisticsResponse{}
+	requestClient := newRequestBuilderWithTokenHeader(ctx, s.client, token).
+		WithURL(statisticsApi+"/jobDetail/"+request.JobID).
+		WithMethod(http.GET).
+		WithQueryParam("start", strconv.FormatInt(request.Start, 10)).
+		WithQueryParam("end", strconv.FormatInt(request.End, 10))
+	if request.Step > 0 {
+		requestClient.WithQueryParam("step", strconv.FormatInt(request.Step, 10))
+	}
+	err = requestClient.WithResult(result).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+type StatisticsGetter interface {
+	Statistics() StatisticsInterface
+}
+
+type StatisticsInterface interface {
+	GetJobStatistics(ctx context.Context, jobID string, token string) (*JobStatisticsResponse, error)
+	GetJobDetailStatistics(ctx context.Context, request *JobDetailStatisticsRequest, token string) (*JobDetailStatisticsResponse, error)
+}
+
+// newStatistics returns a statistics.
+func newStatistics(c *APIV1Client) *statistics {
+	return &statistics{
+		client: c.RESTClient(),
+	}
+}
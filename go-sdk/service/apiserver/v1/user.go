@@ -44,7 +44,7 @@ type LoginResponse struct {
 
 func (u *user) Login(ctx context.Context, request *LoginInfo) (result *LoginResponse, err error) {
 	result = &LoginResponse{}
-	err = core.NewRequestBuilder(u.client).
+	err = core.NewRequestBuilder(u.client).WithContext(ctx).
 		WithURL(LoginApi).
 		WithMethod(http.POST).
 		WithBody(request).
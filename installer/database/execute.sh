@@ -1,20 +1,29 @@
 #bin/bash
 
+# tables are created by migrations of paddleflow-server, when database.migrateOnStart is true
+# or `paddleflow-server migrate up` is executed, so only the database is created here.
 if [ $DB_DRIVER == "mysql" ];then
   mysql -u$DB_USER -h$DB_HOST -p$DB_PW -P$DB_PORT -e "use $DB_DATABASE" &>/dev/null
   if [ $? -ne 0 ]
   then
-   echo "MySQL database $DB_DATABASE is not exist"
+   echo "creating MySQL database $DB_DATABASE."
+   mysql -u$DB_USER -h$DB_HOST -p$DB_PW -P$DB_PORT -e "CREATE DATABASE $DB_DATABASE DEFAULT CHARACTER SET utf8 COLLATE utf8_bin;"
+   echo "creating database $DB_DATABASE completed."
   else
    echo "MySQL database $DB_DATABASE is exist, starting backup."
    mysqldump -u$DB_USER -h$DB_HOST -p$DB_PW -P$DB_PORT --databases $DB_DATABASE >  $DB_DATABASE.bak_`date +%Y%m%d`.sql
-   mysql -u$DB_USER -h$DB_HOST -p$DB_PW -P$DB_PORT $DB_DATABASE -e "drop database if exists $DB_DATABASE;"
   fi
-  echo "creating database $DB_DATABASE."
-  sed -i "s/paddleflow_db/$DB_DATABASE/g" paddleflow.sql
-  mysql -u$DB_USER -h$DB_HOST -p$DB_PW -P$DB_PORT -e "source paddleflow.sql"
-  echo "creating database $DB_DATABASE completed."
 
 elif [ $DB_DRIVER == "postgres" ];then
-  echo "not implement"
-fi
\ No newline at end of file
+  export PGPASSWORD=$DB_PW
+  psql -U $DB_USER -h $DB_HOST -p $DB_PORT -d postgres -tAc "SELECT 1 FROM pg_database WHERE datname='$DB_DATABASE'" | grep -q 1
+  if [ $? -ne 0 ]
+  then
+   echo "creating PostgreSQL database $DB_DATABASE."
+   psql -U $DB_USER -h $DB_HOST -p $DB_PORT -d postgres -c "CREATE DATABASE $DB_DATABASE ENCODING 'UTF8';"
+   echo "creating database $DB_DATABASE completed."
+  else
+   echo "PostgreSQL database $DB_DATABASE is exist, starting backup."
+   pg_dump -U $DB_USER -h $DB_HOST -p $DB_PORT $DB_DATABASE > $DB_DATABASE.bak_`date +%Y%m%d`.sql
+  fi
+fi
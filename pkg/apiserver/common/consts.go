@@ -24,10 +24,12 @@ const (
 	PrefixPipeline   = "ppl-"
 	PrefixCache      = "cch-"
 	PrefixGrant      = "grant"
+	PrefixToken      = "token"
 	PrefixQueue      = "queue"
 	PrefixCluster    = "cluster"
 	PrefixFlavour    = "flavour"
 	PrefixConnection = "conn"
+	PrefixWarmup     = "warmup"
 
 	ResourceTypeSchedule      = "schedule"
 	ResourceTypeRun           = "run"
@@ -41,6 +43,7 @@ const (
 	ResourceTypePipeline      = "pipeline"
 	ResourceTypeCluster       = "cluster"
 	ResourceTypeJob           = "job"
+	ResourceTypeGroup         = "group"
 
 	HeaderKeyRequestID     = "x-pf-request-id"
 	HeaderKeyUserName      = "x-pf-user-name"
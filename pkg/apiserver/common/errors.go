@@ -55,6 +55,10 @@ const (
 	AuthInvalidToken = "AuthInvalidToken" // 无效token
 	AuthFailed       = "AuthFailed"       // 用户名或者密码错误
 	AuthIllegalUser  = "AuthIllegalUser"  // 非法用户
+	AuthScopeDenied  = "AuthScopeDenied"  // access token的scope不允许该操作
+
+	AccessTokenNotFound     = "AccessTokenNotFound"
+	InvalidAccessTokenScope = "InvalidAccessTokenScope"
 
 	DBUpdateFailed = "UpdateDatabaseFailed"
 
@@ -78,6 +82,13 @@ const (
 	GrantNotFound             = "GrantNotFound"
 	GrantAlreadyExist         = "GrantAlreadyExist"
 	GrantRootActionNotSupport = "GrantRootActionNotSupport"
+	GrantInvalidRole          = "GrantInvalidRole"
+	GrantInvalidSubject       = "GrantInvalidSubject"
+
+	GroupNotFound           = "GroupNotFound"
+	GroupAlreadyExist       = "GroupAlreadyExist"
+	GroupMemberNotFound     = "GroupMemberNotFound"
+	GroupMemberAlreadyExist = "GroupMemberAlreadyExist"
 
 	RunNameDuplicated     = "RunNameDuplicated"
 	RunNotFound           = "RunNotFound"
@@ -163,6 +174,10 @@ var errorHTTPStatus = map[string]int{
 	AuthInvalidToken: http.StatusBadRequest,
 	AuthFailed:       http.StatusBadRequest,
 	AuthIllegalUser:  http.StatusBadRequest,
+	AuthScopeDenied:  http.StatusForbidden,
+
+	AccessTokenNotFound:     http.StatusNotFound,
+	InvalidAccessTokenScope: http.StatusBadRequest,
 
 	QueueNameDuplicated:          http.StatusForbidden,
 	QueueActionIsNotSupported:    http.StatusBadRequest,
@@ -184,6 +199,13 @@ var errorHTTPStatus = map[string]int{
 	GrantNotFound:             http.StatusBadRequest,
 	GrantAlreadyExist:         http.StatusBadRequest,
 	GrantRootActionNotSupport: http.StatusBadRequest,
+	GrantInvalidRole:          http.StatusBadRequest,
+	GrantInvalidSubject:       http.StatusBadRequest,
+
+	GroupNotFound:           http.StatusNotFound,
+	GroupAlreadyExist:       http.StatusBadRequest,
+	GroupMemberNotFound:     http.StatusNotFound,
+	GroupMemberAlreadyExist: http.StatusBadRequest,
 
 	FlavourNotFound:     http.StatusNotFound,
 	FlavourNameEmpty:    http.StatusBadRequest,
@@ -260,6 +282,10 @@ var errorMessage = map[string]string{
 	AuthInvalidToken: "Invalid token. Please re-login",
 	AuthFailed:       "Username or password not correct",
 	AuthIllegalUser:  "The user does not have permission to operate other users",
+	AuthScopeDenied:  "The access token does not have the scope of this operation",
+
+	AccessTokenNotFound:     "Access token not found",
+	InvalidAccessTokenScope: "Scope of access token is invalid",
 
 	QueueNameDuplicated:          "The queue name already exists",
 	QueueActionIsNotSupported:    "Queue action not supported",
@@ -283,6 +309,13 @@ var errorMessage = map[string]string{
 	GrantNotFound:             "Grant not found. check the user and resource",
 	GrantAlreadyExist:         "This user already have the grant of the resource",
 	GrantRootActionNotSupport: "Can not delete or create root's grant",
+	GrantInvalidRole:          "Role of grant is invalid, only viewer, submitter and admin are supported",
+	GrantInvalidSubject:       "Grant should be given to either a user or a group",
+
+	GroupNotFound:           "Group not found",
+	GroupAlreadyExist:       "Group already exist",
+	GroupMemberNotFound:     "User is not a member of the group",
+	GroupMemberAlreadyExist: "User is already a member of the group",
 
 	ClusterNameNotFound:      "ClusterName does not exist",
 	ClusterIdNotFound:        "ClusterId does not exist",
@@ -0,0 +1,61 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+// 授权的角色, 每个角色包含对资源的一组操作权限
+const (
+	// RoleViewer 只读, 可以查看资源
+	RoleViewer = "viewer"
+	// RoleSubmitter 可以查看和使用资源, 如向队列提交作业、挂载文件系统、运行工作流
+	RoleSubmitter = "submitter"
+	// RoleAdmin 可以查看、使用和修改资源
+	RoleAdmin = "admin"
+)
+
+// 对资源的操作
+const (
+	ActionRead  = "read"
+	ActionUse   = "use"
+	ActionWrite = "write"
+)
+
+// rolePermissions 角色拥有的操作权限
+var rolePermissions = map[string]map[string]bool{
+	RoleViewer: {
+		ActionRead: true,
+	},
+	RoleSubmitter: {
+		ActionRead: true,
+		ActionUse:  true,
+	},
+	RoleAdmin: {
+		ActionRead:  true,
+		ActionUse:   true,
+		ActionWrite: true,
+	},
+}
+
+// IsValidRole 检查角色是否存在
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RoleAllows 检查角色是否允许对资源进行操作
+func RoleAllows(role, action string) bool {
+	return rolePermissions[role][action]
+}
@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// access token的权限范围格式为<资源>:<操作>, 如job:write, *:read
+const (
+	ScopeAll       = "*"
+	ScopeRead      = "read"
+	ScopeWrite     = "write"
+	ScopeSeparator = ":"
+
+	// ScopeResourceToken access token不能用于管理access token, 因此不允许授予该资源的权限
+	ScopeResourceToken = "token"
+	// ScopeResourceVersion 查询服务版本不需要权限
+	ScopeResourceVersion = "version"
+)
+
+// ScopeResources 可以授予access token的资源, 对应api路径的第一段
+var ScopeResources = map[string]bool{
+	"artifact":   true,
+	"cluster":    true,
+	"flavour":    true,
+	"fs":         true,
+	"fsCache":    true,
+	"grant":      true,
+	"group":      true,
+	"job":        true,
+	"link":       true,
+	"log":        true,
+	"pipeline":   true,
+	"queue":      true,
+	"run":        true,
+	"runCache":   true,
+	"schedule":   true,
+	"statistics": true,
+	"user":       true,
+}
+
+// scopeResourceAlias api路径与资源不一致时, 映射为对应的资源
+var scopeResourceAlias = map[string]string{
+	"wsjob":   "job",
+	"runjson": "run",
+}
+
+// ScopeResourceOfPath 返回api路径对应的资源, 如job/xxx对应job
+func ScopeResourceOfPath(path string) string {
+	resource := strings.SplitN(strings.Trim(path, "/"), "/", 2)[0]
+	if alias, ok := scopeResourceAlias[resource]; ok {
+		return alias
+	}
+	return resource
+}
+
+// ValidateScopes 检查权限范围格式是否合法
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("scopes of access token is empty")
+	}
+	for _, scope := range scopes {
+		items := strings.Split(scope, ScopeSeparator)
+		if len(items) != 2 {
+			return fmt.Errorf("scope %s is not in format of <resource>:<read|write>", scope)
+		}
+		if items[0] != ScopeAll && !ScopeResources[items[0]] {
+			return fmt.Errorf("resource %s of scope %s is not supported", items[0], scope)
+		}
+		if items[1] != ScopeRead && items[1] != ScopeWrite {
+			return fmt.Errorf("action %s of scope %s is not supported, only read and write are supported", items[1], scope)
+		}
+	}
+	return nil
+}
+
+// ScopesAllow 检查权限范围是否允许对资源进行读或写, 写权限包含读权限
+func ScopesAllow(scopes []string, resource string, write bool) bool {
+	switch resource {
+	case ScopeResourceToken:
+		return false
+	case ScopeResourceVersion:
+		return true
+	}
+	for _, scope := range scopes {
+		items := strings.Split(scope, ScopeSeparator)
+		if len(items) != 2 {
+			continue
+		}
+		if items[0] != ScopeAll && items[0] != resource {
+			continue
+		}
+		if items[1] == ScopeWrite || !write && items[1] == ScopeRead {
+			return true
+		}
+	}
+	return false
+}
@@ -27,6 +27,7 @@ const (
 	StatusRunTerminating = "terminating"
 	StatusRunTerminated  = "terminated"
 	StatusRunSkipped     = "skipped"
+	StatusRunTimeout     = "timeout"
 
 	WfEventKeyRunID         = "runID"
 	WfEventKeyPK            = "pk"
@@ -34,6 +35,7 @@ const (
 	WfEventKeyView          = "runtime"
 	WfEventKeyComponentName = "componentName"
 	WfEventKeyStartTime     = "startTime"
+	WfEventKeyReason        = "reason"
 )
 
 var (
@@ -42,6 +44,7 @@ var (
 		StatusRunSucceeded,
 		StatusRunTerminated,
 		StatusRunSkipped,
+		StatusRunTimeout,
 	}
 
 	RunActiveStatus = []string{
@@ -56,7 +59,8 @@ func IsRunFinalStatus(status string) bool {
 	if strings.EqualFold(status, StatusRunFailed) ||
 		strings.EqualFold(status, StatusRunSucceeded) ||
 		strings.EqualFold(status, StatusRunTerminated) ||
-		strings.EqualFold(status, StatusRunSkipped) {
+		strings.EqualFold(status, StatusRunSkipped) ||
+		strings.EqualFold(status, StatusRunTimeout) {
 		return true
 	}
 	return false
@@ -38,6 +38,7 @@ const (
 	RegPatternScheduleName = "^[A-Za-z_][A-Za-z0-9_]{1,49}$"
 	RegPatternResource     = "^[1-9][0-9]*([numkMGTPE]|Ki|Mi|Gi|Ti|Pi|Ei)?$"
 	RegPatternClusterName  = "^[A-Za-z0-9_][A-Za-z0-9-_]{0,253}[A-Za-z0-9_]$"
+	RegPatternGroupName    = "^[A-Za-z][A-Za-z0-9_-]{1,59}$"
 
 	// DNS1123LabelMaxLength is a label's max length in DNS (RFC 1123)
 	DNS1123LabelMaxLength = 63
@@ -292,7 +292,7 @@ func CreateCluster(ctx *logger.RequestContext, request *CreateClusterRequest) (*
 	}
 
 	err := storage.Cluster.CreateCluster(&clusterInfo)
-	response := CreateClusterResponse{clusterInfo}
+	response := CreateClusterResponse{clusterInfo.Redacted()}
 	return &response, err
 }
 
@@ -363,34 +363,23 @@ func ListCluster(ctx *logger.RequestContext, marker string, maxKeys int,
 	}
 	response.MaxKeys = int(maxKeys)
 	for _, cluster := range clusterList {
-		response.ClusterList = append(response.ClusterList, cluster)
+		response.ClusterList = append(response.ClusterList, cluster.Redacted())
 	}
 
 	return &response, nil
 }
 
 func GetCluster(ctx *logger.RequestContext, clusterName string) (*GetClusterResponse, error) {
-	if !common.IsRootUser(ctx.UserName) {
-		ctx.ErrorCode = common.OnlyRootAllowed
-		ctx.Logging().Errorln("get cluster failed. error: admin is needed.")
-		return nil, errors.New("get cluster failed")
-	}
-
 	clusterInfo, err := storage.Cluster.GetClusterByName(clusterName)
 	if err != nil {
 		ctx.ErrorMessage = err.Error()
 		ctx.Logging().Errorf("get cluster failed. clusterName:[%s]", clusterName)
 		return nil, err
 	}
-	return &GetClusterResponse{clusterInfo}, nil
+	return &GetClusterResponse{clusterInfo.Redacted()}, nil
 }
 
 func DeleteCluster(ctx *logger.RequestContext, clusterName string) error {
-	if !common.IsRootUser(ctx.UserName) {
-		ctx.ErrorCode = common.OnlyRootAllowed
-		ctx.Logging().Errorln("delete cluster failed. error: admin is needed.")
-		return errors.New("delete cluster failed")
-	}
 	// 检查clusterName是否存在
 	clusterInfo, err := storage.Cluster.GetClusterByName(clusterName)
 	if err != nil {
@@ -433,12 +422,6 @@ func DeleteCluster(ctx *logger.RequestContext, clusterName string) error {
 
 func UpdateCluster(ctx *logger.RequestContext,
 	clusterName string, request *UpdateClusterRequest) (*UpdateClusterReponse, error) {
-	if !common.IsRootUser(ctx.UserName) {
-		ctx.ErrorCode = common.OnlyRootAllowed
-		ctx.Logging().Errorln("update cluster failed. error: admin is needed.")
-		return nil, errors.New("update cluster failed")
-	}
-
 	clusterInfo, err := storage.Cluster.GetClusterByName(clusterName)
 	if err != nil {
 		ctx.ErrorCode = common.ClusterNameNotFound
@@ -457,7 +440,7 @@ func UpdateCluster(ctx *logger.RequestContext,
 		ctx.Logging().Errorf("delete cluster failed. clusterName:[%s]", clusterName)
 		return nil, err
 	}
-	response := UpdateClusterReponse{clusterInfo}
+	response := UpdateClusterReponse{clusterInfo.Redacted()}
 	return &response, nil
 }
 
@@ -92,10 +92,29 @@ func updateMountPodCacheStats(clusterID string, k8sRuntime *runtime.KubeRuntime)
 }
 
 func syncCacheFromMountPod(pod *k8sCore.Pod, clusterID string) error {
+	fsCache, err := fsCacheFromMountPod(pod, clusterID)
+	if err != nil {
+		return err
+	}
+	warmupFromMountPod(pod, fsCache)
+	if fsCache.WarmupID != "" {
+		err = updateFSCacheWarmup(fsCache)
+	} else {
+		err = addOrUpdateFSCache(fsCache)
+	}
+	if err != nil {
+		errRet := fmt.Errorf("addOrUpdateFSCache[%+v] for pod[%s] failed: %v", *fsCache, pod.Name, err)
+		log.Errorf(errRet.Error())
+		return errRet
+	}
+	return nil
+}
+
+func fsCacheFromMountPod(pod *k8sCore.Pod, clusterID string) (*model.FSCache, error) {
 	if pod.Labels == nil || pod.Annotations == nil {
 		errRet := fmt.Errorf("mount pod[%s] Labels or Annotations is nil", pod.Name)
 		log.Errorf(errRet.Error())
-		return errRet
+		return nil, errRet
 	}
 	fsCache := &model.FSCache{ClusterID: clusterID}
 	for k, v := range pod.Labels {
@@ -105,7 +124,7 @@ func syncCacheFromMountPod(pod *k8sCore.Pod, clusterID string) error {
 			if err != nil {
 				errRet := fmt.Errorf("mount pod[%s] used size %s failed to convert to int err: %v", pod.Name, v, err)
 				log.Errorf(errRet.Error())
-				return errRet
+				return nil, errRet
 			}
 			fsCache.UsedSize = usedSize
 		case schema.LabelKeyFsID:
@@ -121,7 +140,7 @@ func syncCacheFromMountPod(pod *k8sCore.Pod, clusterID string) error {
 	if !ok {
 		errRet := fmt.Errorf("mount pod[%s] cache dir not exist in annotation", pod.Name)
 		log.Errorf(errRet.Error())
-		return errRet
+		return nil, errRet
 	}
 	fsCache.CacheDir = cacheDir
 
@@ -130,15 +149,9 @@ func syncCacheFromMountPod(pod *k8sCore.Pod, clusterID string) error {
 		fsCache.NodeName == "" {
 		errRet := fmt.Errorf("mount pod[%s] cache stats %+v is not valid", pod.Name, fsCache)
 		log.Errorf(errRet.Error())
-		return errRet
-	}
-
-	if err := addOrUpdateFSCache(fsCache); err != nil {
-		errRet := fmt.Errorf("addOrUpdateFSCache[%+v] for pod[%s] failed: %v", *fsCache, pod.Name, err)
-		log.Errorf(errRet.Error())
-		return errRet
+		return nil, errRet
 	}
-	return nil
+	return fsCache, nil
 }
 
 func addOrUpdateFSCache(fsCache *model.FSCache) error {
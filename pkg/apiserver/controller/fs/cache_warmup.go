@@ -0,0 +1,262 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	k8sCore "k8s.io/api/core/v1"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/uuid"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/csiplugin/csiconfig"
+	locationAwareness "github.com/PaddlePaddle/PaddleFlow/pkg/fs/location-awareness"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/utils"
+	runtime "github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+)
+
+type WarmupFileSystemCacheRequest struct {
+	Username string   `json:"username"`
+	FsID     string   `json:"-"`
+	Paths    []string `json:"paths"`
+	Manifest string   `json:"manifest"`
+	Type     string   `json:"type"`
+	Threads  int      `json:"threads"`
+}
+
+type WarmupNode struct {
+	ClusterID string `json:"clusterID"`
+	NodeName  string `json:"nodename"`
+	PodName   string `json:"podName"`
+}
+
+type WarmupFileSystemCacheResponse struct {
+	WarmupID string       `json:"warmupID"`
+	Nodes    []WarmupNode `json:"nodes"`
+}
+
+type WarmupProgress struct {
+	ClusterID string `json:"clusterID"`
+	NodeName  string `json:"nodename"`
+	CacheDir  string `json:"cacheDir"`
+	UsedSize  int    `json:"usedSize"`
+	WarmupID  string `json:"warmupID"`
+	Status    string `json:"status"`
+	Total     int64  `json:"total"`
+	Done      int64  `json:"done"`
+	Failed    int64  `json:"failed"`
+	Bytes     int64  `json:"bytes"`
+	Message   string `json:"message,omitempty"`
+}
+
+type GetWarmupResponse struct {
+	FsName   string           `json:"fsName"`
+	Username string           `json:"username"`
+	Nodes    []WarmupProgress `json:"nodes"`
+}
+
+// WarmupFileSystemCache dispatches warmup task to running mount pods on nodes selected by node affinity of fs cache config.
+// cache-worker in mount pods warms up paths through fuse mount point and reports progress, which is synced to fs cache.
+func WarmupFileSystemCache(ctx *logger.RequestContext, req WarmupFileSystemCacheRequest) (WarmupFileSystemCacheResponse, error) {
+	var nodeAffinity k8sCore.NodeAffinity
+	cacheConfig, err := storage.Filesystem.GetFSCacheConfig(req.FsID)
+	if err == nil {
+		nodeAffinity = cacheConfig.NodeAffinity
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.Logging().Errorf("GetFSCacheConfig fs[%s] err:%v", req.FsID, err)
+		ctx.ErrorCode = common.FileSystemDataBaseError
+		return WarmupFileSystemCacheResponse{}, err
+	}
+
+	crm, err := getClusterRuntimeMap()
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		return WarmupFileSystemCacheResponse{}, err
+	}
+
+	task := locationAwareness.WarmupTask{
+		ID:       uuid.GenerateID(common.PrefixWarmup),
+		Paths:    req.Paths,
+		Manifest: req.Manifest,
+		Type:     req.Type,
+		Threads:  req.Threads,
+	}
+	resp := WarmupFileSystemCacheResponse{WarmupID: task.ID, Nodes: []WarmupNode{}}
+	var errDispatch error
+	for clusterID, k8sRuntime := range crm {
+		pods, err := listWarmupMountPods(k8sRuntime, req.FsID, nodeAffinity)
+		if err != nil {
+			ctx.Logging().Errorf("list warmup mount pods of fs[%s] in cluster[%s] failed: %v", req.FsID, clusterID, err)
+			errDispatch = err
+			continue
+		}
+		for i := range pods {
+			pod := &pods[i]
+			if err = dispatchWarmupTask(k8sRuntime, pod, clusterID, task); err != nil {
+				ctx.Logging().Errorf("dispatch warmup task[%s] to mount pod[%s] failed: %v", task.ID, pod.Name, err)
+				errDispatch = err
+				continue
+			}
+			resp.Nodes = append(resp.Nodes, WarmupNode{
+				ClusterID: clusterID,
+				NodeName:  pod.Spec.NodeName,
+				PodName:   pod.Name,
+			})
+		}
+	}
+
+	if len(resp.Nodes) == 0 {
+		if errDispatch != nil {
+			ctx.ErrorCode = common.InternalError
+			return resp, fmt.Errorf("fs[%s] dispatch warmup task failed: %v", req.FsID, errDispatch)
+		}
+		err = fmt.Errorf("fs[%s] has no running mount pod on nodes selected by cache config, mount it before warmup", req.FsID)
+		ctx.Logging().Errorf(err.Error())
+		ctx.ErrorCode = common.ActionNotAllowed
+		return resp, err
+	}
+	ctx.Logging().Infof("warmup task %+v dispatched to nodes %+v", task, resp.Nodes)
+	return resp, nil
+}
+
+func GetFileSystemCacheWarmup(ctx *logger.RequestContext, fsID string) (GetWarmupResponse, error) {
+	caches, err := storage.FsCache.List(fsID, "")
+	if err != nil {
+		ctx.Logging().Errorf("list fs[%s] cache err:%v", fsID, err)
+		ctx.ErrorCode = common.FileSystemDataBaseError
+		return GetWarmupResponse{}, err
+	}
+	resp := GetWarmupResponse{Nodes: make([]WarmupProgress, 0, len(caches))}
+	resp.FsName, resp.Username, _ = utils.GetFsNameAndUserNameByFsID(fsID)
+	for _, cache := range caches {
+		resp.Nodes = append(resp.Nodes, WarmupProgress{
+			ClusterID: cache.ClusterID,
+			NodeName:  cache.NodeName,
+			CacheDir:  cache.CacheDir,
+			UsedSize:  cache.UsedSize,
+			WarmupID:  cache.WarmupID,
+			Status:    cache.WarmupStatus,
+			Total:     cache.WarmupTotal,
+			Done:      cache.WarmupDone,
+			Failed:    cache.WarmupFailed,
+			Bytes:     cache.WarmupBytes,
+			Message:   cache.WarmupMsg,
+		})
+	}
+	return resp, nil
+}
+
+func listWarmupMountPods(k8sRuntime *runtime.KubeRuntime, fsID string,
+	nodeAffinity k8sCore.NodeAffinity) ([]k8sCore.Pod, error) {
+	listOptions := k8sMeta.ListOptions{
+		LabelSelector: csiconfig.PodTypeKey + "=" + csiconfig.PodMount + "," + schema.LabelKeyFsID + "=" + fsID,
+		FieldSelector: "status.phase=Running",
+	}
+	pods, err := k8sRuntime.ListPods(schema.MountPodNamespace, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list mount pods err: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, nil
+	}
+	nodes, err := k8sRuntime.ListNodes(k8sMeta.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list nodes err: %v", err)
+	}
+	nodeMap := make(map[string]*k8sCore.Node, len(nodes.Items))
+	for i := range nodes.Items {
+		nodeMap[nodes.Items[i].Name] = &nodes.Items[i]
+	}
+
+	selected := make([]k8sCore.Pod, 0)
+	for _, pod := range pods.Items {
+		node, ok := nodeMap[pod.Spec.NodeName]
+		if !ok || !locationAwareness.NodeSelected(node, nodeAffinity) {
+			log.Debugf("mount pod[%s] on node[%s] is not selected for warmup", pod.Name, pod.Spec.NodeName)
+			continue
+		}
+		selected = append(selected, pod)
+	}
+	return selected, nil
+}
+
+func dispatchWarmupTask(k8sRuntime *runtime.KubeRuntime, pod *k8sCore.Pod, clusterID string,
+	task locationAwareness.WarmupTask) error {
+	data, err := locationAwareness.AnnotationPatch(schema.AnnotationKeyWarmup, task)
+	if err != nil {
+		return err
+	}
+	if err = k8sRuntime.PatchPod(pod.Namespace, pod.Name, data); err != nil {
+		return err
+	}
+
+	fsCache, err := fsCacheFromMountPod(pod, clusterID)
+	if err != nil {
+		return err
+	}
+	fsCache.WarmupID = task.ID
+	fsCache.WarmupStatus = locationAwareness.WarmupPending
+	return updateFSCacheWarmup(fsCache)
+}
+
+// warmupFromMountPod fills fs cache with warmup status reported by cache-worker.
+// status of former tasks is ignored so that pending status of a new task is kept.
+func warmupFromMountPod(pod *k8sCore.Pod, fsCache *model.FSCache) {
+	taskValue, statusValue := pod.Annotations[schema.AnnotationKeyWarmup], pod.Annotations[schema.AnnotationKeyWarmupStatus]
+	if taskValue == "" || statusValue == "" {
+		return
+	}
+	task, err := locationAwareness.ParseWarmupTask(taskValue)
+	if err != nil {
+		log.Errorf("mount pod[%s] warmup task[%s] is not valid: %v", pod.Name, taskValue, err)
+		return
+	}
+	status, err := locationAwareness.ParseWarmupStatus(statusValue)
+	if err != nil {
+		log.Errorf("mount pod[%s] warmup status[%s] is not valid: %v", pod.Name, statusValue, err)
+		return
+	}
+	if status.ID != task.ID {
+		return
+	}
+	fsCache.WarmupID = status.ID
+	fsCache.WarmupStatus = status.Status
+	fsCache.WarmupTotal = status.Total
+	fsCache.WarmupDone = status.Done
+	fsCache.WarmupFailed = status.Failed
+	fsCache.WarmupBytes = status.Bytes
+	fsCache.WarmupMsg = status.Message
+}
+
+func updateFSCacheWarmup(fsCache *model.FSCache) error {
+	if err := addOrUpdateFSCache(fsCache); err != nil {
+		return err
+	}
+	if _, err := storage.FsCache.UpdateWarmup(fsCache); err != nil {
+		log.Errorf("update fsCache[%+v] warmup err:%v", *fsCache, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	k8sCore "k8s.io/api/core/v1"
+	k8sMeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	locationAwareness "github.com/PaddlePaddle/PaddleFlow/pkg/fs/location-awareness"
+	runtime "github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/driver"
+)
+
+func warmupMountPod(nodename string) k8sCore.Pod {
+	pod := mountPodWithCacheID(mockFSID, nodename)
+	pod.Annotations[schema.AnnotationKeyCacheDir] = mockCacheDir
+	pod.Labels[schema.LabelKeyNodeName] = nodename
+	pod.Spec.NodeName = nodename
+	return pod
+}
+
+func Test_WarmupFileSystemCache(t *testing.T) {
+	driver.InitMockDB()
+	cluster := schema.Cluster{
+		ID:   mockClusterID,
+		Name: mockClusterName,
+		Type: schema.KubernetesType,
+	}
+	mockRuntime := runtime.NewKubeRuntime(cluster).(*runtime.KubeRuntime)
+	pods := k8sCore.PodList{Items: []k8sCore.Pod{warmupMountPod(mockNodename), warmupMountPod(mockNodename2)}}
+	nodes := k8sCore.NodeList{Items: []k8sCore.Node{
+		{ObjectMeta: k8sMeta.ObjectMeta{Name: mockNodename, Labels: map[string]string{"zone": "a"}}},
+		{ObjectMeta: k8sMeta.ObjectMeta{Name: mockNodename2, Labels: map[string]string{"zone": "b"}}},
+	}}
+	patched := map[string]string{}
+
+	p1 := gomonkey.ApplyFunc(getClusterRuntimeMap, func() (map[string]*runtime.KubeRuntime, error) {
+		return map[string]*runtime.KubeRuntime{mockClusterID: mockRuntime}, nil
+	})
+	defer p1.Reset()
+	p2 := gomonkey.ApplyMethod(reflect.TypeOf(mockRuntime), "ListPods",
+		func(_ *runtime.KubeRuntime, namespace string, listOptions k8sMeta.ListOptions) (*k8sCore.PodList, error) {
+			return &pods, nil
+		})
+	defer p2.Reset()
+	p3 := gomonkey.ApplyMethod(reflect.TypeOf(mockRuntime), "ListNodes",
+		func(_ *runtime.KubeRuntime, listOptions k8sMeta.ListOptions) (*k8sCore.NodeList, error) {
+			return &nodes, nil
+		})
+	defer p3.Reset()
+	p4 := gomonkey.ApplyMethod(reflect.TypeOf(mockRuntime), "PatchPod",
+		func(_ *runtime.KubeRuntime, namespace, name string, data []byte) error {
+			patched[name] = string(data)
+			return nil
+		})
+	defer p4.Reset()
+
+	// only nodes in zone a are selected
+	cacheConfig := &model.FSCacheConfig{
+		FsID: mockFSID,
+		NodeAffinity: k8sCore.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &k8sCore.NodeSelector{
+				NodeSelectorTerms: []k8sCore.NodeSelectorTerm{{
+					MatchExpressions: []k8sCore.NodeSelectorRequirement{{
+						Key: "zone", Operator: k8sCore.NodeSelectorOpIn, Values: []string{"a"}}},
+				}},
+			},
+		},
+	}
+	assert.Nil(t, storage.Filesystem.CreateFSCacheConfig(cacheConfig))
+
+	ctx := &logger.RequestContext{UserName: mockRootName}
+	resp, err := WarmupFileSystemCache(ctx, WarmupFileSystemCacheRequest{
+		FsID:  mockFSID,
+		Paths: []string{"/data"},
+		Type:  locationAwareness.WarmupTypeData,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.Nodes))
+	assert.Equal(t, mockNodename, resp.Nodes[0].NodeName)
+	assert.Equal(t, 1, len(patched))
+	assert.Contains(t, patched[pods.Items[0].Name], resp.WarmupID)
+
+	progress, err := GetFileSystemCacheWarmup(ctx, mockFSID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(progress.Nodes))
+	assert.Equal(t, resp.WarmupID, progress.Nodes[0].WarmupID)
+	assert.Equal(t, locationAwareness.WarmupPending, progress.Nodes[0].Status)
+
+	// status of former task does not overwrite pending status
+	pod := pods.Items[0]
+	task, _ := json.Marshal(locationAwareness.WarmupTask{ID: resp.WarmupID})
+	pod.Annotations[schema.AnnotationKeyWarmup] = string(task)
+	pod.Annotations[schema.AnnotationKeyWarmupStatus] = `{"id":"warmup-former","status":"succeeded","done":10}`
+	assert.Nil(t, syncCacheFromMountPod(&pod, mockClusterID))
+	progress, err = GetFileSystemCacheWarmup(ctx, mockFSID)
+	assert.Nil(t, err)
+	assert.Equal(t, locationAwareness.WarmupPending, progress.Nodes[0].Status)
+
+	// progress reported by cache-worker is synced to fs cache
+	status, _ := json.Marshal(locationAwareness.WarmupStatus{
+		ID: resp.WarmupID, Status: locationAwareness.WarmupSucceeded, Total: 3, Done: 3, Bytes: 1024})
+	pod.Annotations[schema.AnnotationKeyWarmupStatus] = string(status)
+	assert.Nil(t, syncCacheFromMountPod(&pod, mockClusterID))
+	progress, err = GetFileSystemCacheWarmup(ctx, mockFSID)
+	assert.Nil(t, err)
+	assert.Equal(t, locationAwareness.WarmupSucceeded, progress.Nodes[0].Status)
+	assert.Equal(t, int64(3), progress.Nodes[0].Done)
+	assert.Equal(t, int64(1024), progress.Nodes[0].Bytes)
+
+	// no node selected
+	nodes.Items[0].Labels["zone"] = "b"
+	_, err = WarmupFileSystemCache(ctx, WarmupFileSystemCacheRequest{FsID: mockFSID, Paths: []string{"/data"}})
+	assert.NotNil(t, err)
+	assert.Equal(t, common.ActionNotAllowed, ctx.ErrorCode)
+}
@@ -34,17 +34,22 @@ type ListGrantResponse struct {
 	GrantList []model.Grant `json:"grantList"`
 }
 
+// CreateGrantRequest 授权给用户或用户组, 角色为空时为submitter
 type CreateGrantRequest struct {
 	UserName     string `json:"userName"`
+	GroupName    string `json:"groupName"`
 	ResourceType string `json:"resourceType"`
 	ResourceID   string `json:"resourceID"`
+	Role         string `json:"role"`
 }
 
 func (req *CreateGrantRequest) toModel() model.Grant {
 	return model.Grant{
 		UserName:     req.UserName,
+		GroupName:    req.GroupName,
 		ResourceType: req.ResourceType,
 		ResourceID:   req.ResourceID,
+		Role:         req.Role,
 	}
 }
 
@@ -75,11 +80,52 @@ func checkFs(ctx *logger.RequestContext, fsID string) error {
 	return nil
 }
 
+func checkPipeline(ctx *logger.RequestContext, pipelineID string) error {
+	_, err := storage.Pipeline.GetPipelineByID(pipelineID)
+	if err != nil {
+		ctx.ErrorCode = common.PipelineNotFound
+		return fmt.Errorf("pipeline:%s not found", pipelineID)
+	}
+	return nil
+}
+
+func checkCluster(ctx *logger.RequestContext, clusterName string) error {
+	_, err := storage.Cluster.GetClusterByName(clusterName)
+	if err != nil {
+		ctx.ErrorCode = common.ClusterNameNotFound
+		return fmt.Errorf("cluster:%s not found", clusterName)
+	}
+	return nil
+}
+
+func checkGroup(ctx *logger.RequestContext, groupName string) error {
+	_, err := storage.Auth.GetGroup(ctx, groupName)
+	if err != nil {
+		ctx.ErrorCode = common.GroupNotFound
+		return fmt.Errorf("group:%s not found", groupName)
+	}
+	return nil
+}
+
 func init() {
 	checkFuncs = make(map[string]func(ctx *logger.RequestContext, resourceID string) error)
 	checkFuncs[common.ResourceTypeQueue] = checkQueue
 	checkFuncs[common.ResourceTypeUser] = checkUser
 	checkFuncs[common.ResourceTypeFs] = checkFs
+	checkFuncs[common.ResourceTypePipeline] = checkPipeline
+	checkFuncs[common.ResourceTypeCluster] = checkCluster
+}
+
+// checkSubject 检查授权对象, 用户和用户组有且只有一个
+func checkSubject(ctx *logger.RequestContext, userName, groupName string) error {
+	if (userName == "") == (groupName == "") {
+		ctx.ErrorCode = common.GrantInvalidSubject
+		return fmt.Errorf("one of userName and groupName is required")
+	}
+	if groupName != "" {
+		return checkGroup(ctx, groupName)
+	}
+	return checkFuncs[common.ResourceTypeUser](ctx, userName)
 }
 
 type CreateGrantResponse struct {
@@ -100,6 +146,14 @@ func CreateGrant(ctx *logger.RequestContext, grantInfo CreateGrantRequest) (*Cre
 		return nil, errors.New("create grant failed")
 	}
 
+	if grantInfo.Role == "" {
+		grantInfo.Role = common.RoleSubmitter
+	}
+	if !common.IsValidRole(grantInfo.Role) {
+		ctx.ErrorCode = common.GrantInvalidRole
+		ctx.Logging().Errorf("create grant failed. role[%s] is invalid.", grantInfo.Role)
+		return nil, fmt.Errorf("role %s is invalid", grantInfo.Role)
+	}
 	// check resource type
 	checkResourceFunc, ok := checkFuncs[grantInfo.ResourceType]
 	if !ok {
@@ -112,16 +166,17 @@ func CreateGrant(ctx *logger.RequestContext, grantInfo CreateGrantRequest) (*Cre
 		ctx.Logging().Errorf("create grant failed.%v:%s not exist.", grantInfo.ResourceType, grantInfo.ResourceID)
 		return nil, err
 	}
-	// check user
-	if err := checkFuncs[common.ResourceTypeUser](ctx, grantInfo.UserName); err != nil {
-		ctx.Logging().Errorf("create grant failed.user:%v not exist.", grantInfo.UserName)
+	// check user or group
+	if err := checkSubject(ctx, grantInfo.UserName, grantInfo.GroupName); err != nil {
+		ctx.Logging().Errorf("create grant failed. error: %v", err)
 		return nil, err
 	}
 
-	// can't grant repeatedly
-	if existgrant, _ := storage.Auth.GetGrant(ctx, grantInfo.UserName, grantInfo.ResourceType, grantInfo.ResourceID); existgrant != nil {
+	// can't grant repeatedly, the grant should be deleted before granting another role
+	if existgrant, _ := storage.Auth.GetGrant(ctx, grantInfo.UserName, grantInfo.GroupName, grantInfo.ResourceType, grantInfo.ResourceID); existgrant != nil {
 		ctx.ErrorCode = common.GrantAlreadyExist
-		ctx.Logging().Errorf("create grant failed.user:[%s] already has the grant of resource[%s].", grantInfo.UserName, grantInfo.ResourceID)
+		ctx.Logging().Errorf("create grant failed.user:[%s] group:[%s] already has the grant of resource[%s].",
+			grantInfo.UserName, grantInfo.GroupName, grantInfo.ResourceID)
 		return nil, errors.New("create grant failed")
 	}
 
@@ -142,8 +197,8 @@ func CreateGrant(ctx *logger.RequestContext, grantInfo CreateGrantRequest) (*Cre
 	return response, nil
 }
 
-func DeleteGrant(ctx *logger.RequestContext, userName, resourceID, resourceType string) error {
-	ctx.Logging().Debugf("begin delete grant. userName:%v, resourceID:%v.", userName, resourceID)
+func DeleteGrant(ctx *logger.RequestContext, userName, groupName, resourceID, resourceType string) error {
+	ctx.Logging().Debugf("begin delete grant. userName:%v, groupName:%v, resourceID:%v.", userName, groupName, resourceID)
 	if !common.IsRootUser(ctx.UserName) {
 		ctx.ErrorCode = common.OnlyRootAllowed
 		ctx.Logging().Errorln("delete grant failed. admin is needed.")
@@ -166,19 +221,20 @@ func DeleteGrant(ctx *logger.RequestContext, userName, resourceID, resourceType
 		ctx.Logging().Errorf("delete grant failed.%v:%s not exist.", resourceType, resourceID)
 		return err
 	}
-	// check user
-	if err := checkFuncs[common.ResourceTypeUser](ctx, userName); err != nil {
-		ctx.Logging().Errorf("delete grant failed. user:%v not exist.", userName)
+	// check user or group
+	if err := checkSubject(ctx, userName, groupName); err != nil {
+		ctx.Logging().Errorf("delete grant failed. error: %v", err)
 		return err
 	}
 	// check if grant exist
-	if _, err := storage.Auth.GetGrant(ctx, userName, resourceType, resourceID); err != nil {
+	if _, err := storage.Auth.GetGrant(ctx, userName, groupName, resourceType, resourceID); err != nil {
 		ctx.ErrorCode = common.GrantNotFound
-		ctx.Logging().Errorf("delete grant failed. grant with userName:%v and resourceID:%v not exist.", userName, resourceID)
+		ctx.Logging().Errorf("delete grant failed. grant with userName:%v groupName:%v and resourceID:%v not exist.",
+			userName, groupName, resourceID)
 		return err
 	}
 
-	if err := storage.Auth.DeleteGrant(ctx, userName, resourceType, resourceID); err != nil {
+	if err := storage.Auth.DeleteGrant(ctx, userName, groupName, resourceType, resourceID); err != nil {
 		ctx.ErrorCode = common.GrantNotFound
 		ctx.Logging().Errorf("delete grant failed. userName:%v, resourceID:%v",
 			userName, resourceID)
@@ -187,9 +243,9 @@ func DeleteGrant(ctx *logger.RequestContext, userName, resourceID, resourceType
 	return nil
 }
 
-func ListGrant(ctx *logger.RequestContext, marker string, maxKeys int, userName string) (ListGrantResponse, error) {
+func ListGrant(ctx *logger.RequestContext, marker string, maxKeys int, userName, groupName string) (ListGrantResponse, error) {
 
-	ctx.Logging().Debugf("begin list grants. user:[%s].", userName)
+	ctx.Logging().Debugf("begin list grants. user:[%s] group:[%s].", userName, groupName)
 
 	if !common.IsRootUser(ctx.UserName) {
 		ctx.ErrorCode = common.OnlyRootAllowed
@@ -212,7 +268,7 @@ func ListGrant(ctx *logger.RequestContext, marker string, maxKeys int, userName
 		}
 	}
 
-	grantList, err := storage.Auth.ListGrant(ctx, pk, maxKeys, userName)
+	grantList, err := storage.Auth.ListGrant(ctx, pk, maxKeys, userName, groupName)
 	if err != nil {
 		ctx.Logging().Errorf("models list grant failed. err:[%s]", err.Error())
 		ctx.ErrorCode = common.InternalError
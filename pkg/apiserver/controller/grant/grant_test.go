@@ -86,7 +86,7 @@ func TestCreateGrant(t *testing.T) {
 func TestListGrant(t *testing.T) {
 	TestCreateGrant(t)
 	ctx := &logger.RequestContext{UserName: MockRootUser}
-	resp, err := ListGrant(ctx, "", 0, "")
+	resp, err := ListGrant(ctx, "", 0, "", "")
 	assert.Nil(t, err)
 	assert.NotZero(t, len(resp.GrantList))
 }
@@ -94,6 +94,78 @@ func TestListGrant(t *testing.T) {
 func TestDeleteGrant(t *testing.T) {
 	TestCreateGrant(t)
 	ctx := &logger.RequestContext{UserName: MockRootUser}
-	err := DeleteGrant(ctx, MockUserName, MockResourceID, common.ResourceTypeQueue)
+	err := DeleteGrant(ctx, MockUserName, "", MockResourceID, common.ResourceTypeQueue)
+	assert.Nil(t, err)
+}
+
+func TestCreateGrantWithRole(t *testing.T) {
+	TestCreateGrant(t)
+	ctx := &logger.RequestContext{UserName: MockRootUser}
+	userCtx := &logger.RequestContext{UserName: MockUserName}
+	// grant without role is submitter
+	assert.True(t, storage.Auth.HasPermission(userCtx, common.ResourceTypeQueue, MockResourceID, common.ActionUse))
+	assert.False(t, storage.Auth.HasPermission(userCtx, common.ResourceTypeQueue, MockResourceID, common.ActionWrite))
+
+	// invalid role
+	_, err := CreateGrant(ctx, CreateGrantRequest{
+		UserName:     MockUserName,
+		ResourceType: common.ResourceTypeCluster,
+		ResourceID:   MockClusterName,
+		Role:         "owner",
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, common.GrantInvalidRole, ctx.ErrorCode)
+
+	// user and group are both set
+	ctx = &logger.RequestContext{UserName: MockRootUser}
+	_, err = CreateGrant(ctx, CreateGrantRequest{
+		UserName:     MockUserName,
+		GroupName:    "group1",
+		ResourceType: common.ResourceTypeCluster,
+		ResourceID:   MockClusterName,
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, common.GrantInvalidSubject, ctx.ErrorCode)
+
+	// group not exist
+	ctx = &logger.RequestContext{UserName: MockRootUser}
+	_, err = CreateGrant(ctx, CreateGrantRequest{
+		GroupName:    "group1",
+		ResourceType: common.ResourceTypeCluster,
+		ResourceID:   MockClusterName,
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, common.GroupNotFound, ctx.ErrorCode)
+}
+
+func TestCreateGrantToGroup(t *testing.T) {
+	TestCreateGrant(t)
+	ctx := &logger.RequestContext{UserName: MockRootUser}
+	userCtx := &logger.RequestContext{UserName: MockUserName}
+	assert.Nil(t, storage.Auth.CreateGroup(ctx, &model.Group{Name: "group1"}))
+	assert.Nil(t, storage.Auth.AddGroupMember(ctx, &model.GroupMember{GroupName: "group1", UserName: MockUserName}))
+	assert.False(t, storage.Auth.HasPermission(userCtx, common.ResourceTypeCluster, MockClusterName, common.ActionRead))
+
+	// read-only sharing of cluster to group
+	_, err := CreateGrant(ctx, CreateGrantRequest{
+		GroupName:    "group1",
+		ResourceType: common.ResourceTypeCluster,
+		ResourceID:   MockClusterName,
+		Role:         common.RoleViewer,
+	})
+	assert.Nil(t, err)
+	assert.True(t, storage.Auth.HasPermission(userCtx, common.ResourceTypeCluster, MockClusterName, common.ActionRead))
+	assert.False(t, storage.Auth.HasPermission(userCtx, common.ResourceTypeCluster, MockClusterName, common.ActionUse))
+
+	resp, err := ListGrant(ctx, "", 0, "", "group1")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.GrantList))
+	assert.Equal(t, common.RoleViewer, resp.GrantList[0].Role)
+
+	// remove member
+	assert.Nil(t, storage.Auth.RemoveGroupMember(ctx, "group1", MockUserName))
+	assert.False(t, storage.Auth.HasPermission(userCtx, common.ResourceTypeCluster, MockClusterName, common.ActionRead))
+
+	err = DeleteGrant(ctx, "", "group1", MockClusterName, common.ResourceTypeCluster)
 	assert.Nil(t, err)
 }
@@ -0,0 +1,252 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package group
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	gormErrors "github.com/PaddlePaddle/PaddleFlow/pkg/common/errors"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+)
+
+type CreateGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type CreateGroupResponse struct {
+	Name string `json:"name"`
+}
+
+type AddGroupMemberRequest struct {
+	UserName string `json:"userName"`
+}
+
+type GetGroupResponse struct {
+	model.Group
+	Members []string `json:"members"`
+}
+
+type ListGroupResponse struct {
+	common.MarkerInfo
+	GroupList []model.Group `json:"groupList"`
+}
+
+func CreateGroup(ctx *logger.RequestContext, request CreateGroupRequest) (*CreateGroupResponse, error) {
+	ctx.Logging().Debugf("begin create group. request: %v.", request)
+	if !common.IsRootUser(ctx.UserName) {
+		ctx.ErrorCode = common.OnlyRootAllowed
+		ctx.Logging().Errorln("create group failed. root is needed.")
+		return nil, errors.New("create group failed")
+	}
+	if !schema.CheckReg(request.Name, common.RegPatternGroupName) {
+		ctx.ErrorCode = common.InvalidNamePattern
+		err := common.InvalidNamePatternError(request.Name, common.ResourceTypeGroup, common.RegPatternGroupName)
+		ctx.Logging().Errorf("create group failed. error: %v", err)
+		return nil, err
+	}
+	group := &model.Group{
+		Name:        request.Name,
+		Description: request.Description,
+	}
+	if err := storage.Auth.CreateGroup(ctx, group); err != nil {
+		ctx.Logging().Errorf("create group failed. error:%s", err.Error())
+		if gormErrors.GetErrorCode(err) == gormErrors.ErrorKeyIsDuplicated {
+			ctx.ErrorCode = common.GroupAlreadyExist
+		} else {
+			ctx.ErrorCode = common.InternalError
+		}
+		return nil, err
+	}
+	return &CreateGroupResponse{Name: group.Name}, nil
+}
+
+// DeleteGroup 删除用户组, 同时删除组成员和授予用户组的权限
+func DeleteGroup(ctx *logger.RequestContext, groupName string) error {
+	ctx.Logging().Debugf("begin delete group. name:%s.", groupName)
+	if !common.IsRootUser(ctx.UserName) {
+		ctx.ErrorCode = common.OnlyRootAllowed
+		ctx.Logging().Errorln("delete group failed. root is needed.")
+		return errors.New("delete group failed")
+	}
+	if _, err := storage.Auth.GetGroup(ctx, groupName); err != nil {
+		ctx.ErrorCode = common.GroupNotFound
+		ctx.Logging().Errorf("delete group failed. group[%s] not found.", groupName)
+		return err
+	}
+	if err := storage.Auth.DeleteGroup(ctx, groupName); err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("delete group failed. name:%s, error:%s", groupName, err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetGroup 获取用户组及其成员, 非root用户只能获取自己所在的用户组
+func GetGroup(ctx *logger.RequestContext, groupName string) (*GetGroupResponse, error) {
+	ctx.Logging().Debugf("begin get group. name:%s.", groupName)
+	group, err := storage.Auth.GetGroup(ctx, groupName)
+	if err != nil {
+		ctx.ErrorCode = common.GroupNotFound
+		ctx.Logging().Errorf("get group failed. group[%s] not found.", groupName)
+		return nil, err
+	}
+	if !common.IsRootUser(ctx.UserName) {
+		if _, err := storage.Auth.GetGroupMember(ctx, groupName, ctx.UserName); err != nil {
+			ctx.ErrorCode = common.AccessDenied
+			err = common.NoAccessError(ctx.UserName, common.ResourceTypeGroup, groupName)
+			ctx.Logging().Errorln(err.Error())
+			return nil, err
+		}
+	}
+	members, err := storage.Auth.ListGroupMember(ctx, groupName)
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("list members of group[%s] failed. error:%s", groupName, err.Error())
+		return nil, err
+	}
+	response := &GetGroupResponse{
+		Group:   group,
+		Members: make([]string, 0, len(members)),
+	}
+	for _, member := range members {
+		response.Members = append(response.Members, member.UserName)
+	}
+	return response, nil
+}
+
+// ListGroup 列出用户组, 非root用户只能列出自己所在的用户组
+func ListGroup(ctx *logger.RequestContext, marker string, maxKeys int) (ListGroupResponse, error) {
+	ctx.Logging().Debugf("begin list groups. user:[%s].", ctx.UserName)
+	listGroupResponse := ListGroupResponse{}
+	listGroupResponse.IsTruncated = false
+	listGroupResponse.GroupList = []model.Group{}
+
+	userName := ""
+	if !common.IsRootUser(ctx.UserName) {
+		userName = ctx.UserName
+	}
+
+	var pk int64
+	var err error
+	if marker != "" {
+		pk, err = common.DecryptPk(marker)
+		if err != nil {
+			ctx.Logging().Errorf("DecryptPk marker[%s] failed. err:[%s]",
+				marker, err.Error())
+			ctx.ErrorCode = common.InvalidMarker
+			return listGroupResponse, err
+		}
+	}
+
+	groupList, err := storage.Auth.ListGroup(ctx, pk, maxKeys, userName)
+	if err != nil {
+		ctx.Logging().Errorf("models list group failed. err:[%s]", err.Error())
+		ctx.ErrorCode = common.InternalError
+		return listGroupResponse, err
+	}
+
+	// get next marker
+	if len(groupList) > 0 {
+		group := groupList[len(groupList)-1]
+		if !IsLastGroupPk(ctx, group.Pk, userName) {
+			nextMarker, err := common.EncryptPk(group.Pk)
+			if err != nil {
+				ctx.Logging().Errorf("EncryptPk error. pk:[%d] error:[%s]",
+					group.Pk, err.Error())
+				ctx.ErrorCode = common.InternalError
+				return listGroupResponse, err
+			}
+			listGroupResponse.NextMarker = nextMarker
+			listGroupResponse.IsTruncated = true
+		}
+	}
+	listGroupResponse.MaxKeys = maxKeys
+	listGroupResponse.GroupList = append(listGroupResponse.GroupList, groupList...)
+	return listGroupResponse, nil
+}
+
+func IsLastGroupPk(ctx *logger.RequestContext, pk int64, userName string) bool {
+	lastGroup, err := storage.Auth.GetLastGroup(ctx, userName)
+	if err != nil {
+		ctx.Logging().Errorf("get last group failed. error:[%s]", err.Error())
+	}
+	return lastGroup.Pk == pk
+}
+
+func AddGroupMember(ctx *logger.RequestContext, groupName, userName string) error {
+	ctx.Logging().Debugf("begin add member. group:%s, user:%s.", groupName, userName)
+	if err := checkMemberRequest(ctx, groupName, userName); err != nil {
+		ctx.Logging().Errorf("add member failed. error: %v", err)
+		return err
+	}
+	if _, err := storage.Auth.GetGroupMember(ctx, groupName, userName); err == nil {
+		ctx.ErrorCode = common.GroupMemberAlreadyExist
+		ctx.Logging().Errorf("add member failed. user[%s] is already in group[%s].", userName, groupName)
+		return fmt.Errorf("user %s is already in group %s", userName, groupName)
+	}
+	if err := storage.Auth.AddGroupMember(ctx, &model.GroupMember{
+		GroupName: groupName,
+		UserName:  userName,
+	}); err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("add member failed. error:%s", err.Error())
+		return err
+	}
+	return nil
+}
+
+func RemoveGroupMember(ctx *logger.RequestContext, groupName, userName string) error {
+	ctx.Logging().Debugf("begin remove member. group:%s, user:%s.", groupName, userName)
+	if err := checkMemberRequest(ctx, groupName, userName); err != nil {
+		ctx.Logging().Errorf("remove member failed. error: %v", err)
+		return err
+	}
+	if _, err := storage.Auth.GetGroupMember(ctx, groupName, userName); err != nil {
+		ctx.ErrorCode = common.GroupMemberNotFound
+		ctx.Logging().Errorf("remove member failed. user[%s] is not in group[%s].", userName, groupName)
+		return err
+	}
+	if err := storage.Auth.RemoveGroupMember(ctx, groupName, userName); err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("remove member failed. error:%s", err.Error())
+		return err
+	}
+	return nil
+}
+
+// checkMemberRequest 检查管理组成员的请求, 仅root可以管理组成员
+func checkMemberRequest(ctx *logger.RequestContext, groupName, userName string) error {
+	if !common.IsRootUser(ctx.UserName) {
+		ctx.ErrorCode = common.OnlyRootAllowed
+		return errors.New("root is needed")
+	}
+	if _, err := storage.Auth.GetGroup(ctx, groupName); err != nil {
+		ctx.ErrorCode = common.GroupNotFound
+		return fmt.Errorf("group %s not found", groupName)
+	}
+	if _, err := storage.Auth.GetUserByName(ctx, userName); err != nil {
+		ctx.ErrorCode = common.UserNotExist
+		return fmt.Errorf("user %s not found", userName)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package group
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/driver"
+)
+
+const (
+	MockRootUser  = "root"
+	MockUserName  = "user1"
+	MockUserName2 = "user2"
+	MockGroupName = "group1"
+)
+
+func initGroup(t *testing.T) {
+	driver.InitMockDB()
+	ctx := &logger.RequestContext{UserName: MockRootUser}
+	for _, name := range []string{MockUserName, MockUserName2} {
+		err := storage.Auth.CreateUser(ctx, &model.User{
+			UserInfo: model.UserInfo{Name: name, Password: "fake"},
+		})
+		assert.Nil(t, err)
+	}
+	resp, err := CreateGroup(ctx, CreateGroupRequest{Name: MockGroupName, Description: "test"})
+	assert.Nil(t, err)
+	assert.Equal(t, MockGroupName, resp.Name)
+}
+
+func TestCreateGroup(t *testing.T) {
+	initGroup(t)
+
+	// duplicated
+	ctx := &logger.RequestContext{UserName: MockRootUser}
+	_, err := CreateGroup(ctx, CreateGroupRequest{Name: MockGroupName})
+	assert.NotNil(t, err)
+
+	// invalid name
+	ctx = &logger.RequestContext{UserName: MockRootUser}
+	_, err = CreateGroup(ctx, CreateGroupRequest{Name: "1-group"})
+	assert.NotNil(t, err)
+	assert.Equal(t, common.InvalidNamePattern, ctx.ErrorCode)
+
+	// root is needed
+	ctx = &logger.RequestContext{UserName: MockUserName}
+	_, err = CreateGroup(ctx, CreateGroupRequest{Name: "group2"})
+	assert.NotNil(t, err)
+	assert.Equal(t, common.OnlyRootAllowed, ctx.ErrorCode)
+}
+
+func TestGroupMember(t *testing.T) {
+	initGroup(t)
+	ctx := &logger.RequestContext{UserName: MockRootUser}
+	assert.Nil(t, AddGroupMember(ctx, MockGroupName, MockUserName))
+
+	// add repeatedly
+	err := AddGroupMember(ctx, MockGroupName, MockUserName)
+	assert.NotNil(t, err)
+	assert.Equal(t, common.GroupMemberAlreadyExist, ctx.ErrorCode)
+
+	// user not exist
+	ctx = &logger.RequestContext{UserName: MockRootUser}
+	err = AddGroupMember(ctx, MockGroupName, "notexist")
+	assert.NotNil(t, err)
+	assert.Equal(t, common.UserNotExist, ctx.ErrorCode)
+
+	// member can get the group
+	userCtx := &logger.RequestContext{UserName: MockUserName}
+	resp, err := GetGroup(userCtx, MockGroupName)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{MockUserName}, resp.Members)
+
+	// others can't get the group
+	userCtx = &logger.RequestContext{UserName: MockUserName2}
+	_, err = GetGroup(userCtx, MockGroupName)
+	assert.NotNil(t, err)
+	assert.Equal(t, common.AccessDenied, userCtx.ErrorCode)
+
+	// remove member
+	ctx = &logger.RequestContext{UserName: MockRootUser}
+	assert.Nil(t, RemoveGroupMember(ctx, MockGroupName, MockUserName))
+	err = RemoveGroupMember(ctx, MockGroupName, MockUserName)
+	assert.NotNil(t, err)
+	assert.Equal(t, common.GroupMemberNotFound, ctx.ErrorCode)
+}
+
+func TestListGroup(t *testing.T) {
+	initGroup(t)
+	ctx := &logger.RequestContext{UserName: MockRootUser}
+	_, err := CreateGroup(ctx, CreateGroupRequest{Name: "group2"})
+	assert.Nil(t, err)
+	assert.Nil(t, AddGroupMember(ctx, MockGroupName, MockUserName))
+
+	resp, err := ListGroup(ctx, "", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.GroupList))
+	assert.True(t, resp.IsTruncated)
+
+	resp, err = ListGroup(ctx, resp.NextMarker, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.GroupList))
+	assert.False(t, resp.IsTruncated)
+
+	// regular user only lists the groups he belongs to
+	userCtx := &logger.RequestContext{UserName: MockUserName}
+	resp, err = ListGroup(userCtx, "", 50)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.GroupList))
+	assert.Equal(t, MockGroupName, resp.GroupList[0].Name)
+}
+
+func TestDeleteGroup(t *testing.T) {
+	initGroup(t)
+	ctx := &logger.RequestContext{UserName: MockRootUser}
+	assert.Nil(t, AddGroupMember(ctx, MockGroupName, MockUserName))
+	assert.Nil(t, DeleteGroup(ctx, MockGroupName))
+
+	members, err := storage.Auth.ListGroupMember(ctx, MockGroupName)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(members))
+
+	err = DeleteGroup(ctx, MockGroupName)
+	assert.NotNil(t, err)
+	assert.Equal(t, common.GroupNotFound, ctx.ErrorCode)
+}
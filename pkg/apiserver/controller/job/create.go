@@ -227,6 +227,11 @@ func validateCommonJobInfo(ctx *logger.RequestContext, requestCommonJobInfo *Com
 		ctx.ErrorCode = common.JobInvalidField
 		return err
 	}
+	if err := validateMaxRunTime(requestCommonJobInfo.MaxRunTime); err != nil {
+		ctx.Logging().Errorf("validate max run time failed, err: %v", err)
+		ctx.ErrorCode = common.JobInvalidField
+		return err
+	}
 
 	return nil
 }
@@ -288,6 +293,25 @@ func validateJobMembers(ctx *logger.RequestContext, request *CreateJobInfo) erro
 	return nil
 }
 
+// validateElasticReplicas validate min and max replicas of elastic member, only worker of pytorch and paddle job can be elastic
+func validateElasticReplicas(member *MemberSpec, framework schema.Framework) error {
+	if member.MinReplicas == 0 && member.MaxReplicas == 0 {
+		return nil
+	}
+	if framework != schema.FrameworkPytorch && framework != schema.FrameworkPaddle {
+		return fmt.Errorf("elastic member is not supported by framework %s", framework)
+	}
+	role := schema.MemberRole(member.Role)
+	if role != schema.RoleWorker && role != schema.RolePWorker {
+		return fmt.Errorf("the role[%s] of member cannot be elastic, only worker is supported", member.Role)
+	}
+	if member.MinReplicas < 1 || member.MinReplicas > member.Replicas || member.Replicas > member.MaxReplicas {
+		return fmt.Errorf("the replicas of elastic member must satisfy 1 <= minReplicas(%d) <= replicas(%d) <= maxReplicas(%d)",
+			member.MinReplicas, member.Replicas, member.MaxReplicas)
+	}
+	return nil
+}
+
 // validateMember validate member's fields
 func validateMember(ctx *logger.RequestContext, member *MemberSpec, framework schema.Framework,
 	frameworkRoles map[schema.MemberRole]int, schedulingPolicy SchedulingPolicy) error {
@@ -305,6 +329,10 @@ func validateMember(ctx *logger.RequestContext, member *MemberSpec, framework sc
 		return err
 	}
 	frameworkRoles[memberRole] = frameworkRoles[memberRole] + member.Replicas
+	if err := validateElasticReplicas(member, framework); err != nil {
+		ctx.Logging().Errorf("Failed to check Members' elastic replicas, err: %v", err)
+		return err
+	}
 	// TODO: move more check to checkJobSpec
 	err := checkJobSpec(ctx, &member.JobSpec)
 	if err != nil {
@@ -372,6 +400,14 @@ func validateQueue(ctx *logger.RequestContext, schedulingPolicy *SchedulingPolic
 		ctx.Logging().Error(err)
 		return err
 	}
+	// default queue is open to all users, other queues require use permission
+	if queueName != config.DefaultQueueName &&
+		!storage.Auth.HasPermission(ctx, common.ResourceTypeQueue, queueName, common.ActionUse) {
+		ctx.ErrorCode = common.AccessDenied
+		err = common.NoAccessError(ctx.UserName, common.ResourceTypeQueue, queueName)
+		ctx.Logging().Errorf("Failed to check Queue: %v", err)
+		return err
+	}
 	if queue.Status != schema.StatusQueueOpen {
 		errMsg := fmt.Sprintf("queue[%s] status is %s, and only queue with open status can submit jobs", queueName, queue.Status)
 		ctx.Logging().Errorf(errMsg)
@@ -464,6 +500,13 @@ func validateFileSystem(userName string, fs *schema.FileSystem) error {
 		log.Errorf("get filesystem by userName[%s] fsName[%s] fsID[%s] failed, err: %v", userName, fsName, fsID, err)
 		return fmt.Errorf("find file system %s failed, err: %v", fsName, err)
 	}
+	// file system owned by other user can be mounted only if the user is granted to use it
+	if !common.IsRootUser(userName) && fileSystem.UserName != userName &&
+		!storage.Auth.HasPermission(&logger.RequestContext{UserName: userName}, common.ResourceTypeFs, fileSystem.ID, common.ActionUse) {
+		err = common.NoAccessError(userName, common.ResourceTypeFs, fileSystem.ID)
+		log.Errorf("validateFileSystem failed, err: %v", err)
+		return err
+	}
 	// fill back
 	fs.ID = fileSystem.ID
 	fs.Name = fileSystem.Name
@@ -494,7 +537,8 @@ func validateJobFramework(ctx *logger.RequestContext, jobType schema.JobType, fr
 	case schema.TypeDistributed:
 		switch framework {
 		case schema.FrameworkSpark, schema.FrameworkPaddle, schema.FrameworkTF,
-			schema.FrameworkPytorch, schema.FrameworkMXNet, schema.FrameworkRay, schema.FrameworkMPI:
+			schema.FrameworkPytorch, schema.FrameworkMXNet, schema.FrameworkRay, schema.FrameworkMPI,
+			schema.FrameworkXGBoost:
 			err = nil
 		default:
 			err = fmt.Errorf("invalid framework %s for distributed job", framework)
@@ -538,6 +582,10 @@ func checkMemberRole(framework schema.Framework, roles map[schema.MemberRole]int
 		if roles[schema.RoleMaster] < 1 || roles[schema.RoleWorker] < 1 {
 			err = fmt.Errorf("%s job must be set a master role and a worker role", framework)
 		}
+	case schema.FrameworkXGBoost:
+		if roles[schema.RoleMaster] != 1 || roles[schema.RoleWorker] < 1 {
+			err = fmt.Errorf("%s job must be set a master role with 1 replica and a worker role", framework)
+		}
 	case schema.FrameworkStandalone:
 		if roles[schema.RoleWorker] != 1 {
 			err = fmt.Errorf("replicas for single job must be 1")
@@ -556,7 +604,7 @@ func getFrameworkRoles(framework schema.Framework) map[schema.MemberRole]int {
 	case schema.FrameworkSpark:
 		roles[schema.RoleDriver] = 0
 		roles[schema.RoleExecutor] = 0
-	case schema.FrameworkMPI, schema.FrameworkRay:
+	case schema.FrameworkMPI, schema.FrameworkRay, schema.FrameworkXGBoost:
 		roles[schema.RoleMaster] = 0
 		roles[schema.RoleWorker] = 0
 	case schema.FrameworkStandalone:
@@ -652,6 +700,7 @@ func buildCommonInfo(conf *schema.Conf, commonJobInfo *CommonJobInfo) {
 	conf.SetPriority(schedulingPolicy.Priority)
 	conf.SetClusterID(schedulingPolicy.ClusterId)
 	conf.SetNamespace(schedulingPolicy.Namespace)
+	conf.SetMaxRunTime(commonJobInfo.MaxRunTime)
 }
 
 // newMember convert request.Member to models.member
@@ -676,10 +725,12 @@ func newMember(member MemberSpec, role schema.MemberRole) schema.Member {
 	}
 
 	return schema.Member{
-		ID:       member.ID,
-		Role:     role,
-		Replicas: member.Replicas,
-		Conf:     conf,
+		ID:          member.ID,
+		Role:        role,
+		Replicas:    member.Replicas,
+		MinReplicas: member.MinReplicas,
+		MaxReplicas: member.MaxReplicas,
+		Conf:        conf,
 	}
 }
 
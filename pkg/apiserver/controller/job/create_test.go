@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/resources"
@@ -793,3 +794,82 @@ func TestCreatePFJob(t *testing.T) {
 	}
 
 }
+
+func TestCheckMemberRole_XGBoost(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   map[schema.MemberRole]int
+		wantErr bool
+	}{
+		{
+			name:  "xgboost job with master and workers",
+			roles: map[schema.MemberRole]int{schema.RoleMaster: 1, schema.RoleWorker: 2},
+		},
+		{
+			name:    "xgboost job without worker",
+			roles:   map[schema.MemberRole]int{schema.RoleMaster: 1},
+			wantErr: true,
+		},
+		{
+			name:    "xgboost job with two masters",
+			roles:   map[schema.MemberRole]int{schema.RoleMaster: 2, schema.RoleWorker: 2},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := checkMemberRole(schema.FrameworkXGBoost, tt.roles)
+			if tt.wantErr {
+				assert.Error(t, err)
+				t.Logf("case[%s] check member role failed, err: %v", tt.name, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateQueuePermission(t *testing.T) {
+	driver.InitMockDB()
+	config.GlobalServerConfig = &config.ServerConfig{}
+	err := storage.Cluster.CreateCluster(&model.ClusterInfo{
+		Model: model.Model{
+			ID: MockClusterName,
+		},
+		Name:        MockClusterName,
+		ClusterType: schema.KubernetesType,
+	})
+	assert.NoError(t, err)
+	maxRes, err := resources.NewResourceFromMap(map[string]string{
+		resources.ResCPU:    "10",
+		resources.ResMemory: "20Gi",
+	})
+	assert.NoError(t, err)
+	for _, name := range []string{MockQueueName, "mock-queue"} {
+		err = storage.Queue.CreateQueue(&model.Queue{
+			Model:        model.Model{ID: name},
+			Name:         name,
+			Namespace:    "default",
+			MaxResources: maxRes,
+			QuotaType:    schema.TypeVolcanoCapabilityQuota,
+			ClusterId:    MockClusterName,
+			Status:       schema.StatusQueueOpen,
+		})
+		assert.NoError(t, err)
+	}
+
+	// default queue is open to users without grant
+	ctx := &logger.RequestContext{UserName: "user1"}
+	err = validateQueue(ctx, &SchedulingPolicy{Queue: MockQueueName})
+	assert.NoError(t, err)
+
+	ctx = &logger.RequestContext{UserName: "user1"}
+	err = validateQueue(ctx, &SchedulingPolicy{Queue: "mock-queue"})
+	assert.Error(t, err)
+	assert.Equal(t, common.AccessDenied, ctx.ErrorCode)
+
+	ctx = &logger.RequestContext{UserName: mockRootUser}
+	err = validateQueue(ctx, &SchedulingPolicy{Queue: "mock-queue"})
+	assert.NoError(t, err)
+}
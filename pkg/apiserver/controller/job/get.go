@@ -176,11 +176,6 @@ func GetJob(ctx *logger.RequestContext, jobID string) (*GetJobResponse, error) {
 		ctx.Logging().Errorln(err.Error())
 		return nil, common.NotFoundError(common.ResourceTypeJob, jobID)
 	}
-	if err = common.CheckPermission(ctx.UserName, job.UserName, common.ResourceTypeJob, job.ID); err != nil {
-		ctx.ErrorCode = common.ActionNotAllowed
-		ctx.Logging().Errorln(err.Error())
-		return nil, err
-	}
 
 	response, err := convertJobToResponse(job, true)
 	if err != nil {
@@ -229,6 +224,7 @@ func convertJobToResponse(job model.Job, runtimeFlag bool) (GetJobResponse, erro
 	if job.Config != nil {
 		response.Labels = job.Config.Labels
 		response.Annotations = job.Config.Annotations
+		response.MaxRunTime = job.Config.GetMaxRunTime()
 	}
 	// process runtime info && member
 	switch job.Type {
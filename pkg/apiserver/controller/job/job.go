@@ -26,6 +26,7 @@ import (
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/resources"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/queue/sortpolicy"
 	runtime "github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
@@ -88,6 +89,8 @@ type CommonJobInfo struct {
 	Annotations      map[string]string `json:"annotations"`
 	SchedulingPolicy SchedulingPolicy  `json:"schedulingPolicy"`
 	UserName         string            `json:",omitempty"`
+	// MaxRunTime is the max run time of job in seconds, job will be terminated when it runs out of time
+	MaxRunTime int64 `json:"maxRunTime,omitempty"`
 }
 
 // SchedulingPolicy indicate queueID/priority
@@ -119,6 +122,9 @@ type MemberSpec struct {
 	JobSpec       `json:",inline"`
 	Role          string `json:"role"`
 	Replicas      int    `json:"replicas"`
+	// MinReplicas and MaxReplicas make the member elastic, which is supported by worker of pytorch and paddle job
+	MinReplicas int `json:"minReplicas,omitempty"`
+	MaxReplicas int `json:"maxReplicas,omitempty"`
 }
 
 type UpdateJobRequest struct {
@@ -126,6 +132,10 @@ type UpdateJobRequest struct {
 	Priority    string            `json:"priority"`
 	Labels      map[string]string `json:"labels"`
 	Annotations map[string]string `json:"annotations"`
+	// MaxRunTime nil means not to update, and 0 means no limit
+	MaxRunTime *int64 `json:"maxRunTime,omitempty"`
+	// Replicas is the replicas of elastic member to scale, nil means not to update
+	Replicas *int `json:"replicas,omitempty"`
 }
 
 // CreateJobResponse convey response for create job
@@ -141,11 +151,6 @@ func DeleteJob(ctx *logger.RequestContext, jobID string) error {
 		log.Errorf(msg)
 		return fmt.Errorf(msg)
 	}
-	if err = common.CheckPermission(ctx.UserName, job.UserName, common.ResourceTypeJob, jobID); err != nil {
-		ctx.ErrorCode = common.ActionNotAllowed
-		ctx.Logging().Errorln(err.Error())
-		return err
-	}
 
 	// check job status before delete
 	if !schema.IsImmutableJobStatus(job.Status) {
@@ -170,11 +175,6 @@ func StopJob(ctx *logger.RequestContext, jobID string) error {
 		log.Errorf("get job %s from database failed, err: %v", jobID, err)
 		return err
 	}
-	if err = common.CheckPermission(ctx.UserName, job.UserName, common.ResourceTypeJob, jobID); err != nil {
-		ctx.ErrorCode = common.ActionNotAllowed
-		ctx.Logging().Errorln(err.Error())
-		return err
-	}
 	// check job status
 	if schema.IsImmutableJobStatus(job.Status) {
 		msg := fmt.Sprintf("job %s status is already %s, and job cannot be stopped", jobID, job.Status)
@@ -184,6 +184,8 @@ func StopJob(ctx *logger.RequestContext, jobID string) error {
 
 	if job.Status == schema.StatusJobInit {
 		err = storage.Job.UpdateJobStatus(jobID, "job is terminated.", schema.StatusJobTerminated)
+		// job may be terminated in queue before submitted to cluster
+		sortpolicy.ReleaseJob(jobID)
 	} else {
 		var runtimeSvc runtime.RuntimeService
 		runtimeSvc, err = getRuntimeByQueue(ctx, job.QueueID)
@@ -220,10 +222,29 @@ func UpdateJob(ctx *logger.RequestContext, request *UpdateJobRequest) error {
 		log.Errorf("get job %s from database failed, err: %v", job.ID, err)
 		return err
 	}
-	if err = common.CheckPermission(ctx.UserName, job.UserName, common.ResourceTypeJob, request.JobID); err != nil {
-		ctx.ErrorCode = common.ActionNotAllowed
-		ctx.Logging().Errorln(err.Error())
-		return err
+
+	if request.MaxRunTime != nil {
+		if err = validateMaxRunTime(*request.MaxRunTime); err != nil {
+			ctx.ErrorCode = common.JobInvalidField
+			log.Errorln(err)
+			return err
+		}
+		if schema.IsImmutableJobStatus(job.Status) {
+			ctx.ErrorCode = common.ActionNotAllowed
+			err = fmt.Errorf("the status of job %s is %s, job max run time cannot be updated", job.ID, job.Status)
+			log.Errorln(err)
+			return err
+		}
+	}
+
+	elasticIndex := -1
+	if request.Replicas != nil {
+		elasticIndex, err = validateScaleReplicas(&job, *request.Replicas)
+		if err != nil {
+			ctx.ErrorCode = common.ActionNotAllowed
+			log.Errorln(err)
+			return err
+		}
 	}
 
 	// check job status when update job on cluster
@@ -237,13 +258,18 @@ func UpdateJob(ctx *logger.RequestContext, request *UpdateJobRequest) error {
 			return err
 		}
 		needUpdateCluster = job.Status == schema.StatusJobPending
-	} else {
+	} else if request.Labels != nil || request.Annotations != nil {
 		// need to update job labels or annotations
 		if job.Status == schema.StatusJobPending || job.Status == schema.StatusJobRunning {
 			needUpdateCluster = true
 		}
 	}
 
+	if request.Replicas != nil && (job.Status == schema.StatusJobPending || job.Status == schema.StatusJobRunning) {
+		// scale elastic job on cluster
+		needUpdateCluster = true
+	}
+
 	if needUpdateCluster {
 		// update job on cluster
 		err = updateRuntimeJob(ctx, &job, request)
@@ -264,15 +290,51 @@ func UpdateJob(ctx *logger.RequestContext, request *UpdateJobRequest) error {
 	for key, value := range request.Annotations {
 		job.Config.SetAnnotations(key, value)
 	}
+	if request.MaxRunTime != nil {
+		job.Config.SetMaxRunTime(*request.MaxRunTime)
+	}
 
 	err = storage.Job.UpdateJobConfig(job.ID, job.Config)
 	if err != nil {
 		log.Errorf("update job %s on database failed, err: %v", job.ID, err)
 		ctx.ErrorCode = common.DBUpdateFailed
+		return err
+	}
+	if elasticIndex >= 0 {
+		job.Members[elasticIndex].Replicas = *request.Replicas
+		if err = storage.Job.UpdateJobMembers(job.ID, job.Members); err != nil {
+			log.Errorf("update members of job %s on database failed, err: %v", job.ID, err)
+			ctx.ErrorCode = common.DBUpdateFailed
+		}
 	}
 	return err
 }
 
+// validateScaleReplicas check whether job can be scaled to replicas, and return the index of elastic member
+func validateScaleReplicas(job *model.Job, replicas int) (int, error) {
+	if schema.IsImmutableJobStatus(job.Status) {
+		return -1, fmt.Errorf("the status of job %s is %s, job replicas cannot be updated", job.ID, job.Status)
+	}
+	for index, member := range job.Members {
+		if !member.IsElastic() {
+			continue
+		}
+		if replicas < member.GetMinReplicas() || replicas > member.MaxReplicas {
+			return -1, fmt.Errorf("replicas %d of job %s is out of range [%d, %d]", replicas, job.ID,
+				member.GetMinReplicas(), member.MaxReplicas)
+		}
+		return index, nil
+	}
+	return -1, fmt.Errorf("job %s has no elastic member, and job replicas cannot be updated", job.ID)
+}
+
+func validateMaxRunTime(maxRunTime int64) error {
+	if maxRunTime < 0 {
+		return fmt.Errorf("maxRunTime %d is invalid, it must be no less than 0", maxRunTime)
+	}
+	return nil
+}
+
 func updateRuntimeJob(ctx *logger.RequestContext, job *model.Job, request *UpdateJobRequest) error {
 	// update labels and annotations
 	runtimeSvc, err := getRuntimeByQueue(ctx, job.QueueID)
@@ -294,6 +356,13 @@ func updateRuntimeJob(ctx *logger.RequestContext, job *model.Job, request *Updat
 	if request.Priority != "" {
 		pfjob.UpdateJobPriority(request.Priority)
 	}
+	if request.Replicas != nil {
+		for _, task := range pfjob.Tasks {
+			if task.IsElastic() {
+				pfjob.UpdateReplicas(task.Role, *request.Replicas)
+			}
+		}
+	}
 	return runtimeSvc.UpdateJob(pfjob)
 }
 
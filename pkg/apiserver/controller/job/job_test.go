@@ -76,3 +76,76 @@ func TestDeleteJob(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateScaleReplicas(t *testing.T) {
+	elasticMember := schema.Member{
+		Replicas:    2,
+		MinReplicas: 1,
+		MaxReplicas: 4,
+		Role:        schema.RoleWorker,
+	}
+	tests := []struct {
+		name      string
+		job       *model.Job
+		replicas  int
+		wantIndex int
+		wantErr   bool
+	}{
+		{
+			name: "scale elastic worker",
+			job: &model.Job{
+				ID:      "job-elastic-1",
+				Status:  schema.StatusJobRunning,
+				Members: []schema.Member{{Replicas: 1, Role: schema.RoleMaster}, elasticMember},
+			},
+			replicas:  3,
+			wantIndex: 1,
+		},
+		{
+			name: "replicas out of range",
+			job: &model.Job{
+				ID:      "job-elastic-2",
+				Status:  schema.StatusJobRunning,
+				Members: []schema.Member{elasticMember},
+			},
+			replicas:  5,
+			wantIndex: -1,
+			wantErr:   true,
+		},
+		{
+			name: "job is not elastic",
+			job: &model.Job{
+				ID:      "job-elastic-3",
+				Status:  schema.StatusJobPending,
+				Members: []schema.Member{{Replicas: 2, Role: schema.RoleWorker}},
+			},
+			replicas:  3,
+			wantIndex: -1,
+			wantErr:   true,
+		},
+		{
+			name: "job is finished",
+			job: &model.Job{
+				ID:      "job-elastic-4",
+				Status:  schema.StatusJobSucceeded,
+				Members: []schema.Member{elasticMember},
+			},
+			replicas:  3,
+			wantIndex: -1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, err := validateScaleReplicas(tt.job, tt.replicas)
+			if tt.wantErr {
+				assert.Error(t, err)
+				t.Logf("case[%s] validate scale replicas failed, err: %v", tt.name, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantIndex, index)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+)
+
+// ResubmitJobRequest convey request for resubmit a finished job, all fields are optional overrides
+type ResubmitJobRequest struct {
+	JobID    string            `json:"-"`
+	Queue    string            `json:"queue,omitempty"`
+	Priority string            `json:"priority,omitempty"`
+	Flavour  *schema.Flavour   `json:"flavour,omitempty"`
+	Env      map[string]string `json:"env,omitempty"`
+}
+
+// ResubmitJob create a new job with the config and members of a finished job
+func ResubmitJob(ctx *logger.RequestContext, request *ResubmitJobRequest) (*CreateJobResponse, error) {
+	job, err := storage.Job.GetJobByID(request.JobID)
+	if err != nil {
+		ctx.ErrorCode = common.JobNotFound
+		msg := fmt.Sprintf("get job %s failed, err: %v", request.JobID, err)
+		log.Errorf(msg)
+		return nil, fmt.Errorf(msg)
+	}
+	if !schema.IsImmutableJobStatus(job.Status) {
+		ctx.ErrorCode = common.ActionNotAllowed
+		msg := fmt.Sprintf("job %s status is %s, only finished job can be resubmitted", job.ID, job.Status)
+		log.Errorf(msg)
+		return nil, fmt.Errorf(msg)
+	}
+	if job.Type == string(schema.TypeWorkflow) || job.Config == nil {
+		ctx.ErrorCode = common.ActionNotAllowed
+		msg := fmt.Sprintf("job %s with type %s cannot be resubmitted", job.ID, job.Type)
+		log.Errorf(msg)
+		return nil, fmt.Errorf(msg)
+	}
+
+	jobInfo, err := buildResubmitJobInfo(&job, request)
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("build resubmit request for job %s failed, err: %v", job.ID, err)
+		return nil, err
+	}
+	response, err := CreatePFJob(ctx, jobInfo)
+	if err != nil {
+		if ctx.ErrorCode == "" {
+			ctx.ErrorCode = common.JobCreateFailed
+		}
+		return nil, err
+	}
+	ctx.Logging().Infof("job %s is resubmitted as job %s", job.ID, response.ID)
+	return response, nil
+}
+
+// buildResubmitJobInfo rebuild create job request from the stored job, and apply the overrides in request
+func buildResubmitJobInfo(job *model.Job, request *ResubmitJobRequest) (*CreateJobInfo, error) {
+	conf := job.Config
+	commonJobInfo := CommonJobInfo{
+		Name:        job.Name,
+		Labels:      copyStringMap(conf.Labels),
+		Annotations: copyStringMap(conf.Annotations),
+		SchedulingPolicy: SchedulingPolicy{
+			Queue:    conf.GetQueueName(),
+			Priority: conf.GetPriority(),
+		},
+		MaxRunTime: conf.GetMaxRunTime(),
+	}
+	if request.Queue != "" {
+		commonJobInfo.SchedulingPolicy.Queue = request.Queue
+	}
+	if request.Priority != "" {
+		commonJobInfo.SchedulingPolicy.Priority = request.Priority
+	}
+	// record the lineage to the original job
+	commonJobInfo.Labels[schema.JobResubmitFromLabel] = job.ID
+
+	jobInfo := &CreateJobInfo{
+		CommonJobInfo: commonJobInfo,
+		Framework:     job.Framework,
+		Type:          schema.JobType(job.Type),
+		Mode:          conf.GetJobMode(),
+	}
+	if job.ExtensionTemplate != "" {
+		extensionTemplate, err := parseExtensionTemplate(job.ExtensionTemplate)
+		if err != nil {
+			log.Errorf("parse extension template of job %s failed, err: %v", job.ID, err)
+			return nil, err
+		}
+		jobInfo.ExtensionTemplate = extensionTemplate
+	}
+
+	members := job.Members
+	if len(members) == 0 && jobInfo.Type == schema.TypeSingle {
+		// single job created without members, build member from main conf
+		members = []schema.Member{
+			{
+				Role:     schema.RoleWorker,
+				Replicas: 1,
+				Conf:     *conf,
+			},
+		}
+	}
+	for _, member := range members {
+		memberSpec := MemberSpec{
+			CommonJobInfo: CommonJobInfo{
+				Name:        member.Name,
+				Labels:      copyStringMap(member.Labels),
+				Annotations: copyStringMap(member.Annotations),
+				SchedulingPolicy: SchedulingPolicy{
+					Priority: member.Priority,
+				},
+			},
+			JobSpec: JobSpec{
+				Flavour:          member.Flavour,
+				FileSystem:       member.FileSystem,
+				ExtraFileSystems: member.ExtraFileSystem,
+				Image:            member.Image,
+				Env:              copyStringMap(member.Env),
+				Command:          member.Command,
+				Args:             member.Args,
+				Port:             member.Port,
+			},
+			Role:        string(member.Role),
+			Replicas:    member.Replicas,
+			MinReplicas: member.MinReplicas,
+			MaxReplicas: member.MaxReplicas,
+		}
+		if request.Priority != "" {
+			memberSpec.SchedulingPolicy.Priority = request.Priority
+		}
+		if request.Flavour != nil {
+			memberSpec.Flavour = *request.Flavour
+		}
+		for key, value := range request.Env {
+			memberSpec.Env[key] = value
+		}
+		jobInfo.Members = append(jobInfo.Members, memberSpec)
+	}
+	return jobInfo, nil
+}
+
+// parseExtensionTemplate convert yaml extension template of job to map
+func parseExtensionTemplate(extensionTemplate string) (map[string]interface{}, error) {
+	templateJSON, err := yaml.YAMLToJSON([]byte(extensionTemplate))
+	if err != nil {
+		return nil, err
+	}
+	template := make(map[string]interface{})
+	if err = json.Unmarshal(templateJSON, &template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+	return result
+}
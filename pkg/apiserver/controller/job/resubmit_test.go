@@ -0,0 +1,167 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/driver"
+)
+
+func TestBuildResubmitJobInfo(t *testing.T) {
+	conf := &schema.Conf{
+		Name:      "dis-job",
+		QueueName: MockQueueName,
+		Priority:  schema.EnvJobNormalPriority,
+		Labels:    map[string]string{"app": "test"},
+		Env: map[string]string{
+			schema.EnvJobMode: schema.EnvJobModePS,
+		},
+		MaxRunTime: 600,
+	}
+	job := &model.Job{
+		ID:        "job-origin",
+		Name:      "dis-job",
+		Type:      string(schema.TypeDistributed),
+		Framework: schema.FrameworkPaddle,
+		Status:    schema.StatusJobFailed,
+		Config:    conf,
+		Members: []schema.Member{
+			{
+				Role:     schema.RolePServer,
+				Replicas: 1,
+				Conf: schema.Conf{
+					Image:   "paddle:2.0",
+					Command: "sleep 10",
+					Flavour: schema.Flavour{Name: "flavour1"},
+					Env:     map[string]string{"a": "1"},
+				},
+			},
+			{
+				Role:     schema.RolePWorker,
+				Replicas: 2,
+				Conf: schema.Conf{
+					Image:   "paddle:2.0",
+					Command: "sleep 20",
+					Flavour: schema.Flavour{Name: "flavour1"},
+				},
+			},
+		},
+		ExtensionTemplate: "apiVersion: batch.paddlepaddle.org/v1\nkind: PaddleJob\n",
+	}
+
+	testCases := []struct {
+		name    string
+		request *ResubmitJobRequest
+		check   func(t *testing.T, info *CreateJobInfo)
+	}{
+		{
+			name:    "resubmit without overrides",
+			request: &ResubmitJobRequest{JobID: job.ID},
+			check: func(t *testing.T, info *CreateJobInfo) {
+				assert.Equal(t, MockQueueName, info.SchedulingPolicy.Queue)
+				assert.Equal(t, schema.EnvJobNormalPriority, info.SchedulingPolicy.Priority)
+				assert.Equal(t, int64(600), info.MaxRunTime)
+				assert.Equal(t, schema.EnvJobModePS, info.Mode)
+				assert.Equal(t, "PaddleJob", info.ExtensionTemplate["kind"])
+				assert.Equal(t, 2, len(info.Members))
+				assert.Equal(t, "flavour1", info.Members[0].Flavour.Name)
+				assert.Equal(t, 2, info.Members[1].Replicas)
+			},
+		},
+		{
+			name: "resubmit with overrides",
+			request: &ResubmitJobRequest{
+				JobID:    job.ID,
+				Queue:    "other-queue",
+				Priority: schema.EnvJobHighPriority,
+				Flavour:  &schema.Flavour{Name: "flavour2"},
+				Env:      map[string]string{"b": "2"},
+			},
+			check: func(t *testing.T, info *CreateJobInfo) {
+				assert.Equal(t, "other-queue", info.SchedulingPolicy.Queue)
+				assert.Equal(t, schema.EnvJobHighPriority, info.SchedulingPolicy.Priority)
+				for _, member := range info.Members {
+					assert.Equal(t, "flavour2", member.Flavour.Name)
+					assert.Equal(t, schema.EnvJobHighPriority, member.SchedulingPolicy.Priority)
+					assert.Equal(t, "2", member.Env["b"])
+				}
+				assert.Equal(t, "1", info.Members[0].Env["a"])
+				// original job is not changed
+				assert.Equal(t, 1, len(job.Members[0].Env))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := buildResubmitJobInfo(job, tc.request)
+			assert.NoError(t, err)
+			assert.Equal(t, job.ID, info.Labels[schema.JobResubmitFromLabel])
+			assert.Equal(t, "test", info.Labels["app"])
+			_, found := conf.Labels[schema.JobResubmitFromLabel]
+			assert.False(t, found)
+			tc.check(t, info)
+		})
+	}
+}
+
+func TestResubmitJob(t *testing.T) {
+	driver.InitMockDB()
+	runningJob := &model.Job{
+		ID:       "job-running",
+		UserName: mockRootUser,
+		Type:     string(schema.TypeSingle),
+		Status:   schema.StatusJobRunning,
+		Config:   &schema.Conf{},
+	}
+	err := storage.Job.CreateJob(runningJob)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		jobID     string
+		errorCode string
+	}{
+		{
+			name:      "job not found",
+			jobID:     "job-not-exist",
+			errorCode: common.JobNotFound,
+		},
+		{
+			name:      "job is not finished",
+			jobID:     runningJob.ID,
+			errorCode: common.ActionNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &logger.RequestContext{UserName: mockRootUser}
+			_, err := ResubmitJob(ctx, &ResubmitJobRequest{JobID: tc.jobID})
+			assert.Error(t, err)
+			assert.Equal(t, tc.errorCode, ctx.ErrorCode)
+		})
+	}
+}
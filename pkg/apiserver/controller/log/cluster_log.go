@@ -38,7 +38,7 @@ func GetPFJobLogs(ctx *logger.RequestContext, request GetMixedLogRequest) (schem
 	ctx.Logging().Debugf("Get k8s logs by request: %v", request)
 	switch schema.Framework(request.Framework) {
 	case schema.FrameworkStandalone, schema.FrameworkSpark, schema.FrameworkPaddle, schema.FrameworkTF,
-		schema.FrameworkPytorch, schema.FrameworkMXNet, schema.FrameworkRay:
+		schema.FrameworkPytorch, schema.FrameworkMXNet, schema.FrameworkXGBoost, schema.FrameworkRay:
 		// todo call runtimeSvc.GetJobLog()
 		ctx.Logging().Warnf("todo")
 	default:
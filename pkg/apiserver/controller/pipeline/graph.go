@@ -0,0 +1,480 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/models"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+)
+
+const (
+	GraphFormatJson    = "json"
+	GraphFormatDot     = "dot"
+	GraphFormatMermaid = "mermaid"
+
+	GraphNodeTypeStep = "step"
+	GraphNodeTypeDag  = "dag"
+
+	graphTimeFormat = "2006-01-02 15:04:05"
+)
+
+// RunGraph 为 run 的依赖图，节点由 WorkflowSource 以及 runtime 中的 DagView/JobView 生成
+type RunGraph struct {
+	RunID        string       `json:"runID"`
+	Status       string       `json:"status"`
+	Nodes        []*GraphNode `json:"nodes"`
+	Edges        []GraphEdge  `json:"edges"`
+	CriticalPath []string     `json:"criticalPath"`
+}
+
+type GraphNode struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Type        string           `json:"type"`
+	ParentID    string           `json:"parentID"`
+	LoopSeq     int              `json:"loopSeq"`
+	RuntimeID   string           `json:"runtimeID"` // step 节点为 jobID, dag 节点为 dagID
+	Status      schema.JobStatus `json:"status"`
+	StartTime   string           `json:"startTime"`
+	EndTime     string           `json:"endTime"`
+	Duration    int64            `json:"duration"` // 单位为秒
+	PostProcess bool             `json:"postProcess"`
+}
+
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func IsValidGraphFormat(format string) bool {
+	switch format {
+	case GraphFormatJson, GraphFormatDot, GraphFormatMermaid:
+		return true
+	default:
+		return false
+	}
+}
+
+func GetRunGraph(ctx *logger.RequestContext, runID string) (*RunGraph, error) {
+	ctx.Logging().Debugf("begin get graph of run[%s]", runID)
+	run, err := GetRunByID(ctx.Logging(), runID)
+	if err != nil {
+		ctx.Logging().Errorf("get graph of run[%s] failed when getting run. error: %v", runID, err)
+		return nil, err
+	}
+
+	graph, err := buildRunGraph(run, time.Now())
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("build graph of run[%s] failed. error: %v", runID, err)
+		return nil, err
+	}
+	return graph, nil
+}
+
+type runGraphBuilder struct {
+	run   models.Run
+	now   time.Time
+	graph *RunGraph
+	nodes map[string]*GraphNode
+}
+
+func buildRunGraph(run models.Run, now time.Time) (*RunGraph, error) {
+	builder := runGraphBuilder{
+		run: run,
+		now: now,
+		graph: &RunGraph{
+			RunID:        run.ID,
+			Status:       run.Status,
+			Nodes:        []*GraphNode{},
+			Edges:        []GraphEdge{},
+			CriticalPath: []string{},
+		},
+		nodes: map[string]*GraphNode{},
+	}
+
+	// 去掉最外层的DagView，使结构与WorkflowSource.EntryPoints.EntryPoints对齐
+	runtimeView := run.RemoveOuterDagView(run.Runtime)
+	if err := builder.addComponents(run.WorkflowSource.EntryPoints.EntryPoints, runtimeView, ""); err != nil {
+		return nil, err
+	}
+	builder.addPostProcess()
+	builder.graph.CriticalPath = builder.criticalPath("")
+	return builder.graph, nil
+}
+
+// addComponents 为同一层级的节点生成图中的节点，并根据 deps 生成边，dag 节点会递归处理其子节点
+func (b *runGraphBuilder) addComponents(components map[string]schema.Component,
+	views map[string][]schema.ComponentView, parentID string) error {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	compNodes := map[string][]string{}
+	for _, name := range names {
+		comp, err := b.resolveReference(components[name])
+		if err != nil {
+			return err
+		}
+
+		compViews := append([]schema.ComponentView{}, views[name]...)
+		sort.SliceStable(compViews, func(i, j int) bool {
+			return compViews[i].GetSeq() < compViews[j].GetSeq()
+		})
+		isLoop := comp.GetLoopArgument() != nil || len(compViews) > 1
+
+		if len(compViews) == 0 {
+			// 节点尚未运行，只根据 WorkflowSource 生成节点
+			node := b.newNode(name, parentID, comp, nil, isLoop)
+			compNodes[name] = append(compNodes[name], node.ID)
+			if dag, ok := comp.(*schema.WorkflowSourceDag); ok {
+				if err := b.addComponents(dag.EntryPoints, nil, node.ID); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// loop 节点每一次展开都对应一个节点
+		for _, view := range compViews {
+			node := b.newNode(name, parentID, comp, view, isLoop)
+			compNodes[name] = append(compNodes[name], node.ID)
+			if dagView, ok := view.(*schema.DagView); ok {
+				dag, ok := comp.(*schema.WorkflowSourceDag)
+				if !ok {
+					return fmt.Errorf("runtime view of component[%s] is dag, but it is not dag in WorkflowSource", node.ID)
+				}
+				if err := b.addComponents(dag.EntryPoints, dagView.EntryPoints, node.ID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		for _, dep := range components[name].GetDeps() {
+			for _, from := range compNodes[dep] {
+				for _, to := range compNodes[name] {
+					b.graph.Edges = append(b.graph.Edges, GraphEdge{From: from, To: to})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolveReference 如果节点为 reference 节点，则从 Components 中找到其真正引用的节点
+func (b *runGraphBuilder) resolveReference(comp schema.Component) (schema.Component, error) {
+	for {
+		step, ok := comp.(*schema.WorkflowSourceStep)
+		if !ok || step.Reference.Component == "" {
+			return comp, nil
+		}
+		refComp, ok := b.run.WorkflowSource.Components[step.Reference.Component]
+		if !ok {
+			return nil, fmt.Errorf("reference component[%s] is not exist", step.Reference.Component)
+		}
+		comp = refComp
+	}
+}
+
+func (b *runGraphBuilder) newNode(name, parentID string, comp schema.Component,
+	view schema.ComponentView, isLoop bool) *GraphNode {
+	node := &GraphNode{
+		Name:     name,
+		Type:     GraphNodeTypeStep,
+		ParentID: parentID,
+	}
+	if _, ok := comp.(*schema.WorkflowSourceDag); ok {
+		node.Type = GraphNodeTypeDag
+	}
+
+	if view != nil {
+		node.LoopSeq = view.GetSeq()
+		node.Status = view.GetStatus()
+		node.StartTime = view.GetStartTime()
+		node.EndTime = view.GetEndTime()
+		node.Duration = b.duration(node.StartTime, node.EndTime, node.Status)
+		switch v := view.(type) {
+		case *schema.JobView:
+			node.RuntimeID = v.JobID
+		case *schema.DagView:
+			node.RuntimeID = v.DagID
+		}
+	}
+
+	node.ID = name
+	if parentID != "" {
+		node.ID = parentID + "." + name
+	}
+	if isLoop && view != nil {
+		node.ID = fmt.Sprintf("%s[%d]", node.ID, node.LoopSeq)
+	}
+
+	b.graph.Nodes = append(b.graph.Nodes, node)
+	b.nodes[node.ID] = node
+	return node
+}
+
+func (b *runGraphBuilder) addPostProcess() {
+	names := make([]string, 0, len(b.run.WorkflowSource.PostProcess))
+	for name := range b.run.WorkflowSource.PostProcess {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var view schema.ComponentView
+		if jobView, ok := b.run.PostProcess[name]; ok && jobView != nil {
+			view = jobView
+		}
+		node := b.newNode(name, "", b.run.WorkflowSource.PostProcess[name], view, false)
+		node.PostProcess = true
+	}
+}
+
+// duration 计算节点的运行时长，运行中的节点计算到当前时间
+func (b *runGraphBuilder) duration(startTime, endTime string, status schema.JobStatus) int64 {
+	if startTime == "" {
+		return 0
+	}
+	start, err := time.ParseInLocation(graphTimeFormat, startTime, time.Local)
+	if err != nil {
+		return 0
+	}
+
+	end := b.now
+	if endTime != "" {
+		end, err = time.ParseInLocation(graphTimeFormat, endTime, time.Local)
+		if err != nil {
+			return 0
+		}
+	} else if status != schema.StatusJobRunning {
+		return 0
+	}
+
+	if end.Before(start) {
+		return 0
+	}
+	return int64(end.Sub(start).Seconds())
+}
+
+// criticalPath 计算同一层级节点中运行时长之和最长的路径，路径上的 dag 节点会展开为其内部的关键路径
+func (b *runGraphBuilder) criticalPath(parentID string) []string {
+	siblings := []string{}
+	for _, node := range b.graph.Nodes {
+		if node.ParentID == parentID && !node.PostProcess {
+			siblings = append(siblings, node.ID)
+		}
+	}
+
+	successors := map[string][]string{}
+	for _, edge := range b.graph.Edges {
+		if b.nodes[edge.From].ParentID == parentID {
+			successors[edge.From] = append(successors[edge.From], edge.To)
+		}
+	}
+
+	// 由于依赖关系为 DAG，可以通过带缓存的深度优先遍历计算以每个节点为起点的最长路径
+	longest := map[string]int64{}
+	next := map[string]string{}
+	var visit func(id string) int64
+	visit = func(id string) int64 {
+		if value, ok := longest[id]; ok {
+			return value
+		}
+		best, bestNext := int64(0), ""
+		for _, succ := range successors[id] {
+			if value := visit(succ); bestNext == "" || value > best {
+				best, bestNext = value, succ
+			}
+		}
+		longest[id] = b.nodes[id].Duration + best
+		next[id] = bestNext
+		return longest[id]
+	}
+
+	start := ""
+	for _, id := range siblings {
+		if value := visit(id); start == "" || value > longest[start] {
+			start = id
+		}
+	}
+
+	path := []string{}
+	for id := start; id != ""; id = next[id] {
+		path = append(path, id)
+		if b.nodes[id].Type == GraphNodeTypeDag {
+			path = append(path, b.criticalPath(id)...)
+		}
+	}
+	return path
+}
+
+func (g *RunGraph) String(format string) (string, error) {
+	switch format {
+	case GraphFormatDot:
+		return g.ToDot(), nil
+	case GraphFormatMermaid:
+		return g.ToMermaid(), nil
+	default:
+		return "", fmt.Errorf("graph format[%s] is not supported, only [%s] and [%s] can be rendered as text",
+			format, GraphFormatDot, GraphFormatMermaid)
+	}
+}
+
+// ToDot 将依赖图转换为 graphviz 的 DOT 格式，dag 节点的子节点放在以该 dag 命名的 cluster 中
+func (g *RunGraph) ToDot() string {
+	children := g.children()
+	critical := g.criticalEdges()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %s {\n", dotQuote(g.RunID))
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	var writeNodes func(parentID string, indent string)
+	writeNodes = func(parentID string, indent string) {
+		for _, node := range children[parentID] {
+			if node.Type == GraphNodeTypeDag && len(children[node.ID]) > 0 {
+				fmt.Fprintf(&sb, "%ssubgraph %s {\n", indent, dotQuote("cluster_"+node.ID))
+				fmt.Fprintf(&sb, "%s  label=%s;\n", indent, dotQuote(node.ID))
+				fmt.Fprintf(&sb, "%s  %s [label=%s, shape=folder];\n", indent, dotQuote(node.ID), dotQuote(node.label("\n")))
+				writeNodes(node.ID, indent+"  ")
+				fmt.Fprintf(&sb, "%s}\n", indent)
+				continue
+			}
+			attrs := ""
+			if node.Type == GraphNodeTypeDag {
+				attrs = ", shape=folder"
+			} else if node.PostProcess {
+				attrs = ", style=dashed"
+			}
+			fmt.Fprintf(&sb, "%s%s [label=%s%s];\n", indent, dotQuote(node.ID), dotQuote(node.label("\n")), attrs)
+		}
+	}
+	writeNodes("", "  ")
+
+	for _, edge := range g.Edges {
+		attrs := ""
+		if critical[edge] {
+			attrs = " [color=red, penwidth=2]"
+		}
+		fmt.Fprintf(&sb, "  %s -> %s%s;\n", dotQuote(edge.From), dotQuote(edge.To), attrs)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// ToMermaid 将依赖图转换为 mermaid 的 flowchart 格式，dag 节点转换为 subgraph
+func (g *RunGraph) ToMermaid() string {
+	children := g.children()
+	critical := g.criticalEdges()
+
+	// mermaid 的节点 ID 不支持特殊字符，因此按照节点顺序重新编号
+	ids := map[string]string{}
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+
+	var writeNodes func(parentID string, indent string)
+	writeNodes = func(parentID string, indent string) {
+		for _, node := range children[parentID] {
+			if node.Type == GraphNodeTypeDag && len(children[node.ID]) > 0 {
+				fmt.Fprintf(&sb, "%ssubgraph %s [%s]\n", indent, ids[node.ID], mermaidQuote(node.label("<br/>")))
+				writeNodes(node.ID, indent+"  ")
+				fmt.Fprintf(&sb, "%send\n", indent)
+				continue
+			}
+			fmt.Fprintf(&sb, "%s%s[%s]\n", indent, ids[node.ID], mermaidQuote(node.label("<br/>")))
+		}
+	}
+	writeNodes("", "  ")
+
+	criticalIndexes := []string{}
+	for i, edge := range g.Edges {
+		fmt.Fprintf(&sb, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+		if critical[edge] {
+			criticalIndexes = append(criticalIndexes, fmt.Sprintf("%d", i))
+		}
+	}
+	if len(criticalIndexes) > 0 {
+		fmt.Fprintf(&sb, "  linkStyle %s stroke:red,stroke-width:2px\n", strings.Join(criticalIndexes, ","))
+	}
+	return sb.String()
+}
+
+func (g *RunGraph) children() map[string][]*GraphNode {
+	children := map[string][]*GraphNode{}
+	for _, node := range g.Nodes {
+		children[node.ParentID] = append(children[node.ParentID], node)
+	}
+	return children
+}
+
+// criticalEdges 返回关键路径上同一层级相邻节点之间的边
+func (g *RunGraph) criticalEdges() map[GraphEdge]bool {
+	parents := map[string]string{}
+	for _, node := range g.Nodes {
+		parents[node.ID] = node.ParentID
+	}
+
+	res := map[GraphEdge]bool{}
+	last := map[string]string{}
+	for _, id := range g.CriticalPath {
+		parentID := parents[id]
+		if prev, ok := last[parentID]; ok {
+			res[GraphEdge{From: prev, To: id}] = true
+		}
+		last[parentID] = id
+	}
+	return res
+}
+
+func (n *GraphNode) label(sep string) string {
+	parts := []string{n.Name}
+	if n.Status != "" {
+		parts = append(parts, string(n.Status))
+	}
+	if n.StartTime != "" {
+		parts = append(parts, (time.Duration(n.Duration) * time.Second).String())
+	}
+	return strings.Join(parts, sep)
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
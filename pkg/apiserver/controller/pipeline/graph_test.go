@@ -0,0 +1,155 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/models"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+)
+
+func mockGraphJobView(name, jobID string, seq int, status schema.JobStatus, start, end string) *schema.JobView {
+	return &schema.JobView{
+		JobID:     jobID,
+		StepName:  name,
+		LoopSeq:   seq,
+		Status:    status,
+		StartTime: start,
+		EndTime:   end,
+	}
+}
+
+func mockGraphDagView(name, dagID string, seq int, status schema.JobStatus, start, end string,
+	entryPoints map[string][]schema.ComponentView) *schema.DagView {
+	return &schema.DagView{
+		DagID:       dagID,
+		DagName:     name,
+		LoopSeq:     seq,
+		Status:      status,
+		StartTime:   start,
+		EndTime:     end,
+		EntryPoints: entryPoints,
+	}
+}
+
+func getMockGraphRun(t *testing.T) models.Run {
+	wfs, err := schema.GetWorkflowSource(loadCase(runDagYamlPath))
+	assert.Nil(t, err)
+
+	entryPoints := map[string][]schema.ComponentView{
+		"randint": {
+			mockGraphJobView("randint", "job-1", 0, schema.StatusJobSucceeded, "2022-01-01 10:00:00", "2022-01-01 10:01:00"),
+		},
+		"square-loop": {
+			mockGraphDagView("square-loop", "dag-2", 1, schema.StatusJobSucceeded, "2022-01-01 10:01:00", "2022-01-01 10:01:30",
+				map[string][]schema.ComponentView{
+					"square": {mockGraphJobView("square", "job-3", 0, schema.StatusJobSucceeded, "2022-01-01 10:01:00", "2022-01-01 10:01:30")},
+				}),
+			mockGraphDagView("square-loop", "dag-1", 0, schema.StatusJobSucceeded, "2022-01-01 10:01:00", "2022-01-01 10:03:00",
+				map[string][]schema.ComponentView{
+					"square": {mockGraphJobView("square", "job-2", 0, schema.StatusJobSucceeded, "2022-01-01 10:01:10", "2022-01-01 10:02:50")},
+				}),
+		},
+		"sum": {
+			mockGraphJobView("sum", "job-4", 0, schema.StatusJobRunning, "2022-01-01 10:03:00", ""),
+		},
+		"split-by-threshold": {
+			mockGraphJobView("split-by-threshold", "job-5", 0, schema.StatusJobSucceeded, "2022-01-01 10:01:00", "2022-01-01 10:01:10"),
+		},
+	}
+
+	return models.Run{
+		ID:             "run-000001",
+		Status:         common.StatusRunRunning,
+		WorkflowSource: wfs,
+		Runtime: schema.RuntimeView{
+			"myproject": {
+				mockGraphDagView("myproject", "dag-0", 0, schema.StatusJobRunning, "2022-01-01 10:00:00", "", entryPoints),
+			},
+		},
+		PostProcess: schema.PostProcessView{},
+	}
+}
+
+func TestBuildRunGraph(t *testing.T) {
+	run := getMockGraphRun(t)
+	now, err := time.ParseInLocation(graphTimeFormat, "2022-01-01 10:04:00", time.Local)
+	assert.Nil(t, err)
+
+	graph, err := buildRunGraph(run, now)
+	assert.Nil(t, err)
+	assert.Equal(t, "run-000001", graph.RunID)
+
+	nodes := map[string]*GraphNode{}
+	for _, node := range graph.Nodes {
+		nodes[node.ID] = node
+	}
+
+	// loop 展开后每一次运行对应一个节点
+	assert.Equal(t, "dag-1", nodes["square-loop[0]"].RuntimeID)
+	assert.Equal(t, "dag-2", nodes["square-loop[1]"].RuntimeID)
+	assert.Equal(t, GraphNodeTypeDag, nodes["square-loop[0]"].Type)
+	assert.Equal(t, "job-2", nodes["square-loop[0].square"].RuntimeID)
+	assert.Equal(t, "square-loop[0]", nodes["square-loop[0].square"].ParentID)
+	assert.Equal(t, int64(120), nodes["square-loop[0]"].Duration)
+	// 运行中的节点计算到当前时间
+	assert.Equal(t, int64(60), nodes["sum"].Duration)
+
+	// 未运行的 reference 节点根据 WorkflowSource 展开
+	assert.Equal(t, GraphNodeTypeDag, nodes["process-negetive"].Type)
+	assert.Equal(t, schema.JobStatus(""), nodes["process-negetive"].Status)
+	assert.NotNil(t, nodes["process-negetive.condition2.abs"])
+
+	edges := map[GraphEdge]bool{}
+	for _, edge := range graph.Edges {
+		edges[edge] = true
+	}
+	assert.True(t, edges[GraphEdge{From: "randint", To: "square-loop[0]"}])
+	assert.True(t, edges[GraphEdge{From: "randint", To: "square-loop[1]"}])
+	assert.True(t, edges[GraphEdge{From: "square-loop[1]", To: "sum"}])
+	assert.True(t, edges[GraphEdge{From: "process-negetive.condition2.show", To: "process-negetive.condition2.abs"}])
+
+	assert.Equal(t, []string{"randint", "square-loop[0]", "square-loop[0].square", "sum"}, graph.CriticalPath)
+}
+
+func TestRunGraphString(t *testing.T) {
+	run := getMockGraphRun(t)
+	graph, err := buildRunGraph(run, time.Now())
+	assert.Nil(t, err)
+
+	dot, err := graph.String(GraphFormatDot)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(dot, `digraph "run-000001" {`))
+	assert.Contains(t, dot, `subgraph "cluster_square-loop[0]" {`)
+	assert.Contains(t, dot, `"randint" -> "square-loop[0]" [color=red, penwidth=2];`)
+	assert.Contains(t, dot, `"randint" -> "square-loop[1]";`)
+
+	mermaid, err := graph.String(GraphFormatMermaid)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(mermaid, "flowchart LR\n"))
+	assert.Contains(t, mermaid, "linkStyle")
+
+	_, err = graph.String(GraphFormatJson)
+	assert.NotNil(t, err)
+	assert.False(t, IsValidGraphFormat("png"))
+}
@@ -284,17 +284,13 @@ func UpdatePipeline(ctx *logger.RequestContext, request UpdatePipelineRequest, p
 		return UpdatePipelineResponse{}, fmt.Errorf(errMsg)
 	}
 
-	hasAuth, ppl, err := CheckPipelinePermission(ctx.UserName, pipelineID)
+	// 用户对pipeline的权限已经由middleware.AuthorizePipeline校验
+	ppl, err := getPipeline(pipelineID)
 	if err != nil {
 		ctx.ErrorCode = common.InvalidArguments
 		errMsg := fmt.Sprintf("update pipeline[%s] failed. err:%v", pipelineID, err)
 		ctx.Logging().Errorf(errMsg)
 		return UpdatePipelineResponse{}, fmt.Errorf(errMsg)
-	} else if !hasAuth {
-		ctx.ErrorCode = common.AccessDenied
-		errMsg := fmt.Sprintf("update pipeline[%s] failed. Access denied for user[%s]", pipelineID, ctx.UserName)
-		ctx.Logging().Errorf(errMsg)
-		return UpdatePipelineResponse{}, fmt.Errorf(errMsg)
 	}
 
 	// 校验待更新的pipeline name，和数据库中pipeline name一致
@@ -481,13 +477,6 @@ func GetPipeline(ctx *logger.RequestContext, pipelineID, marker string, maxKeys
 		ctx.Logging().Errorf(errMsg)
 		return GetPipelineResponse{}, fmt.Errorf(errMsg)
 	}
-
-	if !common.IsRootUser(ctx.UserName) && ctx.UserName != ppl.UserName {
-		ctx.ErrorCode = common.AccessDenied
-		err := common.NoAccessError(ctx.UserName, common.ResourceTypePipeline, pipelineID)
-		ctx.Logging().Errorln(err.Error())
-		return GetPipelineResponse{}, err
-	}
 	getPipelineResponse.Pipeline.updateFromPipelineModel(ppl)
 
 	// query pipeline version
@@ -550,17 +539,12 @@ func GetPipelineVersion(ctx *logger.RequestContext, pipelineID string, pipelineV
 	ctx.Logging().Debugf("begin get pipeline version.")
 
 	// query pipeline
-	hasAuth, ppl, pplVersion, err := CheckPipelineVersionPermission(ctx.UserName, pipelineID, pipelineVersionID)
+	ppl, pplVersion, err := getPipelineVersion(pipelineID, pipelineVersionID)
 	if err != nil {
 		ctx.ErrorCode = common.InternalError
 		errMsg := fmt.Sprintf("get pipeline[%s] version[%s] failed. err:%v", pipelineID, pipelineVersionID, err)
 		ctx.Logging().Errorf(errMsg)
 		return GetPipelineVersionResponse{}, fmt.Errorf(errMsg)
-	} else if !hasAuth {
-		ctx.ErrorCode = common.AccessDenied
-		errMsg := fmt.Sprintf("get pipeline[%s] version[%s] failed. Access denied for user[%s]", pipelineID, pipelineVersionID, ctx.UserName)
-		ctx.Logging().Errorf(errMsg)
-		return GetPipelineVersionResponse{}, fmt.Errorf(errMsg)
 	}
 
 	getPipelineVersionResponse := GetPipelineVersionResponse{}
@@ -572,17 +556,11 @@ func GetPipelineVersion(ctx *logger.RequestContext, pipelineID string, pipelineV
 func DeletePipeline(ctx *logger.RequestContext, pipelineID string) error {
 	ctx.Logging().Debugf("begin delete pipeline: %s", pipelineID)
 
-	hasAuth, _, err := CheckPipelinePermission(ctx.UserName, pipelineID)
-	if err != nil {
+	if _, err := getPipeline(pipelineID); err != nil {
 		ctx.ErrorCode = common.InternalError
 		errMsg := fmt.Sprintf("delete pipeline[%s] failed. err:%v", pipelineID, err)
 		ctx.Logging().Errorf(errMsg)
 		return fmt.Errorf(errMsg)
-	} else if !hasAuth {
-		ctx.ErrorCode = common.AccessDenied
-		errMsg := fmt.Sprintf("delete pipeline[%s] failed. Access denied for user[%s]", pipelineID, ctx.UserName)
-		ctx.Logging().Errorf(errMsg)
-		return fmt.Errorf(errMsg)
 	}
 
 	// 需要判断是否有周期调度运行中（单次任务不影响，因为run会直接保存yaml）
@@ -610,17 +588,11 @@ func DeletePipeline(ctx *logger.RequestContext, pipelineID string) error {
 
 func DeletePipelineVersion(ctx *logger.RequestContext, pipelineID string, pipelineVersionID string) error {
 	ctx.Logging().Debugf("begin delete pipeline version[%s], with pipelineID[%s]", pipelineVersionID, pipelineID)
-	hasAuth, _, _, err := CheckPipelineVersionPermission(ctx.UserName, pipelineID, pipelineVersionID)
-	if err != nil {
+	if _, _, err := getPipelineVersion(pipelineID, pipelineVersionID); err != nil {
 		ctx.ErrorCode = common.InternalError
 		errMsg := fmt.Sprintf("delete pipeline[%s] version[%s] failed. err:%v", pipelineID, pipelineVersionID, err)
 		ctx.Logging().Errorf(errMsg)
 		return fmt.Errorf(errMsg)
-	} else if !hasAuth {
-		ctx.ErrorCode = common.AccessDenied
-		errMsg := fmt.Sprintf("delete pipeline[%s] version[%s] failed. Access denied for user[%s]", pipelineID, pipelineVersionID, ctx.UserName)
-		ctx.Logging().Errorf(errMsg)
-		return fmt.Errorf(errMsg)
 	}
 
 	// 如果只有一个pipeline version的话，直接删除pipeline本身
@@ -661,43 +633,76 @@ func DeletePipelineVersion(ctx *logger.RequestContext, pipelineID string, pipeli
 	return nil
 }
 
-func CheckPipelinePermission(userName string, pipelineID string) (bool, model.Pipeline, error) {
-	ppl, err := storage.Pipeline.GetPipelineByID(pipelineID)
+// CheckPipelinePermission 检查用户对pipeline是否有action权限, pipeline的创建者和root拥有全部权限, 其他用户需要被授予相应的角色.
+// 用于请求体中指定的pipeline, 路径参数指定的pipeline由middleware.AuthorizePipeline校验
+func CheckPipelinePermission(userName string, pipelineID string, action string) (bool, model.Pipeline, error) {
+	ppl, err := getPipeline(pipelineID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			errMsg := fmt.Sprintf("pipeline[%s] not exist", pipelineID)
-			return false, model.Pipeline{}, fmt.Errorf(errMsg)
-		} else {
-			errMsg := fmt.Sprintf("get pipeline[%s] failed, err:[%s]", pipelineID, err.Error())
-			return false, model.Pipeline{}, fmt.Errorf(errMsg)
-		}
+		return false, model.Pipeline{}, err
 	}
 
-	if !common.IsRootUser(userName) && userName != ppl.UserName {
+	if !hasPipelinePermission(userName, ppl, action) {
 		return false, model.Pipeline{}, nil
 	}
 
 	return true, ppl, nil
 }
 
-func CheckPipelineVersionPermission(userName string, pipelineID string, pipelineVersionID string) (bool, model.Pipeline, model.PipelineVersion, error) {
-	hasAuth, ppl, err := CheckPipelinePermission(userName, pipelineID)
+func hasPipelinePermission(userName string, ppl model.Pipeline, action string) bool {
+	if common.IsRootUser(userName) || userName == ppl.UserName {
+		return true
+	}
+	ctx := &logger.RequestContext{UserName: userName}
+	return storage.Auth.HasPermission(ctx, common.ResourceTypePipeline, ppl.ID, action)
+}
+
+func CheckPipelineVersionPermission(userName string, pipelineID string, pipelineVersionID string, action string) (bool, model.Pipeline, model.PipelineVersion, error) {
+	hasAuth, ppl, err := CheckPipelinePermission(userName, pipelineID, action)
 	if err != nil {
 		return false, model.Pipeline{}, model.PipelineVersion{}, err
 	} else if !hasAuth {
 		return false, model.Pipeline{}, model.PipelineVersion{}, nil
 	}
 
+	_, pipelineVersion, err := getPipelineVersion(pipelineID, pipelineVersionID)
+	if err != nil {
+		return false, model.Pipeline{}, model.PipelineVersion{}, err
+	}
+
+	return true, ppl, pipelineVersion, nil
+}
+
+// getPipeline 查询pipeline, 不校验用户权限
+func getPipeline(pipelineID string) (model.Pipeline, error) {
+	ppl, err := storage.Pipeline.GetPipelineByID(pipelineID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			errMsg := fmt.Sprintf("pipeline[%s] not exist", pipelineID)
+			return model.Pipeline{}, fmt.Errorf(errMsg)
+		} else {
+			errMsg := fmt.Sprintf("get pipeline[%s] failed, err:[%s]", pipelineID, err.Error())
+			return model.Pipeline{}, fmt.Errorf(errMsg)
+		}
+	}
+	return ppl, nil
+}
+
+// getPipelineVersion 查询pipeline及其版本, 不校验用户权限
+func getPipelineVersion(pipelineID string, pipelineVersionID string) (model.Pipeline, model.PipelineVersion, error) {
+	ppl, err := getPipeline(pipelineID)
+	if err != nil {
+		return model.Pipeline{}, model.PipelineVersion{}, err
+	}
+
 	pipelineVersion, err := storage.Pipeline.GetPipelineVersion(pipelineID, pipelineVersionID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			errMsg := fmt.Sprintf("pipeline[%s] version[%s] not exist", pipelineID, pipelineVersionID)
-			return false, model.Pipeline{}, model.PipelineVersion{}, fmt.Errorf(errMsg)
+			return model.Pipeline{}, model.PipelineVersion{}, fmt.Errorf(errMsg)
 		} else {
 			errMsg := fmt.Sprintf("get pipeline[%s] version[%s] failed, err:[%s]", pipelineID, pipelineVersionID, err.Error())
-			return false, model.Pipeline{}, model.PipelineVersion{}, fmt.Errorf(errMsg)
+			return model.Pipeline{}, model.PipelineVersion{}, fmt.Errorf(errMsg)
 		}
 	}
-
-	return true, ppl, pipelineVersion, nil
+	return ppl, pipelineVersion, nil
 }
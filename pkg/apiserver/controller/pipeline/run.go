@@ -183,7 +183,7 @@ func buildWorkflowSource(ctx logger.RequestContext, req CreateRunRequest, fsID s
 			return schema.WorkflowSource{}, "", "", err
 		}
 	} else if req.PipelineID != "" { // medium priority: wfs in pipeline
-		hasAuth, _, err := CheckPipelinePermission(userName, req.PipelineID)
+		hasAuth, _, err := CheckPipelinePermission(userName, req.PipelineID, common.ActionUse)
 		if err != nil {
 			logger.Logger().Errorf("buildWorkflowSource for pipeline[%s] failed. err:%v", req.PipelineID, err)
 			return schema.WorkflowSource{}, "", "", err
@@ -876,7 +876,8 @@ func isLastRunPk(ctx *logger.RequestContext, pk int64) bool {
 	return false
 }
 
-func GetRunByID(logEntry *log.Entry, userName string, runID string) (models.Run, error) {
+// GetRunByID 查询run, 用户对run的权限由middleware.AuthorizeRun校验
+func GetRunByID(logEntry *log.Entry, runID string) (models.Run, error) {
 	logEntry.Debugf("begin get run by id. runID:%s", runID)
 	run, err := models.GetRunByID(logEntry, runID)
 	if err != nil {
@@ -887,19 +888,13 @@ func GetRunByID(logEntry *log.Entry, userName string, runID string) (models.Run,
 		return models.Run{}, err
 	}
 
-	if !common.IsRootUser(userName) && userName != run.UserName {
-		err := common.NoAccessError(userName, common.ResourceTypeRun, runID)
-		logEntry.Errorln(err.Error())
-		return models.Run{}, err
-	}
-
 	return run, nil
 }
 
-func StopRun(logEntry *log.Entry, userName, runID string, request UpdateRunRequest) error {
+func StopRun(logEntry *log.Entry, runID string, request UpdateRunRequest) error {
 	logEntry.Debugf("begin stop run. runID:%s", runID)
-	// check run exist && check user access right
-	run, err := GetRunByID(logEntry, userName, runID)
+	// check run exist
+	run, err := GetRunByID(logEntry, runID)
 	if err != nil {
 		logEntry.Errorf("stop run[%s] failed when getting run. error: %v", runID, err)
 		return err
@@ -939,15 +934,16 @@ func StopRun(logEntry *log.Entry, userName, runID string, request UpdateRunReque
 func RetryRun(ctx *logger.RequestContext, runID string) (string, error) {
 	ctx.Logging().Debugf("begin retry run. runID:%s\n", runID)
 	// check run exist && check user access right
-	run, err := GetRunByID(ctx.Logging(), ctx.UserName, runID)
+	run, err := GetRunByID(ctx.Logging(), runID)
 	if err != nil {
 		ctx.Logging().Errorf("retry run[%s] failed when getting run. error: %v\n", runID, err)
 		return "", err
 	}
 
 	// check run current status. If already succeeded or running/pending, no need to retry this run.
-	// only failed or terminated runs can retry
-	if !(run.Status == common.StatusRunFailed || run.Status == common.StatusRunTerminated) {
+	// only failed, terminated or timeout runs can retry
+	if !(run.Status == common.StatusRunFailed || run.Status == common.StatusRunTerminated ||
+		run.Status == common.StatusRunTimeout) {
 		err := fmt.Errorf("run[%s] has status[%s], no need to retry", runID, run.Status)
 		ctx.ErrorCode = common.ActionNotAllowed
 		ctx.Logging().Errorln(err.Error())
@@ -969,7 +965,7 @@ func DeleteRun(ctx *logger.RequestContext, id string, request *DeleteRunRequest)
 	ctx.Logging().Debugf("begin delete run: %s", id)
 
 	// check run exist && check user access right
-	run, err := GetRunByID(ctx.Logging(), ctx.UserName, id)
+	run, err := GetRunByID(ctx.Logging(), id)
 	if err != nil {
 		ctx.ErrorCode = common.InternalError
 		err := fmt.Errorf("delete run[%s] failed when getting run, %s", id, err.Error())
@@ -1234,6 +1230,9 @@ func newWorkflowByRun(run models.Run) (*pipeline.Workflow, error) {
 		pplcommon.WfExtraInfoKeyFSUserName: run.RunOptions.FSUsername,
 		pplcommon.WfExtraInfoKeyFsName:     run.FsName,
 	}
+	if run.ScheduledAt.Valid {
+		extraInfo[pplcommon.WfExtraInfoKeyScheduledTime] = run.ScheduledAt.Time.Format("2006-01-02 15:04:05")
+	}
 	wfPtr, err := pipeline.NewWorkflow(run.WorkflowSource, run.ID, run.Parameters, extraInfo, workflowCallbacks)
 	if err != nil {
 		logger.LoggerForRun(run.ID).Warnf("NewWorkflow by run[%s] failed. error:%v\n", run.ID, err)
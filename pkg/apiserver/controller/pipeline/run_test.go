@@ -177,7 +177,7 @@ func TestGetRunSuccess(t *testing.T) {
 	run1.ID, err = models.CreateRun(ctx.Logging(), &run1)
 	assert.Nil(t, err)
 
-	runRsp, err := GetRunByID(ctx.Logging(), ctx.UserName, run1.ID)
+	runRsp, err := GetRunByID(ctx.Logging(), run1.ID)
 	assert.Nil(t, err)
 	assert.Equal(t, run1.ID, runRsp.ID)
 	assert.Equal(t, run1.Name, runRsp.Name)
@@ -186,7 +186,7 @@ func TestGetRunSuccess(t *testing.T) {
 	run3 := getMockRun3()
 	run3.ID, err = models.CreateRun(ctx.Logging(), &run3)
 	assert.Nil(t, err)
-	runRsp, err = GetRunByID(ctx.Logging(), ctx.UserName, run3.ID)
+	runRsp, err = GetRunByID(ctx.Logging(), run3.ID)
 	assert.Nil(t, err)
 	assert.Equal(t, runRsp.FailureOptions.Strategy, schema.FailureStrategyContinue)
 }
@@ -197,15 +197,10 @@ func TestGetRunFail(t *testing.T) {
 	ctx := &logger.RequestContext{UserName: MockRootUser}
 	run1 := getMockRun1()
 	run1.ID, err = models.CreateRun(ctx.Logging(), &run1)
-
-	// test non-admin user no access to other users' run
-	ctxOtherNonAdmin := &logger.RequestContext{UserName: "non-admin"}
-	_, err = GetRunByID(ctxOtherNonAdmin.Logging(), ctxOtherNonAdmin.UserName, run1.ID)
-	assert.NotNil(t, err)
-	assert.Equal(t, common.NoAccessError("non-admin", common.ResourceTypeRun, run1.ID).Error(), err.Error())
+	assert.Nil(t, err)
 
 	// test no record
-	_, err = GetRunByID(ctx.Logging(), ctx.UserName, "run-id_non_existed")
+	_, err = GetRunByID(ctx.Logging(), "run-id_non_existed")
 	assert.NotNil(t, err)
 	assert.Equal(t, common.NotFoundError(common.ResourceTypeRun, "run-id_non_existed").Error(), err.Error())
 }
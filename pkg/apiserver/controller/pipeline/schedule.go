@@ -53,6 +53,19 @@ type CreateScheduleResponse struct {
 	ScheduleID string `json:"scheduleID"`
 }
 
+type BackfillScheduleRequest struct {
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+}
+
+type BackfillScheduleResponse struct {
+	ScheduleID string `json:"scheduleID"`
+	RunCount   int    `json:"runCount"` // 补数据区间内的调度次数，包括因为并发限制被 skip 的run
+}
+
+// 单次补数据最多发起的run数量，避免错误的时间区间发起过多的run
+const MaxBackfillRunCount = 1000
+
 type ScheduleBrief struct {
 	ID                string                 `json:"scheduleID"`
 	Name              string                 `json:"name"`
@@ -70,6 +83,8 @@ type ScheduleBrief struct {
 	NextRunTime       string                 `json:"nextRunTime"`
 	Message           string                 `json:"scheduleMsg"`
 	Status            string                 `json:"status"`
+	BackfillNextTime  string                 `json:"backfillNextTime"`
+	BackfillEndTime   string                 `json:"backfillEndTime"`
 }
 
 type ListScheduleResponse struct {
@@ -118,6 +133,14 @@ func (b *ScheduleBrief) updateFromScheduleModel(schedule models.Schedule) (err e
 		b.EndTime = ""
 	}
 
+	if schedule.BackfillNextAt.Valid {
+		b.BackfillNextTime = schedule.BackfillNextAt.Time.Format("2006-01-02 15:04:05")
+		b.BackfillEndTime = schedule.BackfillEndAt.Time.Format("2006-01-02 15:04:05")
+	} else {
+		b.BackfillNextTime = ""
+		b.BackfillEndTime = ""
+	}
+
 	return nil
 }
 
@@ -291,7 +314,7 @@ func CreateSchedule(ctx *logger.RequestContext, request *CreateScheduleRequest)
 	}
 
 	// 校验用户对pplID pplVersionID是否有权限
-	hasAuth, _, _, err := CheckPipelineVersionPermission(ctx.UserName, request.PipelineID, request.PipelineVersionID)
+	hasAuth, _, _, err := CheckPipelineVersionPermission(ctx.UserName, request.PipelineID, request.PipelineVersionID, common.ActionUse)
 	if err != nil {
 		ctx.ErrorCode = common.InvalidArguments
 		errMsg := fmt.Sprintf("create schedule failed, %s", err.Error())
@@ -442,6 +465,7 @@ func ListSchedule(ctx *logger.RequestContext, marker string, maxKeys int, pplFil
 	return listScheduleResponse, nil
 }
 
+// getSchedule 查询schedule, 用户对schedule的权限由middleware.AuthorizeSchedule校验
 func getSchedule(ctx *logger.RequestContext, scheduleID string) (models.Schedule, error) {
 	ctx.Logging().Debugf("begin get schedule by id. scheduleID:%s", scheduleID)
 	schedule, err := models.GetSchedule(ctx.Logging(), scheduleID)
@@ -449,12 +473,6 @@ func getSchedule(ctx *logger.RequestContext, scheduleID string) (models.Schedule
 		ctx.Logging().Errorln(err.Error())
 		return models.Schedule{}, err
 	}
-
-	if !common.IsRootUser(ctx.UserName) && ctx.UserName != schedule.UserName {
-		err := common.NoAccessError(ctx.UserName, common.ResourceTypeSchedule, scheduleID)
-		ctx.Logging().Errorln(err.Error())
-		return models.Schedule{}, err
-	}
 	return schedule, nil
 }
 
@@ -462,7 +480,7 @@ func GetSchedule(ctx *logger.RequestContext, scheduleID string,
 	marker string, maxKeys int, runFilter, statusFilter []string) (GetScheduleResponse, error) {
 	ctx.Logging().Debugf("begin get schedule[%s]", scheduleID)
 
-	// check schedule exist && user access right
+	// check schedule exist
 	schedule, err := getSchedule(ctx, scheduleID)
 	if err != nil {
 		ctx.ErrorCode = common.InvalidArguments
@@ -493,7 +511,7 @@ func GetSchedule(ctx *logger.RequestContext, scheduleID string,
 // todo: 支持 StopRun
 func StopSchedule(ctx *logger.RequestContext, scheduleID string) error {
 	ctx.Logging().Debugf("begin stop schedule: %s", scheduleID)
-	// check schedule exist && user access right
+	// check schedule exist
 	schedule, err := getSchedule(ctx, scheduleID)
 	if err != nil {
 		ctx.ErrorCode = common.InvalidArguments
@@ -516,6 +534,15 @@ func StopSchedule(ctx *logger.RequestContext, scheduleID string) error {
 		return fmt.Errorf(errMsg)
 	}
 
+	// 停止schedule时，同时取消进行中的补数据
+	if schedule.BackfillNextAt.Valid {
+		if err := models.UpdateScheduleBackfill(ctx.Logging(), scheduleID, sql.NullTime{Valid: false}); err != nil {
+			errMsg := fmt.Sprintf("stop schedule failed cancelling backfill")
+			ctx.ErrorCode = common.InternalError
+			return fmt.Errorf(errMsg)
+		}
+	}
+
 	// 给scheduler发stop channel信号
 	err = SendSingnal(OpTypeStop, scheduleID)
 	if err != nil {
@@ -529,10 +556,237 @@ func StopSchedule(ctx *logger.RequestContext, scheduleID string) error {
 	return nil
 }
 
+// PauseSchedule 暂停周期调度，暂停期间不会发起新的run，可以通过 ResumeSchedule 恢复
+func PauseSchedule(ctx *logger.RequestContext, scheduleID string) error {
+	ctx.Logging().Debugf("begin pause schedule: %s", scheduleID)
+	// check schedule exist
+	schedule, err := getSchedule(ctx, scheduleID)
+	if err != nil {
+		ctx.ErrorCode = common.InvalidArguments
+		err := fmt.Errorf("pause schedule[%s] failed. %s", scheduleID, err.Error())
+		ctx.Logging().Errorf(err.Error())
+		return err
+	}
+
+	// check schedule current status, only running schedule can be paused
+	if schedule.Status != models.ScheduleStatusRunning {
+		ctx.ErrorCode = common.ActionNotAllowed
+		err := fmt.Errorf("pause schedule[%s] failed, only schedule in status[%s] can be paused, current status[%s]",
+			scheduleID, models.ScheduleStatusRunning, schedule.Status)
+		ctx.Logging().Errorln(err.Error())
+		return err
+	}
+
+	if err := models.UpdateScheduleStatusFrom(ctx.Logging(), scheduleID, models.ScheduleStatusRunning, models.ScheduleStatusPaused); err != nil {
+		ctx.ErrorCode = common.InternalError
+		errMsg := fmt.Sprintf("pause schedule failed updating db. error:%s", err.Error())
+		return fmt.Errorf(errMsg)
+	}
+
+	// 给scheduler发pause channel信号
+	err = SendSingnal(OpTypePause, scheduleID)
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		errMsg := fmt.Sprintf("pause schedule failed in sending pause channel signal. error:%v", err)
+		ctx.Logging().Errorf(errMsg)
+		return fmt.Errorf(errMsg)
+	}
+	ctx.Logging().Debugf("send pause schedule channel succeed. scheduleID:%s", scheduleID)
+
+	return nil
+}
+
+// getResumeNextRunAt 计算 schedule 恢复后的 nextRunAt
+// - catchup == true: 保留暂停前的 nextRunAt，暂停期间错过的周期任务由scheduler补发，超出 expire_interval 的任务会被置为 skipped
+// - catchup == false: 暂停期间错过的周期任务全部抛弃，从当前时间开始计算 nextRunAt
+func getResumeNextRunAt(schedule models.Schedule, options models.ScheduleOptions, currentTime time.Time) (time.Time, error) {
+	if options.Catchup || schedule.NextRunAt.After(currentTime) {
+		return schedule.NextRunAt, nil
+	}
+
+	cronSchedule, err := cron.ParseStandard(schedule.Crontab)
+	if err != nil {
+		errMsg := fmt.Sprintf("parse crontab spec[%s] for schedule[%s] failed, errMsg[%s]", schedule.Crontab, schedule.ID, err.Error())
+		return time.Time{}, fmt.Errorf(errMsg)
+	}
+	return cronSchedule.Next(currentTime), nil
+}
+
+// ResumeSchedule 恢复处于暂停状态的周期调度，根据 catchup 与 expire_interval 配置决定是否补发暂停期间错过的run
+func ResumeSchedule(ctx *logger.RequestContext, scheduleID string) error {
+	ctx.Logging().Debugf("begin resume schedule: %s", scheduleID)
+	// check schedule exist
+	schedule, err := getSchedule(ctx, scheduleID)
+	if err != nil {
+		ctx.ErrorCode = common.InvalidArguments
+		err := fmt.Errorf("resume schedule[%s] failed. %s", scheduleID, err.Error())
+		ctx.Logging().Errorf(err.Error())
+		return err
+	}
+
+	// check schedule current status, only paused schedule can be resumed
+	if schedule.Status != models.ScheduleStatusPaused {
+		ctx.ErrorCode = common.ActionNotAllowed
+		err := fmt.Errorf("resume schedule[%s] failed, only schedule in status[%s] can be resumed, current status[%s]",
+			scheduleID, models.ScheduleStatusPaused, schedule.Status)
+		ctx.Logging().Errorln(err.Error())
+		return err
+	}
+
+	options, err := models.DecodeScheduleOptions(schedule.Options)
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		errMsg := fmt.Sprintf("resume schedule[%s] failed, %s", scheduleID, err.Error())
+		ctx.Logging().Errorf(errMsg)
+		return fmt.Errorf(errMsg)
+	}
+
+	nextRunAt, err := getResumeNextRunAt(schedule, options, time.Now())
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		errMsg := fmt.Sprintf("resume schedule[%s] failed, %s", scheduleID, err.Error())
+		ctx.Logging().Errorf(errMsg)
+		return fmt.Errorf(errMsg)
+	}
+
+	if err := models.ResumeSchedule(ctx.Logging(), scheduleID, nextRunAt); err != nil {
+		ctx.ErrorCode = common.InternalError
+		errMsg := fmt.Sprintf("resume schedule failed updating db. error:%s", err.Error())
+		return fmt.Errorf(errMsg)
+	}
+
+	// 给scheduler发resume channel信号
+	err = SendSingnal(OpTypeResume, scheduleID)
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		errMsg := fmt.Sprintf("resume schedule failed in sending resume channel signal. error:%v", err)
+		ctx.Logging().Errorf(errMsg)
+		return fmt.Errorf(errMsg)
+	}
+	ctx.Logging().Debugf("send resume schedule channel succeed. scheduleID:%s", scheduleID)
+
+	return nil
+}
+
+func validateBackfillTime(startTime, endTime string, currentTime time.Time) (startAt, endAt time.Time, err error) {
+	startAt, err = time.ParseInLocation("2006-01-02 15:04:05", startTime, time.Local)
+	if err != nil {
+		errMsg := fmt.Sprintf("starttime[%s] format not correct, should be YYYY-MM-DD hh:mm:ss", startTime)
+		return startAt, endAt, fmt.Errorf(errMsg)
+	}
+
+	endAt, err = time.ParseInLocation("2006-01-02 15:04:05", endTime, time.Local)
+	if err != nil {
+		errMsg := fmt.Sprintf("endtime[%s] format not correct, should be YYYY-MM-DD hh:mm:ss", endTime)
+		return startAt, endAt, fmt.Errorf(errMsg)
+	}
+
+	if endAt.After(currentTime) {
+		errMsg := fmt.Sprintf("endtime[%s] after currentTime[%s], only historical time range can be backfilled", endTime, currentTime.Format("2006-01-02 15:04:05"))
+		return startAt, endAt, fmt.Errorf(errMsg)
+	}
+
+	if startAt.After(endAt) {
+		errMsg := fmt.Sprintf("endtime[%s] not after startTime[%s]", endTime, startTime)
+		return startAt, endAt, fmt.Errorf(errMsg)
+	}
+
+	return startAt, endAt, nil
+}
+
+// getBackfillRunTimes 计算 [startAt, endAt] 区间内所有的调度时间
+func getBackfillRunTimes(crontab string, startAt, endAt time.Time) ([]time.Time, error) {
+	cronSchedule, err := cron.ParseStandard(crontab)
+	if err != nil {
+		return nil, err
+	}
+
+	// cron 计算的是严格晚于给定时间的调度时间，为了包含 startAt，需要从前一秒开始计算
+	runTimes := []time.Time{}
+	for runAt := cronSchedule.Next(startAt.Add(-time.Second)); !runAt.After(endAt); runAt = cronSchedule.Next(runAt) {
+		runTimes = append(runTimes, runAt)
+		if len(runTimes) > MaxBackfillRunCount {
+			return nil, fmt.Errorf("too many runs to backfill, should be no more than %d", MaxBackfillRunCount)
+		}
+	}
+	return runTimes, nil
+}
+
+// BackfillSchedule 为历史时间区间内的每个调度时间发起run，发起的run与周期调度一样受到 concurrency 和 concurrencyPolicy 的限制
+// 实际发起run由scheduler完成，这里只记录补数据区间
+func BackfillSchedule(ctx *logger.RequestContext, scheduleID string, request *BackfillScheduleRequest) (BackfillScheduleResponse, error) {
+	ctx.Logging().Debugf("begin backfill schedule: %s, request: %v", scheduleID, request)
+	// check schedule exist
+	schedule, err := getSchedule(ctx, scheduleID)
+	if err != nil {
+		ctx.ErrorCode = common.InvalidArguments
+		err := fmt.Errorf("backfill schedule[%s] failed. %s", scheduleID, err.Error())
+		ctx.Logging().Errorf(err.Error())
+		return BackfillScheduleResponse{}, err
+	}
+
+	if schedule.Status == models.ScheduleStatusTerminated {
+		ctx.ErrorCode = common.ActionNotAllowed
+		err := fmt.Errorf("backfill schedule[%s] failed, schedule is %s", scheduleID, schedule.Status)
+		ctx.Logging().Errorln(err.Error())
+		return BackfillScheduleResponse{}, err
+	}
+
+	if schedule.BackfillNextAt.Valid {
+		ctx.ErrorCode = common.ActionNotAllowed
+		err := fmt.Errorf("backfill schedule[%s] failed, another backfill is in progress, next backfill time[%s], end time[%s]",
+			scheduleID, schedule.BackfillNextAt.Time.Format("2006-01-02 15:04:05"), schedule.BackfillEndAt.Time.Format("2006-01-02 15:04:05"))
+		ctx.Logging().Errorln(err.Error())
+		return BackfillScheduleResponse{}, err
+	}
+
+	startAt, endAt, err := validateBackfillTime(request.StartTime, request.EndTime, time.Now())
+	if err != nil {
+		ctx.ErrorCode = common.InvalidArguments
+		errMsg := fmt.Sprintf("backfill schedule[%s] failed, %s", scheduleID, err.Error())
+		ctx.Logging().Errorf(errMsg)
+		return BackfillScheduleResponse{}, fmt.Errorf(errMsg)
+	}
+
+	runTimes, err := getBackfillRunTimes(schedule.Crontab, startAt, endAt)
+	if err != nil {
+		ctx.ErrorCode = common.InvalidArguments
+		errMsg := fmt.Sprintf("backfill schedule[%s] failed, %s", scheduleID, err.Error())
+		ctx.Logging().Errorf(errMsg)
+		return BackfillScheduleResponse{}, fmt.Errorf(errMsg)
+	}
+
+	if len(runTimes) == 0 {
+		ctx.ErrorCode = common.InvalidArguments
+		errMsg := fmt.Sprintf("backfill schedule[%s] failed, no schedule time between starttime[%s] and endtime[%s] with crontab[%s]",
+			scheduleID, request.StartTime, request.EndTime, schedule.Crontab)
+		ctx.Logging().Errorf(errMsg)
+		return BackfillScheduleResponse{}, fmt.Errorf(errMsg)
+	}
+
+	if err := models.StartScheduleBackfill(ctx.Logging(), scheduleID, runTimes[0], endAt); err != nil {
+		ctx.ErrorCode = common.InternalError
+		errMsg := fmt.Sprintf("backfill schedule failed updating db. error:%s", err.Error())
+		return BackfillScheduleResponse{}, fmt.Errorf(errMsg)
+	}
+
+	// 给scheduler发backfill channel信号
+	err = SendSingnal(OpTypeBackfill, scheduleID)
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		errMsg := fmt.Sprintf("backfill schedule failed in sending backfill channel signal. error:%v", err)
+		ctx.Logging().Errorf(errMsg)
+		return BackfillScheduleResponse{}, fmt.Errorf(errMsg)
+	}
+	ctx.Logging().Debugf("send backfill schedule channel succeed. scheduleID:%s", scheduleID)
+
+	return BackfillScheduleResponse{ScheduleID: scheduleID, RunCount: len(runTimes)}, nil
+}
+
 // todo: 支持 StopRun
 func DeleteSchedule(ctx *logger.RequestContext, scheduleID string) error {
 	ctx.Logging().Debugf("begin delete schedule: %s", scheduleID)
-	// check schedule exist && user access right
+	// check schedule exist
 	schedule, err := getSchedule(ctx, scheduleID)
 	if err != nil {
 		ctx.ErrorCode = common.InvalidArguments
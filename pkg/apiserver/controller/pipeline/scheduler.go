@@ -32,9 +32,12 @@ import (
 )
 
 const (
-	OpTypeCreate = "create"
-	OpTypeStop   = "stop"
-	OpTypeDelete = "delete"
+	OpTypeCreate   = "create"
+	OpTypeStop     = "stop"
+	OpTypeDelete   = "delete"
+	OpTypePause    = "pause"
+	OpTypeResume   = "resume"
+	OpTypeBackfill = "backfill"
 )
 
 type OpInfo struct {
@@ -43,7 +46,8 @@ type OpInfo struct {
 }
 
 func NewOpInfo(opType string, scheduleID string) (OpInfo, error) {
-	if opType != OpTypeCreate && opType != OpTypeStop && opType != OpTypeDelete {
+	if opType != OpTypeCreate && opType != OpTypeStop && opType != OpTypeDelete &&
+		opType != OpTypePause && opType != OpTypeResume && opType != OpTypeBackfill {
 		errMsg := fmt.Sprintf("optype[%s] not supported", opType)
 		return OpInfo{}, fmt.Errorf(errMsg)
 	}
@@ -173,15 +177,20 @@ func (s *Scheduler) getTimeout(nextWakeupTime *time.Time) <-chan time.Time {
 // - 计算timeout，如果有 schedule 的 next_run_at 在 expire_interval以外，会直接把 timeout 设置为0
 //   - 有过期任务，此处不会更新next_run_at，而是马上触发dealWithTimeout函数处理
 //
-// 对于 stop/delete 操作，可以不再计算timeout
+// 对于 stop/delete/pause 操作，可以不再计算timeout
 // - 如果停止的schedule，【不是】下一次wakeup要执行的，那对timeout毫无影响
 // - 如果停止的schedule恰好是下一次wakeup要执行的，那只是导致一次无效的wakeup而已
 //   - 一次无效的timeout，代价是一次扫表；但是为了避免无效的timeout，这里也要扫表，代价是一致的。
+//
+// 对于 resume 操作，与 create 一样需要重新计算timeout
+// - 暂停期间错过的周期任务，已经在resume时根据catchup配置更新了next_run_at，并交由dealWithTimeout根据expire_interval处理
+//
+// 对于 backfill 操作，补数据的调度时间都早于当前时间，重新计算得到的timeout为0，会马上触发dealWithTimeout处理
 func (s *Scheduler) dealWithOps(opInfo OpInfo) (toUpdate bool, timeout *time.Time, err error) {
 	logger.Logger().Debugf("begin to deal with shedule op[%s] of schedule[%s]", opInfo.GetOpType(), opInfo.GetScheduleID())
 
 	opType := opInfo.GetOpType()
-	if opType == OpTypeStop || opType == OpTypeDelete {
+	if opType == OpTypeStop || opType == OpTypeDelete || opType == OpTypePause {
 		return false, nil, nil
 	}
 
@@ -321,7 +330,7 @@ func (s *Scheduler) createRun(schedule models.Schedule, fsConfig models.FsConfig
 func (s *Scheduler) stopRun(runID string, schedule models.Schedule) {
 	logger.Logger().Infof("start to stop run[%s] for schedule[%s]", runID, schedule.ID)
 	request := UpdateRunRequest{StopForce: false}
-	err := StopRun(logger.Logger(), schedule.UserName, runID, request)
+	err := StopRun(logger.Logger(), runID, request)
 	if err != nil {
 		logger.Logger().Errorf("stop run[%s] failed for schedule[%s], err:[%s]", runID, schedule.ID, err.Error())
 	}
@@ -379,22 +388,23 @@ func (s *Scheduler) updateScheduleAndWakeupTime(schedule models.Schedule, curren
 		schedule.ID, s.formatTime(&nextRunAt), s.formatTime(&currentTime))
 
 	// 更新 NextRunAt 字段
-	to_update := false
+	// 只更新有变化的字段，避免覆盖用户在调度过程中更新的字段，如补数据区间
+	toUpdate := map[string]interface{}{}
 	if !nextRunAt.Equal(schedule.NextRunAt) {
 		schedule.NextRunAt = nextRunAt
-		to_update = true
+		toUpdate["next_run_at"] = nextRunAt
 	}
 
 	// 更新 status 字段
 	if schedule.EndAt.Valid && nextRunAt.After(schedule.EndAt.Time) {
 		schedule.Status = models.ScheduleStatusSuccess
-		to_update = true
+		toUpdate["status"] = models.ScheduleStatusSuccess
 	}
 
 	// 更新异常不能影响调度，先只打日志（否则影响整个server的逻辑）
 	// todo: 这就需要每次schedule开始调度前，判断当前的schedule run发起情况，那是否还需要nextRunAt？
-	if to_update {
-		result := storage.DB.Model(&schedule).Save(schedule)
+	if len(toUpdate) > 0 {
+		result := storage.DB.Model(&models.Schedule{}).Where("id = ?", schedule.ID).Updates(toUpdate)
 		if result.Error != nil {
 			errMsg := fmt.Sprintf("update schedule[%s] of pipeline detail[%s] failed, error:%v",
 				schedule.ID, schedule.PipelineVersionID, result.Error)
@@ -472,9 +482,83 @@ func (s *Scheduler) dealWithTimeout() (nextWakeupTime *time.Time, err error) {
 		logger.Logger().Infof("after updateScheduleAndWakeupTime for schedule[%s], nextWakeupTime[%s]", schedule.ID, s.formatTime(nextWakeupTime))
 	}
 
+	// 处理补数据任务，补数据失败只打日志，不影响周期调度
+	s.dealWithBackfill(currentTime)
+
 	return nextWakeupTime, err
 }
 
+// 处理补数据任务，补数据的区间为[backfill_next_at, backfill_end_at]，区间内的调度时间都早于当前时间
+// - 与周期调度一样，根据 concurrency 和 concurrencyPolicy 发起run，concurrency 为0时不限制并发度，但是不需要考虑 expire_interval
+// - suspend 策略下，达到并发上限后，剩余的调度时间会在并发度空闲时(dealWithConcurrency)唤醒后继续处理
+// - 补数据的调度时间都早于当前时间，处理完成后不需要更新 nextWakeupTime
+func (s *Scheduler) dealWithBackfill(currentTime time.Time) {
+	schedules, err := models.GetSchedulesInBackfill(logger.Logger())
+	if err != nil {
+		logger.Logger().Errorf("get schedules in backfill failed, err:[%s]", err.Error())
+		return
+	}
+
+	for _, schedule := range schedules {
+		if err := s.processBackfill(schedule, currentTime); err != nil {
+			logger.Logger().Errorf("process backfill for schedule[%s] failed, err:[%s]", schedule.ID, err.Error())
+		}
+	}
+}
+
+func (s *Scheduler) processBackfill(schedule models.Schedule, currentTime time.Time) error {
+	// schedule 被停止后，补数据随之取消
+	if schedule.Status == models.ScheduleStatusTerminated {
+		logger.Logger().Infof("schedule[%s] is terminated, cancel backfill", schedule.ID)
+		return models.UpdateScheduleBackfill(logger.Logger(), schedule.ID, sql.NullTime{Valid: false})
+	}
+
+	options, err := models.DecodeScheduleOptions(schedule.Options)
+	if err != nil {
+		return err
+	}
+
+	fsConfig, err := models.DecodeFsConfig(schedule.FsConfig)
+	if err != nil {
+		return err
+	}
+
+	scheduleIDList := []string{schedule.ID}
+	activeRuns, err := models.ListRun(logger.Logger(), 0, 0, []string{}, []string{}, []string{}, []string{}, common.RunActiveStatus, scheduleIDList)
+	if err != nil {
+		return fmt.Errorf("get active runs for schedule[%s] failed, err: %s", schedule.ID, err.Error())
+	}
+
+	// 复用周期调度的逻辑，把补数据区间当成 [NextRunAt, EndAt] 来生成需要发起的run
+	options.ExpireInterval = 0
+	strOptions, err := options.Encode(logger.Logger())
+	if err != nil {
+		return err
+	}
+	backfillSchedule := schedule
+	backfillSchedule.Options = strOptions
+	backfillSchedule.NextRunAt = schedule.BackfillNextAt.Time
+	backfillSchedule.EndAt = schedule.BackfillEndAt
+
+	activeCount := len(activeRuns)
+	_, execList, skipList, nextRunAt, stopCount, err := s.generateRunListForSchedule(backfillSchedule, currentTime, activeCount)
+	if err != nil {
+		return err
+	}
+
+	logger.Logger().Infof("before processRunList for backfill of schedule[%s], execList[%v], skipList[%v], activeCount:[%d], stopCount[%d]",
+		schedule.ID, execList, skipList, activeCount, stopCount)
+	s.processRunList(backfillSchedule, options, fsConfig, currentTime, nil, skipList, execList, stopCount, activeRuns)
+
+	// 补数据区间内的调度时间都处理完后，清空补数据区间
+	backfillNextAt := sql.NullTime{Time: nextRunAt, Valid: true}
+	if !s.checkNextRunAt(nextRunAt, currentTime, schedule.BackfillEndAt) {
+		backfillNextAt = sql.NullTime{Valid: false}
+		logger.Logger().Infof("backfill of schedule[%s] finished", schedule.ID)
+	}
+	return models.UpdateScheduleBackfill(logger.Logger(), schedule.ID, backfillNextAt)
+}
+
 // 1. 判断要不要重新计算全局timeout（计算耗时，尽量过滤非必需场景）
 // - 如果当前schdule状态不是running，不做任何处理
 // - 查询当前schedule的并发度，如果当前并发度>=concurrency，不做任何处理
@@ -492,8 +576,8 @@ func (s *Scheduler) dealWithConcurrency(scheduleID string, originNextWakeupTime
 		return false, nil, err
 	}
 
-	if schedule.Status != models.ScheduleStatusRunning {
-		logger.Logger().Infof("schedule[%s] not running, doing nothing", scheduleID)
+	if schedule.Status != models.ScheduleStatusRunning && !schedule.BackfillNextAt.Valid {
+		logger.Logger().Infof("schedule[%s] not running or backfilling, doing nothing", scheduleID)
 		return false, nil, nil
 	}
 
@@ -522,8 +606,14 @@ func (s *Scheduler) dealWithConcurrency(scheduleID string, originNextWakeupTime
 		return false, nil, nil
 	}
 
-	if originNextWakeupTime == nil || (*originNextWakeupTime).After(schedule.NextRunAt) {
-		return true, &schedule.NextRunAt, nil
+	// 正在补数据的schedule，补数据的调度时间早于当前时间，会马上被唤醒
+	nextRunAt := schedule.NextRunAt
+	if schedule.BackfillNextAt.Valid && (schedule.Status != models.ScheduleStatusRunning || schedule.BackfillNextAt.Time.Before(nextRunAt)) {
+		nextRunAt = schedule.BackfillNextAt.Time
+	}
+
+	if originNextWakeupTime == nil || (*originNextWakeupTime).After(nextRunAt) {
+		return true, &nextRunAt, nil
 	} else {
 		return false, nil, nil
 	}
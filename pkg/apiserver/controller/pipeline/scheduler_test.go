@@ -486,3 +486,189 @@ func TestConcurrency(t *testing.T) {
 
 	// 带测试：concurrencyPolicy是replace，而且有运行中的任务
 }
+
+// 测试补数据
+func TestBackfill(t *testing.T) {
+	driver.InitMockDB()
+	logEntry := log.WithFields(log.Fields{})
+
+	patch1 := gomonkey.ApplyFunc(checkFs, func(string, *schema.WorkflowSource) error {
+		return nil
+	})
+	patch2 := gomonkey.ApplyFunc(StartWf, func(models.Run, *pipeline.Workflow) error {
+		return nil
+	})
+	defer patch1.Reset()
+	defer patch2.Reset()
+
+	pplID1, _, pplVersionID1, _ := insertPipeline(t, logEntry)
+
+	fsConfig := models.FsConfig{Username: "user1"}
+	StrFsConfig, err := fsConfig.Encode(logEntry)
+	assert.Nil(t, err)
+
+	// 补数据区间内共有5个调度时间
+	backfillStartAt := time.Now().Add(-10 * time.Minute).Truncate(time.Minute)
+	backfillEndAt := backfillStartAt.Add(4 * time.Minute)
+
+	// concurrency = 2，所以只发起前两个run，剩余的调度时间等待并发度空闲后再发起
+	// schedule 已经执行结束，不影响补数据
+	scheduleOptions, err := models.NewScheduleOptions(logEntry, false, 60, 2, models.ConcurrencyPolicySuspend)
+	assert.Nil(t, err)
+	strOptions, err := scheduleOptions.Encode(logEntry)
+	assert.Nil(t, err)
+
+	schedule := models.Schedule{
+		Name:              "schedule1",
+		Desc:              "schedule1",
+		PipelineID:        pplID1,
+		PipelineVersionID: pplVersionID1,
+		UserName:          "user1",
+		FsConfig:          StrFsConfig,
+		Crontab:           "*/1 * * * *",
+		Options:           strOptions,
+		Status:            models.ScheduleStatusSuccess,
+		NextRunAt:         time.Now().Add(time.Hour),
+		BackfillNextAt:    sql.NullTime{Time: backfillStartAt, Valid: true},
+		BackfillEndAt:     sql.NullTime{Time: backfillEndAt, Valid: true},
+	}
+	schedID, err := models.CreateSchedule(logEntry, schedule)
+	assert.Nil(t, err)
+
+	nextWakeupTime, err := models.GetNextGlobalWakeupTime(logEntry)
+	assert.Nil(t, err)
+	assert.NotNil(t, nextWakeupTime)
+	assert.Equal(t, backfillStartAt.Format("2006-01-02 15:04:05"), nextWakeupTime.Format("2006-01-02 15:04:05"))
+
+	scheduler := Scheduler{}
+	nextWakeupTime, err = scheduler.dealWithTimeout()
+	assert.Nil(t, err)
+	assert.Nil(t, nextWakeupTime)
+
+	runs, err := models.ListRun(logger.Logger(), 0, 0, []string{}, []string{}, []string{}, []string{}, []string{}, []string{schedID})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(runs))
+	assert.Equal(t, backfillStartAt.Format("2006-01-02 15:04:05"), runs[0].ScheduledAt.Time.Format("2006-01-02 15:04:05"))
+	assert.Equal(t, common.StatusRunInitiating, runs[0].Status)
+	assert.Equal(t, backfillStartAt.Add(time.Minute).Format("2006-01-02 15:04:05"), runs[1].ScheduledAt.Time.Format("2006-01-02 15:04:05"))
+
+	schedule, err = models.GetSchedule(logEntry, schedID)
+	assert.Nil(t, err)
+	assert.True(t, schedule.BackfillNextAt.Valid)
+	assert.Equal(t, backfillStartAt.Add(2*time.Minute).Format("2006-01-02 15:04:05"), schedule.BackfillNextAt.Time.Format("2006-01-02 15:04:05"))
+	assert.Equal(t, models.ScheduleStatusSuccess, schedule.Status)
+
+	// 并发度已满，不会被唤醒
+	toUpdate, _, err := scheduler.dealWithConcurrency(schedID, nil)
+	assert.Nil(t, err)
+	assert.False(t, toUpdate)
+
+	// run结束后并发度空闲，suspend策略下会被唤醒，继续发起剩余的run
+	result := storage.DB.Model(&models.Run{}).Where("schedule_id = ?", schedID).Update("status", common.StatusRunSucceeded)
+	assert.Nil(t, result.Error)
+	toUpdate, timeout, err := scheduler.dealWithConcurrency(schedID, nil)
+	assert.Nil(t, err)
+	assert.True(t, toUpdate)
+	assert.Equal(t, backfillStartAt.Add(2*time.Minute).Format("2006-01-02 15:04:05"), timeout.Format("2006-01-02 15:04:05"))
+
+	_, err = scheduler.dealWithTimeout()
+	assert.Nil(t, err)
+	runs, err = models.ListRun(logger.Logger(), 0, 0, []string{}, []string{}, []string{}, []string{}, []string{}, []string{schedID})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(runs))
+	schedule, err = models.GetSchedule(logEntry, schedID)
+	assert.Nil(t, err)
+	assert.Equal(t, backfillStartAt.Add(4*time.Minute).Format("2006-01-02 15:04:05"), schedule.BackfillNextAt.Time.Format("2006-01-02 15:04:05"))
+
+	// concurrency = 2，且policy = skip，发起前两个run，剩余的调度时间会创建状态为skipped的run，补数据结束
+	scheduleOptions, err = models.NewScheduleOptions(logEntry, false, 60, 2, models.ConcurrencyPolicySkip)
+	assert.Nil(t, err)
+	strOptions, err = scheduleOptions.Encode(logEntry)
+	assert.Nil(t, err)
+
+	schedule.Pk = 0
+	schedule.ID = ""
+	schedule.Name = "schedule2"
+	schedule.Options = strOptions
+	schedule.Status = models.ScheduleStatusRunning
+	schedule.BackfillNextAt = sql.NullTime{Time: backfillStartAt, Valid: true}
+	schedID, err = models.CreateSchedule(logEntry, schedule)
+	assert.Nil(t, err)
+
+	nextWakeupTime, err = scheduler.dealWithTimeout()
+	assert.Nil(t, err)
+	assert.NotNil(t, nextWakeupTime)
+	assert.Equal(t, schedule.NextRunAt.Format("2006-01-02 15:04:05"), nextWakeupTime.Format("2006-01-02 15:04:05"))
+
+	runs, err = models.ListRun(logger.Logger(), 0, 0, []string{}, []string{}, []string{}, []string{}, []string{}, []string{schedID})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(runs))
+	skippedCount := 0
+	for _, run := range runs {
+		if run.Status == common.StatusRunSkipped {
+			skippedCount += 1
+		}
+	}
+	assert.Equal(t, 3, skippedCount)
+
+	schedule, err = models.GetSchedule(logEntry, schedID)
+	assert.Nil(t, err)
+	assert.False(t, schedule.BackfillNextAt.Valid)
+	assert.False(t, schedule.BackfillEndAt.Valid)
+
+	// concurrency = 0，不限制并发度，一次发起区间内全部的run，补数据结束
+	scheduleOptions, err = models.NewScheduleOptions(logEntry, false, 60, 0, models.ConcurrencyPolicySuspend)
+	assert.Nil(t, err)
+	strOptions, err = scheduleOptions.Encode(logEntry)
+	assert.Nil(t, err)
+
+	schedule.Pk = 0
+	schedule.ID = ""
+	schedule.Name = "schedule3"
+	schedule.Options = strOptions
+	schedule.BackfillNextAt = sql.NullTime{Time: backfillStartAt, Valid: true}
+	schedule.BackfillEndAt = sql.NullTime{Time: backfillEndAt, Valid: true}
+	schedID, err = models.CreateSchedule(logEntry, schedule)
+	assert.Nil(t, err)
+
+	nextWakeupTime, err = scheduler.dealWithTimeout()
+	assert.Nil(t, err)
+	assert.NotNil(t, nextWakeupTime)
+	assert.Equal(t, schedule.NextRunAt.Format("2006-01-02 15:04:05"), nextWakeupTime.Format("2006-01-02 15:04:05"))
+
+	runs, err = models.ListRun(logger.Logger(), 0, 0, []string{}, []string{}, []string{}, []string{}, []string{}, []string{schedID})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(runs))
+	for _, run := range runs {
+		assert.NotEqual(t, common.StatusRunSkipped, run.Status)
+	}
+
+	schedule, err = models.GetSchedule(logEntry, schedID)
+	assert.Nil(t, err)
+	assert.False(t, schedule.BackfillNextAt.Valid)
+	assert.False(t, schedule.BackfillEndAt.Valid)
+
+	nextWakeupTime, err = models.GetNextGlobalWakeupTime(logEntry)
+	assert.Nil(t, err)
+	assert.NotNil(t, nextWakeupTime)
+	assert.True(t, nextWakeupTime.After(time.Now()))
+
+	// schedule 停止后，补数据被取消
+	schedule.Pk = 0
+	schedule.ID = ""
+	schedule.Name = "schedule4"
+	schedule.Status = models.ScheduleStatusTerminated
+	schedule.BackfillNextAt = sql.NullTime{Time: backfillStartAt, Valid: true}
+	schedule.BackfillEndAt = sql.NullTime{Time: backfillEndAt, Valid: true}
+	schedID, err = models.CreateSchedule(logEntry, schedule)
+	assert.Nil(t, err)
+
+	_, err = scheduler.dealWithTimeout()
+	assert.Nil(t, err)
+	runs, err = models.ListRun(logger.Logger(), 0, 0, []string{}, []string{}, []string{}, []string{}, []string{}, []string{schedID})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(runs))
+	schedule, err = models.GetSchedule(logEntry, schedID)
+	assert.Nil(t, err)
+	assert.False(t, schedule.BackfillNextAt.Valid)
+}
@@ -363,11 +363,6 @@ func CreateQueue(ctx *logger.RequestContext, request *CreateQueueRequest) (Creat
 
 func UpdateQueue(ctx *logger.RequestContext, request *UpdateQueueRequest) (UpdateQueueResponse, error) {
 	ctx.Logging().Debugf("begin update request. request:%s", config.PrettyFormat(request))
-	if !common.IsRootUser(ctx.UserName) {
-		ctx.ErrorCode = common.OnlyRootAllowed
-		ctx.Logging().Errorln("update request failed. error: admin is needed.")
-		return UpdateQueueResponse{}, errors.New("update request failed")
-	}
 	// check queue name
 	if request.Name == "" {
 		ctx.ErrorCode = common.QueueNameNotFound
@@ -572,12 +567,6 @@ func validateQueueResource(rResource schema.ResourceInfo, qResource *resources.R
 func GetQueueByName(ctx *logger.RequestContext, queueName string) (GetQueueResponse, error) {
 	ctx.Logging().Debugf("begin get queue by name. queueName:%s", queueName)
 
-	if !storage.Auth.HasAccessToResource(ctx, common.ResourceTypeQueue, queueName) {
-		ctx.ErrorCode = common.ActionNotAllowed
-		ctx.Logging().Errorf("get queueName[%s] failed. error: access denied.", queueName)
-		return GetQueueResponse{}, fmt.Errorf("get queueName[%s] failed.\n", queueName)
-	}
-
 	queue, err := storage.Queue.GetQueueByName(queueName)
 	if err != nil {
 		ctx.ErrorCode = common.QueueNameNotFound
@@ -627,12 +616,6 @@ func GetQueueByName(ctx *logger.RequestContext, queueName string) (GetQueueRespo
 
 func DeleteQueue(ctx *logger.RequestContext, queueName string) error {
 	ctx.Logging().Debugf("begin delete queue. queueName:%s", queueName)
-	if !common.IsRootUser(ctx.UserName) {
-		ctx.ErrorCode = common.OnlyRootAllowed
-		ctx.Logging().Errorln("delete queue failed. error: admin is needed.")
-		return errors.New("delete queue failed")
-	}
-
 	queue, err := storage.Queue.GetQueueByName(queueName)
 	if err != nil {
 		ctx.ErrorCode = common.QueueNameNotFound
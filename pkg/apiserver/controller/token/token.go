@@ -0,0 +1,198 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package token
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/middleware"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+)
+
+const maxTokenNameLength = 128
+
+type CreateTokenRequest struct {
+	Name string `json:"name"`
+	// UserName 仅root可以为其他用户创建token, 为空时为当前用户创建
+	UserName string   `json:"userName"`
+	Scopes   []string `json:"scopes"`
+	// ExpiresInHours token有效期, 为0时永不过期, 可以通过删除token撤销
+	ExpiresInHours int `json:"expiresInHours"`
+}
+
+type CreateTokenResponse struct {
+	TokenID string `json:"tokenID"`
+	// Token 仅在创建时返回
+	Token      string     `json:"token"`
+	ExpireTime *time.Time `json:"expireTime,omitempty"`
+}
+
+type ListTokenResponse struct {
+	common.MarkerInfo
+	TokenList []model.AccessToken `json:"tokenList"`
+}
+
+func CreateToken(ctx *logger.RequestContext, request CreateTokenRequest) (*CreateTokenResponse, error) {
+	ctx.Logging().Debugf("begin create access token. request: %v.", request)
+	if request.UserName == "" {
+		request.UserName = ctx.UserName
+	}
+	if request.UserName != ctx.UserName && !common.IsRootUser(ctx.UserName) {
+		ctx.ErrorCode = common.OnlyRootAllowed
+		ctx.Logging().Errorf("create access token of user[%s] failed. root is needed.", request.UserName)
+		return nil, errors.New("create access token failed")
+	}
+	if len(request.Name) > maxTokenNameLength {
+		ctx.ErrorCode = common.InvalidNamePattern
+		err := fmt.Errorf("name of access token is longer than %d", maxTokenNameLength)
+		ctx.Logging().Errorf("create access token failed. error: %v", err)
+		return nil, err
+	}
+	if err := common.ValidateScopes(request.Scopes); err != nil {
+		ctx.ErrorCode = common.InvalidAccessTokenScope
+		ctx.Logging().Errorf("create access token failed. error: %v", err)
+		return nil, err
+	}
+	if request.ExpiresInHours < 0 {
+		ctx.ErrorCode = common.InvalidHTTPRequest
+		err := fmt.Errorf("expiresInHours %d is negative", request.ExpiresInHours)
+		ctx.Logging().Errorf("create access token failed. error: %v", err)
+		return nil, err
+	}
+	if _, err := storage.Auth.GetUserByName(ctx, request.UserName); err != nil {
+		ctx.ErrorCode = common.UserNotExist
+		ctx.Logging().Errorf("create access token failed. user[%s] not exist.", request.UserName)
+		return nil, err
+	}
+
+	accessToken := &model.AccessToken{
+		Name:     request.Name,
+		UserName: request.UserName,
+		Scopes:   request.Scopes,
+	}
+	if request.ExpiresInHours > 0 {
+		expireTime := time.Now().Add(time.Duration(request.ExpiresInHours) * time.Hour)
+		accessToken.ExpiresAt = &expireTime
+	}
+	if err := storage.Auth.CreateAccessToken(ctx, accessToken); err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("create access token failed. error: %v", err)
+		return nil, err
+	}
+	token, err := middleware.GenerateAccessToken(accessToken)
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("generate access token failed. error: %v", err)
+		return nil, err
+	}
+	return &CreateTokenResponse{
+		TokenID:    accessToken.ID,
+		Token:      token,
+		ExpireTime: accessToken.ExpiresAt,
+	}, nil
+}
+
+// DeleteToken 删除access token, 使用该token的请求立即失效
+func DeleteToken(ctx *logger.RequestContext, tokenID string) error {
+	ctx.Logging().Debugf("begin delete access token. tokenID:%s.", tokenID)
+	accessToken, err := storage.Auth.GetAccessToken(ctx, tokenID)
+	if err != nil {
+		ctx.ErrorCode = common.AccessTokenNotFound
+		ctx.Logging().Errorf("delete access token failed. tokenID[%s] not found.", tokenID)
+		return err
+	}
+	if accessToken.UserName != ctx.UserName && !common.IsRootUser(ctx.UserName) {
+		ctx.ErrorCode = common.AccessDenied
+		ctx.Logging().Errorf("delete access token failed. user[%s] has no access to tokenID[%s].",
+			ctx.UserName, tokenID)
+		return errors.New("delete access token failed")
+	}
+	if err := storage.Auth.DeleteAccessToken(ctx, tokenID); err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("delete access token failed. tokenID:%s, error: %v", tokenID, err)
+		return err
+	}
+	return nil
+}
+
+// ListToken 列出用户的access token, root未指定用户时列出所有用户的token
+func ListToken(ctx *logger.RequestContext, marker string, maxKeys int, userName string) (ListTokenResponse, error) {
+	ctx.Logging().Debugf("begin list access tokens. user:[%s].", userName)
+	listTokenResponse := ListTokenResponse{}
+	listTokenResponse.IsTruncated = false
+	listTokenResponse.TokenList = []model.AccessToken{}
+
+	if !common.IsRootUser(ctx.UserName) {
+		if userName != "" && userName != ctx.UserName {
+			ctx.ErrorCode = common.OnlyRootAllowed
+			ctx.Logging().Errorf("list user[%s]'s access tokens failed. root is needed.", userName)
+			return listTokenResponse, errors.New("list access tokens failed")
+		}
+		userName = ctx.UserName
+	}
+
+	var pk int64
+	var err error
+	if marker != "" {
+		pk, err = common.DecryptPk(marker)
+		if err != nil {
+			ctx.Logging().Errorf("DecryptPk marker[%s] failed. err:[%s]",
+				marker, err.Error())
+			ctx.ErrorCode = common.InvalidMarker
+			return listTokenResponse, err
+		}
+	}
+
+	tokenList, err := storage.Auth.ListAccessToken(ctx, pk, maxKeys, userName)
+	if err != nil {
+		ctx.Logging().Errorf("models list access token failed. err:[%s]", err.Error())
+		ctx.ErrorCode = common.InternalError
+		return listTokenResponse, err
+	}
+
+	// get next marker
+	if len(tokenList) > 0 {
+		accessToken := tokenList[len(tokenList)-1]
+		if !IsLastTokenPk(ctx, accessToken.Pk, userName) {
+			nextMarker, err := common.EncryptPk(accessToken.Pk)
+			if err != nil {
+				ctx.Logging().Errorf("EncryptPk error. pk:[%d] error:[%s]",
+					accessToken.Pk, err.Error())
+				ctx.ErrorCode = common.InternalError
+				return listTokenResponse, err
+			}
+			listTokenResponse.NextMarker = nextMarker
+			listTokenResponse.IsTruncated = true
+		}
+	}
+	listTokenResponse.MaxKeys = maxKeys
+	listTokenResponse.TokenList = append(listTokenResponse.TokenList, tokenList...)
+	return listTokenResponse, nil
+}
+
+func IsLastTokenPk(ctx *logger.RequestContext, pk int64, userName string) bool {
+	lastToken, err := storage.Auth.GetLastAccessToken(ctx, userName)
+	if err != nil {
+		ctx.Logging().Errorf("get last access token failed. error:[%s]", err.Error())
+	}
+	return lastToken.Pk == pk
+}
@@ -0,0 +1,125 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/driver"
+)
+
+const (
+	MockRootUser  = "root"
+	MockUserName  = "user1"
+	MockUserName2 = "user2"
+)
+
+func mockUsers(t *testing.T) {
+	driver.InitMockDB()
+	ctx := &logger.RequestContext{UserName: MockRootUser}
+	for _, name := range []string{MockRootUser, MockUserName, MockUserName2} {
+		assert.Nil(t, storage.Auth.CreateUser(ctx, &model.User{
+			UserInfo: model.UserInfo{Name: name, Password: "fake"},
+		}))
+	}
+}
+
+func TestCreateToken(t *testing.T) {
+	mockUsers(t)
+	ctx := &logger.RequestContext{UserName: MockUserName}
+
+	// invalid scopes
+	for _, scopes := range [][]string{nil, {"job"}, {"token:read"}, {"job:delete"}} {
+		ctx.ErrorCode = ""
+		_, err := CreateToken(ctx, CreateTokenRequest{Scopes: scopes})
+		assert.NotNil(t, err)
+		assert.Equal(t, common.InvalidAccessTokenScope, ctx.ErrorCode)
+	}
+
+	// only root can create token for other users
+	_, err := CreateToken(ctx, CreateTokenRequest{UserName: MockUserName2, Scopes: []string{"job:read"}})
+	assert.NotNil(t, err)
+	assert.Equal(t, common.OnlyRootAllowed, ctx.ErrorCode)
+
+	response, err := CreateToken(ctx, CreateTokenRequest{
+		Name:           "ci",
+		Scopes:         []string{"job:write", "*:read"},
+		ExpiresInHours: 24,
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, response.Token)
+	assert.NotNil(t, response.ExpireTime)
+	accessToken, err := storage.Auth.GetAccessToken(ctx, response.TokenID)
+	assert.Nil(t, err)
+	assert.Equal(t, MockUserName, accessToken.UserName)
+	assert.Equal(t, []string{"job:write", "*:read"}, accessToken.Scopes)
+
+	rootCtx := &logger.RequestContext{UserName: MockRootUser}
+	response, err = CreateToken(rootCtx, CreateTokenRequest{UserName: MockUserName2, Scopes: []string{"*:write"}})
+	assert.Nil(t, err)
+	assert.Nil(t, response.ExpireTime)
+}
+
+func TestListAndDeleteToken(t *testing.T) {
+	mockUsers(t)
+	ctx1 := &logger.RequestContext{UserName: MockUserName}
+	ctx2 := &logger.RequestContext{UserName: MockUserName2}
+	rootCtx := &logger.RequestContext{UserName: MockRootUser}
+	var tokenIDs []string
+	for _, ctx := range []*logger.RequestContext{ctx1, ctx1, ctx2} {
+		response, err := CreateToken(ctx, CreateTokenRequest{Scopes: []string{"job:read"}})
+		assert.Nil(t, err)
+		tokenIDs = append(tokenIDs, response.TokenID)
+	}
+
+	response, err := ListToken(ctx1, "", 1, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(response.TokenList))
+	assert.True(t, response.IsTruncated)
+	response, err = ListToken(ctx1, response.NextMarker, 1, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(response.TokenList))
+	assert.Equal(t, tokenIDs[1], response.TokenList[0].ID)
+	assert.False(t, response.IsTruncated)
+
+	_, err = ListToken(ctx1, "", 50, MockUserName2)
+	assert.NotNil(t, err)
+	assert.Equal(t, common.OnlyRootAllowed, ctx1.ErrorCode)
+	response, err = ListToken(rootCtx, "", 50, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(response.TokenList))
+
+	// user can not delete token of others
+	err = DeleteToken(ctx1, tokenIDs[2])
+	assert.NotNil(t, err)
+	assert.Equal(t, common.AccessDenied, ctx1.ErrorCode)
+	assert.Nil(t, DeleteToken(ctx1, tokenIDs[0]))
+	assert.Nil(t, DeleteToken(rootCtx, tokenIDs[2]))
+	err = DeleteToken(ctx1, tokenIDs[0])
+	assert.NotNil(t, err)
+	assert.Equal(t, common.AccessTokenNotFound, ctx1.ErrorCode)
+
+	response, err = ListToken(rootCtx, "", 50, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(response.TokenList))
+}
@@ -205,6 +205,16 @@ func DeleteUser(ctx *logger.RequestContext, userName string) error {
 		ctx.Logging().Errorf("models delete user failed. delete user's grant  error:%s", err.Error())
 		return err
 	}
+	if err := storage.Auth.DeleteGroupMemberByUserName(ctx, userName); err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("models delete user failed. delete user's group member error:%s", err.Error())
+		return err
+	}
+	if err := storage.Auth.DeleteAccessTokenByUserName(ctx, userName); err != nil {
+		ctx.ErrorCode = common.InternalError
+		ctx.Logging().Errorf("models delete user failed. delete user's access token error:%s", err.Error())
+		return err
+	}
 	return nil
 }
 
@@ -17,12 +17,19 @@ limitations under the License.
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	iofs "io/fs"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/bluele/gcache"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
@@ -34,8 +41,13 @@ const (
 	maxRetryCount = 3
 	// unit is time.Millisecond
 	sleepMillisecond = 100
+
+	fileDigestCacheSize = 100000
 )
 
+// 文件内容摘要的缓存，key 由 fsID、路径、文件大小与 modtime 组成，文件未被修改时无需再次读取内容
+var fileDigestCache = gcache.New(fileDigestCacheSize).LRU().Build()
+
 type FsServerEmptyError struct {
 }
 
@@ -234,3 +246,72 @@ func (fh *FsHandler) LastModTime(path string) (time.Time, error) {
 		}
 	}
 }
+
+// 获取 path 的内容摘要，path 为目录时，摘要由目录下所有文件的相对路径及其内容摘要计算得到
+func (fh *FsHandler) ContentDigest(path string) (string, error) {
+	ok, err := fh.fsClient.IsDir(path)
+	if err != nil {
+		fh.log.Debugf("cannot get the type of path[%s] with fsId[%s]: %s", path, fh.fsID, err.Error())
+		return "", err
+	} else if !ok {
+		fileInfo, err := fh.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		return fh.fileDigest(path, fileInfo)
+	}
+
+	fileDigests := []string{}
+	err = fh.fsClient.Walk(path, func(filePath string, info iofs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		digest, err := fh.fileDigest(filePath, info)
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(path, filePath)
+		if err != nil {
+			return err
+		}
+		fileDigests = append(fileDigests, fmt.Sprintf("%s:%s", relPath, digest))
+		return nil
+	})
+	if err != nil {
+		fh.log.Debugf("cannot get the content digest of path[%s] with fsId[%s]: %s", path, fh.fsID, err.Error())
+		return "", err
+	}
+
+	sort.Strings(fileDigests)
+	hash := sha256.New()
+	hash.Write([]byte(strings.Join(fileDigests, "\n")))
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (fh *FsHandler) fileDigest(path string, fileInfo os.FileInfo) (string, error) {
+	key := fmt.Sprintf("%s:%s:%d:%d", fh.fsID, path, fileInfo.Size(), fileInfo.ModTime().UnixNano())
+	if value, err := fileDigestCache.Get(key); err == nil {
+		return value.(string), nil
+	}
+
+	reader, err := fh.fsClient.Open(path)
+	if err != nil {
+		fh.log.Errorf("open file[%s] for fsID [%s] failed: %s", path, fh.fsID, err.Error())
+		return "", err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, reader); err != nil {
+		fh.log.Errorf("read the content of file[%s] for fsID [%s] failed: %s", path, fh.fsID, err.Error())
+		return "", err
+	}
+	digest := hex.EncodeToString(hash.Sum(nil))
+	if err = fileDigestCache.Set(key, digest); err != nil {
+		fh.log.Warningf("cache the digest of file[%s] for fsID [%s] failed: %s", path, fh.fsID, err.Error())
+	}
+	return digest, nil
+}
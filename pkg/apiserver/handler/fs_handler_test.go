@@ -184,3 +184,46 @@ func TestLastModTime(t *testing.T) {
 	fi, err := os.Lstat("mock_fs_handler/test_path_time/path_time/time/a.txt")
 	assert.Equal(t, modTime.UnixNano(), fi.ModTime().UnixNano())
 }
+
+func TestContentDigest(t *testing.T) {
+	fsClient, requestContext, err := prepareTestEnv()
+	assert.Equal(t, err, nil)
+
+	fsHandler := FsHandler{
+		fsClient: fsClient,
+		log:      logger.LoggerForRequest(requestContext),
+	}
+
+	fileDigest, err := fsHandler.ContentDigest("run.yaml")
+	assert.Equal(t, nil, err)
+	fileDigest2, err := fsHandler.ContentDigest("test_path_time/path_time/time/a.txt")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, fileDigest, fileDigest2)
+
+	dirDigest, err := fsHandler.ContentDigest("test_path_time")
+	assert.Equal(t, nil, err)
+
+	// 目录内容相同，摘要相同
+	err = os.MkdirAll("./mock_fs_handler/test_path_time2/path_time/time", 0755)
+	assert.Equal(t, nil, err)
+	err = os.WriteFile("./mock_fs_handler/test_path_time2/path_time/time/a.txt", []byte("test paddleflow pipeline"), 0644)
+	assert.Equal(t, nil, err)
+	dirDigest2, err := fsHandler.ContentDigest("test_path_time2")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, dirDigest, dirDigest2)
+
+	// 修改时间变化但内容不变，摘要不变
+	touchTime := time.Now().Add(time.Hour)
+	err = os.Chtimes("./mock_fs_handler/test_path_time2/path_time/time/a.txt", touchTime, touchTime)
+	assert.Equal(t, nil, err)
+	dirDigest3, err := fsHandler.ContentDigest("test_path_time2")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, dirDigest, dirDigest3)
+
+	// 内容变化，摘要变化
+	err = os.WriteFile("./mock_fs_handler/test_path_time2/path_time/time/a.txt", []byte("new content"), 0644)
+	assert.Equal(t, nil, err)
+	dirDigest4, err := fsHandler.ContentDigest("test_path_time2")
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, dirDigest, dirDigest4)
+}
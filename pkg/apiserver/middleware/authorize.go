@@ -0,0 +1,113 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/models"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+)
+
+// Authorize 根据授予用户及其所在用户组的角色, 校验用户对路径参数paramKey指定的资源是否有action权限,
+// 需要在BaseAuth之后使用
+func Authorize(resourceType, action, paramKey string) func(http.Handler) http.Handler {
+	return authorize(resourceType, action, func(ctx *logger.RequestContext, r *http.Request) (string, bool) {
+		return chi.URLParam(r, paramKey), false
+	})
+}
+
+// AuthorizeFs 校验用户对文件系统的action权限, 文件系统由路径参数fsName和查询参数username确定,
+// username为空或为当前用户时访问的是用户自己的文件系统, 不需要授权
+func AuthorizeFs(action string) func(http.Handler) http.Handler {
+	return authorize(common.ResourceTypeFs, action, func(ctx *logger.RequestContext, r *http.Request) (string, bool) {
+		owner := r.URL.Query().Get(util.QueryKeyUserName)
+		if owner == "" {
+			owner = ctx.UserName
+		}
+		return common.ID(owner, chi.URLParam(r, util.QueryFsName)), owner == ctx.UserName
+	})
+}
+
+// AuthorizePipeline 校验用户对路径参数pipelineID指定的pipeline的action权限, pipeline的创建者不需要授权
+func AuthorizePipeline(action string) func(http.Handler) http.Handler {
+	return authorizeOwned(common.ResourceTypePipeline, action, util.ParamKeyPipelineID,
+		func(ctx *logger.RequestContext, id string) (string, error) {
+			ppl, err := storage.Pipeline.GetPipelineByID(id)
+			return ppl.UserName, err
+		})
+}
+
+// AuthorizeRun 校验用户对路径参数runID指定的run的action权限, run的创建者不需要授权
+func AuthorizeRun(action string) func(http.Handler) http.Handler {
+	return authorizeOwned(common.ResourceTypeRun, action, util.ParamKeyRunID,
+		func(ctx *logger.RequestContext, id string) (string, error) {
+			run, err := models.GetRunByID(ctx.Logging(), id)
+			return run.UserName, err
+		})
+}
+
+// AuthorizeSchedule 校验用户对路径参数scheduleID指定的schedule的action权限, schedule的创建者不需要授权
+func AuthorizeSchedule(action string) func(http.Handler) http.Handler {
+	return authorizeOwned(common.ResourceTypeSchedule, action, util.ParamKeyScheduleID,
+		func(ctx *logger.RequestContext, id string) (string, error) {
+			schedule, err := models.GetSchedule(ctx.Logging(), id)
+			return schedule.UserName, err
+		})
+}
+
+// AuthorizeJob 校验用户对路径参数jobID指定的作业的action权限, 作业的创建者不需要授权
+func AuthorizeJob(action string) func(http.Handler) http.Handler {
+	return authorizeOwned(common.ResourceTypeJob, action, util.ParamKeyJobID,
+		func(ctx *logger.RequestContext, id string) (string, error) {
+			job, err := storage.Job.GetJobByID(id)
+			return job.UserName, err
+		})
+}
+
+// authorizeOwned ownerOf返回资源的创建者, 资源不存在时只有root等被授权的用户可以访问, 由handler返回资源不存在的错误
+func authorizeOwned(resourceType, action, paramKey string,
+	ownerOf func(ctx *logger.RequestContext, id string) (string, error)) func(http.Handler) http.Handler {
+	return authorize(resourceType, action, func(ctx *logger.RequestContext, r *http.Request) (string, bool) {
+		resourceID := chi.URLParam(r, paramKey)
+		owner, err := ownerOf(ctx, resourceID)
+		return resourceID, err == nil && owner == ctx.UserName
+	})
+}
+
+// authorize resourceOf返回请求访问的资源ID, 以及当前用户是否为资源所有者, 所有者不需要授权
+func authorize(resourceType, action string,
+	resourceOf func(ctx *logger.RequestContext, r *http.Request) (string, bool)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := common.GetRequestContext(r)
+			resourceID, isOwner := resourceOf(&ctx, r)
+			if !isOwner && !storage.Auth.HasPermission(&ctx, resourceType, resourceID, action) {
+				err := common.NoAccessError(ctx.UserName, resourceType, resourceID)
+				ctx.Logging().Errorf("Authorize deny %s. error: %s", action, err.Error())
+				common.RenderErrWithMessage(w, ctx.RequestID, common.AccessDenied, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/driver"
+)
+
+func TestAuthorize(t *testing.T) {
+	driver.InitMockDB()
+	ctx := &logger.RequestContext{UserName: "root"}
+	assert.Nil(t, storage.Auth.CreateGrant(ctx, &model.Grant{UserName: "viewer1", ResourceType: common.ResourceTypeQueue,
+		ResourceID: "q1", Role: common.RoleViewer}))
+	assert.Nil(t, storage.Auth.CreateGrant(ctx, &model.Grant{UserName: "admin1", ResourceType: common.ResourceTypeQueue,
+		ResourceID: "q1", Role: common.RoleAdmin}))
+	assert.Nil(t, storage.Auth.CreateGrant(ctx, &model.Grant{UserName: "admin1", ResourceType: common.ResourceTypeFs,
+		ResourceID: common.ID("user1", "fs1"), Role: common.RoleAdmin}))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	r := chi.NewRouter()
+	r.With(Authorize(common.ResourceTypeQueue, common.ActionRead, "queueName")).Get("/queue/{queueName}", ok)
+	r.With(Authorize(common.ResourceTypeQueue, common.ActionWrite, "queueName")).Put("/queue/{queueName}", ok)
+	r.With(AuthorizeFs(common.ActionWrite)).Delete("/fs/{fsName}", ok)
+
+	cases := []struct {
+		user   string
+		method string
+		url    string
+		code   int
+	}{
+		{"root", http.MethodPut, "/queue/q1", http.StatusOK},
+		{"viewer1", http.MethodGet, "/queue/q1", http.StatusOK},
+		{"viewer1", http.MethodPut, "/queue/q1", http.StatusForbidden},
+		{"viewer1", http.MethodGet, "/queue/q2", http.StatusForbidden},
+		{"admin1", http.MethodPut, "/queue/q1", http.StatusOK},
+		// owner of file system needs no grant
+		{"user1", http.MethodDelete, "/fs/fs1", http.StatusOK},
+		{"admin1", http.MethodDelete, "/fs/fs1?username=user1", http.StatusOK},
+		{"viewer1", http.MethodDelete, "/fs/fs1?username=user1", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.url, nil)
+		req.Header.Set(common.HeaderKeyUserName, c.user)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, c.code, w.Code, "%s %s %s", c.user, c.method, c.url)
+	}
+}
+
+func TestAuthorizeOwned(t *testing.T) {
+	driver.InitMockDB()
+	ctx := &logger.RequestContext{UserName: "root"}
+	pplID, _, err := storage.Pipeline.CreatePipeline(ctx.Logging(), &model.Pipeline{Name: "ppl1", UserName: "user1"},
+		&model.PipelineVersion{FsName: "fs1", YamlPath: "run.yaml", UserName: "user1"})
+	assert.Nil(t, err)
+	assert.Nil(t, storage.Job.CreateJob(&model.Job{ID: "job-000001", UserName: "user1"}))
+	assert.Nil(t, storage.Auth.CreateGrant(ctx, &model.Grant{UserName: "viewer1", ResourceType: common.ResourceTypePipeline,
+		ResourceID: pplID, Role: common.RoleViewer}))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	r := chi.NewRouter()
+	r.With(AuthorizePipeline(common.ActionRead)).Get("/pipeline/{pipelineID}", ok)
+	r.With(AuthorizePipeline(common.ActionWrite)).Delete("/pipeline/{pipelineID}", ok)
+	r.With(AuthorizeJob(common.ActionWrite)).Delete("/job/{jobID}", ok)
+
+	cases := []struct {
+		user   string
+		method string
+		url    string
+		code   int
+	}{
+		{"user1", http.MethodDelete, "/pipeline/" + pplID, http.StatusOK},
+		{"root", http.MethodDelete, "/pipeline/" + pplID, http.StatusOK},
+		{"viewer1", http.MethodGet, "/pipeline/" + pplID, http.StatusOK},
+		{"viewer1", http.MethodDelete, "/pipeline/" + pplID, http.StatusForbidden},
+		{"user2", http.MethodGet, "/pipeline/" + pplID, http.StatusForbidden},
+		// resource not found, left to handler for root user
+		{"root", http.MethodGet, "/pipeline/ppl-999999", http.StatusOK},
+		{"user1", http.MethodGet, "/pipeline/ppl-999999", http.StatusForbidden},
+		{"user1", http.MethodDelete, "/job/job-000001", http.StatusOK},
+		{"user2", http.MethodDelete, "/job/job-000001", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.url, nil)
+		req.Header.Set(common.HeaderKeyUserName, c.user)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, c.code, w.Code, "%s %s %s", c.user, c.method, c.url)
+	}
+}
@@ -20,8 +20,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -29,74 +31,172 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
-	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/user"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
 )
 
+// JWT 签发和校验token, token头部的kid指定校验使用的密钥, 以支持密钥轮换
 type JWT struct {
-	Sigkey []byte
+	signingKeyID string
+	keys         map[string][]byte
 }
 
-const SECERTKEY = "its my precious"
-const UserNameParam = "userName"
+const (
+	// DefaultKeyID 未配置密钥时使用的密钥ID, 没有kid的旧token也使用该ID对应的密钥校验
+	DefaultKeyID = "default"
+	// defaultSigningKey 仅用于兼容未配置密钥的部署, 该密钥是公开的, 生产环境必须配置jwt密钥
+	defaultSigningKey = "its my precious"
+	UserNameParam     = "userName"
+
+	routerPrefixV1 = "/api/paddleflow/v1"
+)
 
 var jwtObj *JWT
 
 func init() {
 	jwtObj = &JWT{
-		Sigkey: []byte(SECERTKEY),
+		signingKeyID: DefaultKeyID,
+		keys:         map[string][]byte{DefaultKeyID: []byte(defaultSigningKey)},
+	}
+}
+
+// InitJWT 根据配置加载jwt密钥, 未配置密钥时使用默认密钥
+func InitJWT(conf *config.JWTConfig) error {
+	j, err := NewJWT(conf)
+	if err != nil {
+		return err
+	}
+	jwtObj = j
+	return nil
+}
+
+func NewJWT(conf *config.JWTConfig) (*JWT, error) {
+	if conf == nil || len(conf.Keys) == 0 {
+		log.Warningf("jwt keys are not configured, tokens are signed by the default key which is not secure")
+		return &JWT{
+			signingKeyID: DefaultKeyID,
+			keys:         map[string][]byte{DefaultKeyID: []byte(defaultSigningKey)},
+		}, nil
+	}
+	j := &JWT{
+		signingKeyID: conf.SigningKeyID,
+		keys:         make(map[string][]byte, len(conf.Keys)),
+	}
+	for _, key := range conf.Keys {
+		if key.ID == "" {
+			return nil, fmt.Errorf("id of jwt key is empty")
+		}
+		if _, ok := j.keys[key.ID]; ok {
+			return nil, fmt.Errorf("jwt key %s is duplicated", key.ID)
+		}
+		secret := key.Secret
+		if key.SecretFile != "" {
+			content, err := os.ReadFile(key.SecretFile)
+			if err != nil {
+				return nil, fmt.Errorf("read secret of jwt key %s failed: %v", key.ID, err)
+			}
+			secret = strings.TrimSpace(string(content))
+		}
+		if secret == "" {
+			return nil, fmt.Errorf("secret of jwt key %s is empty", key.ID)
+		}
+		j.keys[key.ID] = []byte(secret)
+	}
+	// 未指定签名密钥时, 使用第一个密钥签名
+	if j.signingKeyID == "" {
+		j.signingKeyID = conf.Keys[0].ID
 	}
+	if _, ok := j.keys[j.signingKeyID]; !ok {
+		return nil, fmt.Errorf("signing key %s is not found in jwt keys", j.signingKeyID)
+	}
+	return j, nil
 }
 
+// PaddleFlowClaims token的声明, 登录token只包含用户名, access token还包含token ID和权限范围
 type PaddleFlowClaims struct {
 	UserName string `json:"username"`
-	Password string `json:"password"`
+	// Password 仅存在于旧版本签发的token中, 新签发的token不再包含密码
+	Password string   `json:"password,omitempty"`
+	TokenID  string   `json:"tid,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
 	jwtgo.StandardClaims
 }
 
 func (j *JWT) CreateToken(claim PaddleFlowClaims) (string, error) {
 	token := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, claim)
-	return token.SignedString(j.Sigkey)
+	token.Header["kid"] = j.signingKeyID
+	return token.SignedString(j.keys[j.signingKeyID])
 }
 
 func (j *JWT) ParseToken(tokenString string) (*PaddleFlowClaims, error) {
 	token, err := jwtgo.ParseWithClaims(tokenString,
 		&PaddleFlowClaims{},
 		func(token *jwtgo.Token) (interface{}, error) {
-			return j.Sigkey, nil
+			if _, ok := token.Method.(*jwtgo.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+			}
+			kid, _ := token.Header["kid"].(string)
+			if kid == "" {
+				kid = DefaultKeyID
+			}
+			key, ok := j.keys[kid]
+			if !ok {
+				return nil, fmt.Errorf("jwt key %s is not found", kid)
+			}
+			return key, nil
 		})
 	if err != nil {
-		if ve, ok := err.(*jwtgo.ValidationError); ok {
-			if ve.Errors&jwtgo.ValidationErrorMalformed != 0 || ve.Errors&jwtgo.ValidationErrorExpired != 0 ||
-				ve.Errors&jwtgo.ValidationErrorNotValidYet != 0 {
-				return nil, errors.New(common.AuthInvalidToken)
-			} else {
-				return nil, errors.New(common.AuthFailed)
-			}
+		log.Debugf("ParseToken failed. error: %v", err)
+		if ve, ok := err.(*jwtgo.ValidationError); ok && ve.Errors&(jwtgo.ValidationErrorMalformed|
+			jwtgo.ValidationErrorExpired|jwtgo.ValidationErrorNotValidYet|jwtgo.ValidationErrorUnverifiable) == 0 {
+			return nil, errors.New(common.AuthFailed)
 		}
+		return nil, errors.New(common.AuthInvalidToken)
 	}
 	if claims, ok := token.Claims.(*PaddleFlowClaims); ok && token.Valid {
-		log.Debugf("ParseToken succeed. token:[%s] userName:[%s]", tokenString, claims.UserName)
+		log.Debugf("ParseToken succeed. userName:[%s] tokenID:[%s]", claims.UserName, claims.TokenID)
 		return claims, nil
 	}
 	return nil, errors.New(common.AuthInvalidToken)
 }
 
-func GenerateToken(userName string, password string) (string, error) {
-	log.Debugf("GenerateToken userName:[%s] password:[%s]", userName, password)
+// GenerateToken 生成登录token, 用户修改密码后, 之前签发的登录token失效
+func GenerateToken(userName string) (string, error) {
+	log.Debugf("GenerateToken userName:[%s]", userName)
 	claim := &PaddleFlowClaims{
 		UserName: userName,
-		Password: password,
 	}
 	if config.GlobalServerConfig.ApiServer.TokenExpirationHour != -1 {
 		expire := time.Now().Add(time.Duration(config.GlobalServerConfig.ApiServer.TokenExpirationHour) * time.Hour)
 		claim.ExpiresAt = expire.Unix()
 	}
-	claim.NotBefore = int64(time.Now().Unix()) - 1000
+	return signToken(claim)
+}
+
+// GenerateAccessToken 为已保存的access token生成token, 有效期和权限范围以数据库中的记录为准
+func GenerateAccessToken(accessToken *model.AccessToken) (string, error) {
+	log.Debugf("GenerateAccessToken userName:[%s] tokenID:[%s]", accessToken.UserName, accessToken.ID)
+	claim := &PaddleFlowClaims{
+		UserName: accessToken.UserName,
+		TokenID:  accessToken.ID,
+		Scopes:   accessToken.Scopes,
+	}
+	if accessToken.ExpiresAt != nil {
+		claim.ExpiresAt = accessToken.ExpiresAt.Unix()
+	}
+	return signToken(claim)
+}
+
+func signToken(claim *PaddleFlowClaims) (string, error) {
+	now := time.Now().Unix()
+	claim.IssuedAt = now
+	claim.NotBefore = now - 1000
 	claim.Issuer = "paddleflow"
 	token, err := jwtObj.CreateToken(*claim)
 	if err != nil {
+		log.Errorf("sign token failed. error: %v", err)
 		return "", errors.New(common.InternalError)
 	}
 	return token, nil
@@ -132,13 +232,22 @@ func BaseAuth(next http.Handler) http.Handler {
 			common.RenderErr(res, requestID, common.AuthIllegalUser)
 			return
 		}
-		_, err = user.Login(&ctx, claims.UserName, claims.Password, true)
+		scopes, err := verifyClaims(&ctx, claims)
 		if err != nil {
 			ctx.Logging().Errorf(
-				"BaseAuth user verify error. UserName:[%s]", claims.UserName)
+				"BaseAuth user verify error. UserName:[%s], error: %v", claims.UserName, err)
 			common.RenderErr(res, requestID, ctx.ErrorCode)
 			return
 		}
+		if claims.TokenID != "" {
+			resource := common.ScopeResourceOfPath(strings.TrimPrefix(req.URL.Path, routerPrefixV1))
+			if !common.ScopesAllow(scopes, resource, isWriteMethod(req.Method)) {
+				ctx.Logging().Errorf("BaseAuth access token[%s] scopes %v deny %s %s",
+					claims.TokenID, scopes, req.Method, req.URL.Path)
+				common.RenderErr(res, requestID, common.AuthScopeDenied)
+				return
+			}
+		}
 
 		ctx.Logging().Debugf("BaseAuth add user-name[%s]", claims.UserName)
 		req.Header.Set(common.HeaderKeyUserName, claims.UserName)
@@ -146,6 +255,42 @@ func BaseAuth(next http.Handler) http.Handler {
 	})
 }
 
+// verifyClaims 校验token对应的用户, access token还需要校验是否已被删除或过期, 返回access token的权限范围
+func verifyClaims(ctx *logger.RequestContext, claims *PaddleFlowClaims) ([]string, error) {
+	var scopes []string
+	if claims.TokenID != "" {
+		accessToken, err := storage.Auth.GetAccessToken(ctx, claims.TokenID)
+		if err != nil || accessToken.UserName != claims.UserName || accessToken.IsExpired() {
+			ctx.ErrorCode = common.AuthInvalidToken
+			return nil, fmt.Errorf("access token %s is revoked or expired", claims.TokenID)
+		}
+		scopes = accessToken.Scopes
+	}
+	u, err := storage.Auth.GetUserByName(ctx, claims.UserName)
+	if err != nil {
+		ctx.ErrorCode = common.UserNotExist
+		return nil, err
+	}
+	if claims.TokenID == "" {
+		if claims.IssuedAt == 0 {
+			// 旧版本签发的token包含加密后的密码, 密码修改后失效
+			if claims.Password != u.Password {
+				ctx.ErrorCode = common.AuthFailed
+				return nil, errors.New(common.AuthFailed)
+			}
+		} else if claims.IssuedAt < u.UpdatedAt.Unix() {
+			// 用户修改密码后, 之前签发的登录token失效
+			ctx.ErrorCode = common.AuthInvalidToken
+			return nil, fmt.Errorf("token is issued before password of user %s changed", claims.UserName)
+		}
+	}
+	return scopes, nil
+}
+
+func isWriteMethod(method string) bool {
+	return method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions
+}
+
 type request struct {
 	UserName string `json:"userName"`
 }
@@ -0,0 +1,173 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/driver"
+)
+
+const (
+	mockUserName = "user1"
+	mockPassword = "encoded-password"
+)
+
+func TestNewJWT(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	assert.Nil(t, os.WriteFile(secretFile, []byte("secret-from-file\n"), 0600))
+
+	_, err := NewJWT(&config.JWTConfig{Keys: []config.JWTKey{{Secret: "s1"}}})
+	assert.NotNil(t, err)
+	_, err = NewJWT(&config.JWTConfig{Keys: []config.JWTKey{{ID: "k1"}}})
+	assert.NotNil(t, err)
+	_, err = NewJWT(&config.JWTConfig{Keys: []config.JWTKey{{ID: "k1", Secret: "s1"}, {ID: "k1", Secret: "s2"}}})
+	assert.NotNil(t, err)
+	_, err = NewJWT(&config.JWTConfig{SigningKeyID: "k2", Keys: []config.JWTKey{{ID: "k1", Secret: "s1"}}})
+	assert.NotNil(t, err)
+
+	j, err := NewJWT(&config.JWTConfig{Keys: []config.JWTKey{{ID: "k1", SecretFile: secretFile}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "k1", j.signingKeyID)
+	assert.Equal(t, []byte("secret-from-file"), j.keys["k1"])
+
+	j, err = NewJWT(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultKeyID, j.signingKeyID)
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	claims := PaddleFlowClaims{UserName: mockUserName}
+	oldJWT, err := NewJWT(&config.JWTConfig{Keys: []config.JWTKey{{ID: "k1", Secret: "s1"}}})
+	assert.Nil(t, err)
+	oldToken, err := oldJWT.CreateToken(claims)
+	assert.Nil(t, err)
+
+	// sign with new key, old key is kept for verification
+	rotatedJWT, err := NewJWT(&config.JWTConfig{
+		SigningKeyID: "k2",
+		Keys:         []config.JWTKey{{ID: "k1", Secret: "s1"}, {ID: "k2", Secret: "s2"}},
+	})
+	assert.Nil(t, err)
+	newToken, err := rotatedJWT.CreateToken(claims)
+	assert.Nil(t, err)
+	for _, token := range []string{oldToken, newToken} {
+		parsed, err := rotatedJWT.ParseToken(token)
+		assert.Nil(t, err)
+		assert.Equal(t, mockUserName, parsed.UserName)
+	}
+
+	// old key is removed
+	newJWT, err := NewJWT(&config.JWTConfig{Keys: []config.JWTKey{{ID: "k2", Secret: "s2"}}})
+	assert.Nil(t, err)
+	_, err = newJWT.ParseToken(oldToken)
+	assert.Equal(t, common.AuthInvalidToken, err.Error())
+	_, err = newJWT.ParseToken(newToken)
+	assert.Nil(t, err)
+
+	// token without kid is verified by the default key
+	legacyToken, err := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, claims).SignedString([]byte(defaultSigningKey))
+	assert.Nil(t, err)
+	defaultJWT, err := NewJWT(nil)
+	assert.Nil(t, err)
+	_, err = defaultJWT.ParseToken(legacyToken)
+	assert.Nil(t, err)
+	_, err = newJWT.ParseToken(legacyToken)
+	assert.NotNil(t, err)
+}
+
+func performAuthRequest(method, path, token string) *httptest.ResponseRecorder {
+	handler := BaseAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(method, routerPrefixV1+path, nil)
+	req.Header.Set(common.HeaderKeyAuthorization, token)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestBaseAuth(t *testing.T) {
+	driver.InitMockDB()
+	config.GlobalServerConfig = &config.ServerConfig{
+		ApiServer: config.ApiServerConfig{
+			TokenExpirationHour: -1,
+		},
+	}
+	assert.Nil(t, InitJWT(&config.JWTConfig{Keys: []config.JWTKey{{ID: "k1", Secret: "s1"}}}))
+	ctx := &logger.RequestContext{UserName: mockUserName}
+	// user is updated before tokens are issued
+	updatedAt := time.Now().Add(-time.Hour)
+	assert.Nil(t, storage.Auth.CreateUser(ctx, &model.User{
+		CreatedAt: updatedAt,
+		UpdatedAt: updatedAt,
+		UserInfo:  model.UserInfo{Name: mockUserName, Password: mockPassword},
+	}))
+
+	token, err := GenerateToken(mockUserName)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, performAuthRequest(http.MethodGet, "/job", token).Code)
+	assert.Equal(t, http.StatusOK, performAuthRequest(http.MethodGet, "/token", token).Code)
+
+	// access token only allows operations in scopes
+	accessToken := &model.AccessToken{UserName: mockUserName, Scopes: []string{"job:read", "queue:write"}}
+	assert.Nil(t, storage.Auth.CreateAccessToken(ctx, accessToken))
+	token, err = GenerateAccessToken(accessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, performAuthRequest(http.MethodGet, "/job/job-1", token).Code)
+	assert.Equal(t, http.StatusOK, performAuthRequest(http.MethodGet, "/version", token).Code)
+	assert.Equal(t, http.StatusOK, performAuthRequest(http.MethodGet, "/queue", token).Code)
+	assert.Equal(t, http.StatusOK, performAuthRequest(http.MethodDelete, "/queue/q1", token).Code)
+	assert.Equal(t, http.StatusForbidden, performAuthRequest(http.MethodPost, "/job", token).Code)
+	assert.Equal(t, http.StatusForbidden, performAuthRequest(http.MethodGet, "/run", token).Code)
+	assert.Equal(t, http.StatusForbidden, performAuthRequest(http.MethodGet, "/token", token).Code)
+
+	// deleted access token is revoked
+	assert.Nil(t, storage.Auth.DeleteAccessToken(ctx, accessToken.ID))
+	assert.Equal(t, http.StatusBadRequest, performAuthRequest(http.MethodGet, "/job", token).Code)
+
+	// expired access token
+	expiresAt := time.Now().Add(-time.Hour)
+	accessToken = &model.AccessToken{UserName: mockUserName, Scopes: []string{"*:read"}, ExpiresAt: &expiresAt}
+	assert.Nil(t, storage.Auth.CreateAccessToken(ctx, accessToken))
+	claims := PaddleFlowClaims{UserName: mockUserName, TokenID: accessToken.ID}
+	token, err = jwtObj.CreateToken(claims)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, performAuthRequest(http.MethodGet, "/job", token).Code)
+
+	// login token issued before password changed is invalid
+	claims = PaddleFlowClaims{UserName: mockUserName}
+	claims.IssuedAt = time.Now().Add(-time.Minute).Unix()
+	token, err = jwtObj.CreateToken(claims)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, performAuthRequest(http.MethodGet, "/job", token).Code)
+	assert.Nil(t, storage.Auth.UpdateUser(ctx, mockUserName, "new-password"))
+	assert.Equal(t, http.StatusBadRequest, performAuthRequest(http.MethodGet, "/job", token).Code)
+}
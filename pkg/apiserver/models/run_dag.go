@@ -102,7 +102,8 @@ func (rd *RunDag) Trans2DagView() schema.DagView {
 		newParameters[k] = v
 	}
 	newEndTime := ""
-	if rd.Status == schema.StatusJobCancelled || rd.Status == schema.StatusJobFailed || rd.Status == schema.StatusJobSucceeded || rd.Status == schema.StatusJobSkipped {
+	if rd.Status == schema.StatusJobCancelled || rd.Status == schema.StatusJobFailed || rd.Status == schema.StatusJobSucceeded || rd.Status == schema.StatusJobSkipped ||
+		rd.Status == schema.StatusJobTimeout {
 		newEndTime = rd.UpdateTime
 	}
 	return schema.DagView{
@@ -30,36 +30,38 @@ import (
 )
 
 type RunJob struct {
-	Pk             int64             `gorm:"primaryKey;autoIncrement;not null"  json:"-"`
-	ID             string            `gorm:"type:varchar(60);not null"          json:"jobID"`
-	RunID          string            `gorm:"type:varchar(60);not null"          json:"runID"`
-	ParentDagID    string            `gorm:"type:varchar(60);not null"          json:"parentDagID"`
-	Name           string            `gorm:"type:varchar(60);not null"          json:"name"`
-	StepName       string            `gorm:"type:varchar(60);not null"          json:"step_name"`
-	Command        string            `gorm:"type:text;size:65535;not null"      json:"command"`
-	Parameters     map[string]string `gorm:"-"                                  json:"parameters"`
-	ParametersJson string            `gorm:"type:text;size:65535;not null"      json:"-"`
-	Artifacts      schema.Artifacts  `gorm:"-"                                  json:"artifacts"`
-	ArtifactsJson  string            `gorm:"type:text;size:65535;not null"      json:"-"`
-	Env            map[string]string `gorm:"-"                                  json:"env"`
-	EnvJson        string            `gorm:"type:text;size:65535;not null"      json:"-"`
-	DockerEnv      string            `gorm:"type:varchar(128);not null"         json:"docker_env"`
-	LoopSeq        int               `gorm:"type:int;not null"                  json:"-"`
-	Status         schema.JobStatus  `gorm:"type:varchar(32);not null"          json:"status"`
-	Message        string            `gorm:"type:text;size:65535;not null"      json:"message"`
-	Cache          schema.Cache      `gorm:"-"                                  json:"cache"`
-	CacheJson      string            `gorm:"type:text;size:65535;not null"      json:"-"`
-	CacheRunID     string            `gorm:"type:varchar(60);not null"          json:"cacheRunID"`
-	CacheJobID     string            `gorm:"type:varchar(60);not null"          json:"cacheJobID"`
-	ExtraFS        []schema.FsMount  `gorm:"-"                                  json:"extraFs"`
-	ExtraFSJson    string            `gorm:"type:text;size:65535;not null"      json:"-"`
-	CreateTime     string            `gorm:"-"                                  json:"createTime"`
-	ActivateTime   string            `gorm:"-"                                  json:"activateTime"`
-	UpdateTime     string            `gorm:"-"                                  json:"updateTime,omitempty"`
-	CreatedAt      time.Time         `                                          json:"-"`
-	ActivatedAt    sql.NullTime      `                                          json:"-"`
-	UpdatedAt      time.Time         `                                          json:"-"`
-	DeletedAt      gorm.DeletedAt    `gorm:"index"                              json:"-"`
+	Pk             int64               `gorm:"primaryKey;autoIncrement;not null"  json:"-"`
+	ID             string              `gorm:"type:varchar(60);not null"          json:"jobID"`
+	RunID          string              `gorm:"type:varchar(60);not null"          json:"runID"`
+	ParentDagID    string              `gorm:"type:varchar(60);not null"          json:"parentDagID"`
+	Name           string              `gorm:"type:varchar(60);not null"          json:"name"`
+	StepName       string              `gorm:"type:varchar(60);not null"          json:"step_name"`
+	Command        string              `gorm:"type:text;size:65535;not null"      json:"command"`
+	Parameters     map[string]string   `gorm:"-"                                  json:"parameters"`
+	ParametersJson string              `gorm:"type:text;size:65535;not null"      json:"-"`
+	Artifacts      schema.Artifacts    `gorm:"-"                                  json:"artifacts"`
+	ArtifactsJson  string              `gorm:"type:text;size:65535;not null"      json:"-"`
+	Env            map[string]string   `gorm:"-"                                  json:"env"`
+	EnvJson        string              `gorm:"type:text;size:65535;not null"      json:"-"`
+	DockerEnv      string              `gorm:"type:varchar(128);not null"         json:"docker_env"`
+	LoopSeq        int                 `gorm:"type:int;not null"                  json:"-"`
+	Status         schema.JobStatus    `gorm:"type:varchar(32);not null"          json:"status"`
+	Message        string              `gorm:"type:text;size:65535;not null"      json:"message"`
+	Cache          schema.Cache        `gorm:"-"                                  json:"cache"`
+	CacheJson      string              `gorm:"type:text;size:65535;not null"      json:"-"`
+	CacheRunID     string              `gorm:"type:varchar(60);not null"          json:"cacheRunID"`
+	CacheJobID     string              `gorm:"type:varchar(60);not null"          json:"cacheJobID"`
+	ExtraFS        []schema.FsMount    `gorm:"-"                                  json:"extraFs"`
+	ExtraFSJson    string              `gorm:"type:text;size:65535;not null"      json:"-"`
+	Attempts       []schema.JobAttempt `gorm:"-"                                  json:"attempts"`
+	AttemptsJson   string              `gorm:"type:text;size:65535;not null"      json:"-"`
+	CreateTime     string              `gorm:"-"                                  json:"createTime"`
+	ActivateTime   string              `gorm:"-"                                  json:"activateTime"`
+	UpdateTime     string              `gorm:"-"                                  json:"updateTime,omitempty"`
+	CreatedAt      time.Time           `                                          json:"-"`
+	ActivatedAt    sql.NullTime        `                                          json:"-"`
+	UpdatedAt      time.Time           `                                          json:"-"`
+	DeletedAt      gorm.DeletedAt      `gorm:"index"                              json:"-"`
 }
 
 func CreateRunJob(logEntry *log.Entry, runJob *RunJob) (int64, error) {
@@ -155,6 +157,13 @@ func (rj *RunJob) Encode() error {
 	}
 	rj.ExtraFSJson = string(fsMountJson)
 
+	attemptsJson, err := json.Marshal(rj.Attempts)
+	if err != nil {
+		logger.Logger().Errorf("encode run job attempts failed. error: %v", err)
+		return err
+	}
+	rj.AttemptsJson = string(attemptsJson)
+
 	if rj.ActivateTime != "" {
 		activatedAt := sql.NullTime{}
 		activatedAt.Time, err = time.ParseInLocation("2006-01-02 15:04:05", rj.ActivateTime, time.Local)
@@ -210,6 +219,14 @@ func (rj *RunJob) decode() error {
 		rj.ExtraFS = fsMount
 	}
 
+	if len(rj.AttemptsJson) > 0 {
+		attempts := []schema.JobAttempt{}
+		if err := json.Unmarshal([]byte(rj.AttemptsJson), &attempts); err != nil {
+			logger.Logger().Errorf("decode run job attempts failed. error: %v", err)
+		}
+		rj.Attempts = attempts
+	}
+
 	// format time
 	rj.CreateTime = rj.CreatedAt.Format("2006-01-02 15:04:05")
 	rj.UpdateTime = rj.UpdatedAt.Format("2006-01-02 15:04:05")
@@ -232,10 +249,12 @@ func (rj *RunJob) Trans2JobView() schema.JobView {
 		newEnv[k] = v
 	}
 	newEndTime := ""
-	if rj.Status == schema.StatusJobCancelled || rj.Status == schema.StatusJobFailed || rj.Status == schema.StatusJobSucceeded || rj.Status == schema.StatusJobSkipped {
+	if rj.Status == schema.StatusJobCancelled || rj.Status == schema.StatusJobFailed || rj.Status == schema.StatusJobSucceeded || rj.Status == schema.StatusJobSkipped ||
+		rj.Status == schema.StatusJobTimeout {
 		newEndTime = rj.UpdateTime
 	}
 	newFsMount := append(rj.ExtraFS, []schema.FsMount{}...)
+	newAttempts := append(rj.Attempts, []schema.JobAttempt{}...)
 
 	return schema.JobView{
 		PK:          rj.Pk,
@@ -258,6 +277,7 @@ func (rj *RunJob) Trans2JobView() schema.JobView {
 		CacheRunID:  rj.CacheRunID,
 		CacheJobID:  rj.CacheJobID,
 		ExtraFS:     newFsMount,
+		Attempts:    newAttempts,
 	}
 }
 
@@ -279,6 +299,7 @@ func ParseRunJob(jobView *schema.JobView) RunJob {
 	}
 
 	newFsMount := append(jobView.ExtraFS, []schema.FsMount{}...)
+	newAttempts := append(jobView.Attempts, []schema.JobAttempt{}...)
 
 	return RunJob{
 		ID:           jobView.JobID,
@@ -297,5 +318,6 @@ func ParseRunJob(jobView *schema.JobView) RunJob {
 		CacheJobID:   jobView.CacheJobID,
 		ActivateTime: jobView.StartTime,
 		ExtraFS:      newFsMount,
+		Attempts:     newAttempts,
 	}
 }
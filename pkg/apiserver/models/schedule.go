@@ -40,6 +40,7 @@ const (
 	ScheduleStatusRunning    = "running"
 	ScheduleStatusFailed     = "failed"
 	ScheduleStatusTerminated = "terminated"
+	ScheduleStatusPaused     = "paused"
 )
 
 var ConcurrencyPolicyList = []string{
@@ -53,6 +54,7 @@ var ScheduleStatusList = []string{
 	ScheduleStatusRunning,
 	ScheduleStatusFailed,
 	ScheduleStatusTerminated,
+	ScheduleStatusPaused,
 }
 
 var ScheduleFinalStatusList = []string{
@@ -63,6 +65,7 @@ var ScheduleFinalStatusList = []string{
 
 var ScheduleNotFinalStatusList = []string{
 	ScheduleStatusRunning,
+	ScheduleStatusPaused,
 }
 
 type Schedule struct {
@@ -81,6 +84,8 @@ type Schedule struct {
 	StartAt           sql.NullTime   `                                         json:"-"`
 	EndAt             sql.NullTime   `                                         json:"-"`
 	NextRunAt         time.Time      `                                         json:"-"`
+	BackfillNextAt    sql.NullTime   `                                         json:"-"`
+	BackfillEndAt     sql.NullTime   `                                         json:"-"`
 	CreatedAt         time.Time      `                                         json:"-"`
 	UpdatedAt         time.Time      `                                         json:"-"`
 	DeletedAt         gorm.DeletedAt `                                         json:"-"`
@@ -362,6 +367,89 @@ func UpdateScheduleStatus(logEntry *log.Entry, scheduleID, status string) error
 	return nil
 }
 
+// UpdateScheduleStatusFrom 只有当 schedule 处于 fromStatus 时，才会更新其状态，避免与scheduler的更新相互覆盖
+func UpdateScheduleStatusFrom(logEntry *log.Entry, scheduleID, fromStatus, toStatus string) error {
+	logEntry.Debugf("begin update schedule status. scheduleID:%s, from status:%s, to status:%s", scheduleID, fromStatus, toStatus)
+	tx := storage.DB.Model(&Schedule{}).Where("id = ?", scheduleID).Where("status = ?", fromStatus).Update("status", toStatus)
+	if tx.Error != nil {
+		logEntry.Errorf("update schedule status failed. scheduleID:%s, error:%s",
+			scheduleID, tx.Error.Error())
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		errMsg := fmt.Sprintf("update schedule[%s] status to [%s] failed, schedule not in status[%s]", scheduleID, toStatus, fromStatus)
+		logEntry.Errorf(errMsg)
+		return fmt.Errorf(errMsg)
+	}
+	return nil
+}
+
+// ResumeSchedule 将 paused 状态的 schedule 恢复为 running，并更新 next_run_at
+func ResumeSchedule(logEntry *log.Entry, scheduleID string, nextRunAt time.Time) error {
+	logEntry.Debugf("begin resume schedule. scheduleID:%s, nextRunAt:%s", scheduleID, nextRunAt.Format("2006-01-02 15:04:05"))
+	tx := storage.DB.Model(&Schedule{}).Where("id = ?", scheduleID).Where("status = ?", ScheduleStatusPaused).
+		Updates(map[string]interface{}{"status": ScheduleStatusRunning, "next_run_at": nextRunAt})
+	if tx.Error != nil {
+		logEntry.Errorf("resume schedule failed. scheduleID:%s, error:%s",
+			scheduleID, tx.Error.Error())
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		errMsg := fmt.Sprintf("resume schedule[%s] failed, schedule not in status[%s]", scheduleID, ScheduleStatusPaused)
+		logEntry.Errorf(errMsg)
+		return fmt.Errorf(errMsg)
+	}
+	return nil
+}
+
+// StartScheduleBackfill 记录 schedule 的补数据区间，同一时间每个 schedule 只能有一个进行中的补数据任务
+func StartScheduleBackfill(logEntry *log.Entry, scheduleID string, backfillNextAt, backfillEndAt time.Time) error {
+	logEntry.Debugf("begin start schedule backfill. scheduleID:%s, backfillNextAt:%s, backfillEndAt:%s", scheduleID,
+		backfillNextAt.Format("2006-01-02 15:04:05"), backfillEndAt.Format("2006-01-02 15:04:05"))
+	tx := storage.DB.Model(&Schedule{}).Where("id = ?", scheduleID).Where("backfill_next_at IS NULL").
+		Updates(map[string]interface{}{"backfill_next_at": backfillNextAt, "backfill_end_at": backfillEndAt})
+	if tx.Error != nil {
+		logEntry.Errorf("start schedule backfill failed. scheduleID:%s, error:%s",
+			scheduleID, tx.Error.Error())
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		errMsg := fmt.Sprintf("start backfill for schedule[%s] failed, another backfill is in progress", scheduleID)
+		logEntry.Errorf(errMsg)
+		return fmt.Errorf(errMsg)
+	}
+	return nil
+}
+
+// UpdateScheduleBackfill 更新补数据的下一个调度时间，backfillNextAt 无效时表示补数据已经结束
+func UpdateScheduleBackfill(logEntry *log.Entry, scheduleID string, backfillNextAt sql.NullTime) error {
+	logEntry.Debugf("begin update schedule backfill. scheduleID:%s, backfillNextAt:%v", scheduleID, backfillNextAt)
+	values := map[string]interface{}{"backfill_next_at": backfillNextAt}
+	if !backfillNextAt.Valid {
+		values["backfill_end_at"] = sql.NullTime{Valid: false}
+	}
+
+	tx := storage.DB.Model(&Schedule{}).Where("id = ?", scheduleID).Updates(values)
+	if tx.Error != nil {
+		logEntry.Errorf("update schedule backfill failed. scheduleID:%s, error:%s",
+			scheduleID, tx.Error.Error())
+		return tx.Error
+	}
+	return nil
+}
+
+func GetSchedulesInBackfill(logEntry *log.Entry) (schedules []Schedule, err error) {
+	logEntry.Debugf("begin to get schedules in backfill")
+	result := storage.DB.Model(&Schedule{}).Where("backfill_next_at IS NOT NULL").Find(&schedules)
+	if result.Error != nil {
+		errMsg := fmt.Sprintf("get schedules in backfill failed: error:%s", result.Error.Error())
+		logEntry.Errorf(errMsg)
+		return nil, fmt.Errorf(errMsg)
+	}
+
+	return schedules, nil
+}
+
 func DeleteSchedule(logEntry *log.Entry, scheduleID string) error {
 	logEntry.Debugf("begin delete schedule. scheduleID:%s", scheduleID)
 	result := storage.DB.Model(&Schedule{}).Where("id = ?", scheduleID).Delete(&Schedule{})
@@ -384,7 +472,7 @@ func ScheduleUsedFsIDs() (map[string]bool, error) {
 	results := []result{}
 	tx := storage.DB.Model(&Schedule{}).Select("schedule.user_name, schedule.fs_config, pipeline_version.pipeline_yaml").
 		Joins("join pipeline_version on schedule.pipeline_version_id = pipeline_version.id and schedule.pipeline_id = pipeline_version.pipeline_id").
-		Where("schedule.status = ?", ScheduleStatusRunning).Find(&results)
+		Where("schedule.status IN (?)", ScheduleNotFinalStatusList).Find(&results)
 
 	if tx.Error != nil {
 		return nil, tx.Error
@@ -439,8 +527,9 @@ func GetNextGlobalWakeupTime(logEntry *log.Entry) (*time.Time, error) {
 	currentTime := time.Now()
 	logEntry.Debugf("begin to get next wakeup time after[%s]", currentTime.Format("01-02-2006 15:04:05"))
 
+	// 除了running状态的schedule外，正在补数据的schedule也需要纳入计算
 	var schedules []Schedule
-	result := storage.DB.Model(&Schedule{}).Where("status = ?", ScheduleStatusRunning).Find(&schedules)
+	result := storage.DB.Model(&Schedule{}).Where("status = ? OR backfill_next_at IS NOT NULL", ScheduleStatusRunning).Find(&schedules)
 	if result.Error != nil {
 		errMsg := fmt.Sprintf("search running schedules failed. error:%s", result.Error.Error())
 		logEntry.Errorf(errMsg)
@@ -472,17 +561,25 @@ func GetNextGlobalWakeupTime(logEntry *log.Entry) (*time.Time, error) {
 
 		// 计算nextWakeupTime，通过比较nextRunAt 和 EndAt
 		// 无论nextRunAt是否在expire_interval内，都会直接拿来比较
-		var earlierTime time.Time
-		if !schedule.EndAt.Valid {
-			earlierTime = schedule.NextRunAt
-		} else {
-			earlierTime = getEarlierTime(schedule.EndAt.Time, schedule.NextRunAt)
+		var candidates []time.Time
+		if schedule.Status == ScheduleStatusRunning {
+			if !schedule.EndAt.Valid {
+				candidates = append(candidates, schedule.NextRunAt)
+			} else {
+				candidates = append(candidates, getEarlierTime(schedule.EndAt.Time, schedule.NextRunAt))
+			}
 		}
 
-		if nextWakeupTime == nil {
-			nextWakeupTime = &earlierTime
-		} else {
-			earlierTime = getEarlierTime(earlierTime, *nextWakeupTime)
+		// 补数据的调度时间都早于当前时间，会立即触发 timeout
+		if schedule.BackfillNextAt.Valid {
+			candidates = append(candidates, schedule.BackfillNextAt.Time)
+		}
+
+		for _, candidate := range candidates {
+			earlierTime := candidate
+			if nextWakeupTime != nil {
+				earlierTime = getEarlierTime(earlierTime, *nextWakeupTime)
+			}
 			nextWakeupTime = &earlierTime
 		}
 	}
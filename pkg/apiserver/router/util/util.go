@@ -47,6 +47,8 @@ const (
 	QueryActionDelete = "delete"
 	QueryActionCreate = "create"
 	QueryActionModify = "modify"
+	QueryActionPause  = "pause"
+	QueryActionResume = "resume"
 
 	QueryKeyMarker  = "marker"
 	QueryKeyMaxKeys = "maxKeys"
@@ -66,6 +68,7 @@ const (
 	QueryKeyName             = "name"
 	QueryKeyNamespace        = "namespace"
 	QueryKeyUserName         = "username"
+	QueryKeyGroupName        = "groupName"
 	QueryKeyClusterName      = "clusterName"
 	QueryResourceType        = "resourceType"
 	QueryResourceID          = "resourceID"
@@ -79,6 +82,8 @@ const (
 	QueryKeyLineLimit        = "lineLimit"
 	QueryKeyType             = "type"
 	QueryKeyFramework        = "framework"
+	QueryKeyFormat           = "format"
+	QueryKeyWithSecret       = "withSecret"
 
 	ParamKeyClusterName   = "clusterName"
 	ParamKeyClusterNames  = "clusterNames"
@@ -93,6 +98,9 @@ const (
 	QueryMountPoint = "mountpoint"
 
 	ParamFlavourName = "flavourName"
+	ParamKeyTokenID  = "tokenID"
+	ParamKeyGroup    = "groupName"
+	ParamKeyMember   = "userName"
 
 	// cluster name最大长度
 	ClusterNameMaxLength = 255
@@ -27,6 +27,7 @@ import (
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/cluster"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/middleware"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
@@ -43,9 +44,12 @@ func (cr *ClusterRouter) AddRouter(r chi.Router) {
 
 	r.Post("/cluster", cr.createCluster)
 	r.Get("/cluster", cr.listCluster)
-	r.Get("/cluster/{clusterName}", cr.getClusterDetail)
-	r.Delete("/cluster/{clusterName}", cr.deleteCluster)
-	r.Put("/cluster/{clusterName}", cr.updateCluster)
+	r.With(middleware.Authorize(common.ResourceTypeCluster, common.ActionRead, util.ParamKeyClusterName)).
+		Get("/cluster/{clusterName}", cr.getClusterDetail)
+	r.With(middleware.Authorize(common.ResourceTypeCluster, common.ActionWrite, util.ParamKeyClusterName)).
+		Delete("/cluster/{clusterName}", cr.deleteCluster)
+	r.With(middleware.Authorize(common.ResourceTypeCluster, common.ActionWrite, util.ParamKeyClusterName)).
+		Put("/cluster/{clusterName}", cr.updateCluster)
 	r.Get("/cluster/resource", cr.listClusterQuota)
 	r.Post("/cluster/resource", cr.listClusterQuotaV2)
 
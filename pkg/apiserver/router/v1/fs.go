@@ -31,10 +31,12 @@ import (
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
 	api "github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/fs"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/middleware"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
 	fuse "github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/fs"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/ufs"
 	fsCommon "github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/utils"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
@@ -52,12 +54,14 @@ func (pr *PFSRouter) AddRouter(r chi.Router) {
 	// fs
 	r.Post("/fs", pr.createFileSystem)
 	r.Get("/fs", pr.listFileSystem)
-	r.Get("/fs/{fsName}", pr.getFileSystem)
-	r.Delete("/fs/{fsName}", pr.deleteFileSystem)
+	r.With(middleware.AuthorizeFs(common.ActionRead)).Get("/fs/{fsName}", pr.getFileSystem)
+	r.With(middleware.AuthorizeFs(common.ActionWrite)).Delete("/fs/{fsName}", pr.deleteFileSystem)
 	// fs cache config
 	r.Post("/fsCache", pr.createFSCacheConfig)
-	r.Get("/fsCache/{fsName}", pr.getFSCacheConfig)
-	r.Delete("/fsCache/{fsName}", pr.deleteFSCacheConfig)
+	r.With(middleware.AuthorizeFs(common.ActionRead)).Get("/fsCache/{fsName}", pr.getFSCacheConfig)
+	r.With(middleware.AuthorizeFs(common.ActionWrite)).Delete("/fsCache/{fsName}", pr.deleteFSCacheConfig)
+	r.Post("/fsCache/{fsName}/warmup", pr.warmupFSCache)
+	r.With(middleware.AuthorizeFs(common.ActionRead)).Get("/fsCache/{fsName}/warmup", pr.getFSCacheWarmup)
 }
 
 var URLPrefix = map[string]bool{
@@ -261,6 +265,20 @@ func checkProperties(fsType string, req *api.CreateFileSystemRequest) error {
 		if req.Properties[fsCommon.Region] == "" {
 			req.Properties[fsCommon.Region] = ""
 		}
+		// s3PartSize单位为MiB，需满足s3分片大小的限制
+		if value := req.Properties[fsCommon.S3PartSize]; value != "" {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n*ufs.MiB < ufs.MPUMinPartSize || n*ufs.MiB > ufs.MPUMaxPartSize {
+				return common.InvalidField("properties", fmt.Sprintf("key[%s] should be an integer between %d and %d",
+					fsCommon.S3PartSize, ufs.MPUMinPartSize/ufs.MiB, ufs.MPUMaxPartSize/ufs.MiB))
+			}
+		}
+		if value := req.Properties[fsCommon.S3UploadConcurrency]; value != "" {
+			if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+				return common.InvalidField("properties", fmt.Sprintf("key[%s] should be a positive integer",
+					fsCommon.S3UploadConcurrency))
+			}
+		}
 		encodedSk, err := common.AesEncrypt(req.Properties[fsCommon.SecretKey], common.AESEncryptKey)
 		if err != nil {
 			log.Errorf("encrypt s3 sk failed: %v", err)
@@ -457,6 +475,7 @@ func getListResult(fsModel []model.FileSystem, nextMarker, marker string) *api.L
 // @Produce  json
 // @Param fsName path string true "文件系统名称"
 // @Param username query string false "root用户指定其他用户"
+// @Param withSecret query bool false "是否返回secretKey、password等敏感属性，默认脱敏"
 // @Success 200 {object} models.FileSystem
 // @Router /fs/{fsName} [get]
 func (pr *PFSRouter) getFileSystem(w http.ResponseWriter, r *http.Request) {
@@ -469,7 +488,7 @@ func (pr *PFSRouter) getFileSystem(w http.ResponseWriter, r *http.Request) {
 	log.Infof("get file system with req[%v] and fileSystemID[%s]", getRequest, fsName)
 
 	fileSystemService := api.GetFileSystemService()
-	realUserName := getRealUserName(&ctx, getRequest.Username)
+	realUserName := getFsOwnerName(&ctx, getRequest.Username)
 	fsModel, err := fileSystemService.GetFileSystem(realUserName, fsName)
 	if err != nil {
 		ctx.Logging().Errorf("get file system username[%s] fsname[%s] with error[%v]", getRequest.Username, fsName, err)
@@ -486,6 +505,18 @@ func (pr *PFSRouter) getFileSystem(w http.ResponseWriter, r *http.Request) {
 
 	response := *fsResponseFromModel(fsModel)
 	ctx.Logging().Debugf("GetFileSystem Fs:%v", string(config.PrettyFormat(response)))
+	// fuse client needs secrets to access storage
+	if r.URL.Query().Get(util.QueryKeyWithSecret) == "true" {
+		// secrets are only returned to users who can mount the file system
+		if fsModel.UserName != ctx.UserName &&
+			!storage.Auth.HasPermission(&ctx, common.ResourceTypeFs, fsModel.ID, common.ActionUse) {
+			err = common.NoAccessError(ctx.UserName, common.ResourceTypeFs, fsModel.ID)
+			ctx.Logging().Errorf("get file system with secret failed. error: %v", err)
+			common.RenderErrWithMessage(w, ctx.RequestID, common.AccessDenied, err.Error())
+			return
+		}
+		response.Properties = fsModel.PropertiesMap
+	}
 	common.Render(w, http.StatusOK, response)
 }
 
@@ -497,7 +528,7 @@ func fsResponseFromModel(fsModel model.FileSystem) *api.FileSystemResponse {
 		Type:                    fsModel.Type,
 		SubPath:                 fsModel.SubPath,
 		Username:                fsModel.UserName,
-		Properties:              fsModel.PropertiesMap,
+		Properties:              model.RedactedProperties(fsModel.PropertiesMap),
 		IndependentMountProcess: fsModel.IndependentMountProcess,
 	}
 }
@@ -520,7 +551,7 @@ func (pr *PFSRouter) deleteFileSystem(w http.ResponseWriter, r *http.Request) {
 
 	log.Debugf("delete file system with fsName[%s] username[%s]", fsName, username)
 
-	realUserName := getRealUserName(&ctx, username)
+	realUserName := getFsOwnerName(&ctx, username)
 	fsID := common.ID(realUserName, fsName)
 
 	if err := fsExistsForModify(&ctx, fsID); err != nil {
@@ -561,3 +592,11 @@ func getRealUserName(ctx *logger.RequestContext,
 	}
 	return ctx.UserName
 }
+
+// getFsOwnerName 用于已经过AuthorizeFs鉴权的请求, username为空时访问当前用户自己的文件系统
+func getFsOwnerName(ctx *logger.RequestContext, username string) string {
+	if username != "" {
+		return username
+	}
+	return ctx.UserName
+}
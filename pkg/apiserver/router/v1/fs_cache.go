@@ -32,6 +32,7 @@ import (
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	locationAwareness "github.com/PaddlePaddle/PaddleFlow/pkg/fs/location-awareness"
 )
 
 // createFSCacheConfig handles requests of creating filesystem cache config
@@ -151,7 +152,7 @@ func (pr *PFSRouter) getFSCacheConfig(w http.ResponseWriter, r *http.Request) {
 	username := r.URL.Query().Get(util.QueryKeyUserName)
 	ctx := common.GetRequestContext(r)
 
-	realUserName := getRealUserName(&ctx, username)
+	realUserName := getFsOwnerName(&ctx, username)
 	fsID := common.ID(realUserName, fsName)
 
 	fsCacheConfigResp, err := api.GetFileSystemCacheConfig(&ctx, fsID)
@@ -185,7 +186,7 @@ func (pr *PFSRouter) deleteFSCacheConfig(w http.ResponseWriter, r *http.Request)
 	username := r.URL.Query().Get(util.QueryKeyUserName)
 
 	log.Debugf("delete fs cache config with fsName[%s] username[%s]", fsName, username)
-	realUserName := getRealUserName(&ctx, username)
+	realUserName := getFsOwnerName(&ctx, username)
 	fsID := common.ID(realUserName, fsName)
 
 	if err := fsExistsForModify(&ctx, fsID); err != nil {
@@ -215,3 +216,101 @@ func (pr *PFSRouter) deleteFSCacheConfig(w http.ResponseWriter, r *http.Request)
 
 	common.RenderStatus(w, http.StatusOK)
 }
+
+// warmupFSCache handles requests of warming up file system cache on nodes
+// @Summary warmupFSCache
+// @Description 在缓存配置节点亲和性选中的节点上预热文件系统缓存
+// @tag fs
+// @Accept   json
+// @Produce  json
+// @Param fsName path string true "文件系统名称"
+// @Param request body fs.WarmupFileSystemCacheRequest true "request body"
+// @Success 200 {object} fs.WarmupFileSystemCacheResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Failure 500 {object} common.ErrorResponse
+// @Router /fsCache/{fsName}/warmup [post]
+func (pr *PFSRouter) warmupFSCache(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	var warmupRequest api.WarmupFileSystemCacheRequest
+	if err := common.BindJSON(r, &warmupRequest); err != nil {
+		ctx.Logging().Errorf("WarmupFSCache bindjson failed. err:%s", err.Error())
+		common.RenderErrWithMessage(w, ctx.RequestID, common.MalformedJSON, err.Error())
+		return
+	}
+	fsName := chi.URLParam(r, util.QueryFsName)
+	realUserName := getRealUserName(&ctx, warmupRequest.Username)
+	warmupRequest.FsID = common.ID(realUserName, fsName)
+	ctx.Logging().Tracef("warmup file system cache with req[%v]", warmupRequest)
+
+	if err := fsExistsForModify(&ctx, warmupRequest.FsID); err != nil {
+		ctx.Logging().Errorf("checkCanModifyFs[%s] err: %v", warmupRequest.FsID, err)
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	if err := validateWarmup(&ctx, &warmupRequest); err != nil {
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	resp, err := api.WarmupFileSystemCache(&ctx, warmupRequest)
+	if err != nil {
+		ctx.Logging().Errorf("warmup file system cache with service error[%v]", err)
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	common.Render(w, http.StatusOK, resp)
+}
+
+func validateWarmup(ctx *logger.RequestContext, req *api.WarmupFileSystemCacheRequest) error {
+	if len(req.Paths) == 0 && req.Manifest == "" {
+		return validationReturnError(ctx, fmt.Errorf("fs[%s] warmup: paths or manifest should be set", req.FsID))
+	}
+	switch req.Type {
+	case "":
+		req.Type = locationAwareness.WarmupTypeData
+	case locationAwareness.WarmupTypeData, locationAwareness.WarmupTypeMeta:
+	default:
+		return validationReturnError(ctx, fmt.Errorf("fs[%s] warmup: type[%s] not valid, must be %s or %s",
+			req.FsID, req.Type, locationAwareness.WarmupTypeMeta, locationAwareness.WarmupTypeData))
+	}
+	if req.Threads < 0 || req.Threads > locationAwareness.MaxWarmupThreads {
+		return validationReturnError(ctx, fmt.Errorf("fs[%s] warmup: threads[%d] should be in [0, %d]",
+			req.FsID, req.Threads, locationAwareness.MaxWarmupThreads))
+	}
+	return nil
+}
+
+// getFSCacheWarmup
+// @Summary 获取文件系统各节点缓存预热进度
+// @Description 获取文件系统各节点缓存预热进度
+// @Id getFSCacheWarmup
+// @tags FSCacheConfig
+// @Accept  json
+// @Produce json
+// @Param fsName path string true "存储名称"
+// @Param username query string false "用户名"
+// @Success 200 {object} fs.GetWarmupResponse "各节点预热进度"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 500 {object} common.ErrorResponse "500"
+// @Router /fsCache/{fsName}/warmup [GET]
+func (pr *PFSRouter) getFSCacheWarmup(w http.ResponseWriter, r *http.Request) {
+	fsName := chi.URLParam(r, util.QueryFsName)
+	username := r.URL.Query().Get(util.QueryKeyUserName)
+	ctx := common.GetRequestContext(r)
+
+	realUserName := getFsOwnerName(&ctx, username)
+	fsID := common.ID(realUserName, fsName)
+
+	if err := fsExistsForModify(&ctx, fsID); err != nil {
+		ctx.Logging().Errorf("checkCanModifyFs[%s] err: %v", fsID, err)
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	resp, err := api.GetFileSystemCacheWarmup(&ctx, fsID)
+	if err != nil {
+		ctx.Logging().Errorf("GetFSCacheWarmup[%s] failed. error:%v", fsID, err)
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	common.Render(w, http.StatusOK, resp)
+}
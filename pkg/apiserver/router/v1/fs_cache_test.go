@@ -146,3 +146,49 @@ func TestRouter_FSCacheConfig(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, http.StatusBadRequest, result.Code)
 }
+
+func TestRouter_FSCacheWarmup(t *testing.T) {
+	router, baseUrl := prepareDBAndAPI(t)
+	mockFs := mockFS()
+	url := baseUrl + "/fsCache/" + mockFsName + "/warmup"
+	warmupReq := fs.WarmupFileSystemCacheRequest{
+		Username: MockRootUser,
+		Paths:    []string{"/data"},
+	}
+
+	// test warmup failure - no fs
+	result, err := PerformPostRequest(router, url, warmupReq)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, result.Code)
+
+	err = storage.Filesystem.CreatFileSystem(&mockFs)
+	assert.Nil(t, err)
+
+	// test warmup failure - no paths
+	result, err = PerformPostRequest(router, url, fs.WarmupFileSystemCacheRequest{Username: MockRootUser})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, result.Code)
+
+	// test warmup failure - wrong type
+	warmupReq.Type = "notValid"
+	result, err = PerformPostRequest(router, url, warmupReq)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, result.Code)
+
+	// test warmup failure - too many threads
+	warmupReq.Type = "meta"
+	warmupReq.Threads = 10000
+	result, err = PerformPostRequest(router, url, warmupReq)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, result.Code)
+
+	// test get warmup progress
+	result, err = PerformGetRequest(router, url)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, result.Code)
+	warmupRsp := fs.GetWarmupResponse{}
+	err = ParseBody(result.Body, &warmupRsp)
+	assert.Nil(t, err)
+	assert.Equal(t, mockFsName, warmupRsp.FsName)
+	assert.Equal(t, 0, len(warmupRsp.Nodes))
+}
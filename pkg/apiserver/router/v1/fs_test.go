@@ -29,6 +29,7 @@ import (
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/fs"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/secret"
 	fsCommon "github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
 	runtime "github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
@@ -148,6 +149,30 @@ func Test_validateCreateFileSystem(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "s3 part size",
+			args: args{
+				ctx: ctx,
+				req: &fs.CreateFileSystemRequest{Name: "testname", Username: "testUsername", Url: "s3://bucket/subpath", Properties: map[string]string{fsCommon.Endpoint: "bj.bos.com", fsCommon.Region: "bj", fsCommon.AccessKey: "testak", fsCommon.SecretKey: "testsk", fsCommon.S3PartSize: "5120"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "s3 part size too small",
+			args: args{
+				ctx: ctx,
+				req: &fs.CreateFileSystemRequest{Name: "testname", Username: "testUsername", Url: "s3://bucket/subpath", Properties: map[string]string{fsCommon.Endpoint: "bj.bos.com", fsCommon.Region: "bj", fsCommon.AccessKey: "testak", fsCommon.SecretKey: "testsk", fsCommon.S3PartSize: "4"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "s3 part size too large",
+			args: args{
+				ctx: ctx,
+				req: &fs.CreateFileSystemRequest{Name: "testname", Username: "testUsername", Url: "s3://bucket/subpath", Properties: map[string]string{fsCommon.Endpoint: "bj.bos.com", fsCommon.Region: "bj", fsCommon.AccessKey: "testak", fsCommon.SecretKey: "testsk", fsCommon.S3PartSize: "5121"}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "local url wrong",
 			args: args{
@@ -518,3 +543,31 @@ func RandomString(n int) string {
 	}
 	return string(b)
 }
+
+func TestGetFSRedactSecret(t *testing.T) {
+	router, baseUrl := prepareDBAndAPI(t)
+	fsModel := mockFS()
+	fsModel.Type = fsCommon.S3Type
+	fsModel.PropertiesMap = map[string]string{
+		fsCommon.AccessKey: "ak",
+		fsCommon.SecretKey: "sk",
+	}
+	assert.Nil(t, storage.Filesystem.CreatFileSystem(&fsModel))
+
+	fsUrl := baseUrl + "/fs/" + mockFsName
+	result, err := PerformGetRequest(router, fsUrl)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, result.Code)
+	fsResp := fs.FileSystemResponse{}
+	assert.Nil(t, ParseBody(result.Body, &fsResp))
+	assert.Equal(t, "ak", fsResp.Properties[fsCommon.AccessKey])
+	assert.Equal(t, secret.Redacted, fsResp.Properties[fsCommon.SecretKey])
+
+	// fuse client gets secrets to access storage
+	result, err = PerformGetRequest(router, fsUrl+"?withSecret=true")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, result.Code)
+	fsResp = fs.FileSystemResponse{}
+	assert.Nil(t, ParseBody(result.Body, &fsResp))
+	assert.Equal(t, "sk", fsResp.Properties[fsCommon.SecretKey])
+}
@@ -79,7 +79,8 @@ func (gr *GrantRouter) createGrant(w http.ResponseWriter, r *http.Request) {
 // @tags Grant
 // @Accept  json
 // @Produce json
-// @Param username query string true "用户名称"
+// @Param username query string false "用户名称, 与用户组名称二选一"
+// @Param groupName query string false "用户组名称, 与用户名称二选一"
 // @Param resourceType query string true "资源类型"
 // @Param resourceID query string true "资源ID/资源名称"
 // @Success 200 {string} string "成功删除授权的响应码"
@@ -90,12 +91,13 @@ func (gr *GrantRouter) deleteGrant(w http.ResponseWriter, r *http.Request) {
 	ctx := common.GetRequestContext(r)
 
 	userName := r.URL.Query().Get(util.QueryKeyUserName)
+	groupName := r.URL.Query().Get(util.QueryKeyGroupName)
 	resourceType := r.URL.Query().Get(util.QueryResourceType)
 	resourceID := r.URL.Query().Get(util.QueryResourceID)
 
-	if err := grant.DeleteGrant(&ctx, userName, resourceID, resourceType); err != nil {
+	if err := grant.DeleteGrant(&ctx, userName, groupName, resourceID, resourceType); err != nil {
 		ctx.Logging().Errorf(
-			"delete grant failed. userName:%s, resourceID:%s error:%s", userName, resourceID, err.Error())
+			"delete grant failed. userName:%s, groupName:%s, resourceID:%s error:%s", userName, groupName, resourceID, err.Error())
 		common.RenderErr(w, ctx.RequestID, ctx.ErrorCode)
 		return
 	}
@@ -110,6 +112,7 @@ func (gr *GrantRouter) deleteGrant(w http.ResponseWriter, r *http.Request) {
 // @Accept  json
 // @Produce json
 // @Param username query string false "用户名称过滤"
+// @Param groupName query string false "用户组名称过滤"
 // @Param maxKeys query int false "每页包含的最大数量，缺省值为50"
 // @Param marker query string false "批量获取列表的查询的起始位置，是一个由系统生成的字符串"
 // @Success 200 {object} grant.ListGrantResponse "获取授权列表的响应"
@@ -126,10 +129,11 @@ func (gr *GrantRouter) listGrant(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userName := r.URL.Query().Get(util.QueryKeyUserName)
+	groupName := r.URL.Query().Get(util.QueryKeyGroupName)
 	ctx.Logging().Debugf(
-		"ListGrant marker:[%s] maxKeys:[%d] userName:[%s]",
-		marker, maxKeys, userName)
-	response, err := grant.ListGrant(&ctx, marker, maxKeys, userName)
+		"ListGrant marker:[%s] maxKeys:[%d] userName:[%s] groupName:[%s]",
+		marker, maxKeys, userName, groupName)
+	response, err := grant.ListGrant(&ctx, marker, maxKeys, userName, groupName)
 	if err != nil {
 		ctx.Logging().Errorf("list grants failed. error:%s.", err.Error())
 		common.RenderErr(w, ctx.RequestID, ctx.ErrorCode)
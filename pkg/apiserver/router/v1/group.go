@@ -0,0 +1,208 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/group"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
+)
+
+type GroupRouter struct {
+}
+
+func (gr *GroupRouter) Name() string {
+	return "GroupRouter"
+}
+
+func (gr *GroupRouter) AddRouter(r chi.Router) {
+	log.Info("add group router")
+	r.Post("/group", gr.createGroup)
+	r.Get("/group", gr.listGroup)
+	r.Get("/group/{groupName}", gr.getGroup)
+	r.Delete("/group/{groupName}", gr.deleteGroup)
+	r.Post("/group/{groupName}/member", gr.addGroupMember)
+	r.Delete("/group/{groupName}/member/{userName}", gr.removeGroupMember)
+}
+
+// createGroup
+// @Summary 创建用户组
+// @Description 创建用户组, 授予用户组的权限对组内所有用户生效
+// @Id createGroup
+// @tags Group
+// @Accept  json
+// @Produce json
+// @Param request body group.CreateGroupRequest true "创建用户组请求"
+// @Success 200 {object} group.CreateGroupResponse "创建用户组响应"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 500 {object} common.ErrorResponse "500"
+// @Router /group [POST]
+func (gr *GroupRouter) createGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	var request group.CreateGroupRequest
+	if err := common.BindJSON(r, &request); err != nil {
+		ctx.Logging().Errorf("createGroup bindjson failed. error:%s", err.Error())
+		common.RenderErr(w, ctx.RequestID, common.MalformedJSON)
+		return
+	}
+	response, err := group.CreateGroup(&ctx, request)
+	if err != nil {
+		ctx.Logging().Errorf("create group failed. request:%v error:%s", request, err.Error())
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	common.Render(w, http.StatusOK, response)
+}
+
+// listGroup
+// @Summary 获取用户组列表
+// @Description 获取用户组列表, 非root用户只能获取自己所在的用户组
+// @Id listGroup
+// @tags Group
+// @Accept  json
+// @Produce json
+// @Param maxKeys query int false "每页包含的最大数量，缺省值为50"
+// @Param marker query string false "批量获取列表的查询的起始位置，是一个由系统生成的字符串"
+// @Success 200 {object} group.ListGroupResponse "获取用户组列表的响应"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 500 {object} common.ErrorResponse "500"
+// @Router /group [GET]
+func (gr *GroupRouter) listGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	marker := r.URL.Query().Get(util.QueryKeyMarker)
+	maxKeys, err := util.GetQueryMaxKeys(&ctx, r)
+	if err != nil {
+		common.RenderErrWithMessage(w, ctx.RequestID, common.InvalidURI, err.Error())
+		return
+	}
+	response, err := group.ListGroup(&ctx, marker, maxKeys)
+	if err != nil {
+		ctx.Logging().Errorf("list groups failed. error:%s.", err.Error())
+		common.RenderErr(w, ctx.RequestID, ctx.ErrorCode)
+		return
+	}
+	common.Render(w, http.StatusOK, response)
+}
+
+// getGroup
+// @Summary 获取用户组
+// @Description 获取用户组及其成员
+// @Id getGroup
+// @tags Group
+// @Accept  json
+// @Produce json
+// @Param groupName path string true "用户组名称"
+// @Success 200 {object} group.GetGroupResponse "获取用户组的响应"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 404 {object} common.ErrorResponse "404"
+// @Router /group/{groupName} [GET]
+func (gr *GroupRouter) getGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	groupName := chi.URLParam(r, util.ParamKeyGroup)
+	response, err := group.GetGroup(&ctx, groupName)
+	if err != nil {
+		ctx.Logging().Errorf("get group failed. groupName:%s error:%s", groupName, err.Error())
+		common.RenderErr(w, ctx.RequestID, ctx.ErrorCode)
+		return
+	}
+	common.Render(w, http.StatusOK, response)
+}
+
+// deleteGroup
+// @Summary 删除用户组
+// @Description 删除用户组, 同时删除组成员和授予用户组的权限
+// @Id deleteGroup
+// @tags Group
+// @Accept  json
+// @Produce json
+// @Param groupName path string true "用户组名称"
+// @Success 200 {string} string "成功删除用户组的响应码"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 404 {object} common.ErrorResponse "404"
+// @Router /group/{groupName} [DELETE]
+func (gr *GroupRouter) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	groupName := chi.URLParam(r, util.ParamKeyGroup)
+	if err := group.DeleteGroup(&ctx, groupName); err != nil {
+		ctx.Logging().Errorf("delete group failed. groupName:%s error:%s", groupName, err.Error())
+		common.RenderErr(w, ctx.RequestID, ctx.ErrorCode)
+		return
+	}
+	common.RenderStatus(w, http.StatusOK)
+}
+
+// addGroupMember
+// @Summary 添加用户组成员
+// @Description 添加用户组成员
+// @Id addGroupMember
+// @tags Group
+// @Accept  json
+// @Produce json
+// @Param groupName path string true "用户组名称"
+// @Param request body group.AddGroupMemberRequest true "添加用户组成员请求"
+// @Success 200 {string} string "成功添加用户组成员的响应码"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 404 {object} common.ErrorResponse "404"
+// @Router /group/{groupName}/member [POST]
+func (gr *GroupRouter) addGroupMember(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	groupName := chi.URLParam(r, util.ParamKeyGroup)
+	var request group.AddGroupMemberRequest
+	if err := common.BindJSON(r, &request); err != nil {
+		ctx.Logging().Errorf("addGroupMember bindjson failed. error:%s", err.Error())
+		common.RenderErr(w, ctx.RequestID, common.MalformedJSON)
+		return
+	}
+	if err := group.AddGroupMember(&ctx, groupName, request.UserName); err != nil {
+		ctx.Logging().Errorf("add member of group failed. groupName:%s userName:%s error:%s",
+			groupName, request.UserName, err.Error())
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	common.RenderStatus(w, http.StatusOK)
+}
+
+// removeGroupMember
+// @Summary 移除用户组成员
+// @Description 移除用户组成员
+// @Id removeGroupMember
+// @tags Group
+// @Accept  json
+// @Produce json
+// @Param groupName path string true "用户组名称"
+// @Param userName path string true "用户名称"
+// @Success 200 {string} string "成功移除用户组成员的响应码"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 404 {object} common.ErrorResponse "404"
+// @Router /group/{groupName}/member/{userName} [DELETE]
+func (gr *GroupRouter) removeGroupMember(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	groupName := chi.URLParam(r, util.ParamKeyGroup)
+	userName := chi.URLParam(r, util.ParamKeyMember)
+	if err := group.RemoveGroupMember(&ctx, groupName, userName); err != nil {
+		ctx.Logging().Errorf("remove member of group failed. groupName:%s userName:%s error:%s",
+			groupName, userName, err.Error())
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	common.RenderStatus(w, http.StatusOK)
+}
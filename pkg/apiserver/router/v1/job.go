@@ -30,6 +30,7 @@ import (
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/job"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/middleware"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/errors"
@@ -60,9 +61,10 @@ func (jr *JobRouter) AddRouter(r chi.Router) {
 	r.Post("/job/single", jr.CreateSingleJob)
 	r.Post("/job/distributed", jr.CreateDistributedJob)
 	r.Post("/job/workflow", jr.CreateWorkflowJob)
+	r.With(middleware.AuthorizeJob(common.ActionWrite)).Post("/job/{jobID}/resubmit", jr.ResubmitJob)
 
-	r.Delete("/job/{jobID}", jr.DeleteJob)
-	r.Put("/job/{jobID}", func(w http.ResponseWriter, r *http.Request) {
+	r.With(middleware.AuthorizeJob(common.ActionWrite)).Delete("/job/{jobID}", jr.DeleteJob)
+	r.With(middleware.AuthorizeJob(common.ActionWrite)).Put("/job/{jobID}", func(w http.ResponseWriter, r *http.Request) {
 		ctx := common.GetRequestContext(r)
 		action := r.URL.Query().Get(util.QueryKeyAction)
 		switch action {
@@ -77,7 +79,7 @@ func (jr *JobRouter) AddRouter(r chi.Router) {
 
 	r.Get("/wsjob", jr.GetJobByWebsocket)
 	r.Get("/job", jr.ListJob)
-	r.Get("/job/{jobID}", jr.GetJob)
+	r.With(middleware.AuthorizeJob(common.ActionRead)).Get("/job/{jobID}", jr.GetJob)
 }
 
 // CreateSingleJob create single job
@@ -148,6 +150,44 @@ func (jr *JobRouter) CreateDistributedJob(w http.ResponseWriter, r *http.Request
 	common.Render(w, http.StatusOK, response)
 }
 
+// ResubmitJob resubmit a finished job
+// @Summary 重新提交已结束的作业
+// @Description 基于已结束作业的配置重新提交作业，可覆盖队列、优先级、套餐和环境变量
+// @Id resubmitJob
+// @tags Job
+// @Accept  json
+// @Produce json
+// @Param jobID path string true "作业ID"
+// @Param request body job.ResubmitJobRequest false "覆盖的作业配置"
+// @Success 200 {object} job.CreateJobResponse "重新提交作业的响应"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Router /job/{jobID}/resubmit [POST]
+func (jr *JobRouter) ResubmitJob(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	jobID := chi.URLParam(r, util.ParamKeyJobID)
+
+	var request job.ResubmitJobRequest
+	if r.ContentLength != 0 {
+		if err := common.BindJSON(r, &request); err != nil {
+			ctx.ErrorCode = common.MalformedJSON
+			logger.LoggerForRequest(&ctx).Errorf("parsing request body failed:%+v. error:%s", r.Body, err.Error())
+			common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+			return
+		}
+	}
+	request.JobID = jobID
+	log.Debugf("resubmit job request:%+v", request)
+
+	response, err := job.ResubmitJob(&ctx, &request)
+	if err != nil {
+		ctx.Logging().Errorf("resubmit job %s failed, error:%s", jobID, err.Error())
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	ctx.Logging().Debugf("resubmit job:%v", string(config.PrettyFormat(response)))
+	common.Render(w, http.StatusOK, response)
+}
+
 // CreateWorkflowJob create workflow job
 // @Summary 创建Workflow类型作业
 // @Description 创建Workflow类型作业
@@ -247,12 +287,13 @@ func (jr *JobRouter) StopJob(w http.ResponseWriter, r *http.Request) {
 
 // UpdateJob update job
 // @Summary 更新作业
-// @Description 更新作业
+// @Description 更新作业, 支持更新优先级、标签、注解、最大运行时间, 以及弹性作业的副本数
 // @Id UpdateJob
 // @tags Job
 // @Accept  json
 // @Produce json
 // @Param jobID path string true "作业ID"
+// @Param request body job.UpdateJobRequest true "更新作业请求"
 // @Success 200 {string} "更新作业的响应"
 // @Failure 400 {object} common.ErrorResponse "400"
 // @Router /job/{jobID}?action=modify [PUT]
@@ -173,6 +173,7 @@ func MockInitJob(t *testing.T) (*chi.Mux, *chi.Mux, string) {
 
 func TestUpdateJob(t *testing.T) {
 	router, routerNonRoot, baseURL := MockInitJob(t)
+	maxRunTime, invalidMaxRunTime := int64(3600), int64(-1)
 	type args struct {
 		ctx    *logger.RequestContext
 		req    *job.UpdateJobRequest
@@ -207,6 +208,32 @@ func TestUpdateJob(t *testing.T) {
 			wantErr:      false,
 			responseCode: 200,
 		},
+		{
+			name: "update max run time",
+			args: args{
+				ctx: &logger.RequestContext{UserName: mockUserName},
+				req: &job.UpdateJobRequest{
+					JobID:      MockJobID,
+					MaxRunTime: &maxRunTime,
+				},
+				router: router,
+			},
+			wantErr:      false,
+			responseCode: 200,
+		},
+		{
+			name: "invalid max run time",
+			args: args{
+				ctx: &logger.RequestContext{UserName: mockUserName},
+				req: &job.UpdateJobRequest{
+					JobID:      MockJobID,
+					MaxRunTime: &invalidMaxRunTime,
+				},
+				router: router,
+			},
+			wantErr:      false,
+			responseCode: 400,
+		},
 		{
 			name: "no permit",
 			args: args{
@@ -509,13 +509,19 @@ func (lr *LinkRouter) getLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := *getLinkListResult(listLinks, nextMarker, getRequest.Marker)
+	// fuse client needs secrets to access storage
+	withSecret := r.URL.Query().Get(util.QueryKeyWithSecret) == "true"
+	response := *getLinkListResult(listLinks, nextMarker, getRequest.Marker, withSecret)
 	ctx.Logging().Debugf("GetLink Link:%v", string(config.PrettyFormat(response)))
 	common.Render(w, http.StatusOK, response)
 }
 
-func linkResponseFromModel(link model.Link) *api.LinkResponse {
+func linkResponseFromModel(link model.Link, withSecret bool) *api.LinkResponse {
 	fsName, _, _ := fsUtils.GetFsNameAndUserNameByFsID(link.FsID)
+	properties := link.PropertiesMap
+	if !withSecret {
+		properties = model.RedactedProperties(properties)
+	}
 	return &api.LinkResponse{
 		FsName:        fsName,
 		FsPath:        link.FsPath,
@@ -523,14 +529,14 @@ func linkResponseFromModel(link model.Link) *api.LinkResponse {
 		Type:          link.Type,
 		SubPath:       link.SubPath,
 		Username:      link.UserName,
-		Properties:    link.PropertiesMap,
+		Properties:    properties,
 	}
 }
 
-func getLinkListResult(linkModel []model.Link, nextMarker, marker string) *api.GetLinkResponse {
+func getLinkListResult(linkModel []model.Link, nextMarker, marker string, withSecret bool) *api.GetLinkResponse {
 	var linkLists []*api.LinkResponse
 	for _, link := range linkModel {
-		linkList := linkResponseFromModel(link)
+		linkList := linkResponseFromModel(link, withSecret)
 		linkLists = append(linkLists, linkList)
 	}
 	ListFsResponse := &api.GetLinkResponse{
@@ -24,6 +24,7 @@ import (
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/pipeline"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/middleware"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
 )
@@ -38,11 +39,13 @@ func (pr *PipelineRouter) AddRouter(r chi.Router) {
 	log.Info("add pipeline router")
 	r.Post("/pipeline", pr.createPipeline)
 	r.Get("/pipeline", pr.listPipeline)
-	r.Post("/pipeline/{pipelineID}", pr.updatePipeline)
-	r.Get("/pipeline/{pipelineID}", pr.getPipeline)
-	r.Delete("/pipeline/{pipelineID}", pr.deletePipeline)
-	r.Get("/pipeline/{pipelineID}/{pipelineVersionID}", pr.getPipelineVersion)
-	r.Delete("/pipeline/{pipelineID}/{pipelineVersionID}", pr.deletePipelineVersion)
+	r.With(middleware.AuthorizePipeline(common.ActionWrite)).Post("/pipeline/{pipelineID}", pr.updatePipeline)
+	r.With(middleware.AuthorizePipeline(common.ActionRead)).Get("/pipeline/{pipelineID}", pr.getPipeline)
+	r.With(middleware.AuthorizePipeline(common.ActionWrite)).Delete("/pipeline/{pipelineID}", pr.deletePipeline)
+	r.With(middleware.AuthorizePipeline(common.ActionRead)).
+		Get("/pipeline/{pipelineID}/{pipelineVersionID}", pr.getPipelineVersion)
+	r.With(middleware.AuthorizePipeline(common.ActionWrite)).
+		Delete("/pipeline/{pipelineID}/{pipelineVersionID}", pr.deletePipelineVersion)
 }
 
 // createPipeline
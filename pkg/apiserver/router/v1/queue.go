@@ -24,6 +24,7 @@ import (
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/queue"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/middleware"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 )
@@ -38,9 +39,12 @@ func (qr *QueueRouter) AddRouter(r chi.Router) {
 	log.Info("add queue router")
 	r.Post("/queue", qr.createQueue)
 	r.Get("/queue", qr.listQueue)
-	r.Get("/queue/{queueName}", qr.getQueueByName)
-	r.Put("/queue/{queueName}", qr.updateQueue)
-	r.Delete("/queue/{queueName}", qr.deleteQueue)
+	r.With(middleware.Authorize(common.ResourceTypeQueue, common.ActionRead, util.ParamKeyQueueName)).
+		Get("/queue/{queueName}", qr.getQueueByName)
+	r.With(middleware.Authorize(common.ResourceTypeQueue, common.ActionWrite, util.ParamKeyQueueName)).
+		Put("/queue/{queueName}", qr.updateQueue)
+	r.With(middleware.Authorize(common.ResourceTypeQueue, common.ActionWrite, util.ParamKeyQueueName)).
+		Delete("/queue/{queueName}", qr.deleteQueue)
 }
 
 // createQueue
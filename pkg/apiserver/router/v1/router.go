@@ -55,12 +55,14 @@ func RegisterRouters(r *chi.Mux, debugMode bool) {
 			apiV1Router.Use(middleware.BaseAuth)
 		}
 		AddRouter(apiV1Router, &GrantRouter{})
+		AddRouter(apiV1Router, &GroupRouter{})
 		AddRouter(apiV1Router, &QueueRouter{})
 		AddRouter(apiV1Router, &FlavourRouter{})
 		AddRouter(apiV1Router, &RunRouter{})
 		AddRouter(apiV1Router, &PipelineRouter{})
 		AddRouter(apiV1Router, &ScheduleRouter{})
 		AddRouter(apiV1Router, &UserRouter{})
+		AddRouter(apiV1Router, &TokenRouter{})
 		AddRouter(apiV1Router, &LinkRouter{})
 		AddRouter(apiV1Router, &PFSRouter{})
 		AddRouter(apiV1Router, &ClusterRouter{})
@@ -30,6 +30,7 @@ import (
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/pipeline"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/middleware"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/trace_logger"
@@ -46,9 +47,10 @@ func (rr *RunRouter) AddRouter(r chi.Router) {
 	r.Post("/run", rr.createRun)
 	r.Post("/runjson", rr.createRunByJson)
 	r.Get("/run", rr.listRun)
-	r.Get("/run/{runID}", rr.getRunByID)
-	r.Put("/run/{runID}", rr.updateRun)
-	r.Delete("/run/{runID}", rr.deleteRun)
+	r.With(middleware.AuthorizeRun(common.ActionRead)).Get("/run/{runID}", rr.getRunByID)
+	r.With(middleware.AuthorizeRun(common.ActionRead)).Get("/run/{runID}/graph", rr.getRunGraph)
+	r.With(middleware.AuthorizeRun(common.ActionWrite)).Put("/run/{runID}", rr.updateRun)
+	r.With(middleware.AuthorizeRun(common.ActionWrite)).Delete("/run/{runID}", rr.deleteRun)
 }
 
 // createRun
@@ -226,7 +228,7 @@ func (rr *RunRouter) listRun(w http.ResponseWriter, r *http.Request) {
 func (rr *RunRouter) getRunByID(w http.ResponseWriter, r *http.Request) {
 	ctx := common.GetRequestContext(r)
 	runID := chi.URLParam(r, util.ParamKeyRunID)
-	runInfo, err := pipeline.GetRunByID(ctx.Logging(), ctx.UserName, runID)
+	runInfo, err := pipeline.GetRunByID(ctx.Logging(), runID)
 
 	// 优化RuntimeView结构，使显示结果更友好
 	runInfo.Runtime = runInfo.RemoveOuterDagView(runInfo.Runtime)
@@ -238,6 +240,54 @@ func (rr *RunRouter) getRunByID(w http.ResponseWriter, r *http.Request) {
 	common.Render(w, http.StatusOK, runInfo)
 }
 
+// getRunGraph
+// @Summary 获取运行的依赖图
+// @Description 获取运行的依赖图，支持 json, dot 和 mermaid 三种格式
+// @Id getRunGraph
+// @tags Run
+// @Accept  json
+// @Produce json
+// @Param runID path int true "运行ID"
+// @Param format query string false "依赖图格式，json/dot/mermaid，默认为json"
+// @Success 200 {object} pipeline.RunGraph "运行依赖图"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 500 {object} common.ErrorResponse "500"
+// @Router /run/{runID}/graph [GET]
+func (rr *RunRouter) getRunGraph(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	runID := chi.URLParam(r, util.ParamKeyRunID)
+	format := r.URL.Query().Get(util.QueryKeyFormat)
+	if format == "" {
+		format = pipeline.GraphFormatJson
+	}
+	if !pipeline.IsValidGraphFormat(format) {
+		ctx.ErrorCode = common.InvalidURI
+		err := fmt.Errorf("invalid format[%s] for run graph, only json, dot and mermaid are supported", format)
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+
+	graph, err := pipeline.GetRunGraph(&ctx, runID)
+	if err != nil {
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	if format == pipeline.GraphFormatJson {
+		common.Render(w, http.StatusOK, graph)
+		return
+	}
+
+	content, err := graph.String(format)
+	if err != nil {
+		ctx.ErrorCode = common.InternalError
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(content))
+}
+
 // updateRun
 // @Summary 修改运行
 // @Description 修改运行
@@ -277,7 +327,7 @@ func (rr *RunRouter) updateRun(w http.ResponseWriter, r *http.Request) {
 
 	switch action {
 	case util.QueryActionStop:
-		err = pipeline.StopRun(ctx.Logging(), ctx.UserName, runID, request)
+		err = pipeline.StopRun(ctx.Logging(), runID, request)
 		if err != nil {
 			ctx.ErrorCode = common.InternalError
 		}
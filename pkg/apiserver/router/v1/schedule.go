@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/pipeline"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/middleware"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
 )
@@ -38,9 +40,10 @@ func (sr *ScheduleRouter) AddRouter(r chi.Router) {
 	log.Info("add schedule router")
 	r.Post("/schedule", sr.createSchedule)
 	r.Get("/schedule", sr.listSchedule)
-	r.Get("/schedule/{scheduleID}", sr.getSchedule)
-	r.Put("/schedule/{scheduleID}", sr.stopSchedule)
-	r.Delete("/schedule/{scheduleID}", sr.deleteSchedule)
+	r.With(middleware.AuthorizeSchedule(common.ActionRead)).Get("/schedule/{scheduleID}", sr.getSchedule)
+	r.With(middleware.AuthorizeSchedule(common.ActionWrite)).Put("/schedule/{scheduleID}", sr.updateSchedule)
+	r.With(middleware.AuthorizeSchedule(common.ActionWrite)).Post("/schedule/{scheduleID}/backfill", sr.backfillSchedule)
+	r.With(middleware.AuthorizeSchedule(common.ActionWrite)).Delete("/schedule/{scheduleID}", sr.deleteSchedule)
 }
 
 func (sr *ScheduleRouter) createSchedule(w http.ResponseWriter, r *http.Request) {
@@ -144,20 +147,55 @@ func (sr *ScheduleRouter) getSchedule(w http.ResponseWriter, r *http.Request) {
 	common.Render(w, http.StatusOK, getScheduleResponse)
 }
 
-func (sr *ScheduleRouter) stopSchedule(w http.ResponseWriter, r *http.Request) {
+// updateSchedule 根据 action 停止、暂停或恢复周期调度，action 为空时默认停止，以兼容旧版本的接口
+func (sr *ScheduleRouter) updateSchedule(w http.ResponseWriter, r *http.Request) {
 	ctx := common.GetRequestContext(r)
 	scheduleID := chi.URLParam(r, util.ParamKeyScheduleID)
-	logger.LoggerForRequest(&ctx).Debugf("stop schedule id:%v", scheduleID)
-
-	err := pipeline.StopSchedule(&ctx, scheduleID)
+	action := r.URL.Query().Get(util.QueryKeyAction)
+	logger.LoggerForRequest(&ctx).Debugf("update schedule id:%v, action:%s", scheduleID, action)
+
+	var err error
+	switch action {
+	case "", util.QueryActionStop:
+		err = pipeline.StopSchedule(&ctx, scheduleID)
+	case util.QueryActionPause:
+		err = pipeline.PauseSchedule(&ctx, scheduleID)
+	case util.QueryActionResume:
+		err = pipeline.ResumeSchedule(&ctx, scheduleID)
+	default:
+		ctx.ErrorCode = common.InvalidURI
+		err = fmt.Errorf("invalid action[%s] for update schedule", action)
+	}
 	if err != nil {
-		ctx.Logging().Errorf("stop schedule: %s failed. error:%s", scheduleID, err.Error())
+		ctx.Logging().Errorf("update schedule: %s with action[%s] failed. error:%s", scheduleID, action, err.Error())
 		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
 		return
 	}
 	common.RenderStatus(w, http.StatusOK)
 }
 
+func (sr *ScheduleRouter) backfillSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	scheduleID := chi.URLParam(r, util.ParamKeyScheduleID)
+
+	backfillInfo := pipeline.BackfillScheduleRequest{}
+	if err := common.BindJSON(r, &backfillInfo); err != nil {
+		logger.LoggerForRequest(&ctx).Errorf(
+			"backfill schedule failed parsing request body:%+v. error:%s", r.Body, err.Error())
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	logger.LoggerForRequest(&ctx).Debugf("backfill schedule id:%v, request:%v", scheduleID, backfillInfo)
+
+	response, err := pipeline.BackfillSchedule(&ctx, scheduleID, &backfillInfo)
+	if err != nil {
+		ctx.Logging().Errorf("backfill schedule: %s failed. error:%s", scheduleID, err.Error())
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	common.Render(w, http.StatusCreated, response)
+}
+
 func (sr *ScheduleRouter) deleteSchedule(w http.ResponseWriter, r *http.Request) {
 	ctx := common.GetRequestContext(r)
 	scheduleID := chi.URLParam(r, util.ParamKeyScheduleID)
@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/token"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/router/util"
+)
+
+type TokenRouter struct {
+}
+
+func (tr *TokenRouter) Name() string {
+	return "TokenRouter"
+}
+
+func (tr *TokenRouter) AddRouter(r chi.Router) {
+	log.Info("add token router")
+	r.Post("/token", tr.createToken)
+	r.Get("/token", tr.listToken)
+	r.Delete("/token/{tokenID}", tr.deleteToken)
+}
+
+// createToken
+// @Summary 创建access token
+// @Description 创建长期有效、可撤销并限定权限范围的access token, token仅在创建时返回
+// @Id createToken
+// @tags Token
+// @Accept  json
+// @Produce json
+// @Param request body token.CreateTokenRequest true "创建access token请求"
+// @Success 200 {object} token.CreateTokenResponse "创建access token响应"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 403 {object} common.ErrorResponse "403"
+// @Failure 500 {object} common.ErrorResponse "500"
+// @Router /token [POST]
+func (tr *TokenRouter) createToken(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	var request token.CreateTokenRequest
+	err := common.BindJSON(r, &request)
+	if err != nil {
+		ctx.Logging().Errorf("createToken bindjson failed. error:%s", err.Error())
+		common.RenderErr(w, ctx.RequestID, common.MalformedJSON)
+		return
+	}
+	response, err := token.CreateToken(&ctx, request)
+	if err != nil {
+		ctx.Logging().Errorf(
+			"create access token failed. request:%v error:%s", request, err.Error())
+		common.RenderErrWithMessage(w, ctx.RequestID, ctx.ErrorCode, err.Error())
+		return
+	}
+	common.Render(w, http.StatusOK, response)
+}
+
+// listToken
+// @Summary 获取access token列表
+// @Description 获取access token列表, 非root用户只能获取自己的token
+// @Id listToken
+// @tags Token
+// @Accept  json
+// @Produce json
+// @Param username query string false "用户名称过滤"
+// @Param maxKeys query int false "每页包含的最大数量，缺省值为50"
+// @Param marker query string false "批量获取列表的查询的起始位置，是一个由系统生成的字符串"
+// @Success 200 {object} token.ListTokenResponse "获取access token列表的响应"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 500 {object} common.ErrorResponse "500"
+// @Router /token [GET]
+func (tr *TokenRouter) listToken(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	marker := r.URL.Query().Get(util.QueryKeyMarker)
+	maxKeys, err := util.GetQueryMaxKeys(&ctx, r)
+	if err != nil {
+		common.RenderErrWithMessage(w, ctx.RequestID, common.InvalidURI, err.Error())
+		return
+	}
+
+	userName := r.URL.Query().Get(util.QueryKeyUserName)
+	ctx.Logging().Debugf(
+		"ListToken marker:[%s] maxKeys:[%d] userName:[%s]",
+		marker, maxKeys, userName)
+	response, err := token.ListToken(&ctx, marker, maxKeys, userName)
+	if err != nil {
+		ctx.Logging().Errorf("list access tokens failed. error:%s.", err.Error())
+		common.RenderErr(w, ctx.RequestID, ctx.ErrorCode)
+		return
+	}
+	common.Render(w, http.StatusOK, response)
+}
+
+// deleteToken
+// @Summary 删除access token
+// @Description 删除access token, 使用该token的请求立即失效
+// @Id deleteToken
+// @tags Token
+// @Accept  json
+// @Produce json
+// @Param tokenID path string true "access token ID"
+// @Success 200 {string} string "成功删除access token的响应码"
+// @Failure 400 {object} common.ErrorResponse "400"
+// @Failure 404 {object} common.ErrorResponse "404"
+// @Failure 500 {object} common.ErrorResponse "500"
+// @Router /token/{tokenID} [DELETE]
+func (tr *TokenRouter) deleteToken(w http.ResponseWriter, r *http.Request) {
+	ctx := common.GetRequestContext(r)
+	tokenID := chi.URLParam(r, util.ParamKeyTokenID)
+	if err := token.DeleteToken(&ctx, tokenID); err != nil {
+		ctx.Logging().Errorf("delete access token failed. tokenID:%s error:%s", tokenID, err.Error())
+		common.RenderErr(w, ctx.RequestID, ctx.ErrorCode)
+		return
+	}
+	common.RenderStatus(w, http.StatusOK)
+}
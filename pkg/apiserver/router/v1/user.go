@@ -74,7 +74,7 @@ func (ur *UserRouter) login(w http.ResponseWriter, r *http.Request) {
 		common.RenderErr(w, ctx.RequestID, ctx.ErrorCode)
 		return
 	}
-	token, err := middleware.GenerateToken(u.Name, u.Password)
+	token, err := middleware.GenerateToken(u.Name)
 	if err != nil {
 		ctx.Logging().Errorf(
 			"generate token failed. username:%v error:%s", req.UserName, err.Error())
@@ -22,6 +22,7 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/secret"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/trace_logger"
 )
 
@@ -53,6 +54,7 @@ type ServerConfig struct {
 	ImageConf ImageConfig                    `yaml:"imageRepository"`
 	Monitor   PrometheusConfig               `yaml:"monitor"`
 	Metrics   MetricsConfig                  `yaml:"metrics"`
+	Secret    SecretConfig                   `yaml:"secret"`
 }
 
 type StorageConfig struct {
@@ -68,13 +70,32 @@ type StorageConfig struct {
 	MaxIdleConns                         *int   `yaml:"maxIdleConns,omitempty"`
 	MaxOpenConns                         *int   `yaml:"maxOpenConns,omitempty"`
 	ConnMaxLifetimeInHours               *int   `yaml:"connMaxLifetimeInHours,omitempty"`
+	// SSLMode is sslmode of postgres, disable by default
+	SSLMode string `yaml:"sslMode,omitempty"`
+	// MigrateOnStart applies database migrations when server starts, migrations of sqlite are always applied
+	MigrateOnStart bool `yaml:"migrateOnStart,omitempty"`
 }
 
 type ApiServerConfig struct {
 	// Host Port used for FS to create pv/pvc with volumeAttributes point pfs-server pod
-	Host                string `yaml:"host"`
-	Port                int    `yaml:"port"`
-	TokenExpirationHour int    `yaml:"tokenExpirationHour"`
+	Host                string    `yaml:"host"`
+	Port                int       `yaml:"port"`
+	TokenExpirationHour int       `yaml:"tokenExpirationHour"`
+	JWT                 JWTConfig `yaml:"jwt"`
+}
+
+// JWTConfig configures HMAC keys of tokens. tokens are signed by the key of SigningKeyID and verified by the key of
+// kid in token header, so keys can be rotated by adding a new key, signing with it, and removing the old one later.
+type JWTConfig struct {
+	SigningKeyID string   `yaml:"signingKeyID"`
+	Keys         []JWTKey `yaml:"keys"`
+}
+
+type JWTKey struct {
+	ID string `yaml:"id"`
+	// Secret or file containing the secret, SecretFile is preferred for secret not in config file
+	Secret     string `yaml:"secret,omitempty" json:"-"`
+	SecretFile string `yaml:"secretFile,omitempty"`
 }
 
 type JobConfig struct {
@@ -124,6 +145,11 @@ type PrometheusConfig struct {
 	ExporterServicePort int    `yaml:"exporterServicePort"`
 }
 
+// SecretConfig configures envelope encryption of cluster credentials and file system secrets in database
+type SecretConfig struct {
+	secret.Config `yaml:",inline"`
+}
+
 type MetricsConfig struct {
 	Port   int  `yaml:"port"`
 	Enable bool `yaml:"enable"`
@@ -32,6 +32,8 @@ const (
 	GetLinksApis      = Prefix + "/link"
 	CacheReportConfig = Prefix + "/fsCache/report"
 	KeyUsername       = "username"
+	// KeyWithSecret asks for secret properties of fs and links, which are redacted by default
+	KeyWithSecret = "withSecret"
 )
 
 type LoginParams struct {
@@ -105,6 +107,7 @@ func FsRequest(params FsParams, c *core.PaddleFlowClient) (*FsResponse, error) {
 		WithHeader(common.HeaderKeyAuthorization, params.Token).
 		WithURL(GetFsApi+"/"+params.FsName).
 		WithQueryParam(KeyUsername, params.UserName).
+		WithQueryParam(KeyWithSecret, "true").
 		WithMethod(http.GET).
 		WithResult(resp).
 		Do()
@@ -119,7 +122,8 @@ func LinksRequest(params LinksParams, c *core.PaddleFlowClient) (*LinksResponse,
 	err := core.NewRequestBuilder(c).
 		WithHeader(common.HeaderKeyAuthorization, params.Token).
 		WithURL(GetLinksApis+"/"+params.FsName).
-		WithQueryParam(KeyUsername, params.UserName).WithMethod(http.GET).
+		WithQueryParam(KeyUsername, params.UserName).
+		WithQueryParam(KeyWithSecret, "true").WithMethod(http.GET).
 		WithResult(resp).
 		Do()
 	if err != nil {
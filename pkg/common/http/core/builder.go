@@ -17,7 +17,9 @@ limitations under the License.
 package core
 
 import (
+	"context"
 	"fmt"
+	"time"
 )
 
 // RequestBuilder holds config data for bce request.
@@ -32,13 +34,46 @@ type RequestBuilder struct {
 	headers     map[string]string   // optional
 	body        interface{}         // optional
 	result      interface{}         // optional
+	ctx         context.Context     // optional
+	timeout     time.Duration       // optional
+	retry       *RetryPolicy        // optional
+	idempotent  *bool               // optional
 }
 
-// create RequestBuilder with the client.
+// create RequestBuilder with the client, timeout and retry policy are inherited from the client configuration.
 func NewRequestBuilder(client Client) *RequestBuilder {
-	return &RequestBuilder{
+	b := &RequestBuilder{
 		client: client,
 	}
+	if c, ok := client.(*PaddleFlowClient); ok && c != nil && c.Config != nil {
+		b.timeout = c.Config.RequestTimeout
+		b.retry = c.Config.Retry
+	}
+	return b
+}
+
+// WithContext cancels the request and retries when ctx is done.
+func (b *RequestBuilder) WithContext(ctx context.Context) *RequestBuilder {
+	b.ctx = ctx
+	return b
+}
+
+// WithTimeout limits each attempt of the request, zero means no limit.
+func (b *RequestBuilder) WithTimeout(timeout time.Duration) *RequestBuilder {
+	b.timeout = timeout
+	return b
+}
+
+// WithRetry overrides retry policy of the client, nil disables retry.
+func (b *RequestBuilder) WithRetry(policy *RetryPolicy) *RequestBuilder {
+	b.retry = policy
+	return b
+}
+
+// WithIdempotent marks whether the request can be retried, by default only GET, HEAD, PUT, DELETE and OPTIONS can.
+func (b *RequestBuilder) WithIdempotent(idempotent bool) *RequestBuilder {
+	b.idempotent = &idempotent
+	return b
 }
 
 func (b *RequestBuilder) WithURL(url string) *RequestBuilder {
@@ -113,16 +148,44 @@ func (b *RequestBuilder) WithResult(result interface{}) *RequestBuilder {
 }
 
 // Do will send request to core and get result with the builder's parameters.
+// idempotent requests failed with retryable errors are retried with the retry policy.
 func (b *RequestBuilder) Do() error {
 	if err := b.validate(); err != nil {
 		return err
 	}
+	ctx := b.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for retries := 0; ; retries++ {
+		err := b.do(ctx)
+		if err == nil || !b.retryable() || !b.retry.shouldRetry(err, retries) {
+			return err
+		}
+		timer := time.NewTimer(b.retry.backoff(retries))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
 
-	// build PFRequest
+func (b *RequestBuilder) do(ctx context.Context) error {
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+
+	// build PFRequest, body is built for each attempt as it is consumed by the former one
 	req, err := b.buildPFRequest()
 	if err != nil {
 		return err
 	}
+	req.SetContext(ctx)
 
 	// get result from PFResponse
 	if err := b.buildPFResponse(req); err != nil {
@@ -132,6 +195,16 @@ func (b *RequestBuilder) Do() error {
 	return nil
 }
 
+func (b *RequestBuilder) retryable() bool {
+	if b.retry == nil {
+		return false
+	}
+	if b.idempotent != nil {
+		return *b.idempotent
+	}
+	return isIdempotent(b.method)
+}
+
 // Validate if the required fields are providered.
 func (b *RequestBuilder) validate() error {
 	if len(b.url) == 0 {
@@ -0,0 +1,222 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	pfhttp "github.com/PaddlePaddle/PaddleFlow/pkg/common/http/util/http"
+)
+
+func newTestClient(t *testing.T, server *httptest.Server, retry *RetryPolicy) *PaddleFlowClient {
+	u, err := url.Parse(server.URL)
+	assert.Nil(t, err)
+	port, err := strconv.Atoi(u.Port())
+	assert.Nil(t, err)
+	return NewPaddleFlowClient(&PaddleFlowClientConfiguration{
+		Host:                       u.Hostname(),
+		Port:                       port,
+		ConnectionTimeoutInSeconds: 1,
+		Retry:                      retry,
+	})
+}
+
+func writeServiceError(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"requestID":"req-1","code":"%s","message":"mock error"}`, code)
+}
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}
+}
+
+func TestRequestBuilderRetry(t *testing.T) {
+	testCases := []struct {
+		name      string
+		method    string
+		status    int
+		failTimes int32
+		wantCalls int32
+		wantErr   bool
+	}{
+		{
+			name:      "get retried on service unavailable",
+			method:    pfhttp.GET,
+			status:    http.StatusServiceUnavailable,
+			failTimes: 2,
+			wantCalls: 3,
+		},
+		{
+			name:      "get failed after max retries",
+			method:    pfhttp.GET,
+			status:    http.StatusBadGateway,
+			failTimes: 5,
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			name:      "post not retried",
+			method:    pfhttp.POST,
+			status:    http.StatusServiceUnavailable,
+			failTimes: 1,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "bad request not retried",
+			method:    pfhttp.GET,
+			status:    http.StatusBadRequest,
+			failTimes: 1,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) <= tc.failTimes {
+					writeServiceError(w, tc.status, common.InternalError)
+					return
+				}
+				w.Write([]byte(`{"name":"ok"}`))
+			}))
+			defer server.Close()
+
+			result := map[string]string{}
+			err := NewRequestBuilder(newTestClient(t, server, testRetryPolicy())).
+				WithURL("/api/paddleflow/v1/test").
+				WithMethod(tc.method).
+				WithBody(map[string]string{"name": "test"}).
+				WithResult(&result).
+				Do()
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.wantCalls, atomic.LoadInt32(&calls))
+			if !tc.wantErr {
+				assert.Equal(t, "ok", result["name"])
+			}
+		})
+	}
+}
+
+func TestRequestBuilderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	err := NewRequestBuilder(newTestClient(t, server, nil)).
+		WithURL("/api/paddleflow/v1/test").
+		WithMethod(pfhttp.GET).
+		WithTimeout(50 * time.Millisecond).
+		Do()
+	assert.NotNil(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// canceled context stops retries
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	err = NewRequestBuilder(newTestClient(t, server, testRetryPolicy())).
+		WithContext(ctx).
+		WithURL("/api/paddleflow/v1/test").
+		WithMethod(pfhttp.GET).
+		Do()
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestServiceErrorIs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeServiceError(w, http.StatusNotFound, common.JobNotFound)
+	}))
+	defer server.Close()
+
+	err := NewRequestBuilder(newTestClient(t, server, testRetryPolicy())).
+		WithURL("/api/paddleflow/v1/job/job-1").
+		WithMethod(pfhttp.GET).
+		Do()
+	assert.True(t, errors.Is(err, ErrJobNotFound))
+	assert.False(t, errors.Is(err, ErrRunNotFound))
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsForbidden(err))
+	assert.False(t, IsRetryable(err))
+
+	wrapped := fmt.Errorf("get job failed: %w", err)
+	assert.True(t, errors.Is(wrapped, ErrJobNotFound))
+	assert.True(t, IsUnauthorized(NewPFServiceError(common.AuthInvalidToken, "", "", http.StatusBadRequest)))
+}
+
+func TestIterator(t *testing.T) {
+	pages := map[string]Page[int]{
+		"":   {Items: []int{1, 2}, NextMarker: "m1", IsTruncated: true},
+		"m1": {Items: []int{}, NextMarker: "m2", IsTruncated: true},
+		"m2": {Items: []int{3}, NextMarker: "", IsTruncated: false},
+	}
+	var markers []string
+	pageFunc := func(ctx context.Context, marker string) (Page[int], error) {
+		markers = append(markers, marker)
+		return pages[marker], nil
+	}
+
+	items, err := NewIterator(context.TODO(), "", pageFunc).All()
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, items)
+	assert.Equal(t, []string{"", "m1", "m2"}, markers)
+
+	// error of page stops iteration
+	pageErr := errors.New("list failed")
+	it := NewIterator(context.TODO(), "", func(ctx context.Context, marker string) (Page[int], error) {
+		if marker == "m1" {
+			return Page[int]{}, pageErr
+		}
+		return pages[marker], nil
+	})
+	items, err = it.All()
+	assert.Equal(t, pageErr, err)
+	assert.Equal(t, []int{1, 2}, items)
+	assert.False(t, it.Next())
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for retries, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond,
+		400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		d := policy.backoff(retries)
+		assert.GreaterOrEqual(t, d, max/2)
+		assert.LessOrEqual(t, d, max)
+	}
+}
@@ -16,7 +16,10 @@ limitations under the License.
 
 package core
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 const (
 	API_V1_PREFIX = "/api/core/v1"
@@ -26,12 +29,18 @@ type PaddleFlowClientConfiguration struct {
 	Host                       string
 	Port                       int
 	ConnectionTimeoutInSeconds int
+	// RequestTimeout limits each attempt of a request, including reading response body. no limit if zero
+	RequestTimeout time.Duration
+	// Retry retries idempotent requests which failed with retryable errors, no retry if nil
+	Retry *RetryPolicy
 }
 
 func (b *PaddleFlowClientConfiguration) String() string {
 	return fmt.Sprintf(`PaddleFlowClientConfiguration[
 		Host=%s;
 		Port=%v;
-		ConnectionTimeoutInSeconds=%v
-	]`, b.Host, b.Port, b.ConnectionTimeoutInSeconds)
+		ConnectionTimeoutInSeconds=%v;
+		RequestTimeout=%v;
+		Retry=%+v
+	]`, b.Host, b.Port, b.ConnectionTimeoutInSeconds, b.RequestTimeout, b.Retry)
 }
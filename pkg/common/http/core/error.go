@@ -16,6 +16,14 @@ limitations under the License.
 
 package core
 
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+)
+
 const (
 	EACCESS_DENIED        = "AccessDenied"
 	EINTERNAL_ERROR       = "InternalError"
@@ -41,3 +49,66 @@ func (b *PFServiceError) Error() string {
 func NewPFServiceError(code, msg, reqId string, status int) *PFServiceError {
 	return &PFServiceError{code, msg, reqId, status}
 }
+
+// Is reports whether target is a PFServiceError with the same error code,
+// so that errors returned by requests can be checked by errors.Is(err, core.ErrJobNotFound)
+func (b *PFServiceError) Is(target error) bool {
+	t, ok := target.(*PFServiceError)
+	if !ok || t.Code == "" {
+		return false
+	}
+	return t.Code == b.Code
+}
+
+// errors mapped from error codes of server
+var (
+	ErrAccessDenied       = &PFServiceError{Code: common.AccessDenied}
+	ErrActionNotAllowed   = &PFServiceError{Code: common.ActionNotAllowed}
+	ErrOnlyRootAllowed    = &PFServiceError{Code: common.OnlyRootAllowed}
+	ErrInternalError      = &PFServiceError{Code: common.InternalError}
+	ErrInvalidArguments   = &PFServiceError{Code: common.InvalidArguments}
+	ErrInvalidMarker      = &PFServiceError{Code: common.InvalidMarker}
+	ErrRecordNotFound     = &PFServiceError{Code: common.RecordNotFound}
+	ErrDuplicatedName     = &PFServiceError{Code: common.DuplicatedName}
+	ErrAuthWithoutToken   = &PFServiceError{Code: common.AuthWithoutToken}
+	ErrAuthInvalidToken   = &PFServiceError{Code: common.AuthInvalidToken}
+	ErrAuthFailed         = &PFServiceError{Code: common.AuthFailed}
+	ErrUserNotExist       = &PFServiceError{Code: common.UserNotExist}
+	ErrQueueNotFound      = &PFServiceError{Code: common.QueueNameNotFound}
+	ErrQueueIsInUse       = &PFServiceError{Code: common.QueueIsInUse}
+	ErrGrantNotFound      = &PFServiceError{Code: common.GrantNotFound}
+	ErrGrantAlreadyExist  = &PFServiceError{Code: common.GrantAlreadyExist}
+	ErrRunNotFound        = &PFServiceError{Code: common.RunNotFound}
+	ErrPipelineNotFound   = &PFServiceError{Code: common.PipelineNotFound}
+	ErrRunCacheNotFound   = &PFServiceError{Code: common.RunCacheNotFound}
+	ErrArtifactNotFound   = &PFServiceError{Code: common.ArtifactEventNotFound}
+	ErrFlavourNotFound    = &PFServiceError{Code: common.FlavourNotFound}
+	ErrJobNotFound        = &PFServiceError{Code: common.JobNotFound}
+	ErrClusterNotFound    = &PFServiceError{Code: common.ClusterNotFound}
+	ErrFileSystemNotExist = &PFServiceError{Code: common.FileSystemNotExist}
+	ErrLinkNotExist       = &PFServiceError{Code: common.LinkNotExist}
+)
+
+// IsNotFound returns true if the resource requested does not exist
+func IsNotFound(err error) bool {
+	var serviceErr *PFServiceError
+	if !errors.As(err, &serviceErr) {
+		return false
+	}
+	return serviceErr.StatusCode == http.StatusNotFound ||
+		strings.HasSuffix(serviceErr.Code, "NotFound") || strings.HasSuffix(serviceErr.Code, "NotExist")
+}
+
+// IsForbidden returns true if the request is denied for permission or state of the resource
+func IsForbidden(err error) bool {
+	var serviceErr *PFServiceError
+	if !errors.As(err, &serviceErr) {
+		return false
+	}
+	return serviceErr.StatusCode == http.StatusForbidden
+}
+
+// IsUnauthorized returns true if the token is missing or invalid
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrAuthWithoutToken) || errors.Is(err, ErrAuthInvalidToken)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+)
+
+// Page is a page of items returned by list api paginated with marker
+type Page[T any] struct {
+	Items       []T
+	NextMarker  string
+	IsTruncated bool
+}
+
+// PageFunc gets the page starting from marker
+type PageFunc[T any] func(ctx context.Context, marker string) (Page[T], error)
+
+// Iterator iterates items of all pages of a list api, pages are requested lazily
+//
+//	it := core.NewIterator(ctx, marker, pageFunc)
+//	for it.Next() {
+//		item := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	ctx      context.Context
+	pageFunc PageFunc[T]
+	marker   string
+	items    []T
+	index    int
+	item     T
+	done     bool
+	err      error
+}
+
+func NewIterator[T any](ctx context.Context, marker string, pageFunc PageFunc[T]) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:      ctx,
+		pageFunc: pageFunc,
+		marker:   marker,
+	}
+}
+
+// Next advances to the next item, it returns false when all items are iterated or an error occurs
+func (it *Iterator[T]) Next() bool {
+	for it.index >= len(it.items) {
+		if it.done || it.err != nil {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		page, err := it.pageFunc(it.ctx, it.marker)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.items, it.index = page.Items, 0
+		it.marker = page.NextMarker
+		// stop if server returns no next marker, otherwise the same page is requested forever
+		it.done = !page.IsTruncated || page.NextMarker == ""
+	}
+	it.item = it.items[it.index]
+	it.index++
+	return true
+}
+
+// Item returns the current item
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error which stops the iteration
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All collects all remaining items
+func (it *Iterator[T]) All() ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
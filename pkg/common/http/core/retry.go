@@ -0,0 +1,113 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	pfhttp "github.com/PaddlePaddle/PaddleFlow/pkg/common/http/util/http"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+)
+
+// RetryPolicy retries requests with exponential backoff and jitter
+type RetryPolicy struct {
+	// MaxRetries is the max number of retries after the first attempt
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry, which is doubled for each retry
+	InitialBackoff time.Duration
+	// MaxBackoff limits the wait between two attempts
+	MaxBackoff time.Duration
+	// RetryOn decides whether the error should be retried, IsRetryable is used if nil
+	RetryOn func(err error) bool
+}
+
+// DefaultRetryPolicy retries 3 times with backoff from 200ms to 5s
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     defaultMaxRetries,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+	}
+}
+
+func (p *RetryPolicy) shouldRetry(err error, retries int) bool {
+	if p == nil || retries >= p.MaxRetries {
+		return false
+	}
+	if p.RetryOn != nil {
+		return p.RetryOn(err)
+	}
+	return IsRetryable(err)
+}
+
+// backoff returns wait before the retry, a random value in [d/2, d] where d grows exponentially
+func (p *RetryPolicy) backoff(retries int) time.Duration {
+	initial, max := p.InitialBackoff, p.MaxBackoff
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+	d := initial
+	for i := 0; i < retries && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// IsRetryable returns true for transport errors, too many requests and server errors which may be transient.
+// errors caused by cancel or deadline of caller's context are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var serviceErr *PFServiceError
+	if errors.As(err, &serviceErr) {
+		switch serviceErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		default:
+			return false
+		}
+	}
+	// errors of connection, or deadline exceeded of a single attempt
+	return true
+}
+
+// isIdempotent returns whether requests of method can be sent repeatedly without side effect
+func isIdempotent(method string) bool {
+	switch method {
+	case pfhttp.GET, pfhttp.HEAD, pfhttp.PUT, pfhttp.DELETE, pfhttp.OPTIONS:
+		return true
+	default:
+		return false
+	}
+}
@@ -107,7 +107,7 @@ func Execute(request *Request) (*Response, error) {
 		header[k] = val
 	}
 
-	httpRequest, err := http.NewRequest(request.method, url, request.body)
+	httpRequest, err := http.NewRequestWithContext(request.Context(), request.method, url, request.body)
 	if err != nil {
 		return nil, fmt.Errorf("new request with method[%s] and url[%s] failed, %v", request.method, url, err)
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package http
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strconv"
@@ -34,6 +35,7 @@ type Request struct {
 	headers map[string]string
 	params  map[string][]string
 	body    io.ReadCloser
+	ctx     context.Context
 }
 
 func (r *Request) Host() string {
@@ -141,6 +143,18 @@ func (r *Request) QueryString() string {
 	return strings.Join(buf, "&")
 }
 
+// Context returns context of the request, which is context.Background if not set
+func (r *Request) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+func (r *Request) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
 func (r *Request) Body() io.ReadCloser {
 	return r.body
 }
@@ -54,6 +54,7 @@ var (
 		PyTorchJobGVK:   true,
 		TFJobGVK:        true,
 		MXNetJobGVK:     true,
+		XGBoostJobGVK:   true,
 		MPIJobGVK:       true,
 		RayJobGVK:       true,
 	}
@@ -77,6 +78,8 @@ func GetJobFrameworkVersion(jobType commomschema.JobType, framework commomschema
 		gvk = PaddleJobGVK
 	case commomschema.FrameworkMXNet:
 		gvk = MXNetJobGVK
+	case commomschema.FrameworkXGBoost:
+		gvk = XGBoostJobGVK
 	case commomschema.FrameworkMPI:
 		gvk = MPIJobGVK
 	case commomschema.FrameworkRay:
@@ -99,6 +102,8 @@ func GetJobTypeAndFramework(gvk schema.GroupVersionKind) (commomschema.JobType,
 		return commomschema.TypeDistributed, commomschema.FrameworkTF
 	case MXNetJobGVK:
 		return commomschema.TypeDistributed, commomschema.FrameworkMXNet
+	case XGBoostJobGVK:
+		return commomschema.TypeDistributed, commomschema.FrameworkXGBoost
 	case MPIJobGVK:
 		return commomschema.TypeDistributed, commomschema.FrameworkMPI
 	case RayJobGVK:
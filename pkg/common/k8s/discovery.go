@@ -48,6 +48,8 @@ var DiscoveryHandlerFunc = http.HandlerFunc(func(w http.ResponseWriter, req *htt
 				{Name: "pytorchjobs", Namespaced: true, Kind: "PyTorchJob"},
 				{Name: "tfjobs", Namespaced: true, Kind: "TFJob"},
 				{Name: "mpijobs", Namespaced: true, Kind: "MPIJob"},
+				{Name: "mxjobs", Namespaced: true, Kind: "MXJob"},
+				{Name: "xgboostjobs", Namespaced: true, Kind: "XGBoostJob"},
 			},
 		}
 	case "/apis/argoproj.io/v1alpha1":
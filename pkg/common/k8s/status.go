@@ -252,12 +252,17 @@ func MXNetJobStatus(obj interface{}) (StatusInfo, error) {
 	return kubeflowJobStatus(obj, MXNetJobGVK)
 }
 
+// XGBoostJobStatus get job status, message for XGBoostJob
+func XGBoostJobStatus(obj interface{}) (StatusInfo, error) {
+	return kubeflowJobStatus(obj, XGBoostJobGVK)
+}
+
 // MPIJobStatus get job status, message for MPIJob
 func MPIJobStatus(obj interface{}) (StatusInfo, error) {
 	return kubeflowJobStatus(obj, MPIJobGVK)
 }
 
-// kubeflowJobStatus get job status and message for PyTorch, TFJob, MXJob, MPIJob, and covert origin status to PF JobStatus
+// kubeflowJobStatus get job status and message for PyTorch, TFJob, MXJob, XGBoostJob, MPIJob, and covert origin status to PF JobStatus
 func kubeflowJobStatus(obj interface{}, gvk k8sschema.GroupVersionKind) (StatusInfo, error) {
 	status, err := ConvertToStatus(obj, gvk)
 	if err != nil {
@@ -49,6 +49,11 @@ const (
 	AnnotationKeyCacheDir    = "cacheDir"
 	AnnotationKeyMTime       = "modifiedTime"
 	AnnotationKeyMountPrefix = "mount-"
+	// warmup task dispatched by server and its progress reported by cache-worker
+	AnnotationKeyWarmup       = "warmup"
+	AnnotationKeyWarmupStatus = "warmupStatus"
+	// ufs reachability probed by cache-worker through mount point
+	AnnotationKeyUfsHealth = "ufsHealth"
 
 	EnvKeyMountPodName = "POD_NAME"
 	EnvKeyNamespace    = "NAMESPACE"
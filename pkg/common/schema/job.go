@@ -49,6 +49,8 @@ const (
 	EnvMountPath  = "PF_MOUNT_PATH"
 
 	EnvJobRestartPolicy = "PF_JOB_RESTART_POLICY"
+	// EnvJobDeadline the deadline of job, used by deadline sort policy of queue, in RFC3339 format or unix seconds
+	EnvJobDeadline = "PF_JOB_DEADLINE"
 
 	EnvEnableJobQueueSync = "PF_JOB_QUEUE_SYNC"
 
@@ -94,6 +96,8 @@ const (
 	StatusJobTerminated  JobStatus = "terminated"
 	StatusJobCancelled   JobStatus = "cancelled"
 	StatusJobSkipped     JobStatus = "skipped"
+	// StatusJobTimeout only used by components of pipeline, which are stopped by timeout
+	StatusJobTimeout JobStatus = "timeout"
 
 	StatusTaskPending   TaskStatus = "pending"
 	StatusTaskRunning   TaskStatus = "running"
@@ -117,6 +121,7 @@ const (
 	FrameworkPytorch    Framework = "pytorch"
 	FrameworkPaddle     Framework = "paddle"
 	FrameworkMXNet      Framework = "mxnet"
+	FrameworkXGBoost    Framework = "xgboost"
 	FrameworkRay        Framework = "ray"
 	FrameworkStandalone Framework = "standalone"
 
@@ -150,6 +155,8 @@ const (
 	JobIDLabel        = "paddleflow-job-id"
 	JobTTLSeconds     = "padleflow/job-ttl-seconds"
 	JobLabelFramework = "paddleflow-job-framework"
+	// JobResubmitFromLabel records the id of original job for resubmitted job
+	JobResubmitFromLabel = "paddleflow-resubmit-from"
 
 	VolcanoJobNameLabel  = "volcano.sh/job-name"
 	QueueLabelKey        = "volcano.sh/queue-name"
@@ -201,7 +208,7 @@ const (
 
 func IsImmutableJobStatus(status JobStatus) bool {
 	switch status {
-	case StatusJobSucceeded, StatusJobFailed, StatusJobTerminated, StatusJobSkipped, StatusJobCancelled:
+	case StatusJobSucceeded, StatusJobFailed, StatusJobTerminated, StatusJobSkipped, StatusJobCancelled, StatusJobTimeout:
 		return true
 	default:
 		return false
@@ -260,6 +267,8 @@ type Conf struct {
 	Image       string            `json:"image"`
 	Port        int               `json:"port,omitempty"`
 	Args        []string          `json:"args,omitempty"`
+	// MaxRunTime 作业最大运行时间，单位为秒，0表示不限制
+	MaxRunTime int64 `json:"maxRunTime,omitempty"`
 }
 
 // FileSystem indicate PaddleFlow
@@ -330,6 +339,14 @@ func (c *Conf) SetPriority(pc string) {
 	c.Priority = pc
 }
 
+func (c *Conf) GetMaxRunTime() int64 {
+	return c.MaxRunTime
+}
+
+func (c *Conf) SetMaxRunTime(maxRunTime int64) {
+	c.MaxRunTime = maxRunTime
+}
+
 func (c *Conf) GetQueueName() string {
 	return c.QueueName
 }
@@ -484,8 +501,24 @@ func (s Conf) Value() (driver.Value, error) {
 }*/
 
 type Member struct {
-	ID       string     `json:"id"`
-	Replicas int        `json:"replicas"`
-	Role     MemberRole `json:"role"`
-	Conf     `json:",inline"`
+	ID       string `json:"id"`
+	Replicas int    `json:"replicas"`
+	// MinReplicas and MaxReplicas is the range of replicas for elastic member, and zero means member is not elastic
+	MinReplicas int        `json:"minReplicas,omitempty"`
+	MaxReplicas int        `json:"maxReplicas,omitempty"`
+	Role        MemberRole `json:"role"`
+	Conf        `json:",inline"`
+}
+
+// IsElastic indicate whether the replicas of member can be scaled between MinReplicas and MaxReplicas
+func (m Member) IsElastic() bool {
+	return m.MaxReplicas > 0
+}
+
+// GetMinReplicas get the min replicas required to run the member
+func (m Member) GetMinReplicas() int {
+	if m.IsElastic() && m.MinReplicas > 0 {
+		return m.MinReplicas
+	}
+	return m.Replicas
 }
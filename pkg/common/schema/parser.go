@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Parser struct {
@@ -142,6 +143,12 @@ func (p *Parser) ParseWorkflowSource(bodyMap map[string]interface{}, wfs *Workfl
 				return err
 			}
 			wfs.FsOptions = fsOptions
+		case "timeout":
+			timeout, err := p.ParseTimeout(value)
+			if err != nil {
+				return fmt.Errorf("[timeout] of workflow %s", err.Error())
+			}
+			wfs.Timeout = timeout
 		default:
 			return fmt.Errorf("workflow has no attribute [%s]", key)
 		}
@@ -297,6 +304,22 @@ func (p *Parser) ParseStep(params map[string]interface{}, step *WorkflowSourceSt
 				return fmt.Errorf("parse cache in step failed, error: %s", err.Error())
 			}
 			step.Cache = cache
+		case "retry":
+			retry := RetryPolicy{}
+			value, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("[retry] in step should be map type")
+			}
+			if err := p.ParseRetry(value, &retry); err != nil {
+				return fmt.Errorf("parse retry in step failed, error: %s", err.Error())
+			}
+			step.Retry = retry
+		case "timeout":
+			timeout, err := p.ParseTimeout(value)
+			if err != nil {
+				return fmt.Errorf("[timeout] in step %s", err.Error())
+			}
+			step.Timeout = timeout
 		case "reference":
 			value, ok := value.(map[string]interface{})
 			if !ok {
@@ -426,6 +449,12 @@ func (p *Parser) ParseDag(params map[string]interface{}, dagComp *WorkflowSource
 				return err
 			}
 			dagComp.EntryPoints = entryPoints
+		case "timeout":
+			timeout, err := p.ParseTimeout(value)
+			if err != nil {
+				return fmt.Errorf("[timeout] in dag %s", err.Error())
+			}
+			dagComp.Timeout = timeout
 		case "type":
 			value, ok := value.(string)
 			if !ok {
@@ -486,6 +515,12 @@ func (p *Parser) ParseCache(cacheMap map[string]interface{}, cache *Cache) error
 			}
 			// 这里这样使用append是为了让后解析的FsScope列表中的元素，排在前面
 			cache.FsScope = append(fsScopeList, cache.FsScope...)
+		case "strategy":
+			cacheValue, ok := cacheValue.(string)
+			if !ok {
+				return fmt.Errorf("[cache.strategy] should be string type")
+			}
+			cache.Strategy = cacheValue
 		default:
 			return fmt.Errorf("[cache] has no attribute [%s]", cacheKey)
 		}
@@ -493,6 +528,88 @@ func (p *Parser) ParseCache(cacheMap map[string]interface{}, cache *Cache) error
 	return nil
 }
 
+// ParseTimeout 解析超时时间，单位为秒，也支持 "1h30m" 格式的字符串
+func (p *Parser) ParseTimeout(value interface{}) (int, error) {
+	var timeout int
+	switch value := value.(type) {
+	case int64:
+		timeout = int(value)
+	case float64:
+		// 兼容由json.Unmarshal得到的值
+		timeout = int(value)
+	case string:
+		if seconds, err := strconv.Atoi(value); err == nil {
+			timeout = seconds
+		} else {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return 0, fmt.Errorf("should be int type or duration string, such as 1h30m")
+			}
+			timeout = int(duration.Seconds())
+		}
+	default:
+		return 0, fmt.Errorf("should be int type or duration string, such as 1h30m")
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("should not be negative")
+	}
+	return timeout, nil
+}
+
+func (p *Parser) ParseRetry(retryMap map[string]interface{}, retry *RetryPolicy) error {
+	for retryKey, retryValue := range retryMap {
+		if retryValue == nil {
+			continue
+		}
+		switch retryKey {
+		case "limit", "backoff", "factor", "max_backoff":
+			var intValue int
+			switch retryValue := retryValue.(type) {
+			case int64:
+				intValue = int(retryValue)
+			case float64:
+				// 兼容由json.Unmarshal得到的值
+				intValue = int(retryValue)
+			default:
+				return fmt.Errorf("[retry.%s] should be int type", retryKey)
+			}
+			if intValue < 0 {
+				return fmt.Errorf("[retry.%s] should not be negative", retryKey)
+			}
+			switch retryKey {
+			case "limit":
+				retry.Limit = intValue
+			case "backoff":
+				retry.Backoff = intValue
+			case "factor":
+				retry.Factor = intValue
+			case "max_backoff":
+				retry.MaxBackoff = intValue
+			}
+		case "retry_on":
+			retryValue, ok := retryValue.([]interface{})
+			if !ok {
+				return fmt.Errorf("[retry.retry_on] should be list type")
+			}
+			retryOn := []string{}
+			for _, v := range retryValue {
+				v, ok := v.(string)
+				if !ok {
+					return fmt.Errorf("[retry.retry_on] should be list of string type")
+				}
+				if v != RetryOnFailed && v != RetryOnEvicted {
+					return fmt.Errorf("[retry.retry_on] only support [%s, %s], not [%s]", RetryOnFailed, RetryOnEvicted, v)
+				}
+				retryOn = append(retryOn, v)
+			}
+			retry.RetryOn = retryOn
+		default:
+			return fmt.Errorf("[retry] has no attribute [%s]", retryKey)
+		}
+	}
+	return nil
+}
+
 func (p *Parser) ParseFsScope(fsMap map[string]interface{}, fs *FsScope) error {
 	for key, value := range fsMap {
 		switch key {
@@ -637,6 +754,11 @@ func (p *Parser) TransJsonMap2Yaml(jsonMap map[string]interface{}) error {
 				return err
 			}
 			jsonMap["cache"] = value
+		case "retry":
+			if err := p.transJsonRetry2Yaml(value); err != nil {
+				return err
+			}
+			jsonMap["retry"] = value
 		case "fsOptions":
 			if err := p.transJsonFsOptions2Yaml(value); err != nil {
 				return err
@@ -666,6 +788,24 @@ func (p *Parser) transJsonCache2Yaml(value interface{}) error {
 	return nil
 }
 
+func (p *Parser) transJsonRetry2Yaml(value interface{}) error {
+	retryMap, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("[retry] should be map type")
+	}
+	for retryKey, retryValue := range retryMap {
+		switch retryKey {
+		case "maxBackoff":
+			retryMap["max_backoff"] = retryValue
+			delete(retryMap, "maxBackoff")
+		case "retryOn":
+			retryMap["retry_on"] = retryValue
+			delete(retryMap, "retryOn")
+		}
+	}
+	return nil
+}
+
 func (p *Parser) transJsonExtraFS2Yaml(value interface{}) error {
 	if value == nil {
 		return nil
@@ -58,6 +58,16 @@ type JobView struct {
 	JobMessage  string            `json:"jobMessage"`
 	CacheRunID  string            `json:"cacheRunID"`
 	CacheJobID  string            `json:"cacheJobID"`
+	Attempts    []JobAttempt      `json:"attempts"`
+}
+
+// JobAttempt is a finished attempt of job for step, which is recorded before the job is resubmitted by retry policy
+type JobAttempt struct {
+	JobID     string    `json:"jobID"`
+	Status    JobStatus `json:"status"`
+	Message   string    `json:"message"`
+	StartTime string    `json:"startTime"`
+	EndTime   string    `json:"endTime"`
 }
 
 func (j JobView) GetComponentName() string {
@@ -39,6 +39,7 @@ const (
 	CacheAttributeEnable         = "enable"
 	CacheAttributeMaxExpiredTime = "max_expired_time"
 	CacheAttributeFsScope        = "fs_scope"
+	CacheAttributeStrategy       = "strategy"
 
 	FailureStrategyFailFast = "fail_fast"
 	FailureStrategyContinue = "continue"
@@ -144,6 +145,8 @@ type WorkflowSourceStep struct {
 	Env          map[string]string      `yaml:"env"               json:"env"`
 	DockerEnv    string                 `yaml:"docker_env"        json:"dockerEnv"`
 	Cache        Cache                  `yaml:"cache"             json:"cache"`
+	Retry        RetryPolicy            `yaml:"retry"             json:"retry"`
+	Timeout      int                    `yaml:"timeout"           json:"timeout"` // seconds
 	Reference    Reference              `yaml:"reference"         json:"reference"`
 	ExtraFS      []FsMount              `yaml:"extra_fs"          json:"extraFS"`
 }
@@ -290,6 +293,8 @@ func (s *WorkflowSourceStep) DeepCopy() Component {
 		Artifacts:    *s.Artifacts.DeepCopy(),
 		DockerEnv:    s.DockerEnv,
 		Cache:        s.Cache,
+		Retry:        *s.Retry.DeepCopy(),
+		Timeout:      s.Timeout,
 		Reference:    s.Reference,
 		ExtraFS:      fsMount,
 	}
@@ -306,6 +311,7 @@ type WorkflowSourceDag struct {
 	Deps         string                 `yaml:"deps"           json:"deps"`
 	Artifacts    Artifacts              `yaml:"artifacts"      json:"artifacts"`
 	EntryPoints  map[string]Component   `yaml:"entry_points"   json:"entryPoints"`
+	Timeout      int                    `yaml:"timeout"        json:"timeout"` // seconds
 }
 
 func (d *WorkflowSourceDag) GetName() string {
@@ -450,6 +456,7 @@ func (d *WorkflowSourceDag) DeepCopy() Component {
 		Deps:         d.Deps,
 		Artifacts:    *d.Artifacts.DeepCopy(),
 		EntryPoints:  ep,
+		Timeout:      d.Timeout,
 	}
 
 	return nd
@@ -468,6 +475,60 @@ type Cache struct {
 	Enable         bool      `yaml:"enable"           json:"enable"`
 	MaxExpiredTime string    `yaml:"max_expired_time" json:"maxExpiredTime"` // seconds
 	FsScope        []FsScope `yaml:"fs_scope"         json:"fsScope"`        // seperated by ","
+	Strategy       string    `yaml:"strategy"         json:"strategy"`       // conservative or aggressive
+}
+
+const (
+	RetryOnFailed  = "failed"
+	RetryOnEvicted = "evicted"
+)
+
+// RetryPolicy step 对应的 job 失败后的重试策略
+type RetryPolicy struct {
+	Limit      int      `yaml:"limit"       json:"limit"`      // 最大重试次数，为0时不重试
+	Backoff    int      `yaml:"backoff"     json:"backoff"`    // 首次重试前的等待时间，seconds
+	Factor     int      `yaml:"factor"      json:"factor"`     // 每次重试后等待时间的增长倍数，不大于1时等待时间不变
+	MaxBackoff int      `yaml:"max_backoff" json:"maxBackoff"` // 等待时间的上限，seconds，为0时不设上限
+	RetryOn    []string `yaml:"retry_on"    json:"retryOn"`    // 可选值为 failed, evicted，为空时对两者都进行重试
+}
+
+func (r *RetryPolicy) DeepCopy() *RetryPolicy {
+	nr := *r
+	if r.RetryOn != nil {
+		nr.RetryOn = append([]string{}, r.RetryOn...)
+	}
+	return &nr
+}
+
+// ShouldRetry 判断第 attempt 次(从1开始)失败的 job 是否需要重试，reason 为 RetryOnFailed 或者 RetryOnEvicted
+func (r *RetryPolicy) ShouldRetry(attempt int, reason string) bool {
+	if attempt > r.Limit {
+		return false
+	}
+	if len(r.RetryOn) == 0 {
+		return true
+	}
+	for _, retryOn := range r.RetryOn {
+		if retryOn == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// BackoffSeconds 第 attempt 次(从1开始)重试前需要等待的时间
+func (r *RetryPolicy) BackoffSeconds(attempt int) int {
+	backoff := r.Backoff
+	for i := 1; i < attempt && r.Factor > 1; i++ {
+		backoff *= r.Factor
+		if r.MaxBackoff > 0 && backoff >= r.MaxBackoff {
+			break
+		}
+	}
+	if r.MaxBackoff > 0 && backoff > r.MaxBackoff {
+		backoff = r.MaxBackoff
+	}
+	return backoff
 }
 
 type FsScope struct {
@@ -504,6 +565,7 @@ type WorkflowSource struct {
 	FailureOptions FailureOptions                 `yaml:"failure_options"    json:"failureOptions"`
 	PostProcess    map[string]*WorkflowSourceStep `yaml:"post_process"       json:"postProcess"`
 	FsOptions      FsOptions                      `yaml:"fs_options"         json:"fsOptions"`
+	Timeout        int                            `yaml:"timeout"            json:"timeout"` // seconds
 }
 
 func (wfs *WorkflowSource) UnmarshalJSON(data []byte) error {
@@ -856,6 +918,7 @@ func (wfs *WorkflowSource) TransToRunYamlRaw() (runYamlRaw string, err error) {
 		FailureOptions FailureOptions                 `yaml:"failure_options"`
 		PostProcess    map[string]*WorkflowSourceStep `yaml:"post_process"`
 		FsOptions      FsOptions                      `yaml:"fs_options"`
+		Timeout        int                            `yaml:"timeout"`
 	}
 
 	wf := workflow{
@@ -868,6 +931,7 @@ func (wfs *WorkflowSource) TransToRunYamlRaw() (runYamlRaw string, err error) {
 		FailureOptions: wfs.FailureOptions,
 		PostProcess:    wfs.PostProcess,
 		FsOptions:      wfs.FsOptions,
+		Timeout:        wfs.Timeout,
 	}
 
 	runYaml, err := yaml.Marshal(wf)
@@ -108,3 +108,99 @@ func TestTransToRunYamlRaw(t *testing.T) {
 	assert.Contains(t, newWfs.PostProcess, "post")
 	assert.Equal(t, len(wfs.EntryPoints.EntryPoints), len(newWfs.EntryPoints.EntryPoints))
 }
+
+func TestParseRetry(t *testing.T) {
+	p := Parser{}
+	retry := RetryPolicy{}
+	err := p.ParseRetry(map[string]interface{}{
+		"limit":       int64(3),
+		"backoff":     float64(10),
+		"factor":      int64(2),
+		"max_backoff": int64(30),
+		"retry_on":    []interface{}{RetryOnEvicted},
+	}, &retry)
+	assert.Nil(t, err)
+	assert.Equal(t, RetryPolicy{Limit: 3, Backoff: 10, Factor: 2, MaxBackoff: 30, RetryOn: []string{RetryOnEvicted}}, retry)
+
+	err = p.ParseRetry(map[string]interface{}{"limit": "3"}, &retry)
+	assert.NotNil(t, err)
+	err = p.ParseRetry(map[string]interface{}{"limit": int64(-1)}, &retry)
+	assert.NotNil(t, err)
+	err = p.ParseRetry(map[string]interface{}{"retry_on": []interface{}{"oom"}}, &retry)
+	assert.NotNil(t, err)
+	err = p.ParseRetry(map[string]interface{}{"wrongKey": int64(1)}, &retry)
+	assert.NotNil(t, err)
+
+	// json格式的key需要先转换
+	jsonMap := map[string]interface{}{
+		"command": "echo retry",
+		"retry": map[string]interface{}{
+			"limit":      float64(1),
+			"maxBackoff": float64(5),
+			"retryOn":    []interface{}{RetryOnFailed},
+		},
+	}
+	err = p.TransJsonMap2Yaml(jsonMap)
+	assert.Nil(t, err)
+	step := WorkflowSourceStep{}
+	err = p.ParseStep(jsonMap, &step)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, step.Retry.Limit)
+	assert.Equal(t, 5, step.Retry.MaxBackoff)
+	assert.Equal(t, []string{RetryOnFailed}, step.Retry.RetryOn)
+}
+
+func TestParseTimeout(t *testing.T) {
+	p := Parser{}
+	testCases := []struct {
+		value   interface{}
+		expect  int
+		wantErr bool
+	}{
+		{value: int64(60), expect: 60},
+		{value: float64(30), expect: 30},
+		{value: "90", expect: 90},
+		{value: "1h30m", expect: 5400},
+		{value: "abc", wantErr: true},
+		{value: int64(-1), wantErr: true},
+		{value: []interface{}{1}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		timeout, err := p.ParseTimeout(tc.value)
+		if tc.wantErr {
+			assert.NotNil(t, err)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expect, timeout)
+	}
+
+	step := WorkflowSourceStep{}
+	err := p.ParseStep(map[string]interface{}{"command": "echo timeout", "timeout": "10m"}, &step)
+	assert.Nil(t, err)
+	assert.Equal(t, 600, step.Timeout)
+
+	dag := WorkflowSourceDag{}
+	err = p.ParseDag(map[string]interface{}{"timeout": int64(100)}, &dag)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, dag.Timeout)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	retry := RetryPolicy{Limit: 4, Backoff: 10, Factor: 2, MaxBackoff: 50}
+	assert.Equal(t, 10, retry.BackoffSeconds(1))
+	assert.Equal(t, 20, retry.BackoffSeconds(2))
+	assert.Equal(t, 40, retry.BackoffSeconds(3))
+	assert.Equal(t, 50, retry.BackoffSeconds(4))
+
+	assert.True(t, retry.ShouldRetry(1, RetryOnFailed))
+	assert.True(t, retry.ShouldRetry(4, RetryOnEvicted))
+	assert.False(t, retry.ShouldRetry(5, RetryOnFailed))
+
+	retry.RetryOn = []string{RetryOnEvicted}
+	assert.False(t, retry.ShouldRetry(1, RetryOnFailed))
+	assert.True(t, retry.ShouldRetry(1, RetryOnEvicted))
+
+	noRetry := RetryPolicy{}
+	assert.False(t, noRetry.ShouldRetry(1, RetryOnFailed))
+}
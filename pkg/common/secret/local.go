@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+const LocalProvider = "local"
+
+// localKeyProvider wraps data keys with master key read from a local file,
+// which can be mounted from a kubernetes secret
+type localKeyProvider struct {
+	key   []byte
+	keyID string
+}
+
+func newLocalKeyProvider(conf Config) (KeyProvider, error) {
+	if conf.KeyFile == "" {
+		return nil, fmt.Errorf("key file of local provider is not set")
+	}
+	content, err := ioutil.ReadFile(conf.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read key file %s failed: %v", conf.KeyFile, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, fmt.Errorf("key file %s is not base64 encoded: %v", conf.KeyFile, err)
+	}
+	return newLocalKeyProviderWithKey(key)
+}
+
+func newLocalKeyProviderWithKey(key []byte) (*localKeyProvider, error) {
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("master key should be %d bytes, but got %d bytes", dataKeySize, len(key))
+	}
+	sum := sha256.Sum256(key)
+	return &localKeyProvider{
+		key:   key,
+		keyID: hex.EncodeToString(sum[:4]),
+	}, nil
+}
+
+func (p *localKeyProvider) Name() string {
+	return LocalProvider
+}
+
+func (p *localKeyProvider) KeyID() string {
+	return p.keyID
+}
+
+func (p *localKeyProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	return seal(p.key, dataKey)
+}
+
+func (p *localKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != p.keyID {
+		return nil, fmt.Errorf("data key is wrapped by master key %s, but master key in key file is %s", keyID, p.keyID)
+	}
+	return open(p.key, wrapped)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secret provides envelope encryption of secrets stored in database, such as cluster credentials
+// and file system keys. Each secret is encrypted by a random data key, and the data key is encrypted by
+// the master key of a KeyProvider, so that master key never leaves the provider.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EnvelopePrefix marks values encrypted by this package, values without it are treated as plaintext
+	EnvelopePrefix = "pfenc:v1:"
+	// Redacted replaces secrets in api responses
+	Redacted = "******"
+
+	dataKeySize = 32
+)
+
+// Config of secret encryption, empty provider disables encryption
+type Config struct {
+	// Provider wraps data keys with its master key, "local" or providers registered by RegisterProvider
+	Provider string `yaml:"provider"`
+	// KeyFile holds master key of local provider, which is 32 bytes encoded in base64
+	KeyFile string `yaml:"keyFile"`
+	// Options are passed to providers such as kms
+	Options map[string]string `yaml:"options,omitempty"`
+}
+
+// KeyProvider wraps and unwraps data keys with a master key, e.g. a local key file or a kms service
+type KeyProvider interface {
+	// Name of provider recorded in envelope
+	Name() string
+	// KeyID identifies the master key wrapping data keys, recorded in envelope
+	KeyID() string
+	WrapKey(dataKey []byte) ([]byte, error)
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// ProviderFactory creates KeyProvider with config
+type ProviderFactory func(conf Config) (KeyProvider, error)
+
+var (
+	factoriesLock sync.RWMutex
+	factories     = map[string]ProviderFactory{
+		LocalProvider: newLocalKeyProvider,
+	}
+
+	provider KeyProvider
+)
+
+// RegisterProvider registers factory of KeyProvider, which can be used by Config.Provider
+func RegisterProvider(name string, factory ProviderFactory) {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	factories[name] = factory
+}
+
+// Init sets the KeyProvider used to encrypt and decrypt secrets
+func Init(conf Config) error {
+	if conf.Provider == "" {
+		log.Warningf("secret key provider is not set, secrets are stored in plaintext")
+		provider = nil
+		return nil
+	}
+	factoriesLock.RLock()
+	factory, ok := factories[conf.Provider]
+	factoriesLock.RUnlock()
+	if !ok {
+		return fmt.Errorf("secret key provider %s is not supported", conf.Provider)
+	}
+	p, err := factory(conf)
+	if err != nil {
+		return fmt.Errorf("init secret key provider %s failed: %v", conf.Provider, err)
+	}
+	provider = p
+	log.Infof("secret key provider %s with key %s is initialized", p.Name(), p.KeyID())
+	return nil
+}
+
+// Enabled returns whether secrets are encrypted before stored
+func Enabled() bool {
+	return provider != nil
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EnvelopePrefix)
+}
+
+// Encrypt returns envelope of value, value is returned as it is if encryption is disabled or it is encrypted already.
+// envelope is pfenc:v1:<provider>:<keyID>:<wrapped data key>:<nonce and ciphertext>
+func Encrypt(value string) (string, error) {
+	if provider == nil || value == "" || IsEncrypted(value) {
+		return value, nil
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataKey, []byte(value))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := provider.WrapKey(dataKey)
+	if err != nil {
+		return "", fmt.Errorf("wrap data key by %s failed: %v", provider.Name(), err)
+	}
+	return EnvelopePrefix + strings.Join([]string{
+		provider.Name(),
+		provider.KeyID(),
+		base64.StdEncoding.EncodeToString(wrapped),
+		base64.StdEncoding.EncodeToString(sealed),
+	}, ":"), nil
+}
+
+// Decrypt returns plaintext of envelope, values not encrypted are returned as they are
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(value, EnvelopePrefix), ":")
+	if len(parts) != 4 {
+		return "", fmt.Errorf("secret envelope is malformed")
+	}
+	if provider == nil {
+		return "", fmt.Errorf("secret is encrypted by %s, but no key provider is configured", parts[0])
+	}
+	if parts[0] != provider.Name() {
+		return "", fmt.Errorf("secret is encrypted by %s, but key provider is %s", parts[0], provider.Name())
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("decode data key failed: %v", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", fmt.Errorf("decode ciphertext failed: %v", err)
+	}
+	dataKey, err := provider.UnwrapKey(parts[1], wrapped)
+	if err != nil {
+		return "", fmt.Errorf("unwrap data key by %s failed: %v", provider.Name(), err)
+	}
+	plaintext, err := open(dataKey, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Redact hides non-empty secret in api responses
+func Redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// seal encrypts plaintext by aes-gcm, and returns nonce followed by ciphertext
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt ciphertext failed: %v", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
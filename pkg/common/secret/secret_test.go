@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKeyFile(t *testing.T, path string, key []byte) {
+	assert.Nil(t, ioutil.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	keyFile := "./mock-key"
+	defer os.Remove(keyFile)
+	writeKeyFile(t, keyFile, bytes.Repeat([]byte("k"), 32))
+
+	// encryption disabled
+	assert.Nil(t, Init(Config{}))
+	assert.False(t, Enabled())
+	value, err := Encrypt("kubeconfig")
+	assert.Nil(t, err)
+	assert.Equal(t, "kubeconfig", value)
+
+	assert.Nil(t, Init(Config{Provider: LocalProvider, KeyFile: keyFile}))
+	defer Init(Config{})
+	assert.True(t, Enabled())
+	encrypted, err := Encrypt("kubeconfig")
+	assert.Nil(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.False(t, strings.Contains(encrypted, "kubeconfig"))
+	// encrypted value is not encrypted again
+	again, err := Encrypt(encrypted)
+	assert.Nil(t, err)
+	assert.Equal(t, encrypted, again)
+	// each value has its own data key and nonce
+	other, err := Encrypt("kubeconfig")
+	assert.Nil(t, err)
+	assert.NotEqual(t, encrypted, other)
+
+	plaintext, err := Decrypt(encrypted)
+	assert.Nil(t, err)
+	assert.Equal(t, "kubeconfig", plaintext)
+	// plaintext stored by former versions
+	plaintext, err = Decrypt("kubeconfig")
+	assert.Nil(t, err)
+	assert.Equal(t, "kubeconfig", plaintext)
+
+	// tampered ciphertext
+	_, err = Decrypt(encrypted[:len(encrypted)-4] + "AAAA")
+	assert.NotNil(t, err)
+
+	// master key changed
+	writeKeyFile(t, keyFile, bytes.Repeat([]byte("n"), 32))
+	assert.Nil(t, Init(Config{Provider: LocalProvider, KeyFile: keyFile}))
+	_, err = Decrypt(encrypted)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "master key")
+
+	// encrypted value can't be read without provider
+	assert.Nil(t, Init(Config{}))
+	_, err = Decrypt(encrypted)
+	assert.NotNil(t, err)
+}
+
+func TestInit(t *testing.T) {
+	defer Init(Config{})
+	assert.NotNil(t, Init(Config{Provider: "unknown"}))
+	assert.NotNil(t, Init(Config{Provider: LocalProvider}))
+	assert.NotNil(t, Init(Config{Provider: LocalProvider, KeyFile: "./not-exist"}))
+
+	keyFile := "./mock-short-key"
+	defer os.Remove(keyFile)
+	writeKeyFile(t, keyFile, []byte("short"))
+	assert.NotNil(t, Init(Config{Provider: LocalProvider, KeyFile: keyFile}))
+
+	// providers such as kms can be registered
+	RegisterProvider("mock", func(conf Config) (KeyProvider, error) {
+		return newLocalKeyProviderWithKey(bytes.Repeat([]byte("m"), 32))
+	})
+	assert.Nil(t, Init(Config{Provider: "mock"}))
+	assert.True(t, Enabled())
+}
+
+func TestRedact(t *testing.T) {
+	assert.Equal(t, "", Redact(""))
+	assert.Equal(t, Redacted, Redact("secret"))
+}
@@ -98,7 +98,10 @@ type BufferPool struct {
 	totalBuffers       uint64
 	computedMaxBuffers uint64
 
+	size int
 	pool *sync.Pool
+	// buffers larger than size, e.g. part buffers of s3 multipart upload, are recycled by their size
+	sizedPools map[int]*sync.Pool
 }
 
 func maxBuffers(bufSize uint64) uint64 {
@@ -125,10 +128,12 @@ func maxBuffers(bufSize uint64) uint64 {
 func (pool *BufferPool) Init(size int) *BufferPool {
 	pool.cond = sync.NewCond(&pool.mu)
 
+	pool.size = size
 	pool.pool = &sync.Pool{New: func() interface{} {
 		out := make([]byte, 0, size)
 		return out
 	}}
+	pool.sizedPools = make(map[int]*sync.Pool)
 
 	return pool
 }
@@ -138,9 +143,9 @@ func (pool *BufferPool) recomputeBufferLimit() {
 }
 
 func (pool *BufferPool) RequestMBuf(size uint64, block bool, blockSize int) []byte {
-	pool.bufSize = size
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
+	pool.bufSize = size
 
 	if pool.totalBuffers%10 == 0 {
 		pool.recomputeBufferLimit()
@@ -159,7 +164,34 @@ func (pool *BufferPool) RequestMBuf(size uint64, block bool, blockSize int) []by
 	}
 
 	pool.totalBuffers++
-	return pool.pool.Get().([]byte)
+	if int(size) <= pool.size {
+		return pool.pool.Get().([]byte)
+	}
+	return pool.sizedPool(int(size)).Get().([]byte)
+}
+
+func (pool *BufferPool) sizedPool(size int) *sync.Pool {
+	p, ok := pool.sizedPools[size]
+	if !ok {
+		p = &sync.Pool{New: func() interface{} {
+			out := make([]byte, 0, size)
+			return out
+		}}
+		pool.sizedPools[size] = p
+	}
+	return p
+}
+
+// FreeMBuf returns a buffer got from RequestMBuf to the pool and wakes up a blocked request.
+func (pool *BufferPool) FreeMBuf(buf []byte) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if cap(buf) == pool.size {
+		pool.pool.Put(buf[:0])
+	} else if cap(buf) > pool.size {
+		pool.sizedPool(cap(buf)).Put(buf[:0])
+	}
+	pool.cond.Signal()
 }
 
 func (pool *BufferPool) MaybeGC() {
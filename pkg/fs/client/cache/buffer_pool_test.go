@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/utils"
 )
@@ -26,3 +27,22 @@ func Test_freeMemory(t *testing.T) {
 		})
 	}
 }
+
+func TestBufferPoolSizes(t *testing.T) {
+	pool := (&BufferPool{}).Init(1024)
+
+	buf := pool.RequestMBuf(1024, true, 1024)
+	assert.Equal(t, 0, len(buf))
+	assert.Equal(t, 1024, cap(buf))
+	pool.FreeMBuf(buf)
+
+	// buffers larger than the block size are allocated and recycled by their size
+	large := pool.RequestMBuf(4096, true, 1024)
+	assert.Equal(t, 4096, cap(large))
+	large = large[:4096]
+	pool.FreeMBuf(large)
+	large = pool.RequestMBuf(4096, true, 1024)
+	assert.Equal(t, 0, len(large))
+	assert.Equal(t, 4096, cap(large))
+	pool.FreeMBuf(large)
+}
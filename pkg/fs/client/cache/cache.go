@@ -17,16 +17,13 @@ limitations under the License.
 package cache
 
 import (
-	"fmt"
 	"io"
-	"path"
 	"path/filepath"
 	"strconv"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 
 	ufs "github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/ufs"
@@ -50,12 +47,21 @@ func NewDataCache(config Config) DataCacheClient {
 	if config.CachePath == "" || config.CachePath == "/" || config.Expire == 0 {
 		return nil
 	}
-	config.CachePath = filepath.Join(config.CachePath, config.FsID,
-		strconv.Itoa(int(time.Now().Unix()))+"_"+utils.GetRandID(5))
+	config.CachePath = dataCacheDir(config)
 	// currently, supports file client only
 	return newFileClient(config)
 }
 
+// dataCacheDir returns a fixed dir of fs for persistent cache, so that blocks can be reused after restart.
+// otherwise each mount uses a new dir.
+func dataCacheDir(config Config) string {
+	if config.Persistent && config.FsID != "" {
+		return filepath.Join(config.CachePath, config.FsID)
+	}
+	return filepath.Join(config.CachePath, config.FsID,
+		strconv.Itoa(int(time.Now().Unix()))+"_"+utils.GetRandID(5))
+}
+
 type rCache struct {
 	id            string
 	flags         uint32
@@ -185,6 +191,10 @@ func (r *rCache) ReadAt(buf []byte, off int64) (n int, err error) {
 	if err == nil {
 		n, err = r.readFromReadAhead(off, buf)
 		log.Debugf("readFromReadAhead n is %v err %v", n, err)
+		if r.store.client != nil {
+			cacheMiss.Inc()
+			cacheMissBytes.Add(float64(n))
+		}
 		return
 	} else {
 		log.Errorf("read ahead err is %v", err)
@@ -205,13 +215,9 @@ func (r *rCache) key(index int) string {
 	keyID, ok := r.store.meta[r.id]
 	r.store.RUnlock()
 	if !ok {
-		r.store.Lock()
-		keyID = uuid.NewString()
-		r.store.meta[r.id] = keyID
-		r.store.Unlock()
+		keyID = r.store.keyID(r.id, r.ufs)
 	}
-	hash := utils.KeyHash(keyID)
-	return path.Clean(fmt.Sprintf("blocks/%d/%v_%v", hash%256, keyID, index))
+	return r.store.key(keyID, index)
 }
 
 func (r *rCache) readCache(buf []byte, key string, off int) (int, bool) {
@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/panjf2000/ants/v2"
@@ -41,29 +42,77 @@ var _ DataCacheClient = &fileDataCache{}
 var deleteCachePool, _ = ants.NewPool(5)
 
 type cacheItem struct {
+	atime   int64 // unix nano of last access, accessed atomically
+	hits    int64 // accessed atomically
 	size    int64
 	expTime time.Time
 }
 
+func newCacheItem(size int64, expTime, atime time.Time) *cacheItem {
+	return &cacheItem{
+		atime:   atime.UnixNano(),
+		size:    size,
+		expTime: expTime,
+	}
+}
+
+func (item *cacheItem) touch() {
+	atomic.StoreInt64(&item.atime, time.Now().UnixNano())
+	atomic.AddInt64(&item.hits, 1)
+}
+
+func (item *cacheItem) lastAccess() int64 {
+	return atomic.LoadInt64(&item.atime)
+}
+
+func (item *cacheItem) hitCount() int64 {
+	return atomic.LoadInt64(&item.hits)
+}
+
 type fileDataCache struct {
 	sync.RWMutex
-	dir      string
-	capacity int64
-	used     int64
-	expire   time.Duration
-	keys     sync.Map
+	dir       string
+	capacity  int64
+	used      int64
+	expire    time.Duration
+	blockSize int
+	keys      sync.Map
+
+	cached    int64 // bytes of blocks in keys, accessed atomically
+	limit     int64 // configured max bytes of data cache, 0 means limited by disk only
+	high      float64
+	low       float64
+	policy    evictPolicy
+	evictLock sync.Mutex
+	sweepCh   chan struct{}
+	full      int32 // set when a block is dropped for cache is full, accessed atomically
 }
 
 func newFileClient(config Config) DataCacheClient {
 	d := &fileDataCache{
-		dir:    config.CachePath,
-		expire: config.Expire,
+		dir:       config.CachePath,
+		expire:    config.Expire,
+		blockSize: config.BlockSize,
+		limit:     config.CacheSize,
+		sweepCh:   make(chan struct{}, 1),
+	}
+	var err error
+	if d.policy, err = newEvictPolicy(config.EvictPolicy); err != nil {
+		log.Errorf("newFileClient err: %v", err)
+		return nil
+	}
+	if d.high, d.low, err = checkWatermark(config.HighWatermark, config.LowWatermark); err != nil {
+		log.Errorf("newFileClient err: %v", err)
+		return nil
 	}
 
 	if err := os.MkdirAll(filepath.Join(d.dir, CacheDir), 0755); err != nil {
 		log.Errorf("newFileClient os.MkdirAll [%s] err: %v", config.CachePath, err)
 		return nil
 	}
+	if config.Persistent {
+		d.loadKeys()
+	}
 	if err := d.updateCapacity(); err != nil {
 		log.Errorf("newFileClient d.updateCapacity err: %v", err)
 		return nil
@@ -75,16 +124,47 @@ func newFileClient(config Config) DataCacheClient {
 			time.Sleep(5 * time.Second)
 		}
 	}()
+	go d.sweep()
 
 	return d
 }
 
+// loadKeys loads blocks cached by last mount. blocks left by a crash, e.g. tmp files and
+// files of wrong size, are removed, and blocks are expired by their modify time.
+func (c *fileDataCache) loadKeys() {
+	cacheDir := filepath.Join(c.dir, CacheDir)
+	var loaded, removed int
+	if err := filepath.Walk(cacheDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.Debugf("load data cache: failure accessing a path %q: %v", path, err)
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		expTime := info.ModTime().Add(c.expire)
+		if strings.HasSuffix(path, ".tmp") || info.Size() == 0 ||
+			(c.blockSize > 0 && info.Size() > int64(c.blockSize)) || time.Until(expTime) <= 0 {
+			removed++
+			_ = os.Remove(path)
+			return nil
+		}
+		loaded++
+		c.add(c.getKeyFromCachePath(path), newCacheItem(info.Size(), expTime, info.ModTime()))
+		return nil
+	}); err != nil {
+		log.Errorf("load data cache: filepath.Walk failed: %v", err)
+	}
+	log.Infof("load data cache from %s: %d blocks loaded, %d removed", cacheDir, loaded, removed)
+}
+
 func (c *fileDataCache) load(key string) (ReadCloser, bool) {
 	if c.dir == "" {
 		return nil, false
 	}
 
-	if !c.exist(key) {
+	value, ok := c.keys.Load(key)
+	if !ok || time.Until(value.(*cacheItem).expTime) <= 0 {
 		return nil, false
 	}
 
@@ -104,6 +184,7 @@ func (c *fileDataCache) load(key string) (ReadCloser, bool) {
 	if err != nil {
 		return nil, false
 	}
+	value.(*cacheItem).touch()
 	return f, true
 }
 
@@ -111,8 +192,13 @@ func (c *fileDataCache) save(key string, buf []byte) {
 	if c.dir == "" {
 		return
 	}
+	start := time.Now()
 	cacheSize := int64(len(buf))
-	if c.used+cacheSize >= c.capacity {
+	if atomic.LoadInt64(&c.cached)+cacheSize > c.cacheLimit() {
+		// cache is full, drop the block and leave eviction to the sweeper, so reading is not blocked by it
+		cacheDrops.Inc()
+		atomic.StoreInt32(&c.full, 1)
+		c.triggerSweep()
 		return
 	}
 
@@ -144,25 +230,54 @@ func (c *fileDataCache) save(key string, buf []byte) {
 		return
 	}
 
-	c.keys.Store(key, &cacheItem{
-		expTime: time.Now().Add(c.expire),
-		size:    cacheSize,
-	})
+	now := time.Now()
+	c.add(key, newCacheItem(cacheSize, now.Add(c.expire), now))
+	cacheWrites.Inc()
+	cacheWriteBytes.Add(float64(cacheSize))
+	cacheWriteHist.Observe(time.Since(start).Seconds())
+	if c.overHighWatermark() {
+		c.triggerSweep()
+	}
 	return
 }
 
-func (c *fileDataCache) delete(key string) {
-	path := c.cachePath(key)
-	_, ok := c.load(key)
-	if ok {
-		c.keys.Delete(key)
+// add adds a cached block, and replaces the old one of the same key
+func (c *fileDataCache) add(key string, item *cacheItem) {
+	delta := item.size
+	c.Lock()
+	if old, ok := c.keys.Load(key); ok {
+		delta -= old.(*cacheItem).size
+	}
+	c.keys.Store(key, item)
+	// disk usage is refreshed by updateCapacity periodically, estimate it in between
+	c.used += delta
+	c.Unlock()
+	cacheUsedBytes.Set(float64(atomic.AddInt64(&c.cached, delta)))
+}
+
+// remove removes a cached block and its file, returns false if the block is not cached
+func (c *fileDataCache) remove(key string) bool {
+	c.Lock()
+	old, loaded := c.keys.LoadAndDelete(key)
+	if loaded {
+		c.used -= old.(*cacheItem).size
+	}
+	c.Unlock()
+	if loaded {
+		cacheUsedBytes.Set(float64(atomic.AddInt64(&c.cached, -old.(*cacheItem).size)))
 	}
+	path := c.cachePath(key)
 	_ = deleteCachePool.Submit(func() {
 		err := os.Remove(path)
 		if err != nil {
 			log.Debugf("delete cache remove err: %v", err)
 		}
 	})
+	return loaded
+}
+
+func (c *fileDataCache) delete(key string) {
+	c.remove(key)
 }
 
 func (c *fileDataCache) clean() {
@@ -207,6 +322,10 @@ func (c *fileDataCache) clean() {
 	if errCheck := c.updateCapacity(); errCheck != nil {
 		log.Debugf("data cache clean: updateCapacity failed: %v", errCheck)
 	}
+	// disk may be filled by others
+	if c.overHighWatermark() {
+		c.triggerSweep()
+	}
 }
 
 func (c *fileDataCache) cachePath(key string) string {
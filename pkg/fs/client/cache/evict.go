@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	EvictLRU  = "lru"
+	EvictLFU  = "lfu"
+	EvictSize = "size"
+
+	DefaultHighWatermark = 0.9
+	DefaultLowWatermark  = 0.8
+)
+
+// evictPolicy decides which cached blocks are evicted first when data cache is full
+type evictPolicy interface {
+	// before reports whether a should be evicted before b
+	before(a, b *cacheItem, now int64) bool
+}
+
+// lruPolicy evicts the least recently used blocks first
+type lruPolicy struct{}
+
+func (lruPolicy) before(a, b *cacheItem, now int64) bool {
+	return a.lastAccess() < b.lastAccess()
+}
+
+// lfuPolicy evicts the least frequently used blocks first, blocks of same hits are evicted by lru
+type lfuPolicy struct{}
+
+func (lfuPolicy) before(a, b *cacheItem, now int64) bool {
+	ha, hb := a.hitCount(), b.hitCount()
+	if ha != hb {
+		return ha < hb
+	}
+	return a.lastAccess() < b.lastAccess()
+}
+
+// sizePolicy evicts large and cold blocks first, which frees more space with fewer evictions.
+// blocks are ordered by size * idle time.
+type sizePolicy struct{}
+
+func (sizePolicy) before(a, b *cacheItem, now int64) bool {
+	return float64(a.size)*float64(now-a.lastAccess()) > float64(b.size)*float64(now-b.lastAccess())
+}
+
+func newEvictPolicy(name string) (evictPolicy, error) {
+	switch name {
+	case "", EvictLRU:
+		return lruPolicy{}, nil
+	case EvictLFU:
+		return lfuPolicy{}, nil
+	case EvictSize:
+		return sizePolicy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown data cache evict policy %s, should be one of %s, %s and %s",
+			name, EvictLRU, EvictLFU, EvictSize)
+	}
+}
+
+func checkWatermark(high, low float64) (float64, float64, error) {
+	if high == 0 {
+		high = DefaultHighWatermark
+	}
+	if low == 0 {
+		low = DefaultLowWatermark
+	}
+	if high > 1 || low <= 0 || low >= high {
+		return 0, 0, fmt.Errorf("data cache watermark should satisfy 0 < low[%v] < high[%v] <= 1", low, high)
+	}
+	return high, low, nil
+}
+
+// cacheLimit returns max bytes of data cache. it is the configured capacity if set,
+// and is bounded by bytes cached plus free space of cache disk.
+func (c *fileDataCache) cacheLimit() int64 {
+	c.RLock()
+	diskFree := c.capacity - c.used
+	c.RUnlock()
+	if diskFree < 0 {
+		diskFree = 0
+	}
+	limit := atomic.LoadInt64(&c.cached) + diskFree
+	if c.limit > 0 && c.limit < limit {
+		limit = c.limit
+	}
+	return limit
+}
+
+func (c *fileDataCache) overHighWatermark() bool {
+	return float64(atomic.LoadInt64(&c.cached)) > c.high*float64(c.cacheLimit())
+}
+
+// triggerSweep wakes up the sweeper without blocking
+func (c *fileDataCache) triggerSweep() {
+	select {
+	case c.sweepCh <- struct{}{}:
+	default:
+	}
+}
+
+// sweep evicts blocks in background once bytes cached exceed the high watermark or
+// a block is dropped for cache is full
+func (c *fileDataCache) sweep() {
+	for range c.sweepCh {
+		full := atomic.SwapInt32(&c.full, 0) == 1
+		if full || c.overHighWatermark() {
+			c.evict()
+		}
+	}
+}
+
+// evict evicts blocks by policy till bytes cached fall below the low watermark
+func (c *fileDataCache) evict() {
+	c.evictLock.Lock()
+	defer c.evictLock.Unlock()
+	target := int64(c.low * float64(c.cacheLimit()))
+	if atomic.LoadInt64(&c.cached) <= target {
+		return
+	}
+
+	type candidate struct {
+		key  string
+		item *cacheItem
+	}
+	var candidates []candidate
+	c.keys.Range(func(key, value interface{}) bool {
+		candidates = append(candidates, candidate{key: key.(string), item: value.(*cacheItem)})
+		return true
+	})
+	now := time.Now().UnixNano()
+	sort.Slice(candidates, func(i, j int) bool {
+		return c.policy.before(candidates[i].item, candidates[j].item, now)
+	})
+
+	var evicted int
+	var evictedBytes int64
+	for _, cand := range candidates {
+		if atomic.LoadInt64(&c.cached) <= target {
+			break
+		}
+		if c.remove(cand.key) {
+			evicted++
+			evictedBytes += cand.item.size
+		}
+	}
+	cacheEvicts.Add(float64(evicted))
+	log.Debugf("data cache evict %d blocks %d bytes, cached %d bytes target %d bytes",
+		evicted, evictedBytes, atomic.LoadInt64(&c.cached), target)
+}
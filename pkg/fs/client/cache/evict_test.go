@@ -0,0 +1,117 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/kv"
+)
+
+func TestEvictPolicy(t *testing.T) {
+	now := time.Now()
+	old := newCacheItem(10, now.Add(time.Hour), now.Add(-time.Minute))
+	recent := newCacheItem(10, now.Add(time.Hour), now)
+	large := newCacheItem(100, now.Add(time.Hour), now.Add(-time.Second))
+
+	lru, err := newEvictPolicy(EvictLRU)
+	assert.Nil(t, err)
+	assert.True(t, lru.before(old, recent, now.UnixNano()))
+
+	// recent block is hit more often
+	recent.touch()
+	recent.touch()
+	lfu, err := newEvictPolicy(EvictLFU)
+	assert.Nil(t, err)
+	assert.True(t, lfu.before(old, recent, now.UnixNano()))
+	assert.False(t, lfu.before(recent, old, now.UnixNano()))
+
+	// large block idle for a while is evicted before small block idle longer
+	size, err := newEvictPolicy(EvictSize)
+	assert.Nil(t, err)
+	assert.True(t, size.before(large, recent, now.UnixNano()))
+
+	policy, err := newEvictPolicy("")
+	assert.Nil(t, err)
+	assert.Equal(t, lruPolicy{}, policy)
+	_, err = newEvictPolicy("fifo")
+	assert.NotNil(t, err)
+}
+
+func TestCheckWatermark(t *testing.T) {
+	high, low, err := checkWatermark(0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultHighWatermark, high)
+	assert.Equal(t, DefaultLowWatermark, low)
+
+	_, _, err = checkWatermark(0.5, 0.6)
+	assert.NotNil(t, err)
+	_, _, err = checkWatermark(1.2, 0.6)
+	assert.NotNil(t, err)
+}
+
+func TestFileDataCacheEvict(t *testing.T) {
+	defer os.RemoveAll("./tmp-evict")
+	client := newFileClient(Config{
+		BlockSize:     10,
+		Expire:        time.Hour,
+		CacheSize:     50,
+		EvictPolicy:   EvictLRU,
+		HighWatermark: 1,
+		LowWatermark:  0.6,
+		Config:        kv.Config{CachePath: "./tmp-evict"},
+	})
+	assert.NotNil(t, client)
+	c := client.(*fileDataCache)
+	block := bytes.Repeat([]byte("a"), 10)
+	key := func(i int) string {
+		return fmt.Sprintf("blocks/0/key_%d", i)
+	}
+
+	for i := 0; i < 5; i++ {
+		c.save(key(i), block)
+	}
+	assert.Equal(t, int64(50), atomic.LoadInt64(&c.cached))
+	file, ok := c.load(key(0))
+	assert.True(t, ok)
+	file.Close()
+
+	// cache is full, the block is dropped and the sweeper evicts least recently used blocks
+	// till the low watermark in background
+	c.save(key(5), block)
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt64(&c.cached) == 30
+	}, time.Second, 10*time.Millisecond)
+	for i, cached := range []bool{true, false, false, true, true, false} {
+		_, ok := c.keys.Load(key(i))
+		assert.Equal(t, cached, ok, key(i))
+	}
+
+	c.save(key(5), block)
+	assert.Equal(t, int64(40), atomic.LoadInt64(&c.cached))
+	c.delete(key(5))
+	assert.Equal(t, int64(30), atomic.LoadInt64(&c.cached))
+	_, ok = c.load(key(5))
+	assert.False(t, ok)
+}
@@ -32,6 +32,7 @@ func registerMetrics() {
 	_ = prometheus.Register(cacheWriteBytes)
 	_ = prometheus.Register(cacheDrops)
 	_ = prometheus.Register(cacheEvicts)
+	_ = prometheus.Register(cacheUsedBytes)
 	_ = prometheus.Register(cacheReadHist)
 	_ = prometheus.Register(cacheWriteHist)
 }
@@ -65,6 +66,10 @@ var (
 		Name: "blockcache_evicts",
 		Help: "evicted cache blocks",
 	})
+	cacheUsedBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "blockcache_used_bytes",
+		Help: "bytes of cached blocks",
+	})
 	cacheHitBytes = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "blockcache_hit_bytes",
 		Help: "read bytes from cached block",
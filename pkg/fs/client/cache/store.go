@@ -17,14 +17,18 @@ limitations under the License.
 package cache
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"path"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/base"
 	kv "github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/kv"
 	ufs "github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/ufs"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/utils"
@@ -56,15 +60,31 @@ type Config struct {
 	BlockSize    int
 	MaxReadAhead int
 	Expire       time.Duration
+	// CacheSize is max bytes of data cache, 0 means limited by free space of cache disk
+	CacheSize int64
+	// EvictPolicy is one of lru, lfu and size, default lru
+	EvictPolicy string
+	// blocks are evicted once cache usage exceeds HighWatermark of the limit till it falls below LowWatermark
+	HighWatermark float64
+	LowWatermark  float64
 }
 
 type store struct {
 	conf   Config
 	meta   map[string]string
 	client DataCacheClient
+	// index persists meta to reuse cached blocks after restart, only used in persistent mode
+	index kv.KvClient
 	sync.RWMutex
 }
 
+// indexItem records which version of file the cached blocks belong to
+type indexItem struct {
+	ID    string `json:"id"`
+	Size  int64  `json:"size"`
+	Mtime uint64 `json:"mtime"`
+}
+
 func NewCacheStore(config Config) Store {
 	if config.BlockSize == 0 {
 		return nil
@@ -74,6 +94,19 @@ func NewCacheStore(config Config) Store {
 		meta: make(map[string]string, 100),
 	}
 	cacheStore.client = NewDataCache(config)
+	if cacheStore.client != nil && config.Persistent && config.FsID != "" {
+		index, err := kv.NewBadgerClient(kv.Config{
+			FsID:       "index",
+			Driver:     kv.DiskType,
+			CachePath:  filepath.Join(config.CachePath, config.FsID),
+			Persistent: true,
+		})
+		if err != nil {
+			log.Errorf("new data cache index failed, cached blocks will not be reused: %v", err)
+		} else {
+			cacheStore.index = index
+		}
+	}
 	log.Debugf("metrics register NewCacheStore")
 	registerMetrics()
 	return cacheStore
@@ -90,32 +123,109 @@ func (store *store) NewWriter(name string, length int, fh ufs.FileHandle) Writer
 }
 
 func (store *store) InvalidateCache(name string, length int) error {
-	write := 0
-	index := 0
 	name = path.Clean(name)
-	store.RLock()
-	keyID, ok := store.meta[name]
-	store.RUnlock()
-	if !ok {
-		return nil
-	}
 	store.Lock()
+	keyID, ok := store.meta[name]
 	delete(store.meta, name)
 	store.Unlock()
-	go func() {
-		for write <= length {
-			key := store.key(keyID, index)
-			log.Debugf("cache del key is %s and keyID %s", key, keyID)
-			if store.client != nil {
-				store.client.delete(key)
+	if store.index != nil {
+		// blocks cached by last mount are not loaded into meta yet
+		if item := store.getIndex(name); !ok && item != nil {
+			keyID, ok = item.ID, true
+			if int(item.Size) > length {
+				length = int(item.Size)
 			}
-			write += store.conf.BlockSize
-			index += 1
 		}
-	}()
+		store.deleteIndex(name)
+	}
+	if !ok {
+		return nil
+	}
+	go store.deleteBlocks(keyID, length)
 	return nil
 }
 
+func (store *store) deleteBlocks(keyID string, length int) {
+	for write, index := 0, 0; write <= length; write, index = write+store.conf.BlockSize, index+1 {
+		key := store.key(keyID, index)
+		log.Debugf("cache del key is %s and keyID %s", key, keyID)
+		if store.client != nil {
+			store.client.delete(key)
+		}
+	}
+}
+
+// keyID returns id of cached blocks of file name.
+// in persistent mode, blocks cached by last mount are reused if size and mtime of the file are not changed.
+func (store *store) keyID(name string, ufs_ ufs.UnderFileStorage) string {
+	var info *base.FileInfo
+	var item *indexItem
+	if store.index != nil && ufs_ != nil {
+		finfo, err := ufs_.GetAttr(name)
+		if err == nil {
+			info = finfo
+			item = store.getIndex(name)
+		}
+	}
+
+	store.Lock()
+	defer store.Unlock()
+	if keyID, ok := store.meta[name]; ok {
+		return keyID
+	}
+	if item != nil {
+		if item.Size == info.Size && item.Mtime == info.Mtime {
+			store.meta[name] = item.ID
+			return item.ID
+		}
+		log.Debugf("data cache of %s is stale: cached size %d mtime %d, file size %d mtime %d",
+			name, item.Size, item.Mtime, info.Size, info.Mtime)
+		go store.deleteBlocks(item.ID, int(item.Size))
+	}
+	keyID := uuid.NewString()
+	store.meta[name] = keyID
+	if info != nil {
+		store.setIndex(name, &indexItem{ID: keyID, Size: info.Size, Mtime: info.Mtime})
+	}
+	return keyID
+}
+
+func (store *store) getIndex(name string) *indexItem {
+	var value []byte
+	err := store.index.Txn(func(tx kv.KvTxn) error {
+		value = tx.Get([]byte(name))
+		return nil
+	})
+	if err != nil || value == nil {
+		return nil
+	}
+	item := &indexItem{}
+	if err = json.Unmarshal(value, item); err != nil {
+		log.Warnf("data cache index of %s is corrupted: %v", name, err)
+		return nil
+	}
+	return item
+}
+
+func (store *store) setIndex(name string, item *indexItem) {
+	value, _ := json.Marshal(item)
+	err := store.index.Txn(func(tx kv.KvTxn) error {
+		return tx.Set([]byte(name), value)
+	})
+	if err != nil {
+		log.Errorf("set data cache index of %s failed: %v", name, err)
+	}
+}
+
+func (store *store) deleteIndex(name string) {
+	err := store.index.Txn(func(tx kv.KvTxn) error {
+		return tx.Dels([]byte(name))
+	})
+	if err != nil {
+		log.Errorf("delete data cache index of %s failed: %v", name, err)
+	}
+}
+
 func (store *store) key(keyID string, index int) string {
 	hash := utils.KeyHash(keyID)
 	return path.Clean(fmt.Sprintf("blocks/%d/%v_%v", hash%256, keyID, index))
@@ -0,0 +1,88 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/kv"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/ufs"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
+)
+
+func TestPersistentStore(t *testing.T) {
+	root, err := filepath.Abs("./tmp")
+	assert.Nil(t, err)
+	defer os.RemoveAll(root)
+	ufsRoot := filepath.Join(root, "ufs")
+	fs, err := ufs.NewLocalFileSystem(map[string]interface{}{common.SubPath: ufsRoot})
+	assert.Nil(t, err)
+	data := []byte("0123456789")
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(ufsRoot, "foo"), data, 0644))
+
+	config := Config{
+		BlockSize: 4,
+		Expire:    time.Hour,
+		Config: kv.Config{
+			FsID:       "fs-root-test",
+			CachePath:  filepath.Join(root, "cache"),
+			Persistent: true,
+		},
+	}
+	newStore := func() *store {
+		s := NewCacheStore(config).(*store)
+		assert.NotNil(t, s.client)
+		assert.NotNil(t, s.index)
+		return s
+	}
+	keyOf := func(s *store, index int) string {
+		r := s.NewReader("foo", len(data), 0, fs, nil, nil, 0).(*rCache)
+		return r.key(index)
+	}
+
+	s := newStore()
+	key := keyOf(s, 0)
+	s.client.save(key, data[:4])
+	// a tmp file left by crash is removed when restart
+	tmp := filepath.Join(config.CachePath, config.FsID, CacheDir, key+"_1.tmp")
+	assert.Nil(t, ioutil.WriteFile(tmp, data, 0644))
+	assert.Nil(t, s.index.Close())
+
+	// blocks are reused after restart
+	s = newStore()
+	assert.Equal(t, key, keyOf(s, 0))
+	_, ok := s.client.load(key)
+	assert.True(t, ok)
+	_, err = os.Stat(tmp)
+	assert.True(t, os.IsNotExist(err))
+	assert.Nil(t, s.index.Close())
+
+	// blocks are not reused if the file is changed
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(ufsRoot, "foo"), data[:8], 0644))
+	s = newStore()
+	newKey := keyOf(s, 0)
+	assert.NotEqual(t, key, newKey)
+	assert.Nil(t, s.InvalidateCache("foo", len(data)))
+	assert.Nil(t, s.getIndex("foo"))
+	assert.Nil(t, s.index.Close())
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	kv "github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/kv"
+	meta "github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/meta"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/vfs"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
+)
+
+const writeBackFsID = "fs-root-writeback"
+
+func newWriteBackFS(t *testing.T) *FileSystem {
+	os.MkdirAll("./mock", 0755)
+	fsMeta := common.FSMeta{
+		ID:      writeBackFsID,
+		UfsType: common.LocalType,
+		Properties: map[string]string{
+			common.RootKey: "./mock",
+		},
+		SubPath: "./mock",
+	}
+	config := vfs.InitConfig(
+		vfs.WithMetaConfig(meta.Config{
+			AttrCacheExpire:  MetaCacheExpire,
+			EntryCacheExpire: EntryCacheExpire,
+			Config: kv.Config{
+				Driver: kv.MemType,
+			},
+		}),
+		vfs.WithWriteBackConfig(vfs.WriteBackConfig{
+			Dir:     "./mock-cache",
+			Timeout: 10 * time.Second,
+			Retry:   1,
+		}),
+	)
+	pfs, err := NewFileSystem(fsMeta, nil, true, false, "", config)
+	assert.Nil(t, err)
+	return pfs
+}
+
+func TestWriteBack(t *testing.T) {
+	clean()
+	defer clean()
+	pfs := newWriteBackFS(t)
+
+	file, err := pfs.Create("wb", uint32(syscall.O_WRONLY|syscall.O_CREAT), 0644)
+	assert.Nil(t, err)
+	_, err = file.Write([]byte("hello write back"))
+	assert.Nil(t, err)
+	// fsync waits for the upload
+	assert.Nil(t, file.Sync())
+	data, err := ioutil.ReadFile("./mock/wb")
+	assert.Nil(t, err)
+	assert.Equal(t, "hello write back", string(data))
+
+	// data written after fsync is uploaded on close
+	_, err = file.Write([]byte("!"))
+	assert.Nil(t, err)
+	// chmod is not overwritten by the upload
+	assert.Nil(t, pfs.Chmod("wb", 0600))
+	assert.Nil(t, file.Close())
+
+	reader, err := pfs.Open("wb")
+	assert.Nil(t, err)
+	buf := make([]byte, 100)
+	n, err := reader.Read(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello write back!", string(buf[:n]))
+	assert.Nil(t, reader.Close())
+
+	// rename waits for the upload to the old path
+	assert.Nil(t, pfs.Rename("wb", "wb-renamed"))
+	data, err = ioutil.ReadFile("./mock/wb-renamed")
+	assert.Nil(t, err)
+	assert.Equal(t, "hello write back!", string(data))
+	info, err := os.Stat("./mock/wb-renamed")
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestWriteBackReplay(t *testing.T) {
+	clean()
+	defer clean()
+	// an upload left by last mount
+	dir := filepath.Join("./mock-cache", writeBackFsID, "writeback")
+	assert.Nil(t, os.MkdirAll(dir, 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "7.data"), []byte("replayed"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "7.json"),
+		[]byte(`{"seq":7,"path":"/replay","size":8}`), 0644))
+	// a staging file never flushed is dropped
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "8.data"), []byte("dropped"), 0644))
+	// only the latest version of a path is replayed
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "3.data"), []byte("old"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "3.json"),
+		[]byte(`{"seq":3,"path":"/replay","size":3}`), 0644))
+	// a version older than the uploaded one is dropped
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "4.data"), []byte("stale"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "4.json"),
+		[]byte(`{"seq":4,"path":"/uploaded","size":5}`), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "5.json"),
+		[]byte(`{"seq":5,"path":"/uploaded","size":6,"uploaded":true}`), 0644))
+
+	newWriteBackFS(t)
+	var data []byte
+	for i := 0; i < 50; i++ {
+		if data, _ = ioutil.ReadFile("./mock/replay"); string(data) == "replayed" {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Equal(t, "replayed", string(data))
+	for _, name := range []string{"8.data", "3.data", "3.json", "4.data", "4.json", "5.json"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.True(t, os.IsNotExist(err), name)
+	}
+	_, err := os.Stat("./mock/uploaded")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWriteBackOpenFile(t *testing.T) {
+	clean()
+	defer clean()
+	pfs := newWriteBackFS(t)
+
+	// a file deleted while open is not uploaded on close
+	file, err := pfs.Create("wb-deleted", uint32(syscall.O_WRONLY|syscall.O_CREAT), 0644)
+	assert.Nil(t, err)
+	_, err = file.Write([]byte("deleted"))
+	assert.Nil(t, err)
+	assert.Nil(t, pfs.Unlink("wb-deleted"))
+	assert.Nil(t, file.Close())
+	time.Sleep(500 * time.Millisecond)
+	_, err = os.Stat("./mock/wb-deleted")
+	assert.True(t, os.IsNotExist(err))
+
+	// a file renamed while open is uploaded to the new path
+	file, err = pfs.Create("wb-old", uint32(syscall.O_WRONLY|syscall.O_CREAT), 0644)
+	assert.Nil(t, err)
+	_, err = file.Write([]byte("renamed"))
+	assert.Nil(t, err)
+	assert.Nil(t, pfs.Rename("wb-old", "wb-new"))
+	assert.Nil(t, file.Sync())
+	assert.Nil(t, file.Close())
+	data, err := ioutil.ReadFile("./mock/wb-new")
+	assert.Nil(t, err)
+	assert.Equal(t, "renamed", string(data))
+	_, err = os.Stat("./mock/wb-old")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -166,11 +166,21 @@ func (fs *PFS) Link(cancel <-chan struct{}, input *fuse.LinkIn, filename string,
 }
 
 func (fs *PFS) Symlink(cancel <-chan struct{}, header *fuse.InHeader, pointedTo string, linkName string, out *fuse.EntryOut) fuse.Status {
-	return fuse.ENOSYS
+	log.Debugf("pfs POSIX Symlink: header[%+v] pointedTo[%s] linkName[%s]", *header, pointedTo, linkName)
+	ctx := meta.NewContext(cancel, header.Uid, header.Pid, header.Gid)
+	entry, code := vfs.GetVFS().Symlink(ctx, pointedTo, vfs.Ino(header.NodeId), linkName)
+	if code != 0 {
+		return fuse.Status(code)
+	}
+	fs.replyEntry(entry, out)
+	return fuse.OK
 }
 
 func (fs *PFS) Readlink(cancel <-chan struct{}, header *fuse.InHeader) (out []byte, code fuse.Status) {
-	return out, fuse.ENOSYS
+	log.Debugf("pfs POSIX Readlink: header[%+v]", *header)
+	ctx := meta.NewContext(cancel, header.Uid, header.Pid, header.Gid)
+	out, errno := vfs.GetVFS().Readlink(ctx, vfs.Ino(header.NodeId))
+	return out, fuse.Status(errno)
 }
 
 func (fs *PFS) Access(cancel <-chan struct{}, input *fuse.AccessIn) fuse.Status {
@@ -18,10 +18,12 @@ package kv
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dgraph-io/badger/v3"
@@ -37,8 +39,25 @@ type KVTxn struct {
 const (
 	MemType  = "mem"
 	DiskType = "disk"
+
+	// formatVersion is kept in persistent cache, a cache of other format will be rebuilt
+	formatVersion = "pfs-kv-1"
 )
 
+// formatKey does not conflict with keys of meta, which start with a letter
+var formatKey = []byte("\xffformat")
+
+// errFormatMismatch is returned when the persistent cache is not written by formatVersion
+var errFormatMismatch = errors.New("cache format mismatch")
+
+// badgerCorruptedErrors are messages of badger errors caused by damaged files.
+// badger wraps errors with fmt.Errorf without %w, so they can only be matched by message.
+var badgerCorruptedErrors = []string{
+	"checksum mismatch",
+	"manifest has bad magic",
+	"manifest has unsupported version",
+}
+
 func NewBadgerClient(config Config) (KvClient, error) {
 	var db *badger.DB
 	var err error
@@ -49,12 +68,19 @@ func NewBadgerClient(config Config) (KvClient, error) {
 			return nil, fmt.Errorf("meta cache config path is not allowed empty")
 		}
 		cachePath := filepath.Join(config.CachePath, config.FsID+".db")
-		log.Infof("meta disk cache path %v", cachePath)
-		os.RemoveAll(cachePath)
-		if config.FsID == "" {
-			cachePath = filepath.Join(config.CachePath, strconv.Itoa(int(time.Now().Unix()))+"_"+utils.GetRandID(5))
+		log.Infof("meta disk cache path %v persistent %v", cachePath, config.Persistent)
+		if config.Persistent {
+			if config.FsID == "" {
+				return nil, fmt.Errorf("persistent disk cache requires fs id")
+			}
+			db, err = openPersistentBadger(cachePath)
+		} else {
+			os.RemoveAll(cachePath)
+			if config.FsID == "" {
+				cachePath = filepath.Join(config.CachePath, strconv.Itoa(int(time.Now().Unix()))+"_"+utils.GetRandID(5))
+			}
+			db, err = badger.Open(badger.DefaultOptions(cachePath))
 		}
-		db, err = badger.Open(badger.DefaultOptions(cachePath))
 	} else {
 		return nil, fmt.Errorf("not found meta driver name %s", config.Driver)
 	}
@@ -64,6 +90,82 @@ func NewBadgerClient(config Config) (KvClient, error) {
 	return &kvClient{db: db}, nil
 }
 
+// openPersistentBadger reuses the badger db left by last run.
+// badger replays its write-ahead log after a crash, and a db with damaged files
+// or an unknown format is considered corrupted and rebuilt from scratch.
+// Other errors, such as the directory lock held by another mount, are returned
+// without touching the db.
+func openPersistentBadger(path string) (*badger.DB, error) {
+	opts := badger.DefaultOptions(path).WithLogger(nil)
+	db, err := badger.Open(opts)
+	if err == nil {
+		if err = checkFormat(db); err != nil {
+			_ = db.Close()
+		}
+	}
+	if err == nil {
+		return db, nil
+	}
+	if !isCorrupted(err) {
+		log.Errorf("open persistent cache %s failed: %v", path, err)
+		return nil, err
+	}
+
+	log.Warnf("persistent cache %s is corrupted, rebuild it: %v", path, err)
+	if err = os.RemoveAll(path); err != nil {
+		return nil, err
+	}
+	if db, err = badger.Open(opts); err != nil {
+		return nil, err
+	}
+	if err = checkFormat(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// checkFormat sets format of a new db and checks format of an existing one
+func checkFormat(db *badger.DB) error {
+	return db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(formatKey)
+		if err == badger.ErrKeyNotFound {
+			it := txn.NewIterator(badger.IteratorOptions{})
+			it.Rewind()
+			empty := !it.Valid()
+			it.Close()
+			if !empty {
+				return fmt.Errorf("%w: cache format not found", errFormatMismatch)
+			}
+			return txn.Set(formatKey, []byte(formatVersion))
+		}
+		if err != nil {
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		if string(value) != formatVersion {
+			return fmt.Errorf("%w: cache format %s does not match %s", errFormatMismatch, string(value), formatVersion)
+		}
+		return nil
+	})
+}
+
+// isCorrupted tells whether the db should be rebuilt for err
+func isCorrupted(err error) bool {
+	if errors.Is(err, errFormatMismatch) {
+		return true
+	}
+	for _, msg := range badgerCorruptedErrors {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+	return false
+}
+
 func (kv *KVTxn) Get(key []byte) []byte {
 	item, err := kv.t.Get(key)
 	if err == badger.ErrKeyNotFound {
@@ -107,7 +209,8 @@ func (kv *KVTxn) ScanValues(prefix []byte) (map[string][]byte, error) {
 		k := item.Key()
 
 		err := item.Value(func(v []byte) error {
-			result[string(k)] = v
+			// v is only valid in the callback
+			result[string(k)] = append([]byte(nil), v...)
 			return nil
 		})
 		if err != nil {
@@ -149,6 +252,10 @@ func (c *kvClient) Name() string {
 	return "tikv"
 }
 
+func (c *kvClient) Close() error {
+	return c.db.Close()
+}
+
 func (c *kvClient) Txn(f func(txn KvTxn) error) error {
 	tx := c.db.NewTransaction(true)
 	defer tx.Discard()
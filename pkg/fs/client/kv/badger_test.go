@@ -1,8 +1,12 @@
 package kv
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewBadgerClient(t *testing.T) {
@@ -37,3 +41,74 @@ func TestNewBadgerClient(t *testing.T) {
 		})
 	}
 }
+
+func TestPersistentBadgerClient(t *testing.T) {
+	defer os.RemoveAll("./persistent")
+	config := Config{
+		FsID:       "fs-root-test",
+		Driver:     DiskType,
+		CachePath:  "./persistent",
+		Persistent: true,
+	}
+	get := func(client KvClient, key string) []byte {
+		var value []byte
+		assert.Nil(t, client.Txn(func(tx KvTxn) error {
+			value = tx.Get([]byte(key))
+			return nil
+		}))
+		return value
+	}
+
+	client, err := NewBadgerClient(config)
+	assert.Nil(t, err)
+	assert.Nil(t, client.Txn(func(tx KvTxn) error {
+		return tx.Set([]byte("foo"), []byte("bar"))
+	}))
+	assert.Nil(t, client.Close())
+
+	// data is kept after reopen
+	client, err = NewBadgerClient(config)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("bar"), get(client, "foo"))
+	assert.Nil(t, client.Close())
+
+	// db locked by another mount is neither opened nor removed
+	client, err = NewBadgerClient(config)
+	assert.Nil(t, err)
+	_, err = NewBadgerClient(config)
+	assert.NotNil(t, err)
+	assert.Equal(t, []byte("bar"), get(client, "foo"))
+	assert.Nil(t, client.Close())
+	client, err = NewBadgerClient(config)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("bar"), get(client, "foo"))
+	assert.Nil(t, client.Close())
+
+	// corrupted db is rebuilt
+	dbPath := filepath.Join(config.CachePath, config.FsID+".db")
+	files, err := ioutil.ReadDir(dbPath)
+	assert.Nil(t, err)
+	for _, f := range files {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dbPath, f.Name()), []byte("corrupted"), 0644))
+	}
+	client, err = NewBadgerClient(config)
+	assert.Nil(t, err)
+	assert.Nil(t, get(client, "foo"))
+	assert.Nil(t, client.Close())
+
+	// db of unknown format is rebuilt
+	client, err = NewBadgerClient(config)
+	assert.Nil(t, err)
+	assert.Nil(t, client.Txn(func(tx KvTxn) error {
+		return tx.Set(formatKey, []byte("unknown"))
+	}))
+	assert.Nil(t, client.Close())
+	client, err = NewBadgerClient(config)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte(formatVersion), get(client, string(formatKey)))
+	assert.Nil(t, client.Close())
+
+	config.FsID = ""
+	_, err = NewBadgerClient(config)
+	assert.NotNil(t, err)
+}
@@ -21,6 +21,8 @@ type Config struct {
 	Driver    string
 	CachePath string
 	Capacity  int64
+	// Persistent keeps disk cache across restarts instead of removing it at startup
+	Persistent bool
 }
 
 type KvTxn interface {
@@ -36,4 +38,5 @@ type KvTxn interface {
 type KvClient interface {
 	Name() string
 	Txn(f func(KvTxn) error) error
+	Close() error
 }
@@ -123,6 +123,13 @@ func newKvMeta(fsMeta common.FSMeta, links map[string]common.FSMeta, config Conf
 	if err != nil {
 		return nil, err
 	}
+	if config.Persistent {
+		// 上次退出时仍打开的文件句柄已失效，需清零以便过期的 inode 能够重新向 ufs 校验
+		if err = m.resetFileHandles(); err != nil {
+			log.Errorf("reset file handles of persistent meta cache failed: %v", err)
+			return nil, err
+		}
+	}
 	if config.PathCacheExpire > 0 {
 		pathCache, err := ristretto.NewCache(&ristretto.Config{
 			NumCounters: 1e7,
@@ -139,6 +146,30 @@ func newKvMeta(fsMeta common.FSMeta, links map[string]common.FSMeta, config Conf
 	return m, nil
 }
 
+func (m *kvMeta) resetFileHandles() error {
+	var values map[string][]byte
+	err := m.client.Txn(func(tx kv.KvTxn) error {
+		var err error
+		values, err = tx.ScanValues(m.fmtKey("I"))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		item := &inodeItem{}
+		m.parseInode(value, item)
+		if item.fileHandles == 0 {
+			continue
+		}
+		item.fileHandles = 0
+		if err = m.set([]byte(key), m.marshalInode(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *kvMeta) UpdateUFSMap(fsMetas map[string]common.FSMeta) error {
 	var ufsMap sync.Map
 	for key, value := range fsMetas {
@@ -730,8 +761,8 @@ func (m *kvMeta) SetAttr(ctx *Context, inode Ino, set uint32, attr *Attr) (strin
 	mode := attr.Mode
 	atime := attr.Atime
 	atimensec := attr.Atimensec
-	ctime := attr.Ctime
-	ctimensec := attr.Ctimensec
+	mtime := attr.Mtime
+	mtimensec := attr.Mtimensec
 	size := attr.Size
 	err := m.txn(func(tx kv.KvTxn) error {
 		absolutePath = m.absolutePath(inode, tx)
@@ -756,14 +787,29 @@ func (m *kvMeta) SetAttr(ctx *Context, inode Ino, set uint32, attr *Attr) (strin
 		}
 		if set&FATTR_MODE != 0 {
 			log.Debugf("set mode %+v", set)
-			cur.attr.Mode = mode
+			// mode中只修改权限位，保留文件类型
+			cur.attr.Mode = cur.attr.Mode&syscall.S_IFMT | mode&07777
 		}
 		if set&FATTR_ATIME != 0 || set&FATTR_MTIME != 0 || set&FATTR_CTIME != 0 {
 			log.Debugf("set time %+v", set)
-			cur.attr.Atime = atime
-			cur.attr.Atimensec = atimensec
-			cur.attr.Ctime = ctime
-			cur.attr.Ctimensec = ctimensec
+			// 只修改设置的时间，如touch -a只修改atime
+			now := time.Now()
+			if set&FATTR_ATIME_NOW != 0 {
+				cur.attr.Atime = now.Unix()
+				cur.attr.Atimensec = uint32(now.Nanosecond())
+			} else if set&FATTR_ATIME != 0 {
+				cur.attr.Atime = atime
+				cur.attr.Atimensec = atimensec
+			}
+			if set&FATTR_MTIME_NOW != 0 {
+				cur.attr.Mtime = now.Unix()
+				cur.attr.Mtimensec = uint32(now.Nanosecond())
+			} else if set&FATTR_MTIME != 0 {
+				cur.attr.Mtime = mtime
+				cur.attr.Mtimensec = mtimensec
+			}
+			cur.attr.Ctime = now.Unix()
+			cur.attr.Ctimensec = uint32(now.Nanosecond())
 		}
 		if set&FATTR_SIZE != 0 {
 			log.Debugf("set size %+v size %+v", set, size)
@@ -776,29 +822,38 @@ func (m *kvMeta) SetAttr(ctx *Context, inode Ino, set uint32, attr *Attr) (strin
 		}
 		return nil
 	})
+	if err != nil {
+		return "", utils.ToSyscallErrno(err)
+	}
+	// 使用修改后的属性同步到ufs，s3等对象存储会将其保存在object的metadata中
 	if set&FATTR_UID != 0 || set&FATTR_GID != 0 {
-		if err = ufs_.Chown(path, attr.Uid, attr.Gid); err != nil {
+		if err = ufs_.Chown(path, cur.attr.Uid, cur.attr.Gid); err != nil {
 			return "", utils.ToSyscallErrno(err)
 		}
 	}
 
 	if set&FATTR_MODE != 0 {
-		if err = ufs_.Chmod(path, attr.Mode); err != nil {
+		if err = ufs_.Chmod(path, cur.attr.Mode); err != nil {
 			return "", utils.ToSyscallErrno(err)
 		}
 	}
 	// s3未实现utimes函数，创建文件时存在报错：setting times of ‘xx’: Function not implemented。因此这里忽略enosys报错
-	if set&FATTR_ATIME != 0 || set&FATTR_MTIME != 0 || set&FATTR_CTIME != 0 {
-		atime := time.Unix(attr.Atime, int64(attr.Atimensec))
-		ctime := time.Unix(attr.Ctime, int64(attr.Ctimensec))
-		if err = ufs_.Utimens(path, &atime, &ctime); err != nil {
+	// 只传入修改的时间，未修改的为nil
+	if set&FATTR_ATIME != 0 || set&FATTR_MTIME != 0 {
+		var atime, mtime *time.Time
+		if set&FATTR_ATIME != 0 {
+			t := time.Unix(cur.attr.Atime, int64(cur.attr.Atimensec))
+			atime = &t
+		}
+		if set&FATTR_MTIME != 0 {
+			t := time.Unix(cur.attr.Mtime, int64(cur.attr.Mtimensec))
+			mtime = &t
+		}
+		if err = ufs_.Utimens(path, atime, mtime); err != nil {
 			return "", utils.ToSyscallErrno(err)
 		}
 	}
-
-	if err != nil {
-		return "", utils.ToSyscallErrno(err)
-	}
+	*attr = cur.attr
 	m.setPathCache(inode, &cur)
 	return absolutePath, syscall.F_OK
 }
@@ -811,12 +866,61 @@ func (m *kvMeta) Fallocate(ctx *Context, inode Ino, mode uint8, off uint64, size
 	return syscall.ENOSYS
 }
 
+// ufsPath 获取inode对应的ufs及其在ufs中的路径
+func (m *kvMeta) ufsPath(inode Ino) (ufslib.UnderFileStorage, string, error) {
+	var absolutePath string
+	err := m.txn(func(tx kv.KvTxn) error {
+		absolutePath = m.absolutePath(inode, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	ufs_, _, _, path := m.GetUFS(absolutePath)
+	return ufs_, path, nil
+}
+
 func (m *kvMeta) ReadLink(ctx *Context, inode Ino, path *[]byte) syscall.Errno {
-	return syscall.ENOSYS
+	log.Debugf("kv meta ReadLink inode[%v]", inode)
+	ufs_, ufsPath, err := m.ufsPath(inode)
+	if err != nil {
+		return utils.ToSyscallErrno(err)
+	}
+	target, err := ufs_.Readlink(ufsPath)
+	if err != nil {
+		return utils.ToSyscallErrno(err)
+	}
+	*path = []byte(target)
+	return syscall.F_OK
 }
 
 func (m *kvMeta) Symlink(ctx *Context, parent Ino, name string, path string, inode *Ino, attr *Attr) syscall.Errno {
-	return syscall.ENOSYS
+	log.Debugf("kv meta Symlink parent[%v] name[%s] path[%s]", parent, name, path)
+	entry, err := m.get(m.entryKey(parent, name))
+	if err != nil {
+		return syscall.EIO
+	}
+	if entry != nil {
+		return syscall.EEXIST
+	}
+	ufs_, parentPath, err := m.ufsPath(parent)
+	if err != nil {
+		return utils.ToSyscallErrno(err)
+	}
+	if err = ufs_.Symlink(path, filepath.Join(parentPath, name)); err != nil {
+		log.Errorf("kv meta Symlink parent[%v] name[%s] err: %v", parent, name, err)
+		return utils.ToSyscallErrno(err)
+	}
+	// 软链接创建后通过lookup从ufs中获取其属性并生成inode
+	ino, lookupAttr, errno := m.Lookup(ctx, parent, name)
+	if errno != syscall.F_OK {
+		return errno
+	}
+	*inode = ino
+	if attr != nil {
+		*attr = *lookupAttr
+	}
+	return syscall.F_OK
 }
 
 func (m *kvMeta) Mknod(ctx *Context, parent Ino, name string, _type uint8, mode, cumask uint32, rdev uint32, inode *Ino, attr *Attr) syscall.Errno {
@@ -1366,6 +1470,10 @@ func (m *kvMeta) Readdir(ctx *Context, inode Ino, entries *[]*Entry) syscall.Err
 				})
 			}
 			expire = now.Add(m.attrTimeOut).Unix()
+			if dir.AttrIncomplete {
+				// ufs返回的属性不完整时不缓存，由GetAttr从ufs获取
+				expire = 0
+			}
 			insertChildInode = &inodeItem{
 				attr:      *childEntryItem.Attr,
 				parentIno: inode,
@@ -1494,7 +1602,12 @@ func (m *kvMeta) Create(ctx *Context, parent Ino, name string, mode uint32, cuma
 		return nil, "", utils.ToSyscallErrno(err)
 	}
 	ufs_, _, _, newPath = m.GetUFS(absolutePath)
-	fh, err := ufs_.Create(newPath, flags, mode)
+	var fh ufslib.FileHandle
+	if creator, ok := ufs_.(ufslib.OwnerCreator); ok {
+		fh, err = creator.CreateWithOwner(newPath, flags, attr.Mode, attr.Uid, attr.Gid)
+	} else {
+		fh, err = ufs_.Create(newPath, flags, mode)
+	}
 	if err != nil {
 		log.Errorf("Create: name[%s], flags[%d], mode[%d], failed: [%v]",
 			name, flags, mode, err)
@@ -1620,19 +1733,45 @@ func (m *kvMeta) CopyFileRange(ctx *Context, fin Ino, offIn uint64, fout Ino, of
 }
 
 func (m *kvMeta) GetXattr(ctx *Context, inode Ino, attribute string, vbuff *[]byte) syscall.Errno {
-	return syscall.ENOSYS
+	ufs_, path, err := m.ufsPath(inode)
+	if err != nil {
+		return utils.ToSyscallErrno(err)
+	}
+	value, err := ufs_.GetXAttr(path, attribute)
+	if err != nil {
+		return utils.ToSyscallErrno(err)
+	}
+	*vbuff = value
+	return syscall.F_OK
 }
 
 func (m *kvMeta) ListXattr(ctx *Context, inode Ino, dbuff *[]string) syscall.Errno {
-	return syscall.ENOSYS
+	ufs_, path, err := m.ufsPath(inode)
+	if err != nil {
+		return utils.ToSyscallErrno(err)
+	}
+	attributes, err := ufs_.ListXAttr(path)
+	if err != nil {
+		return utils.ToSyscallErrno(err)
+	}
+	*dbuff = attributes
+	return syscall.F_OK
 }
 
 func (m *kvMeta) SetXattr(ctx *Context, inode Ino, name string, value []byte, flags uint32) syscall.Errno {
-	return syscall.ENOSYS
+	ufs_, path, err := m.ufsPath(inode)
+	if err != nil {
+		return utils.ToSyscallErrno(err)
+	}
+	return utils.ToSyscallErrno(ufs_.SetXAttr(path, name, value, int(flags)))
 }
 
 func (m *kvMeta) RemoveXattr(ctx *Context, inode Ino, name string) syscall.Errno {
-	return syscall.ENOSYS
+	ufs_, path, err := m.ufsPath(inode)
+	if err != nil {
+		return utils.ToSyscallErrno(err)
+	}
+	return utils.ToSyscallErrno(ufs_.RemoveXAttr(path, name))
 }
 
 func (m *kvMeta) Flock(ctx *Context, inode Ino, owner uint64, ltype uint32, block bool) syscall.Errno {
@@ -202,7 +202,18 @@ func (fs *hdfsFileSystem) Utimens(name string, atime, mtime *time.Time) error {
 	log.Tracef("hdfs utimens: name[%s], atime[%v] mtime[%v]", name, atime, mtime)
 	fs.Lock()
 	defer fs.Unlock()
-	return fs.client.Chtimes(fs.GetPath(name), *atime, *mtime)
+	path := fs.GetPath(name)
+	a, m, err := completeTimes(atime, mtime, func() (time.Time, time.Time, error) {
+		info, err := fs.client.Stat(path)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return info.(*hdfs.FileInfo).AccessTime(), info.ModTime(), nil
+	})
+	if err != nil {
+		return err
+	}
+	return fs.client.Chtimes(path, a, m)
 }
 
 func (fs *hdfsFileSystem) Truncate(name string, size uint64) error {
@@ -27,6 +27,7 @@ import (
 const (
 	TypeFile      = 1 // type for regular file
 	TypeDirectory = 2 // type for directory
+	TypeSymlink   = 3 // type for symlink
 )
 
 // under file storage interface, copy from pathfs.FileSystem,
@@ -47,6 +48,7 @@ type UnderFileStorage interface {
 	// Note: raw FUSE setattr is translated into Chmod/Chown/Utimens in the higher level APIs.
 	Chmod(name string, mode uint32) error
 	Chown(name string, uid uint32, gid uint32) error
+	// Atime or Mtime is nil when it is not changed.
 	Utimens(name string, Atime *time.Time, Mtime *time.Time) error
 
 	Truncate(name string, size uint64) error
@@ -115,10 +117,37 @@ type withCloser struct {
 
 type Creator func(properties map[string]interface{}) (UnderFileStorage, error)
 
+// OwnerCreator is implemented by ufs which keep the owner of files by themselves, e.g. in s3 object metadata.
+// files are created with the uid and gid of the caller through CreateWithOwner instead of Create.
+type OwnerCreator interface {
+	CreateWithOwner(name string, flags, mode, uid, gid uint32) (FileHandle, error)
+}
+
+// completeTimes fills atime or mtime not changed with the current one got by stat,
+// for ufs which can only set both of them at once.
+func completeTimes(atime, mtime *time.Time, stat func() (time.Time, time.Time, error)) (time.Time, time.Time, error) {
+	if atime != nil && mtime != nil {
+		return *atime, *mtime, nil
+	}
+	curAtime, curMtime, err := stat()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if atime != nil {
+		curAtime = *atime
+	}
+	if mtime != nil {
+		curMtime = *mtime
+	}
+	return curAtime, curMtime, nil
+}
+
 type DirEntry struct {
 	Attr *Attr
 	// Name is the basename of the file in the directory.
 	Name string
+	// AttrIncomplete means some attributes can only be got by GetAttr, thus Attr should not be cached.
+	AttrIncomplete bool
 }
 
 type Ino uint64
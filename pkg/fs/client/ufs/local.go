@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/base"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
@@ -34,6 +35,16 @@ type localFileSystem struct {
 
 var _ UnderFileStorage = &localFileSystem{}
 
+// localTimes returns atime and mtime of a local file
+func localTimes(path string) (time.Time, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	attr := sysToAttr(info)
+	return time.Unix(attr.Atime, int64(attr.Atimensec)), time.Unix(attr.Mtime, int64(attr.Mtimensec)), nil
+}
+
 // Used for pretty printing.
 func (fs *localFileSystem) String() string {
 	return common.LocalType
@@ -107,18 +107,18 @@ func (fs *localFileSystem) SetXAttr(name string, attr string, data []byte, flags
 }
 
 func (fs *localFileSystem) Utimens(name string, Atime *time.Time, Mtime *time.Time) error {
-	var tv []syscall.Timeval
-
-	tv = append(tv, syscall.Timeval{
-		Sec:  int64(Atime.Second()),
-		Usec: Atime.Unix(),
-	})
-
-	tv = append(tv, syscall.Timeval{
-		Sec:  int64(Mtime.Second()),
-		Usec: Mtime.Unix(),
+	path := fs.GetPath(name)
+	atime, mtime, err := completeTimes(Atime, Mtime, func() (time.Time, time.Time, error) {
+		return localTimes(path)
 	})
-	return syscall.Utimes(fs.GetPath(name), tv)
+	if err != nil {
+		return err
+	}
+	tv := []syscall.Timeval{
+		syscall.NsecToTimeval(atime.UnixNano()),
+		syscall.NsecToTimeval(mtime.UnixNano()),
+	}
+	return syscall.Utimes(path, tv)
 }
 
 func (f *localFileHandle) Allocate(off uint64, sz uint64, mode uint32) error {
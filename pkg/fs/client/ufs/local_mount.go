@@ -60,7 +60,14 @@ func (fs *localMount) Chown(name string, uid uint32, gid uint32) error {
 }
 
 func (fs *localMount) Utimens(name string, atime *time.Time, mtime *time.Time) error {
-	return os.Chtimes(fs.GetPath(name), *atime, *mtime)
+	path := fs.GetPath(name)
+	a, m, err := completeTimes(atime, mtime, func() (time.Time, time.Time, error) {
+		return localTimes(path)
+	})
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(path, a, m)
 }
 
 func (fs *localMount) Truncate(name string, size uint64) error {
@@ -56,7 +56,6 @@ const (
 	// mpu
 	MPURetryTimes   = 2
 	MPUThreshold    = 200 * 1024 * 1024      // customized for performance
-	MPUChunkSize    = 1 * 1024 * 1024 * 1024 // chunk size 1 GiB
 	MPUMinPartSize  = 5 * 1024 * 1024        // s3: Each part must be at least 5 MB ~ 5 GB in size (except for the last part)
 	MPUMaxPartSize  = 5 * 1024 * 1024 * 1024 // s3: Each part must be at least 5 MB ~ 5 GB in size (except for the last part)
 	MPUMaxPartNum   = 10000                  // s3: between 1~10,000
@@ -76,7 +75,10 @@ type s3FileSystem struct {
 	s3          *s3.S3
 	defaultTime time.Time
 	sync.Mutex
-	chunkPool *sync.Pool
+	// multipart upload
+	mpuThreshold int64
+	mpuPartSize  int64
+	uploadLimit  chan struct{}
 }
 
 var _ UnderFileStorage = &s3FileSystem{}
@@ -263,16 +265,23 @@ func (fs *s3FileSystem) getRootDirAttr() *base.FileInfo {
 }
 
 func (fs *s3FileSystem) getDefaultDirAttr(name string) (*base.FileInfo, error) {
-	if err := fs.isDirExist(name); err != nil {
+	dirObject, err := fs.isDirExist(name)
+	if err != nil {
 		return nil, err
 	}
+	// 目录object中保存了posix属性时，使用保存的属性，否则使用默认属性
+	if dirObject != nil && hasPosixMeta(dirObject.Metadata) {
+		attr := fs.parseS3Attr(true, 4096, fs.defaultTime, dirObject.Metadata)
+		return attr.fileInfo(name, fs.getFullPath(name), true), nil
+	}
 	fInfo := fs.getRootDirAttr()
 	fInfo.Name = name
 	fInfo.Path = fs.getFullPath(name)
 	return fInfo, nil
 }
 
-func (fs *s3FileSystem) isDirExist(name string) error {
+// isDirExist returns the object of directory if it exists, which may be nil as s3 dir can have no key
+func (fs *s3FileSystem) isDirExist(name string) (*s3.HeadObjectOutput, error) {
 	name = toDirPath(name)
 	path := fs.getFullPath(name)
 	// when s3 prefix/dir has no s3 object key, cannot be list
@@ -307,23 +316,31 @@ func (fs *s3FileSystem) isDirExist(name string) error {
 	for {
 		select {
 		case resp := <-errDirChan:
-			return resp
+			return nil, resp
 		case resp := <-errObjectChan:
 			if !isNotExistErr(resp) {
 				log.Errorf("isDirExist object err: %v", resp)
-				return resp
+				return nil, resp
 			}
 			objectNotFound = true
-		case <-objectChan:
-			return nil
+		case object := <-objectChan:
+			return &object, nil
 		case resp := <-dirChan:
 			if len(resp) > 0 {
-				return nil
+				// 目录object可能存在，需要获取其中保存的posix属性
+				if !objectNotFound {
+					select {
+					case object := <-objectChan:
+						return &object, nil
+					case <-errObjectChan:
+					}
+				}
+				return nil, nil
 			}
 			listDirsEmpty = true
 		}
 		if listDirsEmpty && objectNotFound {
-			return syscall.ENOENT
+			return nil, syscall.ENOENT
 		}
 	}
 }
@@ -361,56 +378,15 @@ func (fs *s3FileSystem) GetAttr(name string) (*base.FileInfo, error) {
 		return nil, err
 	}
 
-	aTime := fuse.UtimeToTimespec(response.LastModified)
-
-	size := *response.ContentLength
-	isDir := strings.HasSuffix(path, Delimiter)
-	mode := syscall.S_IFREG | fs.fileMode
-
 	// if empty directory, s3 will return size=0
-	if isDir {
-		size = 4096
-		mode = syscall.S_IFDIR | fs.dirMode
-	}
-
-	uid := uint32(utils.LookupUser(Owner))
-	gid := uint32(utils.LookupGroup(Group))
-	st := fillStat(1, uint32(mode), uid, gid, size, 4096, size/512, aTime, aTime, aTime)
-
-	var mtime uint64
+	isDir := strings.HasSuffix(path, Delimiter)
+	mtime := *response.LastModified
 	if path == Delimiter {
-		mtime = uint64(time.Now().Unix())
-	} else {
-		mtime = uint64((*response.LastModified).Unix())
+		mtime = time.Now()
 	}
-
-	return &base.FileInfo{
-		Name:  name,
-		Path:  path,
-		Size:  size,
-		Mtime: mtime,
-		IsDir: isDir,
-		Owner: Owner,
-		Group: Group,
-		Mode:  utils.StatModeToFileMode(mode),
-		Sys:   st,
-	}, nil
-}
-
-// These should update the file's ctime too.
-func (fs *s3FileSystem) Chmod(name string, mode uint32) error {
-	// s3不支持chmod，但是返回报错会导致tar解压报错，因此直接跳过
-	return nil
-}
-
-func (fs *s3FileSystem) Chown(name string, uid uint32, gid uint32) error {
-	//  s3不支持chown，但是返回报错会导致tar解压报错，因此直接跳过
-	return nil
-}
-
-func (fs *s3FileSystem) Utimens(name string, atime *time.Time, mtime *time.Time) error {
-	//  s3不支持Utimes，但是返回报错会导致tar解压报错，因此直接跳过
-	return nil
+	// mode, uid/gid, mtime和symlink保存在object的metadata中，没有保存时使用默认值
+	attr := fs.parseS3Attr(isDir, *response.ContentLength, mtime, response.Metadata)
+	return attr.fileInfo(name, path, isDir), nil
 }
 
 func (fs *s3FileSystem) Truncate(name string, size uint64) error {
@@ -446,11 +422,16 @@ func (fs *s3FileSystem) createEmptyDir(name string) error {
 }
 
 func (fs *s3FileSystem) putEmptyFile(path string) error {
-	log.Tracef("s3 putEmptyFile: full path[%s]", path)
+	return fs.putEmptyFileWithMeta(path, nil)
+}
+
+func (fs *s3FileSystem) putEmptyFileWithMeta(path string, meta map[string]*string) error {
+	log.Tracef("s3 putEmptyFile: full path[%s] meta[%v]", path, meta)
 	request := &s3.PutObjectInput{
-		Bucket: &fs.bucket,
-		Key:    aws.String(path),
-		Body:   nil,
+		Bucket:   &fs.bucket,
+		Key:      aws.String(path),
+		Body:     nil,
+		Metadata: meta,
 	}
 	_, err := fs.s3.PutObject(request)
 	if err != nil {
@@ -723,23 +704,6 @@ func (fs *s3FileSystem) Unlink(name string) error {
 	return err
 }
 
-// // Extended attributes.
-func (fs *s3FileSystem) GetXAttr(name string, attribute string) (data []byte, err error) {
-	return nil, syscall.ENOSYS
-}
-
-func (fs *s3FileSystem) ListXAttr(name string) (attributes []string, err error) {
-	return nil, syscall.ENOSYS
-}
-
-func (fs *s3FileSystem) RemoveXAttr(name string, attr string) error {
-	return syscall.ENOSYS
-}
-
-func (fs *s3FileSystem) SetXAttr(name string, attr string, data []byte, flags int) error {
-	return syscall.ENOSYS
-}
-
 func (fs *s3FileSystem) getOpenFlags(name string, flags uint32) int {
 	log.Tracef("s3 getOpenFlags: name[%s], the flags&syscall.O_ACCMODE is %d", name, flags&syscall.O_ACCMODE)
 	if flags&syscall.O_ACCMODE == syscall.O_RDONLY {
@@ -805,6 +769,23 @@ func (fs *s3FileSystem) createEmptyFile(name string) error {
 
 func (fs *s3FileSystem) Create(name string, flags, mode uint32) (fd FileHandle, err error) {
 	log.Tracef("s3 create: name[%s] flags[%d], mode[%d]", name, flags, mode)
+	return fs.create(name, flags, map[string]*string{
+		S3MetaMode: aws.String(strconv.FormatUint(uint64(mode&07777), 8)),
+	})
+}
+
+// CreateWithOwner creates the file like Create, and keeps the owner in object metadata as well
+func (fs *s3FileSystem) CreateWithOwner(name string, flags, mode, uid, gid uint32) (FileHandle, error) {
+	log.Tracef("s3 create: name[%s] flags[%d], mode[%d], uid[%d], gid[%d]", name, flags, mode, uid, gid)
+	return fs.create(name, flags, map[string]*string{
+		S3MetaMode: aws.String(strconv.FormatUint(uint64(mode&07777), 8)),
+		S3MetaUid:  aws.String(strconv.FormatUint(uint64(uid), 10)),
+		S3MetaGid:  aws.String(strconv.FormatUint(uint64(gid), 10)),
+	})
+}
+
+// create opens the file for writing, meta is kept by the object if it does not exist yet
+func (fs *s3FileSystem) create(name string, flags uint32, meta map[string]*string) (fd FileHandle, err error) {
 	fs.Lock()
 	defer fs.Unlock()
 	if flags&syscall.O_CREAT != 0 || flags&syscall.O_EXCL != 0 {
@@ -818,6 +799,7 @@ func (fs *s3FileSystem) Create(name string, flags, mode uint32) (fd FileHandle,
 			size:   0,
 			// for upload new empty file, or the file will disappear after entry-cache expired, rename op will also generate bugs
 			writeDirty: true,
+			meta:       meta,
 		}
 		err = fs.openForWrite(fh)
 		if err != nil {
@@ -853,6 +835,7 @@ func (fs *s3FileSystem) openForWrite(fh *s3FileHandle) error {
 			log.Errorf("s3 openForWrite: s3.GetObject[%s] err: %v", fh.path, err)
 			return err
 		}
+		fh.setMeta(response.Metadata)
 		fh.canWrite = make(chan struct{})
 		go func() {
 			defer close(fh.canWrite)
@@ -866,6 +849,8 @@ func (fs *s3FileSystem) openForWrite(fh *s3FileHandle) error {
 			fh.writeSrcReader = nil
 			response.Body.Close()
 		}()
+	} else if response, err := fh.fs.headObject(fh.path); err == nil {
+		fh.setMeta(response.Metadata)
 	}
 	return nil
 }
@@ -886,46 +871,17 @@ func (fs *s3FileSystem) ReadDir(name string) ([]DirEntry, error) {
 		if finfo.Name == "." {
 			continue
 		}
-		mtime := int64(finfo.Mtime)
-		size := finfo.Size
-		mode := syscall.S_IFREG | fs.fileMode
-		isDir := finfo.IsDir
-		fileType := uint8(TypeFile)
-		if isDir {
-			fileType = TypeDirectory
-			mode = syscall.S_IFDIR | fs.dirMode
-			size = 4096
-		}
-		uid := uint32(utils.LookupUser(Owner))
-		gid := uint32(utils.LookupGroup(Group))
-		subName := strings.TrimSuffix(finfo.Name, Delimiter)
+		// list结果中不包含object的metadata，保存在metadata中的posix属性由GetAttr获取
+		attr := fs.parseS3Attr(finfo.IsDir, finfo.Size, time.Unix(int64(finfo.Mtime), 0), nil)
 		stream = append(stream, DirEntry{
-			Attr: &Attr{
-				Type:      fileType,
-				Size:      uint64(size),
-				Mode:      uint32(mode),
-				Mtime:     mtime,
-				Atimensec: uint32(mtime),
-				Mtimensec: uint32(mtime),
-				Ctimensec: uint32(mtime),
-				Uid:       uid,
-				Gid:       gid,
-			},
-			Name: subName,
+			Attr:           attr.dirEntryAttr(),
+			Name:           strings.TrimSuffix(finfo.Name, Delimiter),
+			AttrIncomplete: true,
 		})
 	}
 	return stream, nil
 }
 
-// Symlinks.
-func (fs *s3FileSystem) Symlink(value string, linkName string) error {
-	return syscall.ENOSYS
-}
-
-func (fs *s3FileSystem) Readlink(name string) (string, error) {
-	return "", syscall.ENOSYS
-}
-
 func (fs *s3FileSystem) Get(name string, flags uint32, off, limit int64) (io.ReadCloser, error) {
 	log.Tracef("s3 get: name[%s] off[%d] limit[%d] ", name, off, limit)
 	fullPath := fs.getFullPath(name)
@@ -968,13 +924,6 @@ func (fs *s3FileSystem) StatFs(name string) *base.StatfsOut {
 	}
 }
 
-type mpuInfo struct {
-	uploadID      *string
-	lastPartNum   int64
-	lastUploadEnd int64
-	partsETag     []*string
-}
-
 type s3FileHandle struct {
 	mpuInfo        mpuInfo
 	bucket         string
@@ -988,6 +937,8 @@ type s3FileHandle struct {
 	fs             *s3FileSystem
 	mu             sync.RWMutex
 	writeDirty     bool
+	// meta keeps posix attributes of the object when it is opened, or those given by create for a new object
+	meta map[string]*string
 }
 
 var _ FileHandle = &s3FileHandle{}
@@ -1059,107 +1010,10 @@ func (fh *s3FileHandle) Write(data []byte, offset uint64) (uint32, error) {
 		return uint32(0), err
 	}
 	fh.writeDirty = true
+	fh.streamUpload(int64(offset), n)
 	return uint32(n), nil
 }
 
-func (fh *s3FileHandle) serialMPUTillEnd() error {
-	fInfo, err := fh.writeTmpfile.Stat()
-	if err != nil {
-		log.Errorf("s3 serialMPUTillEnd: fh.name[%s] writeTmpfile.Stat err: %v", fh.name, err)
-		return err
-	}
-	fileSize := fInfo.Size()
-	log.Tracef("s3 mpu: fh.name[%s], fileSize[%d]", fh.name, fileSize)
-	partSize, chunkSize, _ := fh.partAndChunkSize(fileSize)
-	chunkCnt := fileSize / chunkSize
-	chunkLeftover := fileSize % chunkSize
-	if chunkLeftover != 0 {
-		chunkCnt++
-	}
-	log.Tracef("s3 mpu: fh.name[%s], chunkCnt: %d, chunkLeftover: %d",
-		fh.name, chunkCnt, chunkLeftover)
-
-	partCnt := fileSize / partSize
-	partLeftover := fileSize % partSize
-	if partLeftover != 0 {
-		partCnt++
-	}
-
-	fh.mpuInfo.lastPartNum = partCnt
-	fh.mpuInfo.partsETag = make([]*string, partCnt)
-	chunkEG := new(errgroup.Group)
-
-	log.Tracef("s3 mpu: fh.name[%s], fileSize[%d], chunkCnt[%d], partCnt[%d]",
-		fh.name, fileSize, chunkCnt, partCnt)
-	for i := int64(0); i < chunkCnt; i++ {
-		// read tmp file to chunks
-		chunkBuf := fh.fs.chunkPool.Get().([]byte)
-		// resize chunk buffer for the last chunk to avoid EOF error
-		if chunkLeftover != 0 && i == chunkCnt-1 {
-			chunkBuf = chunkBuf[:chunkLeftover]
-		} else {
-			chunkBuf = chunkBuf[:cap(chunkBuf)]
-		}
-		chunkNum := i
-		chunkEG.Go(func() error {
-			if err := fh.readChunkAndMPU(fileSize, chunkNum, chunkBuf); err != nil {
-				log.Errorf("s3 multipartUploadFile: readChunkAndMPU error: %v", err)
-				return err
-			}
-			return nil
-		})
-	}
-	if err := chunkEG.Wait(); err != nil {
-		log.Errorf("s3 multipartUploadFile: chunkEG.Wait() error: %v", err)
-		return err
-	}
-	return nil
-}
-
-func (fh *s3FileHandle) readChunkAndMPU(fileSize, chunkNum int64, chunk []byte) error {
-	defer func() {
-		fh.fs.chunkPool.Put(chunk)
-	}()
-	partSize, chunkSize, partsPerChunk := fh.partAndChunkSize(fileSize)
-
-	// read from file to chunk buffer
-	_, err := fh.writeTmpfile.ReadAt(chunk, chunkNum*chunkSize)
-	if err != nil {
-		log.Errorf("s3 mpu upload: fh.name[%s], failed reading temp file chunkNum: %d. err:%v", fh.name, chunkNum, err)
-		return err
-	}
-	partCnt := int64(len(chunk)) / partSize
-	if len(chunk)%int(partSize) != 0 {
-		partCnt += 1
-	}
-	mpuEG := new(errgroup.Group)
-	log.Tracef("s3 mpu readFileAndUploadChunks: fh.name[%s], chunkNum: %d, chunkLength: %d, partCnt: %d, partSize: %d",
-		fh.name, chunkNum, len(chunk), partCnt, partSize)
-	// partNum := lastPartNum + 1; partNum <= partCnt; partNum++
-	for i := int64(1); i <= partCnt; i++ {
-		partNum := partsPerChunk*chunkNum + i
-		start := (i - 1) * partSize
-		end := start + partSize
-		if end > int64(len(chunk)) {
-			end = int64(len(chunk))
-		}
-		mpuEG.Go(func() error {
-			if err := fh.multipartUpload(partNum, chunk[start:end]); err != nil {
-				log.Errorf("s3 readFileMPU: fh.name[%s], multipartUpload[%d] err: %v",
-					fh.name, partNum, err)
-				return err
-			}
-			return nil
-		})
-	}
-
-	if err := mpuEG.Wait(); err != nil {
-		log.Errorf("s3 multipartUploadFile: mpuEG.Wait() chunkNum[%d] error: %v", chunkNum, err)
-		return err
-	}
-	return nil
-}
-
 func (fh *s3FileHandle) Release() {
 	if err := fh.uploadWriteTmpFile(); err != nil {
 		log.Errorf("s3 release: fh.name[%s] fh.uploadWriteTmpFile err: %v", fh.name, err)
@@ -1184,30 +1038,34 @@ func (fh *s3FileHandle) uploadWriteTmpFile() error {
 		return nil
 	}
 
-	// abort mpu on error
-	defer func() {
-		if fh.mpuInfo.uploadID != nil {
-			go func() {
-				_ = fh.multipartAbort()
-				fh.mpuInfo.uploadID = nil
-				fh.mpuInfo.lastUploadEnd = 0
-				fh.mpuInfo.lastPartNum = 0
-			}()
-		}
-	}()
-
 	fh.mu.Lock()
 	defer fh.mu.Unlock()
 
 	fInfo, err := fh.writeTmpfile.Stat()
 	if err != nil {
-		log.Errorf("s3 serialMPUTillEnd: fh.name[%s] writeTmpfile.Stat err: %v", fh.name, err)
+		log.Errorf("s3 uploadWriteTmpFile: fh.name[%s] writeTmpfile.Stat err: %v", fh.name, err)
 		return err
 	}
 	fileSize := fInfo.Size()
+	// parts uploaded while writing are still valid, upload the rest of them
+	if fh.mpuInfo.uploadID != nil && !fh.mpuInfo.broken {
+		if err := fh.uploadPartsTillEnd(fileSize); err != nil {
+			log.Errorf("s3 uploadWriteTmpFile: fh.name[%s] stream upload err: %v", fh.name, err)
+			fh.abortMPU()
+			return err
+		}
+		fh.writeDirty = false
+		return nil
+	}
+	// parts uploaded while writing are overwritten, upload the whole file again
+	fh.abortMPU()
 	// put empty file
 	if fileSize == 0 {
-		if err := fh.fs.putEmptyFile(fh.path); err != nil {
+		meta, err := fh.uploadMeta()
+		if err != nil {
+			return err
+		}
+		if err := fh.fs.putEmptyFileWithMeta(fh.path, meta); err != nil {
 			log.Debugf("s3 uploadWriteTmpFile: fh.name[%s], putEmptyFile err: %v", fh.name, err)
 			return err
 		}
@@ -1215,7 +1073,7 @@ func (fh *s3FileHandle) uploadWriteTmpFile() error {
 		return nil
 	}
 	// put file
-	if fileSize <= MPUThreshold {
+	if fileSize <= fh.fs.mpuThreshold {
 		if err := fh.putFile(fileSize); err != nil {
 			log.Debugf("s3 uploadWriteTmpFile: fh.name[%s], putFile length[%d] err: %v", fh.name, fileSize, err)
 			return err
@@ -1224,35 +1082,58 @@ func (fh *s3FileHandle) uploadWriteTmpFile() error {
 		return nil
 	}
 	// multi-part upload
-	if fh.mpuInfo.uploadID != nil {
-		log.Errorf("s3 uploadWriteTmpFile: fh.name[%s] mpuID not nil", fh.name)
-		return syscall.EIO
-	}
-	if err := fh.MPU(); err != nil {
+	if err := fh.MPU(fileSize); err != nil {
 		log.Errorf("s3 uploadWriteTmpFile: fh.name[%s] MPU err: %v", fh.name, err)
+		fh.abortMPU()
 		return err
 	}
 	fh.writeDirty = false
 	return nil
 }
 
-func (fh *s3FileHandle) MPU() error {
+func (fh *s3FileHandle) MPU(fileSize int64) error {
 	if err := fh.multipartCreate(); err != nil {
 		log.Debugf("s3 MPU: fh.name[%s], mpu create err: %v", fh.name, err)
 		return err
 	}
 
-	if err := fh.serialMPUTillEnd(); err != nil {
-		log.Debugf("s3 MPU: fh.name[%s], serialMPUTillEnd err:%v", fh.name, err)
+	if err := fh.uploadPartsTillEnd(fileSize); err != nil {
+		log.Debugf("s3 MPU: fh.name[%s], uploadPartsTillEnd err:%v", fh.name, err)
 		return err
 	}
+	return nil
+}
 
-	if err := fh.multipartCommit(); err != nil {
-		log.Debugf("s3 MPU: fh.name[%s], multipartCommit err:%v",
-			fh.name, err)
-		return err
+// setMeta keeps posix attributes of the object when it is opened
+func (fh *s3FileHandle) setMeta(meta map[string]*string) {
+	fh.meta = normalizeS3Meta(meta)
+}
+
+// uploadMeta returns metadata of the object to upload. posix attributes may be changed by chmod, chown,
+// utimens or setxattr while the file is open, thus they are read from the object again.
+// mtime is kept only if it is changed after the file is opened, e.g. by cp -p, otherwise it is the upload time.
+func (fh *s3FileHandle) uploadMeta() (map[string]*string, error) {
+	meta := fh.meta
+	response, err := fh.fs.headObject(fh.path)
+	if err == nil {
+		meta = normalizeS3Meta(response.Metadata)
+	} else if !isNotExistErr(err) {
+		log.Errorf("s3 uploadMeta: fh.name[%s] s3.HeadObject err: %v", fh.name, err)
+		return nil, err
 	}
-	return nil
+	res := make(map[string]*string, len(meta))
+	for k, v := range meta {
+		res[k] = v
+	}
+	if mtime, ok := res[S3MetaMtime]; ok {
+		if opened, ok := fh.meta[S3MetaMtime]; ok && *opened == *mtime {
+			delete(res, S3MetaMtime)
+		}
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	return res, nil
 }
 
 func (fh *s3FileHandle) putFile(fileSize int64) error {
@@ -1262,10 +1143,15 @@ func (fh *s3FileHandle) putFile(fileSize int64) error {
 		log.Errorf("s3 put: fh.name[%s], fh.writeTmpfile.Seek(0, 0) err:%v", fh.name, err)
 		return err
 	}
+	meta, err := fh.uploadMeta()
+	if err != nil {
+		return err
+	}
 	request := &s3.PutObjectInput{
-		Bucket: &fh.bucket,
-		Key:    aws.String(fh.path),
-		Body:   fh.writeTmpfile,
+		Bucket:   &fh.bucket,
+		Key:      aws.String(fh.path),
+		Body:     fh.writeTmpfile,
+		Metadata: meta,
 	}
 	_, err = fh.fs.s3.PutObject(request)
 	if err != nil {
@@ -1294,12 +1180,21 @@ func (fh *s3FileHandle) Truncate(size uint64) error {
 			break
 		}
 	}
+	fh.mu.Lock()
 	err := fh.writeTmpfile.Truncate(int64(size))
 	if err != nil {
+		fh.mu.Unlock()
 		log.Debugf("s3 truncate: fh.name[%s], writeTmpfile.Truncate err: %v", fh.name, err)
 		return err
 	}
+	// parts uploaded while writing are invalid if they are truncated
+	if int64(size) < fh.mpuInfo.lastUploadEnd {
+		fh.mpuInfo.broken = true
+	} else if int64(size) < fh.mpuInfo.seqWriteEnd {
+		fh.mpuInfo.seqWriteEnd = int64(size)
+	}
 	fh.writeDirty = true
+	fh.mu.Unlock()
 	return fh.uploadWriteTmpFile()
 }
 
@@ -1310,30 +1205,6 @@ func (fh *s3FileHandle) Allocate(off, size uint64, mode uint32) error {
 	return nil
 }
 
-func (fh *s3FileHandle) partAndChunkSize(fileSize int64) (partSize int64, chunkSize int64, partsPerChunk int64) {
-	chunkSize = MPUChunkSize // chunk size = 1 GiB
-	const MiB int64 = 1024 * 1024
-	const GiB int64 = 1024 * 1024 * 1024
-	if fileSize <= 8*GiB { // fileSize <= 8 GiB
-		// 8 MiB, 128 parts/chunk, total: 0 ~ 1,000 parts & chunks <= 8
-		partSize, partsPerChunk = 8*MiB, 128
-	} else if fileSize <= 256*GiB { // fileSize 8 GiB ~ 256 GiB
-		// 64 MiB, 16 parts/chunk, total: 125 ~ 4,000 parts & 8 ~ 256 chunks
-		partSize, partsPerChunk = 64*MiB, 16
-	} else if fileSize <= 2*1024*GiB { // fileSize 256 GiB ~ 2 TiB
-		// 512 MiB, 2 parts/chunk, total: 500 ~ 4000 parts & 256 ~ 2000 chunks
-		partSize, partsPerChunk = 512*MiB, 2
-	} else { // fileSize 2.5 TiB ~ 5 Tib
-		// 1 GiB, 1 parts/chunk, total: 2500 ~ 5000 parts & 2500 ~ 5000 chunks
-		partSize, partsPerChunk = 1*GiB, 1
-	}
-	if chunkSize%partSize != 0 || chunkSize/partSize != partsPerChunk {
-		log.Errorf("not valid partSize: %d or chunkSize: %d", partSize, chunkSize)
-		return 0, 0, 0
-	}
-	return partSize, chunkSize, partsPerChunk
-}
-
 func tidySubpath(subpath string) string {
 	for strings.HasPrefix(subpath, Delimiter) {
 		subpath = strings.TrimPrefix(subpath, Delimiter)
@@ -1375,6 +1246,10 @@ func NewS3FileSystem(properties map[string]interface{}) (UnderFileStorage, error
 	} else {
 		fileMode = DefaultFileMode
 	}
+	partSize, uploadConcurrency, err := mpuConfig(properties)
+	if err != nil {
+		return nil, err
+	}
 
 	endpoint = strings.TrimSuffix(endpoint, Delimiter)
 	bucket = strings.TrimSuffix(bucket, Delimiter)
@@ -1422,16 +1297,16 @@ func NewS3FileSystem(properties map[string]interface{}) (UnderFileStorage, error
 	}
 
 	fs := &s3FileSystem{
-		bucket:      bucket,
-		subpath:     tidySubpath(subpath),
-		dirMode:     dirMode,
-		fileMode:    fileMode,
-		sess:        sess,
-		s3:          s3.New(sess),
-		defaultTime: time.Now(),
-		chunkPool: &sync.Pool{New: func() interface{} {
-			return make([]byte, MPUChunkSize)
-		}},
+		bucket:       bucket,
+		subpath:      tidySubpath(subpath),
+		dirMode:      dirMode,
+		fileMode:     fileMode,
+		sess:         sess,
+		s3:           s3.New(sess),
+		defaultTime:  time.Now(),
+		mpuThreshold: MPUThreshold,
+		mpuPartSize:  partSize,
+		uploadLimit:  make(chan struct{}, uploadConcurrency),
 	}
 
 	exist, err := fs.isBucketExists(bucket)
@@ -1492,10 +1367,15 @@ func init() {
 
 func (fh *s3FileHandle) multipartCreate() error {
 	log.Tracef("s3 mpu create: fh.name[%s]", fh.name)
+	meta, err := fh.uploadMeta()
+	if err != nil {
+		return err
+	}
 
 	mpu := s3.CreateMultipartUploadInput{
-		Bucket: &fh.bucket,
-		Key:    aws.String(fh.path),
+		Bucket:   &fh.bucket,
+		Key:      aws.String(fh.path),
+		Metadata: meta,
 	}
 	log.Debugf("s3 mpu create: fh.name[%s], create param: %v ", fh.name, mpu)
 
@@ -1510,6 +1390,7 @@ func (fh *s3FileHandle) multipartCreate() error {
 		return err
 	}
 	fh.mpuInfo.uploadID = respCreate.UploadId
+	fh.mpuInfo.meta = meta
 	log.Debugf("s3 mpu create: fh.name[%s], create resp: %v ", fh.name, respCreate)
 	return nil
 }
@@ -1531,7 +1412,7 @@ func (fh *s3FileHandle) multipartUpload(partNum int64, data []byte) error {
 			log.Errorf("s3 mpu upload: fh.name[%s], upload part[%v] failed. err: %v. retryNum[%d]", fh.name, mpu, err, retryNum)
 		} else {
 			log.Tracef("s3 mpu upload: fh.name[%s], uploaded partNum: %d, eTag:%s, retryNum[%d]", fh.name, partNum, *resp.ETag, retryNum)
-			fh.mpuInfo.partsETag[partNum-1] = resp.ETag
+			fh.mpuInfo.setPartETag(partNum, resp.ETag)
 			return nil
 		}
 	}
@@ -1569,8 +1450,6 @@ func (fh *s3FileHandle) multipartCommit() error {
 		log.Errorf("s3 mpu commit: fh.name[%s], commit failed. err: %v ", fh.name, err)
 		return err
 	}
-	fh.mpuInfo.uploadID = nil
-	fh.mpuInfo.partsETag = nil
 	log.Tracef("s3 mpu commit: fh.name[%s], commit resp: %v ", fh.name, respCommit)
 	return nil
 }
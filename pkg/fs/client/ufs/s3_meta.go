@@ -0,0 +1,427 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ufs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/base"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/utils"
+)
+
+// posix attributes of s3 objects are kept in object user metadata (x-amz-meta-*).
+// objects without these keys fall back to the modes given by --dir-mode/--file-mode.
+const (
+	S3MetaMode    = "Pf-Mode"    // permission bits in octal
+	S3MetaUid     = "Pf-Uid"     // owner uid
+	S3MetaGid     = "Pf-Gid"     // owner gid
+	S3MetaMtime   = "Pf-Mtime"   // mtime in unix nano
+	S3MetaSymlink = "Pf-Symlink" // url escaped symlink target
+	S3MetaXAttrs  = "Pf-Xattrs"  // base64 encoded json of extended attributes
+
+	// s3MaxCopySize is the max size of object which can be copied by a single CopyObject request
+	s3MaxCopySize = 5 * 1024 * 1024 * 1024
+
+	xattrCreate  = 0x1
+	xattrReplace = 0x2
+)
+
+// s3Attr is the posix attribute of an s3 object
+type s3Attr struct {
+	mode    uint32 // stat mode, including file type
+	uid     uint32
+	gid     uint32
+	size    int64
+	mtime   time.Time
+	symlink string
+}
+
+// normalizeS3Meta canonicalizes metadata keys, as different s3 servers return keys in different cases
+func normalizeS3Meta(meta map[string]*string) map[string]*string {
+	res := make(map[string]*string, len(meta))
+	for k, v := range meta {
+		if v != nil {
+			res[http.CanonicalHeaderKey(k)] = v
+		}
+	}
+	return res
+}
+
+func hasPosixMeta(meta map[string]*string) bool {
+	for k := range normalizeS3Meta(meta) {
+		if strings.HasPrefix(k, "Pf-") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseS3Attr builds attr of object from its metadata, keys not present in metadata use defaults
+func (fs *s3FileSystem) parseS3Attr(isDir bool, size int64, mtime time.Time, meta map[string]*string) s3Attr {
+	attr := s3Attr{
+		mode:  uint32(syscall.S_IFREG | fs.fileMode),
+		uid:   uint32(utils.LookupUser(Owner)),
+		gid:   uint32(utils.LookupGroup(Group)),
+		size:  size,
+		mtime: mtime,
+	}
+	if isDir {
+		attr.mode = uint32(syscall.S_IFDIR | fs.dirMode)
+		attr.size = 4096
+	}
+
+	meta = normalizeS3Meta(meta)
+	if v, ok := meta[S3MetaSymlink]; ok && !isDir {
+		if target, err := url.PathUnescape(*v); err == nil {
+			attr.symlink = target
+			attr.mode = syscall.S_IFLNK | 0777
+			attr.size = int64(len(target))
+		}
+	}
+	if v, ok := meta[S3MetaMode]; ok {
+		if perm, err := strconv.ParseUint(*v, 8, 32); err == nil {
+			attr.mode = attr.mode&syscall.S_IFMT | uint32(perm)&07777
+		}
+	}
+	if v, ok := meta[S3MetaUid]; ok {
+		if uid, err := strconv.ParseUint(*v, 10, 32); err == nil {
+			attr.uid = uint32(uid)
+		}
+	}
+	if v, ok := meta[S3MetaGid]; ok {
+		if gid, err := strconv.ParseUint(*v, 10, 32); err == nil {
+			attr.gid = uint32(gid)
+		}
+	}
+	if v, ok := meta[S3MetaMtime]; ok {
+		if nano, err := strconv.ParseInt(*v, 10, 64); err == nil {
+			attr.mtime = time.Unix(0, nano)
+		}
+	}
+	return attr
+}
+
+func (attr s3Attr) fileInfo(name, path string, isDir bool) *base.FileInfo {
+	aTime := fuse.UtimeToTimespec(&attr.mtime)
+	st := fillStat(1, attr.mode, attr.uid, attr.gid, attr.size, 4096, attr.size/512, aTime, aTime, aTime)
+
+	owner, group := Owner, Group
+	if attr.uid != uint32(utils.LookupUser(Owner)) {
+		owner = utils.UserName(int(attr.uid))
+		if owner == "" {
+			owner = strconv.FormatUint(uint64(attr.uid), 10)
+		}
+	}
+	if attr.gid != uint32(utils.LookupGroup(Group)) {
+		group = utils.GroupName(int(attr.gid))
+		if group == "" {
+			group = strconv.FormatUint(uint64(attr.gid), 10)
+		}
+	}
+
+	return &base.FileInfo{
+		Name:  name,
+		Path:  path,
+		Size:  attr.size,
+		Mtime: uint64(attr.mtime.Unix()),
+		IsDir: isDir,
+		Owner: owner,
+		Group: group,
+		Mode:  utils.StatModeToFileMode(int(attr.mode)),
+		Sys:   st,
+	}
+}
+
+func (attr s3Attr) dirEntryAttr() *Attr {
+	fileType := uint8(TypeFile)
+	switch attr.mode & syscall.S_IFMT {
+	case syscall.S_IFDIR:
+		fileType = TypeDirectory
+	case syscall.S_IFLNK:
+		fileType = TypeSymlink
+	}
+	return &Attr{
+		Type:      fileType,
+		Size:      uint64(attr.size),
+		Mode:      attr.mode,
+		Mtime:     attr.mtime.Unix(),
+		Atime:     attr.mtime.Unix(),
+		Ctime:     attr.mtime.Unix(),
+		Atimensec: uint32(attr.mtime.Nanosecond()),
+		Mtimensec: uint32(attr.mtime.Nanosecond()),
+		Ctimensec: uint32(attr.mtime.Nanosecond()),
+		Uid:       attr.uid,
+		Gid:       attr.gid,
+	}
+}
+
+func (fs *s3FileSystem) headObject(path string) (*s3.HeadObjectOutput, error) {
+	return fs.s3.HeadObject(&s3.HeadObjectInput{
+		Bucket: &fs.bucket,
+		Key:    aws.String(path),
+	})
+}
+
+// headMetaObject returns the object which keeps metadata of name, the object of a directory is "name/"
+func (fs *s3FileSystem) headMetaObject(name string) (string, *s3.HeadObjectOutput, error) {
+	name = toS3Path(name)
+	path := fs.getFullPath(name)
+	response, err := fs.headObject(path)
+	if err == nil {
+		return path, response, nil
+	}
+	if !isNotExistErr(err) || strings.HasSuffix(path, Delimiter) {
+		return "", nil, err
+	}
+	dirPath := fs.getFullPath(toDirPath(name))
+	response, err = fs.headObject(dirPath)
+	if err == nil {
+		return dirPath, response, nil
+	}
+	return "", nil, err
+}
+
+// updateMeta updates posix attributes kept in object metadata.
+// s3 object metadata cannot be modified in place, thus the object is copied to itself with new metadata.
+func (fs *s3FileSystem) updateMeta(name string, update func(meta map[string]*string) error) error {
+	name = toS3Path(name)
+	if fs.getFullPath(name) == "" || name == Delimiter {
+		// root directory has no object to keep its attributes
+		return nil
+	}
+
+	path, response, err := fs.headMetaObject(name)
+	if err != nil {
+		if !isNotExistErr(err) {
+			log.Errorf("s3 updateMeta: name[%s] s3.HeadObject err: %v", name, err)
+			return err
+		}
+		// directory may have no object, create one to keep its attributes
+		if _, err := fs.isDirExist(name); err != nil {
+			return err
+		}
+		meta := map[string]*string{}
+		if err := update(meta); err != nil {
+			return err
+		}
+		return fs.putEmptyFileWithMeta(fs.getFullPath(toDirPath(name)), meta)
+	}
+
+	meta := normalizeS3Meta(response.Metadata)
+	if err := update(meta); err != nil {
+		return err
+	}
+	if response.ContentLength != nil && *response.ContentLength > s3MaxCopySize {
+		log.Errorf("s3 updateMeta: name[%s] size[%d] exceeds max size of s3.CopyObject", name, *response.ContentLength)
+		return syscall.EFBIG
+	}
+	copySource := fs.bucket + Delimiter + path
+	request := &s3.CopyObjectInput{
+		Bucket:            &fs.bucket,
+		Key:               aws.String(path),
+		CopySource:        aws.String(copySource),
+		Metadata:          meta,
+		MetadataDirective: aws.String(s3.MetadataDirectiveReplace),
+		ContentType:       response.ContentType,
+		StorageClass:      response.StorageClass,
+	}
+	if _, err = fs.s3.CopyObject(request); err != nil {
+		log.Errorf("s3 updateMeta: name[%s] s3.CopyObject err: %v", name, err)
+	}
+	return err
+}
+
+// These should update the file's ctime too.
+func (fs *s3FileSystem) Chmod(name string, mode uint32) error {
+	log.Tracef("s3 chmod: name[%s] mode[%o]", name, mode)
+	return fs.updateMeta(name, func(meta map[string]*string) error {
+		meta[S3MetaMode] = aws.String(strconv.FormatUint(uint64(mode&07777), 8))
+		return nil
+	})
+}
+
+func (fs *s3FileSystem) Chown(name string, uid uint32, gid uint32) error {
+	log.Tracef("s3 chown: name[%s] uid[%d] gid[%d]", name, uid, gid)
+	return fs.updateMeta(name, func(meta map[string]*string) error {
+		meta[S3MetaUid] = aws.String(strconv.FormatUint(uint64(uid), 10))
+		meta[S3MetaGid] = aws.String(strconv.FormatUint(uint64(gid), 10))
+		return nil
+	})
+}
+
+func (fs *s3FileSystem) Utimens(name string, atime *time.Time, mtime *time.Time) error {
+	log.Tracef("s3 utimens: name[%s] mtime[%v]", name, mtime)
+	if mtime == nil {
+		return nil
+	}
+	return fs.updateMeta(name, func(meta map[string]*string) error {
+		meta[S3MetaMtime] = aws.String(strconv.FormatInt(mtime.UnixNano(), 10))
+		return nil
+	})
+}
+
+// Symlinks.
+func (fs *s3FileSystem) Symlink(value string, linkName string) error {
+	log.Tracef("s3 symlink: value[%s] linkName[%s]", value, linkName)
+	linkName = toS3Path(linkName)
+	exist, err := fs.exists(linkName)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return syscall.EEXIST
+	}
+	meta := map[string]*string{
+		S3MetaSymlink: aws.String(url.PathEscape(value)),
+	}
+	return fs.putEmptyFileWithMeta(fs.getFullPath(linkName), meta)
+}
+
+func (fs *s3FileSystem) Readlink(name string) (string, error) {
+	log.Tracef("s3 readlink: name[%s]", name)
+	response, err := fs.headObject(fs.getFullPath(toS3Path(name)))
+	if err != nil {
+		if isNotExistErr(err) {
+			return "", syscall.ENOENT
+		}
+		return "", err
+	}
+	target, ok := normalizeS3Meta(response.Metadata)[S3MetaSymlink]
+	if !ok {
+		return "", syscall.EINVAL
+	}
+	return url.PathUnescape(*target)
+}
+
+// Extended attributes.
+func parseXAttrs(meta map[string]*string) (map[string][]byte, error) {
+	xattrs := map[string][]byte{}
+	value, ok := meta[S3MetaXAttrs]
+	if !ok {
+		return xattrs, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(*value)
+	if err != nil {
+		return nil, fmt.Errorf("decode xattrs of s3 object failed: %v", err)
+	}
+	if err := json.Unmarshal(data, &xattrs); err != nil {
+		return nil, fmt.Errorf("unmarshal xattrs of s3 object failed: %v", err)
+	}
+	return xattrs, nil
+}
+
+func setXAttrs(meta map[string]*string, xattrs map[string][]byte) error {
+	if len(xattrs) == 0 {
+		delete(meta, S3MetaXAttrs)
+		return nil
+	}
+	data, err := json.Marshal(xattrs)
+	if err != nil {
+		return err
+	}
+	meta[S3MetaXAttrs] = aws.String(base64.StdEncoding.EncodeToString(data))
+	return nil
+}
+
+func (fs *s3FileSystem) getXAttrs(name string) (map[string][]byte, error) {
+	if fs.getFullPath(toS3Path(name)) == "" {
+		return map[string][]byte{}, nil
+	}
+	_, response, err := fs.headMetaObject(name)
+	if err != nil {
+		if isNotExistErr(err) {
+			// directory without object has no xattrs
+			if _, err := fs.isDirExist(toS3Path(name)); err != nil {
+				return nil, err
+			}
+			return map[string][]byte{}, nil
+		}
+		return nil, err
+	}
+	return parseXAttrs(normalizeS3Meta(response.Metadata))
+}
+
+func (fs *s3FileSystem) GetXAttr(name string, attribute string) (data []byte, err error) {
+	xattrs, err := fs.getXAttrs(name)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := xattrs[attribute]
+	if !ok {
+		return nil, syscall.ENODATA
+	}
+	return value, nil
+}
+
+func (fs *s3FileSystem) ListXAttr(name string) (attributes []string, err error) {
+	xattrs, err := fs.getXAttrs(name)
+	if err != nil {
+		return nil, err
+	}
+	attributes = make([]string, 0, len(xattrs))
+	for attr := range xattrs {
+		attributes = append(attributes, attr)
+	}
+	sort.Strings(attributes)
+	return attributes, nil
+}
+
+func (fs *s3FileSystem) RemoveXAttr(name string, attr string) error {
+	return fs.updateMeta(name, func(meta map[string]*string) error {
+		xattrs, err := parseXAttrs(meta)
+		if err != nil {
+			return err
+		}
+		if _, ok := xattrs[attr]; !ok {
+			return syscall.ENODATA
+		}
+		delete(xattrs, attr)
+		return setXAttrs(meta, xattrs)
+	})
+}
+
+func (fs *s3FileSystem) SetXAttr(name string, attr string, data []byte, flags int) error {
+	return fs.updateMeta(name, func(meta map[string]*string) error {
+		xattrs, err := parseXAttrs(meta)
+		if err != nil {
+			return err
+		}
+		_, exist := xattrs[attr]
+		if flags&xattrCreate != 0 && exist {
+			return syscall.EEXIST
+		}
+		if flags&xattrReplace != 0 && !exist {
+			return syscall.ENODATA
+		}
+		xattrs[attr] = data
+		return setXAttrs(meta, xattrs)
+	})
+}
@@ -0,0 +1,256 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ufs
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeS3TestFile(t *testing.T, fs UnderFileStorage, name string, data []byte) {
+	fh, err := fs.Create(name, uint32(flags), mode)
+	assert.Nil(t, err)
+	_, err = fh.Write(data, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, fh.Flush())
+	fh.Release()
+}
+
+func findDirEntry(entries []DirEntry, name string) *DirEntry {
+	for i := range entries {
+		if entries[i].Name == name {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+func TestS3PosixAttr(t *testing.T) {
+	defer func() {
+		os.RemoveAll("./tmp")
+	}()
+	fs, err := NewS3FSForTest()
+	assert.Nil(t, err)
+
+	assert.Nil(t, fs.Mkdir("meta", 0755))
+	file := "meta/foo"
+	writeS3TestFile(t, fs, file, []byte("hello world"))
+
+	// create keeps mode in object metadata
+	finfo, err := fs.GetAttr(file)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(mode), finfo.Mode.Perm())
+	assert.Equal(t, Owner, finfo.Owner)
+
+	// object without metadata uses default attr
+	s3fs := fs.(*s3FileSystem)
+	assert.Nil(t, s3fs.putEmptyFile(s3fs.getFullPath("meta/nometa")))
+	finfo, err = fs.GetAttr("meta/nometa")
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(DefaultFileMode), finfo.Mode.Perm())
+	assert.Equal(t, Owner, finfo.Owner)
+
+	// create with owner keeps uid and gid as well
+	fh, err := s3fs.CreateWithOwner("meta/owned", uint32(flags), 0640, 1001, 1002)
+	assert.Nil(t, err)
+	assert.Nil(t, fh.Flush())
+	fh.Release()
+	finfo, err = fs.GetAttr("meta/owned")
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0640), finfo.Mode.Perm())
+	assert.Equal(t, uint32(1001), finfo.Sys.(syscall.Stat_t).Uid)
+	assert.Equal(t, uint32(1002), finfo.Sys.(syscall.Stat_t).Gid)
+
+	// chmod, chown and utimens are kept in object metadata
+	mtime := time.Unix(1600000000, 123)
+	assert.Nil(t, fs.Chmod(file, syscall.S_IFREG|0600))
+	assert.Nil(t, fs.Chown(file, 1001, 1002))
+	assert.Nil(t, fs.Utimens(file, &mtime, &mtime))
+
+	finfo, err = fs.GetAttr(file)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), finfo.Mode.Perm())
+	assert.Equal(t, int64(11), finfo.Size)
+	assert.Equal(t, uint64(mtime.Unix()), finfo.Mtime)
+	st := finfo.Sys.(syscall.Stat_t)
+	assert.Equal(t, uint32(1001), st.Uid)
+	assert.Equal(t, uint32(1002), st.Gid)
+
+	// utimens without mtime keeps it
+	atime := time.Unix(1700000000, 0)
+	assert.Nil(t, fs.Utimens(file, &atime, nil))
+	finfo, err = fs.GetAttr(file)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(mtime.Unix()), finfo.Mtime)
+
+	// readDir does not head objects, attributes in metadata are got by getAttr
+	entries, err := fs.ReadDir("meta")
+	assert.Nil(t, err)
+	entry := findDirEntry(entries, "foo")
+	assert.NotNil(t, entry)
+	assert.True(t, entry.AttrIncomplete)
+	assert.Equal(t, uint32(syscall.S_IFREG|DefaultFileMode), entry.Attr.Mode)
+	assert.Equal(t, uint64(11), entry.Attr.Size)
+
+	// rewriting the file keeps mode and owner
+	fh, err = fs.Open(file, uint32(os.O_WRONLY), uint64(finfo.Size))
+	assert.Nil(t, err)
+	_, err = fh.Write([]byte("hello"), 0)
+	assert.Nil(t, err)
+	assert.Nil(t, fh.Flush())
+	fh.Release()
+	finfo, err = fs.GetAttr(file)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), finfo.Mode.Perm())
+	assert.Equal(t, uint32(1001), finfo.Sys.(syscall.Stat_t).Uid)
+
+	// chmod and utimens while the file is open are kept by the upload, e.g. cp -p
+	fh, err = fs.Open(file, uint32(os.O_WRONLY), uint64(finfo.Size))
+	assert.Nil(t, err)
+	_, err = fh.Write([]byte("HELLO"), 0)
+	assert.Nil(t, err)
+	copyMtime := time.Unix(1500000000, 0)
+	assert.Nil(t, fs.Chmod(file, syscall.S_IFREG|0640))
+	assert.Nil(t, fs.Utimens(file, nil, &copyMtime))
+	assert.Nil(t, fh.Flush())
+	fh.Release()
+	finfo, err = fs.GetAttr(file)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0640), finfo.Mode.Perm())
+	assert.Equal(t, uint64(copyMtime.Unix()), finfo.Mtime)
+
+	// directory keeps attr in its dir object.
+	// the fake s3 server stores object "meta/" as "meta", thus dir attr is got by "meta/".
+	assert.Nil(t, fs.Chmod("meta", syscall.S_IFDIR|0700))
+	finfo, err = fs.GetAttr("meta/")
+	assert.Nil(t, err)
+	assert.True(t, finfo.IsDir)
+	assert.Equal(t, os.FileMode(0700), finfo.Mode.Perm())
+
+	// directory without object gets a new dir object
+	writeS3TestFile(t, fs, "implicit/bar", []byte("bar"))
+	assert.Nil(t, fs.Chown("implicit", 1001, 1002))
+	finfo, err = fs.GetAttr("implicit/")
+	assert.Nil(t, err)
+	assert.True(t, finfo.IsDir)
+	assert.Equal(t, uint32(1001), finfo.Sys.(syscall.Stat_t).Uid)
+
+	err = fs.Chmod("meta/not-exist", 0600)
+	assert.Equal(t, syscall.ENOENT, err)
+
+	// failing to copy the object fails updating attributes
+	var c *s3.S3
+	p := gomonkey.ApplyMethod(reflect.TypeOf(c), "CopyObject",
+		func(_ *s3.S3, input *s3.CopyObjectInput) (*s3.CopyObjectOutput, error) {
+			return nil, fmt.Errorf("copy err")
+		})
+	defer p.Reset()
+	assert.NotNil(t, fs.Chmod(file, syscall.S_IFREG|0644))
+	assert.NotNil(t, fs.Utimens(file, &mtime, &mtime))
+	assert.NotNil(t, fs.SetXAttr(file, "user.foo", []byte("bar"), 0))
+	finfo, err = fs.GetAttr(file)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0640), finfo.Mode.Perm())
+}
+
+func TestS3Symlink(t *testing.T) {
+	defer func() {
+		os.RemoveAll("./tmp")
+	}()
+	fs, err := NewS3FSForTest()
+	assert.Nil(t, err)
+
+	assert.Nil(t, fs.Mkdir("link", 0755))
+	writeS3TestFile(t, fs, "link/target", []byte("data"))
+
+	target := "../link/target with space"
+	assert.Nil(t, fs.Symlink(target, "link/foo"))
+	assert.Equal(t, syscall.EEXIST, fs.Symlink(target, "link/foo"))
+
+	value, err := fs.Readlink("link/foo")
+	assert.Nil(t, err)
+	assert.Equal(t, target, value)
+
+	finfo, err := fs.GetAttr("link/foo")
+	assert.Nil(t, err)
+	assert.Equal(t, os.ModeSymlink, finfo.Mode&os.ModeSymlink)
+	assert.Equal(t, int64(len(target)), finfo.Size)
+
+	entries, err := fs.ReadDir("link")
+	assert.Nil(t, err)
+	entry := findDirEntry(entries, "foo")
+	assert.NotNil(t, entry)
+	assert.True(t, entry.AttrIncomplete)
+
+	_, err = fs.Readlink("link/target")
+	assert.Equal(t, syscall.EINVAL, err)
+	_, err = fs.Readlink("link/not-exist")
+	assert.Equal(t, syscall.ENOENT, err)
+}
+
+func TestS3XAttr(t *testing.T) {
+	defer func() {
+		os.RemoveAll("./tmp")
+	}()
+	fs, err := NewS3FSForTest()
+	assert.Nil(t, err)
+
+	file := "xattr"
+	writeS3TestFile(t, fs, file, []byte("data"))
+
+	attrs, err := fs.ListXAttr(file)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(attrs))
+	_, err = fs.GetXAttr(file, "user.a")
+	assert.Equal(t, syscall.ENODATA, err)
+
+	assert.Nil(t, fs.SetXAttr(file, "user.b", []byte("2"), 0))
+	assert.Nil(t, fs.SetXAttr(file, "user.a", []byte{0, 1}, xattrCreate))
+	assert.Equal(t, syscall.EEXIST, fs.SetXAttr(file, "user.a", []byte("1"), xattrCreate))
+	assert.Equal(t, syscall.ENODATA, fs.SetXAttr(file, "user.c", []byte("3"), xattrReplace))
+	assert.Nil(t, fs.SetXAttr(file, "user.b", []byte("22"), xattrReplace))
+
+	value, err := fs.GetXAttr(file, "user.a")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0, 1}, value)
+	value, err = fs.GetXAttr(file, "user.b")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("22"), value)
+
+	attrs, err = fs.ListXAttr(file)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"user.a", "user.b"}, attrs)
+
+	assert.Nil(t, fs.RemoveXAttr(file, "user.a"))
+	assert.Equal(t, syscall.ENODATA, fs.RemoveXAttr(file, "user.a"))
+	attrs, err = fs.ListXAttr(file)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"user.b"}, attrs)
+
+	// content is not changed by updating metadata
+	finfo, err := fs.GetAttr(file)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), finfo.Size)
+}
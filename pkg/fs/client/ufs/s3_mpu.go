@@ -0,0 +1,273 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ufs
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	fsCommon "github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
+)
+
+const (
+	MiB = 1024 * 1024
+
+	DefaultMPUPartSize    = 8 * MiB // size of the first parts, it grows for large files
+	DefaultMPUConcurrency = 16      // parts uploaded concurrently per file system
+
+	// part size doubles every mpuPartSizeGrowStep parts, so that a file of unknown size
+	// can be streamed within MPUMaxPartNum parts: 8 MiB parts reach 5 TiB in 10,000 parts
+	mpuPartSizeGrowStep = 1000
+)
+
+// mpuInfo keeps state of multipart upload of a file handle.
+// parts are uploaded while the application is still writing as long as it writes sequentially.
+type mpuInfo struct {
+	uploadID      *string
+	lastPartNum   int64              // number of parts submitted
+	lastUploadEnd int64              // end offset of parts submitted
+	seqWriteEnd   int64              // end offset of data written sequentially from the beginning of file
+	broken        bool               // submitted parts are overwritten, the whole file needs to be uploaded again
+	meta          map[string]*string // metadata the multipart upload is created with
+
+	mu        sync.Mutex
+	partsETag []*string
+	group     *errgroup.Group
+}
+
+func (mpu *mpuInfo) reset() {
+	mpu.uploadID = nil
+	mpu.lastPartNum = 0
+	mpu.lastUploadEnd = 0
+	mpu.seqWriteEnd = 0
+	mpu.broken = false
+	mpu.meta = nil
+	mpu.mu.Lock()
+	mpu.partsETag = nil
+	mpu.mu.Unlock()
+	mpu.group = nil
+}
+
+func (mpu *mpuInfo) addPart() {
+	mpu.mu.Lock()
+	defer mpu.mu.Unlock()
+	mpu.partsETag = append(mpu.partsETag, nil)
+}
+
+func (mpu *mpuInfo) setPartETag(partNum int64, eTag *string) {
+	mpu.mu.Lock()
+	defer mpu.mu.Unlock()
+	mpu.partsETag[partNum-1] = eTag
+}
+
+// wait waits for parts in flight and returns the first error of them
+func (mpu *mpuInfo) wait() error {
+	if mpu.group == nil {
+		return nil
+	}
+	return mpu.group.Wait()
+}
+
+// BufferPool provides part buffers of multipart upload. cache.BufferPool implements it,
+// so that part buffers count against the same memory budget as read buffers.
+type BufferPool interface {
+	RequestMBuf(size uint64, block bool, blockSize int) []byte
+	FreeMBuf(buf []byte)
+}
+
+// heapBufferPool allocates part buffers directly, it is used until SetBufferPool is called
+type heapBufferPool struct{}
+
+func (heapBufferPool) RequestMBuf(size uint64, block bool, blockSize int) []byte {
+	return make([]byte, 0, size)
+}
+
+func (heapBufferPool) FreeMBuf(buf []byte) {}
+
+var partBufferPool BufferPool = heapBufferPool{}
+
+// SetBufferPool sets the pool part buffers of multipart upload are requested from.
+// it should be called before any file is written.
+func SetBufferPool(pool BufferPool) {
+	partBufferPool = pool
+}
+
+// mpuConfig parses part size and concurrency of multipart upload from fs properties.
+// part size is in MiB.
+func mpuConfig(properties map[string]interface{}) (partSize int64, concurrency int, err error) {
+	partSize, concurrency = DefaultMPUPartSize, DefaultMPUConcurrency
+	if v, ok := properties[fsCommon.S3PartSize].(string); ok && v != "" {
+		size, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || size*MiB < MPUMinPartSize || size*MiB > MPUMaxPartSize {
+			return 0, 0, fmt.Errorf("%s[%s] should be an integer between %d and %d",
+				fsCommon.S3PartSize, v, MPUMinPartSize/MiB, MPUMaxPartSize/MiB)
+		}
+		partSize = size * MiB
+	}
+	if v, ok := properties[fsCommon.S3UploadConcurrency].(string); ok && v != "" {
+		concurrency, err = strconv.Atoi(v)
+		if err != nil || concurrency <= 0 {
+			return 0, 0, fmt.Errorf("%s[%s] should be a positive integer", fsCommon.S3UploadConcurrency, v)
+		}
+	}
+	return partSize, concurrency, nil
+}
+
+// partSize returns size of part partNum, which doubles every mpuPartSizeGrowStep parts
+func (fs *s3FileSystem) partSize(partNum int64) int64 {
+	size := fs.mpuPartSize << uint((partNum-1)/mpuPartSizeGrowStep)
+	if size > MPUMaxPartSize {
+		size = MPUMaxPartSize
+	}
+	return size
+}
+
+// streamUpload is called after data [off, off+n) is written to writeTmpfile.
+// once sequentially written data exceeds mpuThreshold, complete parts are uploaded in background,
+// thus the application writing is throttled by memory limit of part buffers.
+func (fh *s3FileHandle) streamUpload(off int64, n int) {
+	mpu := &fh.mpuInfo
+	if mpu.broken {
+		return
+	}
+	if off < mpu.lastUploadEnd {
+		log.Debugf("s3 stream upload: fh.name[%s] overwrites uploaded parts at off[%d], upload whole file on flush",
+			fh.name, off)
+		mpu.broken = true
+		return
+	}
+	if end := off + int64(n); off <= mpu.seqWriteEnd && end > mpu.seqWriteEnd {
+		mpu.seqWriteEnd = end
+	}
+	if mpu.uploadID == nil {
+		if mpu.seqWriteEnd <= fh.fs.mpuThreshold {
+			return
+		}
+		if err := fh.multipartCreate(); err != nil {
+			mpu.broken = true
+			return
+		}
+	}
+	for {
+		partSize := fh.fs.partSize(mpu.lastPartNum + 1)
+		if mpu.seqWriteEnd-mpu.lastUploadEnd < partSize {
+			return
+		}
+		if err := fh.submitPart(partSize); err != nil {
+			mpu.broken = true
+			return
+		}
+	}
+}
+
+// submitPart reads the next part from writeTmpfile and uploads it in background
+func (fh *s3FileHandle) submitPart(size int64) error {
+	mpu := &fh.mpuInfo
+	partNum := mpu.lastPartNum + 1
+	if partNum > MPUMaxPartNum {
+		err := fmt.Errorf("part number exceeds %d", MPUMaxPartNum)
+		log.Errorf("s3 mpu submit: fh.name[%s] err: %v", fh.name, err)
+		return err
+	}
+
+	// wait for an upload slot before requesting the buffer, so that at most concurrency part buffers
+	// are held per file system and the application writing is throttled by uploading
+	fh.fs.uploadLimit <- struct{}{}
+	// the last part may be smaller, use a buffer of part size to recycle it
+	partSize := fh.fs.partSize(partNum)
+	buf := partBufferPool.RequestMBuf(uint64(partSize), true, int(partSize))[:size]
+	n, err := fh.writeTmpfile.ReadAt(buf, mpu.lastUploadEnd)
+	if err != nil && !(err == io.EOF && int64(n) == size) {
+		partBufferPool.FreeMBuf(buf)
+		<-fh.fs.uploadLimit
+		log.Errorf("s3 mpu submit: fh.name[%s] read part[%d] off[%d] err: %v", fh.name, partNum, mpu.lastUploadEnd, err)
+		return err
+	}
+
+	if mpu.group == nil {
+		mpu.group = new(errgroup.Group)
+	}
+	mpu.addPart()
+	mpu.lastPartNum = partNum
+	mpu.lastUploadEnd += size
+	mpu.group.Go(func() error {
+		defer func() { <-fh.fs.uploadLimit }()
+		defer partBufferPool.FreeMBuf(buf)
+		if err := fh.multipartUpload(partNum, buf); err != nil {
+			log.Errorf("s3 mpu submit: fh.name[%s], multipartUpload[%d] err: %v", fh.name, partNum, err)
+			return err
+		}
+		return nil
+	})
+	return nil
+}
+
+// uploadPartsTillEnd uploads parts left till fileSize and commits the multipart upload
+func (fh *s3FileHandle) uploadPartsTillEnd(fileSize int64) error {
+	mpu := &fh.mpuInfo
+	for mpu.lastUploadEnd < fileSize {
+		size := fh.fs.partSize(mpu.lastPartNum + 1)
+		if left := fileSize - mpu.lastUploadEnd; left < size {
+			size = left
+		}
+		if err := fh.submitPart(size); err != nil {
+			return err
+		}
+	}
+	if err := mpu.wait(); err != nil {
+		log.Errorf("s3 mpu: fh.name[%s] upload parts err: %v", fh.name, err)
+		return err
+	}
+	// attributes may be changed after the multipart upload is created
+	meta, err := fh.uploadMeta()
+	if err != nil {
+		return err
+	}
+	if err := fh.multipartCommit(); err != nil {
+		return err
+	}
+	created := mpu.meta
+	mpu.reset()
+	if reflect.DeepEqual(meta, created) {
+		return nil
+	}
+	return fh.fs.updateMeta(fh.name, func(m map[string]*string) error {
+		for k := range m {
+			delete(m, k)
+		}
+		for k, v := range meta {
+			m[k] = v
+		}
+		return nil
+	})
+}
+
+// abortMPU aborts the incomplete multipart upload after parts in flight finish
+func (fh *s3FileHandle) abortMPU() {
+	mpu := &fh.mpuInfo
+	if mpu.uploadID != nil {
+		_ = mpu.wait()
+		_ = fh.multipartAbort()
+	}
+	mpu.reset()
+}
@@ -0,0 +1,201 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ufs
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+
+	fsCommon "github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
+)
+
+// countingBufferPool records part buffers in use
+type countingBufferPool struct {
+	mu        sync.Mutex
+	inUse     int64
+	requested int
+}
+
+func (pool *countingBufferPool) RequestMBuf(size uint64, block bool, blockSize int) []byte {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.inUse += int64(size)
+	pool.requested++
+	return make([]byte, 0, size)
+}
+
+func (pool *countingBufferPool) FreeMBuf(buf []byte) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.inUse -= int64(cap(buf))
+}
+
+func useCountingBufferPool(t *testing.T) *countingBufferPool {
+	pool := &countingBufferPool{}
+	SetBufferPool(pool)
+	t.Cleanup(func() {
+		SetBufferPool(heapBufferPool{})
+	})
+	return pool
+}
+
+func TestMPUConfig(t *testing.T) {
+	partSize, concurrency, err := mpuConfig(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(DefaultMPUPartSize), partSize)
+	assert.Equal(t, DefaultMPUConcurrency, concurrency)
+
+	partSize, concurrency, err = mpuConfig(map[string]interface{}{
+		fsCommon.S3PartSize:          "16",
+		fsCommon.S3UploadConcurrency: "4",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(16*MiB), partSize)
+	assert.Equal(t, 4, concurrency)
+
+	_, _, err = mpuConfig(map[string]interface{}{fsCommon.S3PartSize: "1"})
+	assert.NotNil(t, err)
+	_, _, err = mpuConfig(map[string]interface{}{fsCommon.S3UploadConcurrency: "0"})
+	assert.NotNil(t, err)
+	_, _, err = mpuConfig(map[string]interface{}{fsCommon.S3UploadConcurrency: "x"})
+	assert.NotNil(t, err)
+}
+
+func TestS3PartSize(t *testing.T) {
+	fs := &s3FileSystem{mpuPartSize: DefaultMPUPartSize}
+	assert.Equal(t, int64(8*MiB), fs.partSize(1))
+	assert.Equal(t, int64(8*MiB), fs.partSize(mpuPartSizeGrowStep))
+	assert.Equal(t, int64(16*MiB), fs.partSize(mpuPartSizeGrowStep+1))
+	assert.Equal(t, int64(MPUMaxPartSize), fs.partSize(MPUMaxPartNum+1))
+
+	// parts can hold the largest s3 object
+	var total int64
+	for i := int64(1); i <= MPUMaxPartNum; i++ {
+		total += fs.partSize(i)
+	}
+	assert.True(t, total >= MaxFileSize)
+}
+
+func newS3MPUFSForTest(t *testing.T) *s3FileSystem {
+	ufs, err := NewS3FSForTest()
+	assert.Nil(t, err)
+	fs := ufs.(*s3FileSystem)
+	fs.mpuThreshold = 2 * MPUMinPartSize
+	fs.mpuPartSize = MPUMinPartSize
+	return fs
+}
+
+func writeInChunks(t *testing.T, fh FileHandle, data []byte, off int) {
+	for chunk := MiB; off < len(data); off += chunk {
+		end := off + chunk
+		if end > len(data) {
+			end = len(data)
+		}
+		_, err := fh.Write(data[off:end], uint64(off))
+		assert.Nil(t, err)
+	}
+}
+
+func readS3TestFile(t *testing.T, fs *s3FileSystem, name string) []byte {
+	reader, err := fs.Get(name, uint32(os.O_RDONLY), 0, 0)
+	assert.Nil(t, err)
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	assert.Nil(t, err)
+	return data
+}
+
+func TestS3StreamUpload(t *testing.T) {
+	defer func() {
+		os.RemoveAll("./tmp")
+	}()
+	fs := newS3MPUFSForTest(t)
+	pool := useCountingBufferPool(t)
+	data := bytes.Repeat([]byte("0123456789abcdef"), 25*MiB/16+100)
+
+	fh, err := fs.Create("stream", uint32(flags), mode)
+	assert.Nil(t, err)
+	s3fh := fh.(*s3FileHandle)
+	writeInChunks(t, fh, data, 0)
+
+	// parts are uploaded before flush
+	assert.NotNil(t, s3fh.mpuInfo.uploadID)
+	assert.False(t, s3fh.mpuInfo.broken)
+	assert.True(t, s3fh.mpuInfo.lastPartNum >= 3)
+	assert.Nil(t, fh.Flush())
+	assert.Nil(t, s3fh.mpuInfo.uploadID)
+	fh.Release()
+
+	finfo, err := fs.GetAttr("stream")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(data)), finfo.Size)
+	assert.Equal(t, data, readS3TestFile(t, fs, "stream"))
+	// part buffers are requested from the shared pool and all freed
+	assert.True(t, pool.requested >= 3)
+	assert.Equal(t, int64(0), pool.inUse)
+}
+
+func TestS3StreamUploadOverwrite(t *testing.T) {
+	defer func() {
+		os.RemoveAll("./tmp")
+	}()
+	fs := newS3MPUFSForTest(t)
+	data := bytes.Repeat([]byte("0123456789abcdef"), 20*MiB/16)
+
+	fh, err := fs.Create("overwrite", uint32(flags), mode)
+	assert.Nil(t, err)
+	s3fh := fh.(*s3FileHandle)
+	writeInChunks(t, fh, data, 0)
+	assert.NotNil(t, s3fh.mpuInfo.uploadID)
+
+	// overwriting uploaded parts falls back to uploading the whole file
+	copy(data, "overwritten")
+	_, err = fh.Write([]byte("overwritten"), 0)
+	assert.Nil(t, err)
+	assert.True(t, s3fh.mpuInfo.broken)
+	assert.Nil(t, fh.Flush())
+	fh.Release()
+
+	assert.Equal(t, data, readS3TestFile(t, fs, "overwrite"))
+}
+
+func TestS3StreamUploadAbort(t *testing.T) {
+	defer func() {
+		os.RemoveAll("./tmp")
+	}()
+	fs := newS3MPUFSForTest(t)
+	pool := useCountingBufferPool(t)
+	data := bytes.Repeat([]byte("0123456789abcdef"), 12*MiB/16)
+
+	fh, err := fs.Create("abort", uint32(flags), mode)
+	assert.Nil(t, err)
+	s3fh := fh.(*s3FileHandle)
+	// parts of a non-existing upload fail
+	s3fh.mpuInfo.uploadID = aws.String("not-exist")
+	writeInChunks(t, fh, data, 0)
+
+	assert.NotNil(t, fh.Flush())
+	assert.Nil(t, s3fh.mpuInfo.uploadID)
+	assert.Equal(t, int64(0), s3fh.mpuInfo.lastPartNum)
+	assert.Equal(t, int64(0), pool.inUse)
+}
@@ -230,7 +230,6 @@ func Test_s3FileSystem_getFullPath(t *testing.T) {
 		s3          *s3.S3
 		defaultTime time.Time
 		Mutex       sync.Mutex
-		chunkPool   *sync.Pool
 	}
 	type args struct {
 		name string
@@ -273,7 +272,6 @@ func Test_s3FileSystem_getFullPath(t *testing.T) {
 				s3:          tt.fields.s3,
 				defaultTime: tt.fields.defaultTime,
 				Mutex:       tt.fields.Mutex,
-				chunkPool:   tt.fields.chunkPool,
 			}
 			assert.Equalf(t, tt.want, fs.getFullPath(tt.args.name), "getFullPath(%v)", tt.args.name)
 		})
@@ -290,7 +288,6 @@ func Test_s3FileSystem_list(t *testing.T) {
 		s3          *s3.S3
 		defaultTime time.Time
 		Mutex       sync.Mutex
-		chunkPool   *sync.Pool
 	}
 	type args struct {
 		name              string
@@ -337,7 +334,6 @@ func Test_s3FileSystem_list(t *testing.T) {
 				s3:          tt.fields.s3,
 				defaultTime: tt.fields.defaultTime,
 				Mutex:       tt.fields.Mutex,
-				chunkPool:   tt.fields.chunkPool,
 			}
 			got, got1, err := fs.list(tt.args.name, tt.args.continuationToken, tt.args.limit, tt.args.recursive)
 			if !tt.wantErr(t, err, fmt.Sprintf("list(%v, %v, %v, %v)", tt.args.name, tt.args.continuationToken, tt.args.limit, tt.args.recursive)) {
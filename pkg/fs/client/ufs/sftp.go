@@ -176,7 +176,21 @@ func (fs *sftpFileSystem) Chown(name string, uid uint32, gid uint32) error {
 }
 
 func (fs *sftpFileSystem) Utimens(name string, atime *time.Time, mtime *time.Time) error {
-	return fs.sc.sftpClient.Chtimes(fs.GetPath(name), *atime, *mtime)
+	path := fs.GetPath(name)
+	a, m, err := completeTimes(atime, mtime, func() (time.Time, time.Time, error) {
+		info, err := fs.sc.sftpClient.Stat(path)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if st, ok := info.Sys().(*sftp.FileStat); ok {
+			return time.Unix(int64(st.Atime), 0), info.ModTime(), nil
+		}
+		return info.ModTime(), info.ModTime(), nil
+	})
+	if err != nil {
+		return err
+	}
+	return fs.sc.sftpClient.Chtimes(path, a, m)
 }
 
 func (fs *sftpFileSystem) Truncate(name string, size uint64) error {
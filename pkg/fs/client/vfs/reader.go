@@ -18,6 +18,7 @@ package vfs
 
 import (
 	"io"
+	"os"
 	"sync"
 	"syscall"
 
@@ -39,7 +40,7 @@ type DataReader interface {
 	Open(inode Ino, length uint64, ufs ufslib.UnderFileStorage, path string) (FileReader, error)
 }
 
-func NewDataReader(m meta.Meta, blockSize int, store cache.Store) DataReader {
+func NewDataReader(m meta.Meta, blockSize int, store cache.Store, wb *writeBack) DataReader {
 	bufferPool := cache.BufferPool{}
 	r := &dataReader{
 		m:          m,
@@ -47,7 +48,10 @@ func NewDataReader(m meta.Meta, blockSize int, store cache.Store) DataReader {
 		store:      store,
 		blockSize:  blockSize,
 		bufferPool: bufferPool.Init(blockSize),
+		writeBack:  wb,
 	}
+	// s3 multipart upload requests part buffers from the same pool as read buffers
+	ufslib.SetBufferPool(r.bufferPool)
 	return r
 }
 
@@ -66,6 +70,8 @@ type fileReader struct {
 	streamReader  io.ReadCloser
 	seqReadAmount uint64
 	readBufOffset uint64
+	// write-back 模式下尚未上传完成的暂存文件
+	staged *os.File
 }
 
 type dataReader struct {
@@ -75,6 +81,7 @@ type dataReader struct {
 	store      cache.Store
 	bufferPool *cache.BufferPool
 	blockSize  int
+	writeBack  *writeBack
 }
 
 func (fh *fileReader) Read(buf []byte, off uint64) (int, syscall.Errno) {
@@ -88,7 +95,13 @@ func (fh *fileReader) Read(buf []byte, off uint64) (int, syscall.Errno) {
 	var err error
 	var nread int
 	bufSize := len(buf)
-	if fh.reader.store != nil {
+	if fh.staged != nil {
+		bytesRead, err = fh.staged.ReadAt(buf, int64(off))
+		if err != nil && err != io.EOF {
+			log.Errorf("read staged file err: %v", err)
+			return 0, syscall.EBADF
+		}
+	} else if fh.reader.store != nil {
 		reader := fh.reader.store.NewReader(fh.path, int(fh.length),
 			fh.flags, fh.ufs, fh.buffersCache, fh.reader.bufferPool, fh.seqReadAmount)
 		for bytesRead < bufSize {
@@ -187,6 +200,10 @@ func (fh *fileReader) release() {
 		_ = fh.streamReader.Close()
 		fh.streamReader = nil
 	}
+	if fh.staged != nil {
+		_ = fh.staged.Close()
+		fh.staged = nil
+	}
 }
 
 func (d *dataReader) Open(inode Ino, length uint64, ufs ufslib.UnderFileStorage, path string) (FileReader, error) {
@@ -198,7 +215,10 @@ func (d *dataReader) Open(inode Ino, length uint64, ufs ufslib.UnderFileStorage,
 		ufs:          ufs,
 		buffersCache: make(cache.ReadBufferMap),
 	}
-	if d.store == nil {
+	if d.writeBack != nil {
+		f.staged = d.writeBack.openStaged(inode)
+	}
+	if d.store == nil && f.staged == nil {
 		fd, err := ufs.Open(path, syscall.O_RDONLY, length)
 		if err != nil {
 			return nil, err
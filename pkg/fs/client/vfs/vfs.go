@@ -18,6 +18,7 @@ package vfs
 
 import (
 	"os"
+	"path"
 	"sync"
 	"syscall"
 	"time"
@@ -51,12 +52,14 @@ type VFS struct {
 	Meta       meta.Meta
 	Store      cache.Store
 	registry   *prometheus.Registry
+	writeBack  *writeBack
 }
 
 type Config struct {
-	Cache *cache.Config
-	owner *Owner
-	Meta  *meta.Config
+	Cache     *cache.Config
+	owner     *Owner
+	Meta      *meta.Config
+	WriteBack *WriteBackConfig
 }
 
 type Owner struct {
@@ -107,6 +110,12 @@ func WithDataCacheConfig(data cache.Config) Option {
 	}
 }
 
+func WithWriteBackConfig(wb WriteBackConfig) Option {
+	return func(config *Config) {
+		config.WriteBack = &wb
+	}
+}
+
 func InitVFS(fsMeta common.FSMeta, links map[string]common.FSMeta, global bool,
 	config *Config, registry *prometheus.Registry) (*VFS, error) {
 	log.Infof("InitVFS fsMeta %+v config %+v", fsMeta, config)
@@ -136,8 +145,15 @@ func InitVFS(fsMeta common.FSMeta, links map[string]common.FSMeta, global bool,
 		blockSize = config.Cache.BlockSize
 	}
 	vfs.Store = store
-	vfs.reader = NewDataReader(vfs.Meta, blockSize, store)
-	vfs.writer = NewDataWriter(vfs.Meta, blockSize, store)
+	if config.WriteBack != nil {
+		vfs.writeBack, err = newWriteBack(vfs.Meta, store, fsMeta.ID, *config.WriteBack)
+		if err != nil {
+			log.Errorf("new write back failed: %v", err)
+			return nil, err
+		}
+	}
+	vfs.reader = NewDataReader(vfs.Meta, blockSize, store, vfs.writeBack)
+	vfs.writer = NewDataWriter(vfs.Meta, blockSize, store, vfs.writeBack)
 	vfs.handleMap = make(map[Ino][]*handle)
 	vfs.nextfh = 1
 
@@ -190,6 +206,7 @@ func (v *VFS) Lookup(ctx *meta.Context, parent Ino, name string) (entry *meta.En
 	if utils.IsError(err) {
 		return nil, err
 	}
+	v.fixStagedAttr(inode, attr)
 	log.Debugf("vfs lookup inode[%x] from meta: attr[%+v] ", inode, *attr)
 	entry = &meta.Entry{Ino: inode, Attr: attr}
 	return entry, err
@@ -208,6 +225,7 @@ func (v *VFS) GetAttr(ctx *meta.Context, ino Ino) (entry *meta.Entry, err syscal
 	if utils.IsError(err) {
 		return nil, err
 	}
+	v.fixStagedAttr(ino, attr)
 	log.Debugf("vfs getattr: %+v", *attr)
 	entry = &meta.Entry{Ino: ino, Attr: attr}
 	return entry, err
@@ -216,6 +234,10 @@ func (v *VFS) GetAttr(ctx *meta.Context, ino Ino) (entry *meta.Entry, err syscal
 func (v *VFS) SetAttr(ctx *meta.Context, ino Ino, set, mode, uid, gid uint32, atime, mtime int64, atimensec, mtimensec uint32, size uint64) (entry *meta.Entry, err syscall.Errno) {
 	log.Tracef("vfs setAttr: ino[%d], set[%d], mode[%d], uid[%d], gid[%d], size[%d]", ino, set, mode, uid, gid, size)
 
+	// 等待尚未上传的暂存文件上传完成，避免之后的上传覆盖本次修改的属性
+	if err = v.drainWriteBack(ino, ""); utils.IsError(err) {
+		return entry, err
+	}
 	// only truncate opened files
 	if set&meta.FATTR_SIZE != 0 {
 		fhs := v.findAllHandle(ino)
@@ -298,11 +320,20 @@ func (v *VFS) Mkdir(ctx *meta.Context, parent Ino, name string, mode uint32, cum
 }
 
 func (v *VFS) Unlink(ctx *meta.Context, parent Ino, name string) (err syscall.Errno) {
+	if v.writeBack != nil {
+		// 删除的文件不再上传，包括仍被打开、之后 flush 的文件
+		if ino, _, errno := v.Meta.Lookup(ctx, parent, name); errno == syscall.F_OK {
+			v.writeBack.unlink(path.Join(v.Meta.InoToPath(parent), name), ino)
+		}
+	}
 	err = v.Meta.Unlink(ctx, parent, name)
 	return err
 }
 
 func (v *VFS) Rmdir(ctx *meta.Context, parent Ino, name string) (err syscall.Errno) {
+	if err = v.drainWriteBack(parent, name); utils.IsError(err) {
+		return err
+	}
 	err = v.Meta.Rmdir(ctx, parent, name)
 	return err
 }
@@ -318,6 +349,13 @@ func (v *VFS) Rmdir(ctx *meta.Context, parent Ino, name string) (err syscall.Err
 func (v *VFS) Rename(ctx *meta.Context, parent Ino, name string, newparent Ino, newname string, flags uint32) (err syscall.Errno) {
 	var ino Ino
 	attr := &Attr{}
+	// 上传中的文件需要先上传到原路径
+	if err = v.drainWriteBack(parent, name); utils.IsError(err) {
+		return err
+	}
+	if err = v.drainWriteBack(newparent, newname); utils.IsError(err) {
+		return err
+	}
 	src, dst, err := v.Meta.Rename(ctx, parent, name, newparent, newname, flags, &ino, attr)
 	if utils.IsError(err) {
 		return err
@@ -347,11 +385,16 @@ func (v *VFS) Link(ctx *meta.Context, ino Ino, newparent Ino, newname string) (e
 }
 
 func (v *VFS) Symlink(ctx *meta.Context, path string, parent Ino, name string) (entry *meta.Entry, err syscall.Errno) {
-	return nil, syscall.ENOSYS
+	var ino Ino
+	attr := &Attr{}
+	err = v.Meta.Symlink(ctx, parent, name, path, &ino, attr)
+	entry = &meta.Entry{Ino: ino, Attr: attr}
+	return
 }
 
 func (v *VFS) Readlink(ctx *meta.Context, ino Ino) (path []byte, err syscall.Errno) {
-	return nil, syscall.ENOSYS
+	err = v.Meta.ReadLink(ctx, ino, &path)
+	return
 }
 
 func (v *VFS) Access(ctx *meta.Context, ino Ino, mask uint32) (err syscall.Errno) {
@@ -468,6 +511,7 @@ func (v *VFS) Open(ctx *meta.Context, ino Ino, flags uint32) (entry *meta.Entry,
 	if utils.IsError(err) {
 		return
 	}
+	v.fixStagedAttr(ino, attr)
 	var errOpen error
 	fh, errOpen = v.newFileHandle(ino, attr.Size, flags, ufs, path)
 	if errOpen != nil {
@@ -692,3 +736,26 @@ func (v *VFS) Truncate(ctx *meta.Context, ino Ino, size, fh uint64) (err syscall
 	}
 	return v.Meta.Truncate(ctx, ino, size)
 }
+
+// fixStagedAttr 使用尚未上传完成的暂存文件大小
+func (v *VFS) fixStagedAttr(ino Ino, attr *Attr) {
+	if v.writeBack == nil {
+		return
+	}
+	if size, ok := v.writeBack.stagedSize(ino); ok {
+		attr.Size = size
+	}
+}
+
+// drainWriteBack 等待 parent 下 name 路径中的上传完成，name 为空时等待 parent 本身
+func (v *VFS) drainWriteBack(parent Ino, name string) syscall.Errno {
+	if v.writeBack == nil {
+		return syscall.F_OK
+	}
+	name = path.Join(v.Meta.InoToPath(parent), name)
+	if err := v.writeBack.drain(name); err != nil {
+		log.Errorf("write back: drain %s err: %v", name, err)
+		return syscall.EIO
+	}
+	return syscall.F_OK
+}
@@ -0,0 +1,557 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/cache"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/meta"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/utils"
+)
+
+const (
+	writeBackDirName = "writeback"
+	dataSuffix       = ".data"
+	journalSuffix    = ".json"
+
+	DefaultWriteBackTimeout     = 5 * time.Minute
+	DefaultWriteBackRetry       = 5
+	DefaultWriteBackConcurrency = 8
+
+	writeBackChunkSize  = 4 * 1024 * 1024
+	writeBackMaxBackoff = 30 * time.Second
+)
+
+// WriteBackConfig 开启 write-back 后，写入先落到本地目录，由后台上传到 ufs
+type WriteBackConfig struct {
+	// Dir 为本地暂存目录，实际使用 Dir/fsID/writeback
+	Dir string
+	// Timeout 为 fsync 等待上传完成的最长时间
+	Timeout time.Duration
+	// Retry 为单次上传失败后的重试次数，重试仍失败时向 fsync/close 返回错误，并在后台继续重试
+	Retry int
+	// Concurrency 为并发上传的文件数
+	Concurrency int
+}
+
+// pendingUpload 记录在 journal 中，用于重启后重放未完成的上传
+type pendingUpload struct {
+	Seq  uint64 `json:"seq"`
+	Path string `json:"path"` // 文件系统中的绝对路径，重放时通过 meta.GetUFS 找到对应的 ufs
+	Size int64  `json:"size"`
+	// Uploaded 表示该版本已经上传完成，重放时丢弃同一路径上更早的版本
+	Uploaded bool `json:"uploaded,omitempty"`
+}
+
+type stagedFile struct {
+	pendingUpload
+	ino  Ino
+	done chan struct{}
+	err  error
+}
+
+// pathState 记录一个路径上待上传的文件，同一路径的上传串行执行，且只上传最新的版本
+type pathState struct {
+	lock    sync.Mutex
+	latest  *stagedFile
+	pending int
+	// uploaded 为最近一次上传成功的版本，在路径上还有待上传的版本时记录在 journal 中
+	uploaded uint64
+}
+
+type writeBack struct {
+	m     meta.Meta
+	store cache.Store
+	dir   string
+	conf  WriteBackConfig
+	queue chan *stagedFile
+
+	mu     sync.Mutex
+	seq    uint64
+	paths  map[string]*pathState
+	inodes map[Ino]*pathState
+	// writers 记录每个 inode 打开的暂存文件数
+	writers map[Ino]int
+	// unlinked 记录已删除但仍被打开的文件，关闭前的 flush 不再上传
+	unlinked map[Ino]bool
+}
+
+func newWriteBack(m meta.Meta, store cache.Store, fsID string, conf WriteBackConfig) (*writeBack, error) {
+	if conf.Dir == "" {
+		return nil, fmt.Errorf("write back dir is not allowed empty")
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = DefaultWriteBackTimeout
+	}
+	if conf.Retry < 0 {
+		conf.Retry = DefaultWriteBackRetry
+	}
+	if conf.Concurrency <= 0 {
+		conf.Concurrency = DefaultWriteBackConcurrency
+	}
+	wb := &writeBack{
+		m:        m,
+		store:    store,
+		dir:      filepath.Join(conf.Dir, fsID, writeBackDirName),
+		conf:     conf,
+		queue:    make(chan *stagedFile, 1024),
+		paths:    make(map[string]*pathState),
+		inodes:   make(map[Ino]*pathState),
+		writers:  make(map[Ino]int),
+		unlinked: make(map[Ino]bool),
+	}
+	if err := os.MkdirAll(wb.dir, 0755); err != nil {
+		return nil, err
+	}
+	pending, err := wb.load()
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < conf.Concurrency; i++ {
+		go wb.worker()
+	}
+	// 按写入顺序重放上次未完成的上传
+	for _, p := range pending {
+		wb.commit(p, 0)
+	}
+	log.Infof("write back dir[%s] replays %d pending uploads", wb.dir, len(pending))
+	return wb, nil
+}
+
+// load 读取上次退出时未完成的上传，并清理没有 journal 的暂存文件
+// 同一路径上只重放最新的版本，早于已上传版本的 journal 直接丢弃，避免旧版本覆盖新版本
+func (wb *writeBack) load() ([]pendingUpload, error) {
+	files, err := ioutil.ReadDir(wb.dir)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]pendingUpload)
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), journalSuffix) {
+			continue
+		}
+		name := filepath.Join(wb.dir, f.Name())
+		p := pendingUpload{}
+		data, err := ioutil.ReadFile(name)
+		if err == nil {
+			err = json.Unmarshal(data, &p)
+		}
+		if err == nil && !p.Uploaded {
+			_, err = os.Stat(wb.dataPath(p.Seq))
+		}
+		if err != nil {
+			log.Errorf("write back: drop broken journal[%s]: %v", name, err)
+			_ = os.Remove(name)
+			continue
+		}
+		if p.Seq > wb.seq {
+			wb.seq = p.Seq
+		}
+		if old, ok := latest[p.Path]; ok {
+			if old.Seq > p.Seq {
+				old, p = p, old
+			}
+			log.Infof("write back: drop journal of %s seq[%d], superseded by seq[%d]", old.Path, old.Seq, p.Seq)
+			_ = os.Remove(wb.journalPath(old.Seq))
+		}
+		latest[p.Path] = p
+	}
+	journaled := make(map[uint64]bool)
+	var pending []pendingUpload
+	for _, p := range latest {
+		if p.Uploaded {
+			// 更早的版本已经丢弃，不再需要记录
+			_ = os.Remove(wb.journalPath(p.Seq))
+			continue
+		}
+		journaled[p.Seq] = true
+		pending = append(pending, p)
+	}
+	for _, f := range files {
+		seq, err := strconv.ParseUint(strings.TrimSuffix(f.Name(), dataSuffix), 10, 64)
+		if err == nil && seq > wb.seq {
+			wb.seq = seq
+		}
+		// 未提交或已被丢弃的暂存文件
+		if strings.HasSuffix(f.Name(), dataSuffix) && (err != nil || !journaled[seq]) ||
+			strings.HasSuffix(f.Name(), ".tmp") {
+			_ = os.Remove(filepath.Join(wb.dir, f.Name()))
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Seq < pending[j].Seq
+	})
+	return pending, nil
+}
+
+func (wb *writeBack) dataPath(seq uint64) string {
+	return filepath.Join(wb.dir, strconv.FormatUint(seq, 10)+dataSuffix)
+}
+
+func (wb *writeBack) journalPath(seq uint64) string {
+	return filepath.Join(wb.dir, strconv.FormatUint(seq, 10)+journalSuffix)
+}
+
+// newStagingFile 创建一个本地暂存文件，src 不为空时复制其内容
+func (wb *writeBack) newStagingFile(src *os.File) (*os.File, uint64, error) {
+	wb.mu.Lock()
+	wb.seq++
+	seq := wb.seq
+	wb.mu.Unlock()
+	f, err := os.OpenFile(wb.dataPath(seq), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, 0, err
+	}
+	if src != nil {
+		if _, err = io.Copy(f, io.NewSectionReader(src, 0, 1<<62)); err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+			return nil, 0, err
+		}
+	}
+	return f, seq, nil
+}
+
+// seal 持久化暂存文件和 journal 后提交上传，此后暂存文件不再修改
+func (wb *writeBack) seal(f *os.File, p pendingUpload, ino Ino) (*stagedFile, error) {
+	if err := f.Sync(); err != nil {
+		return nil, err
+	}
+	if err := wb.writeJournal(p); err != nil {
+		return nil, err
+	}
+	return wb.commit(p, ino), nil
+}
+
+func (wb *writeBack) writeJournal(p pendingUpload) error {
+	data, _ := json.Marshal(p)
+	tmp := wb.journalPath(p.Seq) + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, wb.journalPath(p.Seq)); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (wb *writeBack) commit(p pendingUpload, ino Ino) *stagedFile {
+	sf := &stagedFile{pendingUpload: p, ino: ino, done: make(chan struct{})}
+	wb.mu.Lock()
+	if wb.unlinked[ino] {
+		// 文件在 seal 期间被删除，丢弃本次上传
+		wb.mu.Unlock()
+		_ = os.Remove(wb.journalPath(p.Seq))
+		_ = os.Remove(wb.dataPath(p.Seq))
+		close(sf.done)
+		return sf
+	}
+	st, ok := wb.paths[p.Path]
+	if !ok {
+		st = &pathState{}
+		wb.paths[p.Path] = st
+	}
+	st.latest = sf
+	st.pending++
+	if ino != 0 {
+		wb.inodes[ino] = st
+	}
+	wb.mu.Unlock()
+	wb.queue <- sf
+	return sf
+}
+
+func (wb *writeBack) worker() {
+	for sf := range wb.queue {
+		wb.upload(sf)
+	}
+}
+
+func (wb *writeBack) upload(sf *stagedFile) {
+	wb.mu.Lock()
+	st := wb.paths[sf.Path]
+	wb.mu.Unlock()
+
+	st.lock.Lock()
+	wb.mu.Lock()
+	superseded := st.latest != sf
+	wb.mu.Unlock()
+	var err error
+	if !superseded {
+		backoff := time.Second
+		for i := 0; ; i++ {
+			if err = wb.uploadOnce(sf); err == nil || i >= wb.conf.Retry {
+				break
+			}
+			log.Warnf("write back: upload %s seq[%d] failed, retry after %v: %v", sf.Path, sf.Seq, backoff, err)
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > writeBackMaxBackoff {
+				backoff = writeBackMaxBackoff
+			}
+		}
+	}
+	if err != nil {
+		st.lock.Unlock()
+		log.Errorf("write back: upload %s seq[%d] failed, retry after %v: %v", sf.Path, sf.Seq, writeBackMaxBackoff, err)
+		wb.retry(st, sf)
+		// 向等待中的 fsync/close 返回错误
+		sf.err = err
+		close(sf.done)
+		return
+	}
+	if superseded {
+		_ = os.Remove(wb.journalPath(sf.Seq))
+		_ = os.Remove(wb.dataPath(sf.Seq))
+	} else if wb.markUploaded(st, sf) {
+		_ = os.Remove(wb.dataPath(sf.Seq))
+	}
+	st.lock.Unlock()
+
+	wb.mu.Lock()
+	st.pending--
+	if st.pending == 0 {
+		if st.uploaded != 0 {
+			_ = os.Remove(wb.journalPath(st.uploaded))
+		}
+		delete(wb.paths, sf.Path)
+		for ino, s := range wb.inodes {
+			if s == st {
+				delete(wb.inodes, ino)
+			}
+		}
+	}
+	wb.mu.Unlock()
+	close(sf.done)
+}
+
+// retry 保留上传失败的暂存文件和 journal，等待 writeBackMaxBackoff 后重新上传
+// 期间路径上有新的版本提交时，重新上传会被跳过
+func (wb *writeBack) retry(st *pathState, sf *stagedFile) {
+	next := &stagedFile{pendingUpload: sf.pendingUpload, ino: sf.ino, done: make(chan struct{})}
+	wb.mu.Lock()
+	if st.latest == sf {
+		st.latest = next
+	}
+	wb.mu.Unlock()
+	time.AfterFunc(writeBackMaxBackoff, func() {
+		wb.queue <- next
+	})
+}
+
+// markUploaded 记录路径上最近一次上传成功的版本，重启后用于丢弃更早版本的 journal
+// 记录失败时保留暂存文件和 journal，重启后重新上传该版本
+func (wb *writeBack) markUploaded(st *pathState, sf *stagedFile) bool {
+	p := sf.pendingUpload
+	p.Uploaded = true
+	if err := wb.writeJournal(p); err != nil {
+		log.Errorf("write back: mark %s seq[%d] uploaded err: %v", sf.Path, sf.Seq, err)
+		return false
+	}
+	wb.mu.Lock()
+	prev := st.uploaded
+	st.uploaded = sf.Seq
+	wb.mu.Unlock()
+	if prev != 0 {
+		_ = os.Remove(wb.journalPath(prev))
+	}
+	return true
+}
+
+// uploadOnce 用暂存文件覆盖 ufs 中的文件
+func (wb *writeBack) uploadOnce(sf *stagedFile) error {
+	src, err := os.Open(wb.dataPath(sf.Seq))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	ufs, _, _, ufsPath := wb.m.GetUFS(sf.Path)
+	fd, err := ufs.Open(ufsPath, syscall.O_WRONLY|syscall.O_TRUNC, 0)
+	if utils.IfNotExist(err) {
+		fd, err = ufs.Create(ufsPath, syscall.O_WRONLY|syscall.O_TRUNC, 0644)
+	}
+	if err != nil {
+		return err
+	}
+	defer fd.Release()
+
+	buf := make([]byte, writeBackChunkSize)
+	var off int64
+	for off < sf.Size {
+		n, err := src.ReadAt(buf, off)
+		if n > 0 {
+			if _, werr := fd.Write(buf[:n], uint64(off)); werr != nil {
+				return werr
+			}
+			off += int64(n)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err = fd.Flush(); err != nil {
+		return err
+	}
+	if wb.store != nil {
+		if err := wb.store.InvalidateCache(ufsPath, int(sf.Size)); err != nil {
+			log.Errorf("write back: delete cache of %s err: %v", ufsPath, err)
+		}
+	}
+	return nil
+}
+
+// wait 等待路径上最新版本上传完成
+func (wb *writeBack) wait(name string) error {
+	wb.mu.Lock()
+	st, ok := wb.paths[name]
+	var sf *stagedFile
+	if ok {
+		sf = st.latest
+	}
+	wb.mu.Unlock()
+	if sf == nil {
+		return nil
+	}
+	select {
+	case <-sf.done:
+		return sf.err
+	case <-time.After(wb.conf.Timeout):
+		return syscall.ETIMEDOUT
+	}
+}
+
+// drain 等待 name 及其子路径上的上传完成，在 rename 等改变路径的操作前调用
+func (wb *writeBack) drain(name string) error {
+	prefix := strings.TrimSuffix(name, "/") + "/"
+	var names []string
+	wb.mu.Lock()
+	for p := range wb.paths {
+		if p == name || strings.HasPrefix(p, prefix) {
+			names = append(names, p)
+		}
+	}
+	wb.mu.Unlock()
+	for _, p := range names {
+		if err := wb.wait(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// acquire 在打开暂存文件时调用
+func (wb *writeBack) acquire(ino Ino) {
+	wb.mu.Lock()
+	wb.writers[ino]++
+	wb.mu.Unlock()
+}
+
+// release 在关闭暂存文件时调用，inode 上的暂存文件全部关闭后清除删除标记
+func (wb *writeBack) release(ino Ino) {
+	wb.mu.Lock()
+	if wb.writers[ino]--; wb.writers[ino] <= 0 {
+		delete(wb.writers, ino)
+		delete(wb.unlinked, ino)
+	}
+	wb.mu.Unlock()
+}
+
+// stagingPath 返回暂存文件当前的路径，rename 后为新路径；文件已被删除时返回 false
+func (wb *writeBack) stagingPath(ino Ino) (string, bool) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	if wb.unlinked[ino] {
+		return "", false
+	}
+	// 持有锁查询路径，unlink 在标记删除后才会删除 inode
+	return wb.m.InoToPath(ino), true
+}
+
+// unlink 在删除文件时调用，仍被打开的文件关闭前不再上传，并取消路径上尚未开始的上传
+func (wb *writeBack) unlink(name string, ino Ino) {
+	wb.mu.Lock()
+	if wb.writers[ino] > 0 {
+		wb.unlinked[ino] = true
+	}
+	wb.mu.Unlock()
+	wb.cancel(name)
+}
+
+// cancel 取消路径上尚未开始的上传，在删除文件时调用
+func (wb *writeBack) cancel(name string) {
+	wb.mu.Lock()
+	st, ok := wb.paths[name]
+	if ok {
+		// 排队中的上传发现自己不是最新版本后会跳过
+		st.latest = &stagedFile{done: make(chan struct{})}
+		close(st.latest.done)
+	}
+	wb.mu.Unlock()
+	if ok {
+		// 等待正在进行的上传结束
+		st.lock.Lock()
+		st.lock.Unlock()
+	}
+}
+
+// stagedSize 返回尚未上传完成的文件大小
+func (wb *writeBack) stagedSize(ino Ino) (uint64, bool) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	st, ok := wb.inodes[ino]
+	if !ok || st.latest == nil || st.latest.Seq == 0 {
+		return 0, false
+	}
+	return uint64(st.latest.Size), true
+}
+
+// openStaged 打开尚未上传完成的暂存文件，读取时优先使用
+func (wb *writeBack) openStaged(ino Ino) *os.File {
+	wb.mu.Lock()
+	st, ok := wb.inodes[ino]
+	var seq uint64
+	if ok && st.latest != nil {
+		seq = st.latest.Seq
+	}
+	wb.mu.Unlock()
+	if seq == 0 {
+		return nil
+	}
+	// 上传完成后暂存文件会被删除，此时从 ufs 读取
+	f, err := os.Open(wb.dataPath(seq))
+	if err != nil {
+		return nil
+	}
+	return f
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package vfs
 
 import (
+	"os"
 	"sync"
 	"syscall"
 
@@ -45,12 +46,13 @@ type DataWriter interface {
 	// Truncate(path string, length uint64)
 }
 
-func NewDataWriter(m meta.Meta, blockSize int, store cache.Store) DataWriter {
+func NewDataWriter(m meta.Meta, blockSize int, store cache.Store, wb *writeBack) DataWriter {
 	w := &dataWriter{
 		m:         m,
 		files:     make(map[Ino]*fileWriter),
 		store:     store,
 		blockSize: blockSize,
+		writeBack: wb,
 	}
 	return w
 }
@@ -65,18 +67,83 @@ type fileWriter struct {
 
 	// TODO: 先用base.FileHandle跑通流程，后续修改ufs接口
 	fd ufslib.FileHandle
+
+	// write-back 模式下写入本地暂存文件，fd 为空
+	staging *stagingWriter
+}
+
+// stagingWriter 为 write-back 模式下的本地暂存文件，flush 后提交上传，再次写入时复制一份新的暂存文件
+type stagingWriter struct {
+	file   *os.File
+	seq    uint64
+	size   int64
+	dirty  bool
+	sealed bool
 }
 
 func (f *fileWriter) Fallocate(size int64, off int64, mode uint32) syscall.Errno {
 	f.Lock()
 	defer f.Unlock()
+	if f.staging != nil {
+		return syscall.ENOSYS
+	}
 	return utils.ToSyscallErrno(f.fd.Allocate(uint64(off), uint64(size), mode))
 }
 
+// prepareStaging 在写入已提交上传的暂存文件前复制一份新的暂存文件
+func (f *fileWriter) prepareStaging() error {
+	s := f.staging
+	if !s.sealed {
+		return nil
+	}
+	file, seq, err := f.writer.writeBack.newStagingFile(s.file)
+	if err != nil {
+		return err
+	}
+	_ = s.file.Close()
+	s.file, s.seq, s.sealed = file, seq, false
+	return nil
+}
+
+// seal 提交暂存文件的上传，路径在提交时获取，rename 后上传到新路径
+func (f *fileWriter) seal() error {
+	s := f.staging
+	if !s.dirty {
+		return nil
+	}
+	absPath, ok := f.writer.writeBack.stagingPath(f.inode)
+	if !ok {
+		// 已删除的文件不再上传
+		s.dirty = false
+		return nil
+	}
+	_, err := f.writer.writeBack.seal(s.file, pendingUpload{Seq: s.seq, Path: absPath, Size: s.size}, f.inode)
+	if err != nil {
+		log.Errorf("write back: seal %s err: %v", absPath, err)
+		return err
+	}
+	s.dirty, s.sealed = false, true
+	return nil
+}
+
 func (f *fileWriter) Write(data []byte, offset uint64) syscall.Errno {
 	f.Lock()
 	defer f.Unlock()
 	var err error
+	if f.staging != nil {
+		if err = f.prepareStaging(); err == nil {
+			_, err = f.staging.file.WriteAt(data, int64(offset))
+		}
+		if err != nil {
+			log.Errorf("write back: write inode[%v] err: %v", f.inode, err)
+			return syscall.EIO
+		}
+		if end := int64(offset) + int64(len(data)); end > f.staging.size {
+			f.staging.size = end
+		}
+		f.staging.dirty = true
+		return syscall.F_OK
+	}
 	if f.writer.store != nil {
 		// todo:: length可能会有遗漏
 		log.Debugf("fileWriter write: InvalidateCache path[%s] cache length[%d]", f.path, f.length)
@@ -105,6 +172,13 @@ func (f *fileWriter) Flush() syscall.Errno {
 			return syscall.EBADF
 		}
 	}
+	if f.staging != nil {
+		// 暂存文件由后台上传，上传的超时和重试见 writeBack
+		if err := f.seal(); err != nil {
+			return syscall.EIO
+		}
+		return syscall.F_OK
+	}
 	// todo:: 需要加一个超时和重试
 	return utils.ToSyscallErrno(f.fd.Flush())
 }
@@ -112,6 +186,21 @@ func (f *fileWriter) Flush() syscall.Errno {
 func (f *fileWriter) Fsync(fd int) syscall.Errno {
 	f.Lock()
 	defer f.Unlock()
+	if f.staging != nil {
+		// fsync 需要等待上传完成，保证数据持久化到 ufs
+		if err := f.seal(); err != nil {
+			return syscall.EIO
+		}
+		absPath, ok := f.writer.writeBack.stagingPath(f.inode)
+		if !ok {
+			return syscall.F_OK
+		}
+		if err := f.writer.writeBack.wait(absPath); err != nil {
+			log.Errorf("write back: fsync %s err: %v", absPath, err)
+			return syscall.EIO
+		}
+		return syscall.F_OK
+	}
 	// todo:: 需要加一个超时和重试
 	return utils.ToSyscallErrno(f.fd.Fsync(fd))
 }
@@ -122,10 +211,35 @@ func (f *fileWriter) Close() {
 
 func (f *fileWriter) release() {
 	delete(f.writer.files, f.inode)
+	if f.staging != nil {
+		_ = f.seal()
+		_ = f.staging.file.Close()
+		if !f.staging.sealed {
+			// 未写入过或已删除的暂存文件不需要上传
+			_ = os.Remove(f.staging.file.Name())
+		}
+		f.writer.writeBack.release(f.inode)
+		return
+	}
 	f.fd.Release()
 }
 
 func (f *fileWriter) Truncate(size uint64) syscall.Errno {
+	if f.staging != nil {
+		f.Lock()
+		defer f.Unlock()
+		err := f.prepareStaging()
+		if err == nil {
+			err = f.staging.file.Truncate(int64(size))
+		}
+		if err != nil {
+			log.Errorf("write back: truncate inode[%v] err: %v", f.inode, err)
+			return syscall.EIO
+		}
+		f.staging.size = int64(size)
+		f.staging.dirty = true
+		return syscall.F_OK
+	}
 	return utils.ToSyscallErrno(f.fd.Truncate(size))
 }
 
@@ -136,23 +250,62 @@ type dataWriter struct {
 	files     map[Ino]*fileWriter
 	store     cache.Store
 	blockSize int
+	writeBack *writeBack
 }
 
 func (w *dataWriter) Open(inode Ino, length uint64, ufs ufslib.UnderFileStorage, path string) (FileWriter, error) {
-	fd, err := ufs.Open(path, syscall.O_WRONLY, length)
-	if err != nil {
-		return nil, err
-	}
 	f := &fileWriter{
 		writer: w,
 		inode:  inode,
 		path:   path,
 		length: length,
 		ufs:    ufs,
-		fd:     fd,
+	}
+	staging, err := w.openStaging(inode, length)
+	if err != nil {
+		return nil, err
+	}
+	if staging != nil {
+		f.staging = staging
+	} else {
+		fd, err := ufs.Open(path, syscall.O_WRONLY, length)
+		if err != nil {
+			return nil, err
+		}
+		f.fd = fd
 	}
 	w.Lock()
 	w.files[inode] = f
 	w.Unlock()
 	return f, nil
 }
+
+// openStaging 在 write-back 模式下为新文件或尚未上传完成的文件创建暂存文件。
+// 已存在于 ufs 的文件需要先下载才能在本地修改，这类文件仍然直接写 ufs。
+func (w *dataWriter) openStaging(inode Ino, length uint64) (*stagingWriter, error) {
+	if w.writeBack == nil {
+		return nil, nil
+	}
+	src := w.writeBack.openStaged(inode)
+	if src == nil && length != 0 {
+		return nil, nil
+	}
+	if src != nil {
+		defer src.Close()
+	}
+	file, seq, err := w.writeBack.newStagingFile(src)
+	if err != nil {
+		log.Errorf("write back: new staging file of inode[%v] err: %v", inode, err)
+		return nil, err
+	}
+	size := int64(length)
+	if info, err := file.Stat(); err == nil && src != nil {
+		size = info.Size()
+	}
+	w.writeBack.acquire(inode)
+	return &stagingWriter{
+		file: file,
+		seq:  seq,
+		size: size,
+	}, nil
+}
@@ -59,6 +59,9 @@ const (
 	S3ForcePathStyle   = "s3ForcePathStyle"
 	DirMode            = "dirMode"
 	FileMode           = "fileMode"
+	// S3 multipart upload, sizes are in MiB
+	S3PartSize          = "s3PartSize"
+	S3UploadConcurrency = "s3UploadConcurrency"
 
 	// sftp properties
 	Address  = "address"
@@ -81,6 +84,17 @@ const (
 	LinkMetaFile = "links_meta"
 )
 
+// secretProperties are encrypted at rest and redacted in api responses
+var secretProperties = map[string]bool{
+	SecretKey:  true,
+	Password:   true,
+	KeyTabData: true,
+}
+
+func IsSecretProperty(key string) bool {
+	return secretProperties[key]
+}
+
 type FSMeta struct {
 	ID            string
 	Name          string
@@ -17,12 +17,21 @@ limitations under the License.
 package csidriver
 
 import (
+	"strings"
+
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/kubernetes-csi/drivers/pkg/csi-common"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	k8sCore "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/csiplugin/csiconfig"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/utils"
 )
 
 type controllerServer struct {
@@ -104,13 +113,112 @@ func (cs *controllerServer) ValidateVolumeCapabilities(ctx context.Context,
 	}, nil
 }
 
+// ControllerExpandVolume only records the new capacity, as file systems of paddleflow are not limited by volume capacity
 func (cs *controllerServer) ControllerExpandVolume(ctx context.Context,
 	req *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
-	return &csi.ControllerExpandVolumeResponse{}, status.Error(codes.Unimplemented,
-		"ControllerExpandVolume is not implemented")
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+	if req.GetCapacityRange() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Capacity range missing in request")
+	}
+	if err := cs.Driver.ValidateControllerServiceRequest(csi.ControllerServiceCapability_RPC_EXPAND_VOLUME); err != nil {
+		log.Errorf("invalid expand volume req: %v", req)
+		return nil, err
+	}
+
+	capacityBytes := req.GetCapacityRange().GetRequiredBytes()
+	limitBytes := req.GetCapacityRange().GetLimitBytes()
+	if limitBytes > 0 && capacityBytes > limitBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "required bytes %d exceeds limit bytes %d", capacityBytes, limitBytes)
+	}
+	if _, err := getVolume(volumeID); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Expanding volume %s to %d bytes", volumeID, capacityBytes)
+	return &csi.ControllerExpandVolumeResponse{
+		CapacityBytes:         capacityBytes,
+		NodeExpansionRequired: false,
+	}, nil
 }
 
+// ControllerGetVolume reports nodes the volume is mounted on by mount pods, and volume condition
+// from health of mount pods, ufs reachability and cache usage reported by cache-worker
 func (cs *controllerServer) ControllerGetVolume(ctx context.Context,
 	req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "ControllerGetVolume is not implemented")
+	volumeID := req.GetVolumeId()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+	if err := cs.Driver.ValidateControllerServiceRequest(csi.ControllerServiceCapability_RPC_GET_VOLUME); err != nil {
+		log.Errorf("invalid get volume req: %v", req)
+		return nil, err
+	}
+
+	pv, err := getVolume(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	k8sClient, err := utils.GetK8sClient()
+	if err != nil {
+		log.Errorf("get k8s client failed: %v", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	attributes := pv.Spec.CSI.VolumeAttributes
+	listOptions := metav1.ListOptions{
+		LabelSelector: csiconfig.PodTypeKey + "=" + csiconfig.PodMount + "," + schema.LabelKeyFsID + "=" + attributes[schema.PFSID],
+	}
+	pods, err := k8sClient.ListPods(csiconfig.Namespace, listOptions)
+	if err != nil {
+		log.Errorf("list mount pods of volume[%s] failed: %v", volumeID, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	condition := &volumeCondition{}
+	nodeIDs := make([]string, 0)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		// mount pods are named by node and volume id
+		if !strings.HasSuffix(pod.Name, "-"+volumeID) {
+			continue
+		}
+		nodeIDs = append(nodeIDs, pod.Spec.NodeName)
+		condition.checkMountPod(pod)
+	}
+
+	capacity := pv.Spec.Capacity[k8sCore.ResourceStorage]
+	return &csi.ControllerGetVolumeResponse{
+		Volume: &csi.Volume{
+			VolumeId:      volumeID,
+			CapacityBytes: capacity.Value(),
+			VolumeContext: attributes,
+		},
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			PublishedNodeIds: nodeIDs,
+			VolumeCondition:  condition.csiCondition(),
+		},
+	}, nil
+}
+
+// getVolume returns pv of the volume, volume id of paddleflow is the name of pv
+func getVolume(volumeID string) (*k8sCore.PersistentVolume, error) {
+	k8sClient, err := utils.GetK8sClient()
+	if err != nil {
+		log.Errorf("get k8s client failed: %v", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	pv, err := k8sClient.GetPersistentVolume(volumeID, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, status.Errorf(codes.NotFound, "volume %s not found", volumeID)
+		}
+		log.Errorf("get pv[%s] failed: %v", volumeID, err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != driverName {
+		return nil, status.Errorf(codes.NotFound, "volume %s is not provisioned by %s", volumeID, driverName)
+	}
+	return pv, nil
 }
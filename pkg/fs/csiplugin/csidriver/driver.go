@@ -20,6 +20,7 @@ import (
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/kubernetes-csi/drivers/pkg/csi-common"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/version"
 )
@@ -37,7 +38,10 @@ func NewDriver(nodeID, endpoint string) *driver {
 	log.Infof("Driver: %v version: %v", driverName, version.GitBranch)
 	csiDriver := csicommon.NewCSIDriver(driverName, version.GitBranch, nodeID)
 	csiDriver.AddControllerServiceCapabilities([]csi.ControllerServiceCapability_RPC_Type{
-		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME})
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
+		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION})
 	csiDriver.AddVolumeCapabilityAccessModes([]csi.VolumeCapability_AccessMode_Mode{
 		csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER})
 
@@ -48,6 +52,40 @@ func NewDriver(nodeID, endpoint string) *driver {
 	}
 }
 
+type identityServer struct {
+	*csicommon.DefaultIdentityServer
+}
+
+// GetPluginCapabilities reports online volume expansion besides controller service,
+// so that volumes can be expanded by external-resizer
+func (ids *identityServer) GetPluginCapabilities(ctx context.Context,
+	req *csi.GetPluginCapabilitiesRequest) (*csi.GetPluginCapabilitiesResponse, error) {
+	return &csi.GetPluginCapabilitiesResponse{
+		Capabilities: []*csi.PluginCapability{
+			{
+				Type: &csi.PluginCapability_Service_{
+					Service: &csi.PluginCapability_Service{
+						Type: csi.PluginCapability_Service_CONTROLLER_SERVICE,
+					},
+				},
+			},
+			{
+				Type: &csi.PluginCapability_VolumeExpansion_{
+					VolumeExpansion: &csi.PluginCapability_VolumeExpansion{
+						Type: csi.PluginCapability_VolumeExpansion_ONLINE,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (d *driver) newIdentityServer() *identityServer {
+	return &identityServer{
+		DefaultIdentityServer: csicommon.NewDefaultIdentityServer(d.csiDriver),
+	}
+}
+
 func (d *driver) newControllerServer() *controllerServer {
 	return &controllerServer{
 		DefaultControllerServer: csicommon.NewDefaultControllerServer(d.csiDriver),
@@ -66,7 +104,7 @@ func (d *driver) Run() {
 	s := csicommon.NewNonBlockingGRPCServer()
 	s.Start(
 		d.endpoint,
-		csicommon.NewDefaultIdentityServer(d.csiDriver),
+		d.newIdentityServer(),
 		d.newControllerServer(),
 		d.newNodeServer(),
 	)
@@ -0,0 +1,159 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csidriver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	log "github.com/sirupsen/logrus"
+	k8sCore "k8s.io/api/core/v1"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/csiplugin/mount"
+	locationAwareness "github.com/PaddlePaddle/PaddleFlow/pkg/fs/location-awareness"
+)
+
+const statTimeout = 10 * time.Second
+
+var (
+	// volume paths whose statfs is hanging, a new statfs is not started until the former one returns
+	hangingStats sync.Map
+	statfs       = syscall.Statfs
+)
+
+// statVolumePath returns bytes and inodes usage of volume path. statfs goes through fuse to ufs,
+// so it times out when fuse or ufs hangs.
+func statVolumePath(volumePath string) ([]*csi.VolumeUsage, error) {
+	if _, loaded := hangingStats.LoadOrStore(volumePath, struct{}{}); loaded {
+		return nil, fmt.Errorf("former statfs has been hanging for more than %s", statTimeout)
+	}
+	type result struct {
+		st  syscall.Statfs_t
+		err error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		defer hangingStats.Delete(volumePath)
+		var st syscall.Statfs_t
+		err := statfs(volumePath, &st)
+		resultCh <- result{st: st, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-resultCh:
+	case <-time.After(statTimeout):
+		return nil, fmt.Errorf("statfs timeout after %s, fuse or ufs may be unreachable", statTimeout)
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+	st := res.st
+	bsize := int64(st.Bsize)
+	return []*csi.VolumeUsage{
+		{
+			Unit:      csi.VolumeUsage_BYTES,
+			Total:     int64(st.Blocks) * bsize,
+			Available: int64(st.Bavail) * bsize,
+			Used:      int64(st.Blocks-st.Bfree) * bsize,
+		},
+		{
+			Unit:      csi.VolumeUsage_INODES,
+			Total:     int64(st.Files),
+			Available: int64(st.Ffree),
+			Used:      int64(st.Files - st.Ffree),
+		},
+	}, nil
+}
+
+// volumeCondition collects health of mount pods, ufs reachability and cache usage of a volume
+type volumeCondition struct {
+	abnormal bool
+	messages []string
+	// cache used in MiB, reported by cache-worker in mount pods
+	cacheUsed int
+}
+
+func (c *volumeCondition) addAbnormal(format string, args ...interface{}) {
+	c.abnormal = true
+	c.messages = append(c.messages, fmt.Sprintf(format, args...))
+}
+
+func (c *volumeCondition) checkMountPod(pod *k8sCore.Pod) {
+	if pod.DeletionTimestamp != nil {
+		c.addAbnormal("mount pod %s is terminating", pod.Name)
+	} else if !mount.IsPodReady(pod) {
+		c.addAbnormal("mount pod %s on node %s is not ready%s", pod.Name, pod.Spec.NodeName, containerReasons(pod))
+	}
+
+	if value := pod.Annotations[schema.AnnotationKeyUfsHealth]; value != "" {
+		health, err := locationAwareness.ParseUfsHealth(value)
+		if err != nil {
+			log.Errorf("mount pod[%s] ufs health[%s] is not valid: %v", pod.Name, value, err)
+		} else if !health.Healthy {
+			c.addAbnormal("ufs is unreachable from mount pod %s: %s", pod.Name, health.Message)
+		}
+	}
+
+	if value := pod.Labels[schema.LabelKeyUsedSize]; value != "" {
+		usedSize, err := strconv.Atoi(value)
+		if err != nil {
+			log.Errorf("mount pod[%s] label %s[%s] is not valid: %v", pod.Name, schema.LabelKeyUsedSize, value, err)
+		} else {
+			c.cacheUsed += usedSize
+		}
+	}
+}
+
+func (c *volumeCondition) csiCondition() *csi.VolumeCondition {
+	messages := c.messages
+	if !c.abnormal {
+		messages = append(messages, "volume is healthy")
+	}
+	messages = append(messages, fmt.Sprintf("cache used %dMiB", c.cacheUsed))
+	return &csi.VolumeCondition{
+		Abnormal: c.abnormal,
+		Message:  strings.Join(messages, "; "),
+	}
+}
+
+// containerReasons returns reasons of containers not ready, e.g. CrashLoopBackOff of fuse container
+func containerReasons(pod *k8sCore.Pod) string {
+	var reasons []string
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			continue
+		}
+		reason := "not ready"
+		if cs.State.Waiting != nil {
+			reason = cs.State.Waiting.Reason
+		} else if cs.State.Terminated != nil {
+			reason = cs.State.Terminated.Reason
+		}
+		reasons = append(reasons, fmt.Sprintf("container %s %s, restarted %d times", cs.Name, reason, cs.RestartCount))
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(reasons, ", ")
+}
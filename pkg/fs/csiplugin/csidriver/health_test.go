@@ -0,0 +1,156 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csidriver
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8sCore "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/csiplugin/csiconfig"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/utils"
+)
+
+const (
+	mockFsID     = "fs-root-mock"
+	mockVolumeID = "pfs-fs-root-mock-default-pv"
+)
+
+func mockMountPod(nodeName string, ready bool, labels, annotations map[string]string) *k8sCore.Pod {
+	pod := &k8sCore.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pfs-" + nodeName + "-" + mockVolumeID,
+			Namespace:   csiconfig.Namespace,
+			Labels:      map[string]string{csiconfig.PodTypeKey: csiconfig.PodMount, schema.LabelKeyFsID: mockFsID},
+			Annotations: annotations,
+		},
+		Spec: k8sCore.PodSpec{NodeName: nodeName},
+	}
+	for k, v := range labels {
+		pod.Labels[k] = v
+	}
+	if ready {
+		pod.Status.Conditions = []k8sCore.PodCondition{
+			{Type: k8sCore.PodReady, Status: k8sCore.ConditionTrue},
+			{Type: k8sCore.ContainersReady, Status: k8sCore.ConditionTrue},
+		}
+	} else {
+		pod.Status.ContainerStatuses = []k8sCore.ContainerStatus{{
+			Name:         "pfs-mount",
+			RestartCount: 3,
+			State: k8sCore.ContainerState{
+				Waiting: &k8sCore.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+			},
+		}}
+	}
+	return pod
+}
+
+func TestControllerGetVolume(t *testing.T) {
+	csiconfig.Namespace = schema.MountPodNamespace
+	k8sClient := utils.GetFakeK8sClient()
+	pv := &k8sCore.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: mockVolumeID},
+		Spec: k8sCore.PersistentVolumeSpec{
+			Capacity: k8sCore.ResourceList{k8sCore.ResourceStorage: resource.MustParse("40Gi")},
+			PersistentVolumeSource: k8sCore.PersistentVolumeSource{
+				CSI: &k8sCore.CSIPersistentVolumeSource{
+					Driver:           driverName,
+					VolumeHandle:     mockVolumeID,
+					VolumeAttributes: map[string]string{schema.PFSID: mockFsID},
+				},
+			},
+		},
+	}
+	_, err := k8sClient.CreatePersistentVolume(pv)
+	assert.Nil(t, err)
+	_, err = k8sClient.CreatePod(mockMountPod("node1", true,
+		map[string]string{schema.LabelKeyUsedSize: "100"}, map[string]string{}))
+	assert.Nil(t, err)
+
+	cs := NewDriver("node1", "").newControllerServer()
+	resp, err := cs.ControllerGetVolume(context.TODO(), &csi.ControllerGetVolumeRequest{VolumeId: mockVolumeID})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(40<<30), resp.Volume.CapacityBytes)
+	assert.Equal(t, []string{"node1"}, resp.Status.PublishedNodeIds)
+	assert.False(t, resp.Status.VolumeCondition.Abnormal)
+	assert.Contains(t, resp.Status.VolumeCondition.Message, "cache used 100MiB")
+
+	// mount pod not ready and ufs unreachable on node2
+	_, err = k8sClient.CreatePod(mockMountPod("node2", false,
+		map[string]string{schema.LabelKeyUsedSize: "20"},
+		map[string]string{schema.AnnotationKeyUfsHealth: `{"healthy":false,"message":"list mount point timeout"}`}))
+	assert.Nil(t, err)
+	resp, err = cs.ControllerGetVolume(context.TODO(), &csi.ControllerGetVolumeRequest{VolumeId: mockVolumeID})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(resp.Status.PublishedNodeIds))
+	condition := resp.Status.VolumeCondition
+	assert.True(t, condition.Abnormal)
+	assert.Contains(t, condition.Message, "container pfs-mount CrashLoopBackOff, restarted 3 times")
+	assert.Contains(t, condition.Message, "list mount point timeout")
+	assert.Contains(t, condition.Message, "cache used 120MiB")
+
+	_, err = cs.ControllerGetVolume(context.TODO(), &csi.ControllerGetVolumeRequest{VolumeId: "not-exist"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	expandResp, err := cs.ControllerExpandVolume(context.TODO(), &csi.ControllerExpandVolumeRequest{
+		VolumeId:      mockVolumeID,
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 80 << 30},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(80<<30), expandResp.CapacityBytes)
+	assert.False(t, expandResp.NodeExpansionRequired)
+
+	_, err = cs.ControllerExpandVolume(context.TODO(), &csi.ControllerExpandVolumeRequest{VolumeId: mockVolumeID})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestStatVolumePath(t *testing.T) {
+	origin := statfs
+	defer func() { statfs = origin }()
+	statfs = func(path string, st *syscall.Statfs_t) error {
+		st.Bsize = 4096
+		st.Blocks = 100
+		st.Bfree = 40
+		st.Bavail = 30
+		st.Files = 10
+		st.Ffree = 4
+		return nil
+	}
+	usage, err := statVolumePath("/mnt/volume")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(usage))
+	assert.Equal(t, int64(100*4096), usage[0].Total)
+	assert.Equal(t, int64(60*4096), usage[0].Used)
+	assert.Equal(t, int64(30*4096), usage[0].Available)
+	assert.Equal(t, int64(6), usage[1].Used)
+
+	statfs = func(path string, st *syscall.Statfs_t) error {
+		return syscall.ENOTCONN
+	}
+	_, err = statVolumePath("/mnt/volume")
+	assert.Equal(t, syscall.ENOTCONN, err)
+}
@@ -27,6 +27,7 @@ import (
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
@@ -41,14 +42,21 @@ type nodeServer struct {
 }
 
 func (ns *nodeServer) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
-	nscap := &csi.NodeServiceCapability{
-		Type: &csi.NodeServiceCapability_Rpc{
-			Rpc: &csi.NodeServiceCapability_RPC{
-				Type: csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+	var nscaps []*csi.NodeServiceCapability
+	for _, capType := range []csi.NodeServiceCapability_RPC_Type{
+		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
+	} {
+		nscaps = append(nscaps, &csi.NodeServiceCapability{
+			Type: &csi.NodeServiceCapability_Rpc{
+				Rpc: &csi.NodeServiceCapability_RPC{
+					Type: capType,
+				},
 			},
-		},
+		})
 	}
-	return &csi.NodeGetCapabilitiesResponse{Capabilities: []*csi.NodeServiceCapability{nscap}}, nil
+	return &csi.NodeGetCapabilitiesResponse{Capabilities: nscaps}, nil
 }
 
 func (ns *nodeServer) NodePublishVolume(ctx context.Context,
@@ -132,6 +140,56 @@ func (ns *nodeServer) NodeExpandVolume(ctx context.Context,
 	return nil, status.Error(codes.Unimplemented, "NodeExpandVolume is not implemented")
 }
 
+// NodeGetVolumeStats reports usage of the volume path, and volume condition from the mount point,
+// health of mount pod, ufs reachability and cache usage reported by cache-worker
+func (ns *nodeServer) NodeGetVolumeStats(ctx context.Context,
+	req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
+	volumeID, volumePath := req.GetVolumeId(), req.GetVolumePath()
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+	if len(volumePath) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume path missing in request")
+	}
+
+	condition := &volumeCondition{}
+	resp := &csi.NodeGetVolumeStatsResponse{}
+	// stat of a hanging fuse mount point never returns, so mount point is checked only if stat succeeds
+	usage, err := statVolumePath(volumePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, status.Errorf(codes.NotFound, "volume path %s not found", volumePath)
+		}
+		condition.addAbnormal("stat volume path %s failed: %v", volumePath, err)
+	} else {
+		resp.Usage = usage
+		if isMountPoint, err := utils.IsMountPoint(volumePath); err != nil {
+			condition.addAbnormal("volume path %s is broken: %v", volumePath, err)
+		} else if !isMountPoint {
+			condition.addAbnormal("volume path %s is not a mount point", volumePath)
+		}
+	}
+
+	// process mount has no mount pod
+	k8sClient, err := utils.GetK8sClient()
+	if err != nil {
+		log.Errorf("get k8s client failed: %v", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	pod, err := k8sClient.GetPod(csiconfig.Namespace, mount.GeneratePodNameByVolumeID(volumeID))
+	if err == nil {
+		condition.checkMountPod(pod)
+	} else if !k8sErrors.IsNotFound(err) {
+		log.Errorf("get mount pod of volume[%s] failed: %v", volumeID, err)
+	}
+
+	resp.VolumeCondition = condition.csiCondition()
+	if condition.abnormal {
+		log.Warningf("volume[%s] path[%s] is abnormal: %s", volumeID, volumePath, resp.VolumeCondition.Message)
+	}
+	return resp, nil
+}
+
 func mountVolume(volumeID string, mountInfo mount.Info) error {
 	log.Infof("mountVolume: indepedentMp:%t, readOnly:%t", mountInfo.FS.IndependentMountProcess, mountInfo.ReadOnly)
 	if !mountInfo.FS.IndependentMountProcess && mountInfo.FS.Type != common.GlusterFSType {
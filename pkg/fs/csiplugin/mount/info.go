@@ -191,5 +191,6 @@ func (mountInfo *Info) CacheWorkerCmd() string {
 	if mountInfo.CacheConfig.CacheDir != "" {
 		cmd += FusePodCachePath
 	}
+	cmd += " --podMountPoint=" + FusePodMountPoint
 	return cmd
 }
@@ -219,7 +219,7 @@ func waitUtilPodReady(k8sClient utils.Client, podName string) error {
 		if err != nil {
 			return status.Errorf(codes.Internal, "waitUtilPodReady: Get pod %v failed: %v", podName, err)
 		}
-		if isPodReady(pod) {
+		if IsPodReady(pod) {
 			log.Infof("waitUtilPodReady: Pod %v is successful", podName)
 			return nil
 		}
@@ -232,7 +232,7 @@ func waitUtilPodReady(k8sClient utils.Client, podName string) error {
 	return status.Errorf(codes.Internal, "waitUtilPodReady: mount pod %s isn't ready in 30 seconds: %v", podName, podLog)
 }
 
-func isPodReady(pod *k8sCore.Pod) bool {
+func IsPodReady(pod *k8sCore.Pod) bool {
 	conditionsTrue := 0
 	for _, cond := range pod.Status.Conditions {
 		if cond.Status == k8sCore.ConditionTrue && (cond.Type == k8sCore.ContainersReady || cond.Type == k8sCore.PodReady) {
@@ -374,21 +374,31 @@ func generatePodVolumes(cacheDir string) []k8sCore.Volume {
 func buildCacheWorkerContainer(cacheContainer k8sCore.Container, mountInfo Info) k8sCore.Container {
 	cacheContainer.Name = ContainerNameCacheWorker
 	cacheContainer.Command = []string{"sh", "-c", mountInfo.CacheWorkerCmd()}
+	// fuse mount point of pfs-mount container is visible to cache-worker for warmup
+	hostToContainer := k8sCore.MountPropagationHostToContainer
+	volumeMounts := []k8sCore.VolumeMount{
+		{
+			Name:             VolumesKeyMount,
+			MountPath:        schema.FusePodMntDir,
+			SubPath:          mountInfo.FS.ID,
+			MountPropagation: &hostToContainer,
+		},
+	}
 	if mountInfo.CacheConfig.CacheDir != "" {
 		mp := k8sCore.MountPropagationBidirectional
-		volumeMounts := []k8sCore.VolumeMount{
-			{
+		volumeMounts = append(volumeMounts,
+			k8sCore.VolumeMount{
 				Name:             VolumesKeyDataCache,
 				MountPath:        FusePodCachePath + DataCacheDir,
 				MountPropagation: &mp,
 			},
-			{
+			k8sCore.VolumeMount{
 				Name:             VolumesKeyMetaCache,
 				MountPath:        FusePodCachePath + MetaCacheDir,
 				MountPropagation: &mp,
 			},
-		}
-		cacheContainer.VolumeMounts = volumeMounts
+		)
 	}
+	cacheContainer.VolumeMounts = volumeMounts
 	return cacheContainer
 }
@@ -121,7 +121,7 @@ func TestPFSMountWithCache(t *testing.T) {
 	info, err := ConstructMountInfo(fsBase64, fsCacheBase64, testTargetPath, fakeClientSet, false)
 	assert.Nil(t, err)
 
-	patch1 := ApplyFunc(isPodReady, func(pod *k8sCore.Pod) bool {
+	patch1 := ApplyFunc(IsPodReady, func(pod *k8sCore.Pod) bool {
 		return true
 	})
 	defer patch1.Reset()
@@ -0,0 +1,118 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package location_awareness
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/utils"
+)
+
+const (
+	ufsProbeInterval = 30 * time.Second
+	ufsProbeTimeout  = 10 * time.Second
+)
+
+// UfsHealth is reported by cache-worker through pod annotation and read by csi plugin as volume condition
+type UfsHealth struct {
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+}
+
+func ParseUfsHealth(value string) (*UfsHealth, error) {
+	health := &UfsHealth{}
+	if err := json.Unmarshal([]byte(value), health); err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// UfsHealthLoop probes ufs through the mount point periodically, and patches the result to pod annotation when it changes
+func UfsHealthLoop(k8sClient utils.Client, podNamespace, podName, mountPoint string) {
+	prober := &ufsProber{mountPoint: mountPoint, timeout: ufsProbeTimeout}
+	var last *UfsHealth
+	for {
+		time.Sleep(ufsProbeInterval)
+		health := prober.probe()
+		if last != nil && *last == health {
+			continue
+		}
+		if !health.Healthy {
+			log.Errorf("ufs of mount pod %s is unhealthy: %s", podName, health.Message)
+		}
+		data, err := AnnotationPatch(schema.AnnotationKeyUfsHealth, health)
+		if err != nil {
+			log.Errorf("build ufs health patch %+v err: %v", health, err)
+			continue
+		}
+		if err = k8sClient.PatchPod(podNamespace, podName, data); err != nil {
+			log.Errorf("patch mount pod[%s] ufs health %+v err: %v", podName, health, err)
+			continue
+		}
+		last = &health
+	}
+}
+
+// listMountPoint reads an entry of the root of mount point, which goes through fuse to ufs
+var listMountPoint = func(mountPoint string) error {
+	fd, err := os.Open(mountPoint)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	_, err = fd.Readdirnames(1)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+type ufsProber struct {
+	mountPoint string
+	timeout    time.Duration
+	probing    int32
+}
+
+// probe waits for listing of mount point until timeout. a probe hanging on unreachable ufs is not waited for again,
+// and no new probe is started until it returns.
+func (p *ufsProber) probe() UfsHealth {
+	if !atomic.CompareAndSwapInt32(&p.probing, 0, 1) {
+		return UfsHealth{Message: fmt.Sprintf("list mount point %s has been hanging for more than %s", p.mountPoint, p.timeout)}
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		defer atomic.StoreInt32(&p.probing, 0)
+		errCh <- listMountPoint(p.mountPoint)
+	}()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return UfsHealth{Message: fmt.Sprintf("list mount point %s failed: %v", p.mountPoint, err)}
+		}
+		return UfsHealth{Healthy: true}
+	case <-time.After(p.timeout):
+		return UfsHealth{Message: fmt.Sprintf("list mount point %s timeout after %s", p.mountPoint, p.timeout)}
+	}
+}
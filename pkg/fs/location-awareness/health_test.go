@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package location_awareness
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUfsProber(t *testing.T) {
+	mountPoint := "./mock-health"
+	defer os.RemoveAll(mountPoint)
+	assert.Nil(t, os.MkdirAll(mountPoint, 0755))
+
+	prober := &ufsProber{mountPoint: mountPoint, timeout: 100 * time.Millisecond}
+	assert.Equal(t, UfsHealth{Healthy: true}, prober.probe())
+
+	prober.mountPoint = "./not-exist"
+	health := prober.probe()
+	assert.False(t, health.Healthy)
+	assert.Contains(t, health.Message, "failed")
+
+	// probe hanging on ufs times out, and is not started again before it returns
+	release := make(chan struct{})
+	origin := listMountPoint
+	listMountPoint = func(string) error {
+		<-release
+		return nil
+	}
+	defer func() { listMountPoint = origin }()
+	health = prober.probe()
+	assert.False(t, health.Healthy)
+	assert.Contains(t, health.Message, "timeout")
+	health = prober.probe()
+	assert.False(t, health.Healthy)
+	assert.Contains(t, health.Message, "hanging")
+
+	close(release)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, UfsHealth{Healthy: true}, prober.probe())
+}
+
+func TestParseUfsHealth(t *testing.T) {
+	health, err := ParseUfsHealth(`{"healthy":false,"message":"list mount point failed"}`)
+	assert.Nil(t, err)
+	assert.False(t, health.Healthy)
+	assert.Equal(t, "list mount point failed", health.Message)
+
+	_, err = ParseUfsHealth("healthy")
+	assert.NotNil(t, err)
+}
@@ -18,6 +18,7 @@ package location_awareness
 
 import (
 	"fmt"
+	"strconv"
 
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -88,3 +89,88 @@ func FsNodeAffinity(fsIDs []string) (*corev1.Affinity, error) {
 	}
 	return &corev1.Affinity{NodeAffinity: nodeAffinity}, nil
 }
+
+// NodeSelected reports whether the node is selected by node affinity of fs cache config.
+// required terms are used if set, otherwise preferred terms are used. all nodes are selected if neither is set.
+func NodeSelected(node *corev1.Node, affinity corev1.NodeAffinity) bool {
+	var terms []corev1.NodeSelectorTerm
+	if affinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		terms = affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	}
+	if len(terms) == 0 {
+		for _, preferred := range affinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			if preferred.Weight > 0 {
+				terms = append(terms, preferred.Preference)
+			}
+		}
+	}
+	if len(terms) == 0 {
+		return true
+	}
+	// terms are ORed
+	for _, term := range terms {
+		if nodeMatchTerm(node, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeMatchTerm(node *corev1.Node, term corev1.NodeSelectorTerm) bool {
+	// an empty term matches no node
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, req := range term.MatchExpressions {
+		if !matchRequirement(node.Labels, req) {
+			return false
+		}
+	}
+	for _, req := range term.MatchFields {
+		// metadata.name is the only field supported by kubernetes
+		if req.Key != "metadata.name" || !matchRequirement(map[string]string{req.Key: node.Name}, req) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchRequirement(labels map[string]string, req corev1.NodeSelectorRequirement) bool {
+	value, ok := labels[req.Key]
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		return ok && containsValue(req.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !ok || !containsValue(req.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return ok
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !ok
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !ok || len(req.Values) != 1 {
+			return false
+		}
+		labelValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		reqValue, err := strconv.ParseInt(req.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if req.Operator == corev1.NodeSelectorOpGt {
+			return labelValue > reqValue
+		}
+		return labelValue < reqValue
+	}
+	return false
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package location_awareness
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/panjf2000/ants/v2"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/utils"
+)
+
+const (
+	WarmupTypeMeta = "meta"
+	WarmupTypeData = "data"
+
+	WarmupPending   = "pending"
+	WarmupRunning   = "running"
+	WarmupSucceeded = "succeeded"
+	WarmupFailed    = "failed"
+
+	DefaultWarmupThreads = 50
+	MaxWarmupThreads     = 500
+
+	warmupPollInterval   = 5 * time.Second
+	warmupReportInterval = 10 * time.Second
+)
+
+// WarmupTask is dispatched by server to mount pods through pod annotation.
+// paths and manifest are relative to the root of file system.
+type WarmupTask struct {
+	ID       string   `json:"id"`
+	Paths    []string `json:"paths,omitempty"`
+	Manifest string   `json:"manifest,omitempty"`
+	Type     string   `json:"type"`
+	Threads  int      `json:"threads"`
+}
+
+// WarmupStatus is reported by cache-worker through pod annotation and synced to fs cache by server
+type WarmupStatus struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Total   int64  `json:"total"`
+	Done    int64  `json:"done"`
+	Failed  int64  `json:"failed"`
+	Bytes   int64  `json:"bytes"`
+	Message string `json:"message,omitempty"`
+}
+
+func ParseWarmupTask(value string) (*WarmupTask, error) {
+	task := &WarmupTask{}
+	if err := json.Unmarshal([]byte(value), task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func ParseWarmupStatus(value string) (*WarmupStatus, error) {
+	status := &WarmupStatus{}
+	if err := json.Unmarshal([]byte(value), status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+type annotationPatch struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+// AnnotationPatch builds json patch setting a single annotation, leaving other annotations such as mount refs untouched
+func AnnotationPatch(key string, value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	// "/" and "~" in key should be escaped in json pointer
+	key = strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+	return json.Marshal([]annotationPatch{{
+		Op:    "add",
+		Path:  "/metadata/annotations/" + key,
+		Value: string(data),
+	}})
+}
+
+// WarmupLoop polls warmup task of the mount pod and warms up paths on the mount point one task at a time
+func WarmupLoop(k8sClient utils.Client, podNamespace, podName, mountPoint string) {
+	for {
+		time.Sleep(warmupPollInterval)
+		pod, err := k8sClient.GetPod(podNamespace, podName)
+		if err != nil {
+			log.Errorf("Can't get mount pod %s: %v", podName, err)
+			continue
+		}
+		task := pendingWarmupTask(pod)
+		if task == nil {
+			continue
+		}
+		w := &warmer{
+			k8sClient:    k8sClient,
+			podNamespace: podNamespace,
+			podName:      podName,
+			mountPoint:   mountPoint,
+			task:         task,
+		}
+		w.run()
+	}
+}
+
+func pendingWarmupTask(pod *corev1.Pod) *WarmupTask {
+	value := pod.Annotations[schema.AnnotationKeyWarmup]
+	if value == "" {
+		return nil
+	}
+	task, err := ParseWarmupTask(value)
+	if err != nil {
+		log.Errorf("mount pod[%s] warmup task[%s] is not valid: %v", pod.Name, value, err)
+		return nil
+	}
+	if value = pod.Annotations[schema.AnnotationKeyWarmupStatus]; value != "" {
+		status, err := ParseWarmupStatus(value)
+		// a running task of same id is left by last cache-worker, run it again
+		if err == nil && status.ID == task.ID && status.Status != WarmupRunning {
+			return nil
+		}
+	}
+	return task
+}
+
+type warmer struct {
+	k8sClient    utils.Client
+	podNamespace string
+	podName      string
+	mountPoint   string
+	task         *WarmupTask
+
+	total  int64
+	done   int64
+	failed int64
+	bytes  int64
+}
+
+func (w *warmer) run() {
+	log.Infof("warmup task %+v started", *w.task)
+	w.report(WarmupRunning, "")
+	paths, err := w.listPaths()
+	if err != nil {
+		log.Errorf("warmup task[%s] list paths failed: %v", w.task.ID, err)
+		w.report(WarmupFailed, err.Error())
+		return
+	}
+	atomic.StoreInt64(&w.total, int64(len(paths)))
+
+	threads := w.task.Threads
+	if threads <= 0 {
+		threads = DefaultWarmupThreads
+	}
+	pool, err := ants.NewPool(threads)
+	if err != nil {
+		w.report(WarmupFailed, err.Error())
+		return
+	}
+	defer pool.Release()
+
+	stopCh := make(chan struct{})
+	go w.reportLoop(stopCh)
+	var wg sync.WaitGroup
+	for _, path := range paths {
+		path_ := path
+		wg.Add(1)
+		if err = pool.Submit(func() {
+			defer wg.Done()
+			w.warmup(path_)
+		}); err != nil {
+			wg.Done()
+			atomic.AddInt64(&w.failed, 1)
+		}
+	}
+	wg.Wait()
+	close(stopCh)
+
+	if failed := atomic.LoadInt64(&w.failed); failed > 0 {
+		w.report(WarmupFailed, fmt.Sprintf("%d of %d paths failed to warm up", failed, len(paths)))
+	} else {
+		w.report(WarmupSucceeded, "")
+	}
+	log.Infof("warmup task[%s] finished, done %d failed %d bytes %d", w.task.ID,
+		atomic.LoadInt64(&w.done), atomic.LoadInt64(&w.failed), atomic.LoadInt64(&w.bytes))
+}
+
+// fsPath converts path of file system to path on the mount point, paths out of file system are not allowed
+func (w *warmer) fsPath(path string) string {
+	return filepath.Join(w.mountPoint, filepath.Clean("/"+path))
+}
+
+// listPaths returns paths of the task and in the manifest, directories are walked recursively.
+// only regular files are returned for data warmup.
+func (w *warmer) listPaths() ([]string, error) {
+	roots := w.task.Paths
+	if w.task.Manifest != "" {
+		fd, err := os.Open(w.fsPath(w.task.Manifest))
+		if err != nil {
+			return nil, fmt.Errorf("open manifest %s failed: %v", w.task.Manifest, err)
+		}
+		defer fd.Close()
+		scanner := bufio.NewScanner(fd)
+		for scanner.Scan() {
+			if p := strings.TrimSpace(scanner.Text()); p != "" {
+				roots = append(roots, p)
+			}
+		}
+		if err = scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read manifest %s failed: %v", w.task.Manifest, err)
+		}
+	}
+
+	var paths []string
+	for _, root := range roots {
+		_ = filepath.Walk(w.fsPath(root), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				// keep the path to count it as failed
+				log.Errorf("warmup walk path %s failed: %v", path, err)
+				paths = append(paths, path)
+				return nil
+			}
+			if w.task.Type == WarmupTypeMeta || info.Mode().IsRegular() {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+	}
+	return paths, nil
+}
+
+func (w *warmer) warmup(path string) {
+	var err error
+	if w.task.Type == WarmupTypeMeta {
+		_, err = os.Stat(path)
+	} else {
+		err = w.warmupData(path)
+	}
+	if err != nil {
+		log.Errorf("warmup %s failed: %v", path, err)
+		atomic.AddInt64(&w.failed, 1)
+		return
+	}
+	atomic.AddInt64(&w.done, 1)
+}
+
+func (w *warmer) warmupData(path string) error {
+	fh, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	n, err := io.Copy(ioutil.Discard, fh)
+	atomic.AddInt64(&w.bytes, n)
+	return err
+}
+
+func (w *warmer) reportLoop(stopCh chan struct{}) {
+	ticker := time.NewTicker(warmupReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			w.report(WarmupRunning, "")
+		}
+	}
+}
+
+func (w *warmer) report(status, message string) {
+	warmupStatus := WarmupStatus{
+		ID:      w.task.ID,
+		Status:  status,
+		Total:   atomic.LoadInt64(&w.total),
+		Done:    atomic.LoadInt64(&w.done),
+		Failed:  atomic.LoadInt64(&w.failed),
+		Bytes:   atomic.LoadInt64(&w.bytes),
+		Message: message,
+	}
+	data, err := AnnotationPatch(schema.AnnotationKeyWarmupStatus, warmupStatus)
+	if err != nil {
+		log.Errorf("build warmup status patch %+v err: %v", warmupStatus, err)
+		return
+	}
+	if err = w.k8sClient.PatchPod(w.podNamespace, w.podName, data); err != nil {
+		log.Errorf("patch mount pod[%s] warmup status %+v err: %v", w.podName, warmupStatus, err)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package location_awareness
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/utils"
+)
+
+func TestNodeSelected(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node1",
+			Labels: map[string]string{"zone": "a", "gpu": "8"},
+		},
+	}
+	// no affinity selects all nodes
+	assert.True(t, NodeSelected(node, v1.NodeAffinity{}))
+
+	required := func(reqs ...v1.NodeSelectorRequirement) v1.NodeAffinity {
+		return v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+				NodeSelectorTerms: []v1.NodeSelectorTerm{{MatchExpressions: reqs}},
+			},
+		}
+	}
+	assert.True(t, NodeSelected(node, required(v1.NodeSelectorRequirement{
+		Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a", "b"}})))
+	assert.False(t, NodeSelected(node, required(v1.NodeSelectorRequirement{
+		Key: "zone", Operator: v1.NodeSelectorOpNotIn, Values: []string{"a"}})))
+	assert.True(t, NodeSelected(node, required(v1.NodeSelectorRequirement{
+		Key: "gpu", Operator: v1.NodeSelectorOpGt, Values: []string{"4"}})))
+	assert.False(t, NodeSelected(node, required(
+		v1.NodeSelectorRequirement{Key: "zone", Operator: v1.NodeSelectorOpExists},
+		v1.NodeSelectorRequirement{Key: "ssd", Operator: v1.NodeSelectorOpExists})))
+
+	// preferred terms are used if no required term
+	preferred := v1.NodeAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{{
+			Weight: 10,
+			Preference: v1.NodeSelectorTerm{
+				MatchFields: []v1.NodeSelectorRequirement{{
+					Key: "metadata.name", Operator: v1.NodeSelectorOpIn, Values: []string{"node2"}}},
+			},
+		}},
+	}
+	assert.False(t, NodeSelected(node, preferred))
+}
+
+func TestAnnotationPatch(t *testing.T) {
+	data, err := AnnotationPatch("a/b", WarmupStatus{ID: "warmup-1", Status: WarmupRunning})
+	assert.Nil(t, err)
+	var patches []annotationPatch
+	assert.Nil(t, json.Unmarshal(data, &patches))
+	assert.Equal(t, 1, len(patches))
+	assert.Equal(t, "/metadata/annotations/a~1b", patches[0].Path)
+	status, err := ParseWarmupStatus(patches[0].Value)
+	assert.Nil(t, err)
+	assert.Equal(t, "warmup-1", status.ID)
+}
+
+func TestPendingWarmupTask(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+	assert.Nil(t, pendingWarmupTask(pod))
+
+	pod.Annotations[schema.AnnotationKeyWarmup] = `{"id":"warmup-1","paths":["/data"],"type":"data"}`
+	task := pendingWarmupTask(pod)
+	assert.NotNil(t, task)
+	assert.Equal(t, []string{"/data"}, task.Paths)
+
+	// task left running by last cache-worker is run again
+	pod.Annotations[schema.AnnotationKeyWarmupStatus] = `{"id":"warmup-1","status":"running"}`
+	assert.NotNil(t, pendingWarmupTask(pod))
+	pod.Annotations[schema.AnnotationKeyWarmupStatus] = `{"id":"warmup-1","status":"succeeded"}`
+	assert.Nil(t, pendingWarmupTask(pod))
+}
+
+func TestWarmer(t *testing.T) {
+	mountPoint := "./mock-warmup"
+	defer os.RemoveAll(mountPoint)
+	assert.Nil(t, os.MkdirAll(filepath.Join(mountPoint, "data/sub"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(mountPoint, "data/a"), []byte("12345"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(mountPoint, "data/sub/b"), []byte("123"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(mountPoint, "manifest"), []byte("data/sub\n\nnot-exist\n"), 0644))
+
+	k8sClient := utils.GetFakeK8sClient()
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pfs-node1-warmup",
+			Namespace:   schema.MountPodNamespace,
+			Annotations: map[string]string{schema.AnnotationKeyCacheDir: "/var/cache"},
+		},
+	}
+	_, err := k8sClient.CreatePod(pod)
+	assert.Nil(t, err)
+
+	w := &warmer{
+		k8sClient:    k8sClient,
+		podNamespace: pod.Namespace,
+		podName:      pod.Name,
+		mountPoint:   mountPoint,
+		task: &WarmupTask{
+			ID:       "warmup-1",
+			Paths:    []string{"/data/a", "../../data"},
+			Manifest: "manifest",
+			Type:     WarmupTypeData,
+		},
+	}
+	w.run()
+
+	pod, err = k8sClient.GetPod(pod.Namespace, pod.Name)
+	assert.Nil(t, err)
+	status, err := ParseWarmupStatus(pod.Annotations[schema.AnnotationKeyWarmupStatus])
+	assert.Nil(t, err)
+	assert.Equal(t, "warmup-1", status.ID)
+	assert.Equal(t, WarmupFailed, status.Status)
+	// data/a, data/a, data/sub/b, data/sub/b and not-exist
+	assert.Equal(t, int64(5), status.Total)
+	assert.Equal(t, int64(4), status.Done)
+	assert.Equal(t, int64(1), status.Failed)
+	assert.Equal(t, int64(16), status.Bytes)
+}
@@ -39,6 +39,7 @@ type Client interface {
 	ProxyGetPods(nodeID string) (result *corev1.PodList, err error)
 	CreatePod(pod *corev1.Pod) (*corev1.Pod, error)
 	GetPod(namespace, name string) (*corev1.Pod, error)
+	ListPods(namespace string, listOptions metav1.ListOptions) (*corev1.PodList, error)
 	PatchPod(namespace, name string, data []byte) error
 	UpdatePod(namespace string, pod *corev1.Pod) (*corev1.Pod, error)
 	DeletePod(pod *corev1.Pod) error
@@ -159,6 +160,10 @@ func (c *k8sClient) GetPod(namespace, name string) (*corev1.Pod, error) {
 	return mntPod, nil
 }
 
+func (c *k8sClient) ListPods(namespace string, listOptions metav1.ListOptions) (*corev1.PodList, error) {
+	return c.CoreV1().Pods(namespace).List(context.TODO(), listOptions)
+}
+
 type PatchMapValue struct {
 	Op    string            `json:"op"`
 	Path  string            `json:"path"`
@@ -222,7 +222,7 @@ func GetRootToken(ctx *logger.RequestContext) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	token, err := middleware.GenerateToken(u.Name, u.Password)
+	token, err := middleware.GenerateToken(u.Name)
 	if err != nil {
 		return "", err
 	}
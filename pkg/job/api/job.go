@@ -18,6 +18,7 @@ package api
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -70,6 +71,8 @@ type PFJob struct {
 	// Labels for job to update
 	Labels      map[string]string
 	Annotations map[string]string
+	// Replicas of tasks to scale, and the key is role of task
+	Replicas map[schema.MemberRole]int
 
 	// extend field
 	Tags   []string
@@ -79,6 +82,8 @@ type PFJob struct {
 	CreateTime  time.Time
 	StartTime   time.Time
 	EndTIme     time.Time
+	// Deadline of job, zero means job has no deadline
+	Deadline time.Time
 }
 
 func NewJobInfo(job *model.Job) (*PFJob, error) {
@@ -103,11 +108,45 @@ func NewJobInfo(job *model.Job) (*PFJob, error) {
 		Resource:          job.Resource,
 		Tasks:             job.Members,
 		ExtensionTemplate: []byte(job.ExtensionTemplate),
+		Priority:          JobPriorityValue(job.Config.GetPriority()),
+		CreateTime:        job.CreatedAt,
+		Deadline:          getJobDeadline(job.Config.GetEnvValue(schema.EnvJobDeadline)),
 	}
 	log.Debugf("gererated pfjob is: %#v", pfjob)
 	return pfjob, nil
 }
 
+// JobPriorityValue convert priority of job to int value, job with larger value has higher priority
+func JobPriorityValue(priority string) int32 {
+	switch priority {
+	case schema.EnvJobVeryLowPriority, schema.PriorityClassVeryLow:
+		return 1
+	case schema.EnvJobLowPriority, schema.PriorityClassLow:
+		return 2
+	case schema.EnvJobHighPriority, schema.PriorityClassHigh:
+		return 4
+	case schema.EnvJobVeryHighPriority, schema.PriorityClassVeryHigh:
+		return 5
+	default:
+		return 3
+	}
+}
+
+// getJobDeadline parse deadline of job, which is in RFC3339 format or unix seconds
+func getJobDeadline(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	if deadline, err := time.Parse(time.RFC3339, value); err == nil {
+		return deadline
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+		return time.Unix(seconds, 0)
+	}
+	log.Warningf("deadline %s of job is invalid, ignore it", value)
+	return time.Time{}
+}
+
 func (pfj *PFJob) NamespacedName() string {
 	return fmt.Sprintf("%s/%s", pfj.Namespace, pfj.ID)
 }
@@ -130,6 +169,13 @@ func (pfj *PFJob) UpdateJobPriority(priorityClassName string) {
 	pfj.PriorityClassName = priorityClassName
 }
 
+func (pfj *PFJob) UpdateReplicas(role schema.MemberRole, replicas int) {
+	if pfj.Replicas == nil {
+		pfj.Replicas = make(map[schema.MemberRole]int)
+	}
+	pfj.Replicas[role] = replicas
+}
+
 func (pfj *PFJob) GetID() string {
 	return pfj.ID
 }
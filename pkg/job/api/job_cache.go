@@ -50,6 +50,9 @@ func (qj *JobQueue) Insert(job *PFJob) {
 		defer qj.Unlock()
 		if _, exist := qj.jobExist.Load(job.ID); !exist {
 			qj.jobExist.Store(job.ID, struct{}{})
+			if qj.Queue != nil {
+				qj.Queue.OnJobEnqueued(job)
+			}
 			qj.Jobs.Push(job)
 		}
 	}
@@ -62,7 +65,11 @@ func (qj *JobQueue) GetJob() (*PFJob, bool) {
 		if qj.Jobs.Empty() {
 			return nil, false
 		} else {
-			return qj.Jobs.Pop().(*PFJob), true
+			job := qj.Jobs.Pop().(*PFJob)
+			if qj.Queue != nil {
+				qj.Queue.OnJobDequeued(job)
+			}
+			return job, true
 		}
 	}
 	return nil, false
@@ -71,7 +71,7 @@ func NewQueueInfo(q model.Queue) *QueueInfo {
 		Type:            q.QuotaType,
 		Status:          q.Status,
 		SortPolicyNames: q.SchedulingPolicy,
-		SortPolicies:    NewRegistry(q.SchedulingPolicy),
+		SortPolicies:    NewRegistry(q.Name, q.SchedulingPolicy),
 		MaxResources:    q.MaxResources,
 		MinResources:    q.MinResources,
 		Location:        q.Location,
@@ -94,6 +94,24 @@ func (q *QueueInfo) JobOrderFn(l, r interface{}) bool {
 	return lv.CreateTime.Before(rv.CreateTime)
 }
 
+// OnJobEnqueued notify sort policies of queue that the job is enqueued
+func (q *QueueInfo) OnJobEnqueued(job *PFJob) {
+	for _, policy := range q.SortPolicies {
+		if handler, ok := policy.(JobQueueHandler); ok {
+			handler.OnJobEnqueued(job)
+		}
+	}
+}
+
+// OnJobDequeued notify sort policies of queue that the job is dequeued
+func (q *QueueInfo) OnJobDequeued(job *PFJob) {
+	for _, policy := range q.SortPolicies {
+		if handler, ok := policy.(JobQueueHandler); ok {
+			handler.OnJobDequeued(job)
+		}
+	}
+}
+
 // QueueSyncInfo contains queue sync info
 type QueueSyncInfo struct {
 	Name        string
@@ -113,9 +131,19 @@ type SortPolicy interface {
 	OrderFn(interface{}, interface{}) int
 }
 
+// JobQueueHandler is an optional interface for stateful sort policy, which needs to know the enqueued and dequeued jobs
+type JobQueueHandler interface {
+	OnJobEnqueued(job *PFJob)
+	OnJobDequeued(job *PFJob)
+}
+
 // Arguments map
 type Arguments map[string]string
 
+// ArgumentQueueName is the argument of queue name, which is given to sort policies of queue.
+// Stateful sort policy keeps its state by queue name, so the state survives rebuilding of QueueInfo.
+const ArgumentQueueName = "queueName"
+
 // PolicyFactory is a function that builds a sort policy.
 type PolicyFactory = func(configuration Arguments) (SortPolicy, error)
 
@@ -146,7 +174,7 @@ func (r Registry) Unregister(name string) error {
 var QueueSortPolicies = make(Registry)
 
 // NewRegistry registry sort policy for queue
-func NewRegistry(policyNames []string) []SortPolicy {
+func NewRegistry(queueName string, policyNames []string) []SortPolicy {
 	var policies []SortPolicy
 
 	for _, name := range policyNames {
@@ -155,7 +183,7 @@ func NewRegistry(policyNames []string) []SortPolicy {
 			logrus.Warningf("queue sort policy[%s] is not found.", name)
 			continue
 		}
-		policy, err := policyNew(Arguments{})
+		policy, err := policyNew(Arguments{ArgumentQueueName: queueName})
 		if err != nil {
 			logrus.Warningf("new sort policy[%s] failed, err: %v", name, err)
 			continue
@@ -27,6 +27,7 @@ import (
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+	_ "github.com/PaddlePaddle/PaddleFlow/pkg/job/queue/sortpolicy"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/metrics"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
@@ -159,6 +160,47 @@ func (m *JobManagerImpl) pJobProcessLoop() {
 	}
 }
 
+func (m *JobManagerImpl) pJobMaxRunTimeLoop() {
+	log.Infof("start job max run time loop ...")
+	for {
+		m.terminateTimeoutJobs(time.Now())
+		time.Sleep(m.jobLoopPeriod)
+	}
+}
+
+// terminateTimeoutJobs stop the running jobs whose run time exceeds max run time, and mark them terminating,
+// the terminated status is updated by job sync when the jobs are stopped on cluster
+func (m *JobManagerImpl) terminateTimeoutJobs(now time.Time) {
+	jobs := storage.Job.ListTimeoutJobs(now)
+	for idx := range jobs {
+		job := &jobs[idx]
+		if job.Config == nil {
+			continue
+		}
+		maxRunTime := time.Duration(job.Config.GetMaxRunTime()) * time.Second
+		clusterID := api.ClusterID(job.Config.GetClusterID())
+		cRuntime, find := m.clusterRuntimes.Get(clusterID)
+		if !find || cRuntime == nil {
+			log.Warnf("get runtime of cluster %s for job %s failed, skip it", clusterID, job.ID)
+			continue
+		}
+		pfJob, err := api.NewJobInfo(job)
+		if err != nil {
+			continue
+		}
+		log.Infof("job %s runs out of max run time %s, begin to stop it", job.ID, maxRunTime)
+		if err = cRuntime.RuntimeSvc.StopJob(pfJob); err != nil {
+			log.Errorf("stop job %s on cluster failed, err: %v", job.ID, err)
+			continue
+		}
+		msg := fmt.Sprintf("job is terminating, as its run time exceeds max run time %s", maxRunTime)
+		trace_logger.KeyWithUpdate(job.ID).Infof(msg)
+		if err = storage.Job.UpdateJobStatus(job.ID, msg, schema.StatusJobTerminating); err != nil {
+			log.Errorf("update job[%s] status to [%s] failed, err: %v", job.ID, schema.StatusJobTerminating, err)
+		}
+	}
+}
+
 func (m *JobManagerImpl) pSubmitQueueJob(jobQueue *api.JobQueue, clusterRuntime *ClusterRuntimeInfo) {
 	if jobQueue == nil || clusterRuntime == nil {
 		log.Infof("exit submit job loop, as jobQueue or clusterRuntime is nil")
@@ -257,6 +299,8 @@ func (m *JobManagerImpl) startRuntime() {
 	log.Infof("Start job manager on runtime v2!")
 	// submit job to cluster
 	go m.pJobProcessLoop()
+	// terminate jobs which run out of max run time
+	go m.pJobMaxRunTimeLoop()
 
 	for {
 		// get active clusters
@@ -106,3 +106,78 @@ func TestJobManager(t *testing.T) {
 		})
 	}
 }
+
+func TestTerminateTimeoutJobs(t *testing.T) {
+	config.GlobalServerConfig = &config.ServerConfig{}
+	driver.InitMockDB()
+
+	runningJobs := []*model.Job{
+		{
+			ID:     "job-timeout",
+			Status: schema.StatusJobPending,
+			Config: &schema.Conf{
+				ClusterID:  mockClusterID,
+				MaxRunTime: 60,
+			},
+		},
+		{
+			ID:     "job-in-time",
+			Status: schema.StatusJobPending,
+			Config: &schema.Conf{
+				ClusterID:  mockClusterID,
+				MaxRunTime: 600,
+			},
+		},
+		{
+			ID:     "job-no-limit",
+			Status: schema.StatusJobPending,
+			Config: &schema.Conf{
+				ClusterID: mockClusterID,
+			},
+		},
+	}
+	for _, job := range runningJobs {
+		err := storage.Job.CreateJob(job)
+		assert.Equal(t, nil, err)
+		// deadline is set when job becomes running
+		_, err = storage.Job.UpdateJob(job.ID, schema.StatusJobRunning, nil, nil, "")
+		assert.Equal(t, nil, err)
+	}
+
+	rts := &runtime.KubeRuntime{}
+	var stoppedJobs []string
+	var p1 = gomonkey.ApplyMethod(reflect.TypeOf(rts), "StopJob", func(_ *runtime.KubeRuntime, job *api.PFJob) error {
+		stoppedJobs = append(stoppedJobs, job.ID)
+		return nil
+	})
+	defer p1.Reset()
+
+	jobM, err := NewJobManagerImpl()
+	assert.Equal(t, nil, err)
+	jobM.clusterRuntimes.Store(mockClusterID, NewClusterRuntimeInfo("test-cluster", rts))
+	now := time.Now().Add(2 * time.Minute)
+	jobM.terminateTimeoutJobs(now)
+
+	assert.Equal(t, []string{"job-timeout"}, stoppedJobs)
+	job, err := storage.Job.GetJobByID("job-timeout")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, schema.StatusJobTerminating, job.Status)
+	assert.Contains(t, job.Message, "max run time")
+	job, err = storage.Job.GetJobByID("job-in-time")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, schema.StatusJobRunning, job.Status)
+
+	// terminating job is not stopped again, and max run time updated takes effect
+	job.Config.SetMaxRunTime(60)
+	err = storage.Job.UpdateJobConfig(job.ID, job.Config)
+	assert.Equal(t, nil, err)
+	jobM.terminateTimeoutJobs(now)
+	assert.Equal(t, []string{"job-timeout", "job-in-time"}, stoppedJobs)
+
+	// terminated status is updated by job sync
+	_, err = storage.Job.UpdateJob("job-timeout", schema.StatusJobTerminated, nil, nil, "")
+	assert.Equal(t, nil, err)
+	job, err = storage.Job.GetJobByID("job-timeout")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, schema.StatusJobTerminated, job.Status)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sortpolicy
+
+import (
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+)
+
+// DeadlinePolicyName indicates the name of earliest-deadline-first sort policy,
+// jobs without deadline are ordered after the jobs with deadline.
+const DeadlinePolicyName = "deadline"
+
+type deadlinePolicy struct {
+	// Arguments given for the sort policy
+	policyArguments api.Arguments
+}
+
+// DeadlinePolicyNew return deadline sort policy
+func DeadlinePolicyNew(arguments api.Arguments) (api.SortPolicy, error) {
+	return &deadlinePolicy{
+		policyArguments: arguments,
+	}, nil
+}
+
+func (dp *deadlinePolicy) Name() string {
+	return DeadlinePolicyName
+}
+
+func (dp *deadlinePolicy) OrderFn(l, r interface{}) int {
+	lv := l.(*api.PFJob)
+	rv := r.(*api.PFJob)
+
+	switch {
+	case lv.Deadline.IsZero() && rv.Deadline.IsZero():
+		return 0
+	case lv.Deadline.IsZero():
+		return 1
+	case rv.Deadline.IsZero():
+		return -1
+	case lv.Deadline.Before(rv.Deadline):
+		return -1
+	case lv.Deadline.After(rv.Deadline):
+		return 1
+	}
+
+	return 0
+}
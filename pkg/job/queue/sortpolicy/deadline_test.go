@@ -0,0 +1,77 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sortpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+)
+
+func TestQueueDeadline(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name string
+		l    *api.PFJob
+		r    *api.PFJob
+		ans  int
+	}{
+		{
+			name: "left deadline is earlier than right",
+			l:    &api.PFJob{Deadline: now},
+			r:    &api.PFJob{Deadline: now.Add(time.Hour)},
+			ans:  -1,
+		},
+		{
+			name: "left deadline is later than right",
+			l:    &api.PFJob{Deadline: now.Add(time.Hour)},
+			r:    &api.PFJob{Deadline: now},
+			ans:  1,
+		},
+		{
+			name: "left has no deadline",
+			l:    &api.PFJob{},
+			r:    &api.PFJob{Deadline: now},
+			ans:  1,
+		},
+		{
+			name: "right has no deadline",
+			l:    &api.PFJob{Deadline: now},
+			r:    &api.PFJob{},
+			ans:  -1,
+		},
+		{
+			name: "both have no deadline",
+			l:    &api.PFJob{},
+			r:    &api.PFJob{},
+			ans:  0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sp, err := DeadlinePolicyNew(api.Arguments{})
+			t.Logf("run %s", sp.Name())
+			assert.Equal(t, nil, err)
+			ans := sp.OrderFn(tc.l, tc.r)
+			assert.Equal(t, tc.ans, ans)
+		})
+	}
+}
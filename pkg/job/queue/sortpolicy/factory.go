@@ -22,4 +22,7 @@ import (
 
 func init() {
 	api.QueueSortPolicies.Register(PriorityPolicyName, PriorityPolicyNew)
+	api.QueueSortPolicies.Register(FIFOPolicyName, FIFOPolicyNew)
+	api.QueueSortPolicies.Register(FairSharePolicyName, FairSharePolicyNew)
+	api.QueueSortPolicies.Register(DeadlinePolicyName, DeadlinePolicyNew)
 }
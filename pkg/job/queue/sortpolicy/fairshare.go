@@ -0,0 +1,150 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sortpolicy
+
+import (
+	"sync"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+)
+
+// FairSharePolicyName indicates the name of per-user fair-share sort policy.
+//
+// Each job gets a virtual start tag when it is enqueued, which is
+// max(virtual time of queue, next tag of its user), and jobs with smaller tag are dequeued first.
+// So jobs of different users are dequeued in turn, and a user who submits lots of jobs can not
+// starve other users. The tag of job is never changed once assigned, which keeps the heap of
+// queue consistent. The tags are kept by queue name, so they survive rebuilding of QueueInfo,
+// and the tag of job is released when the job is dequeued or completed.
+const FairSharePolicyName = "fair-share"
+
+// fairShareUsage is the usage of users in a queue
+type fairShareUsage struct {
+	mutex sync.Mutex
+	// virtualTime is the largest tag of dequeued jobs
+	virtualTime uint64
+	// userNextTag records the next tag for each user
+	userNextTag map[string]uint64
+	// jobTags records the tag of jobs in queue
+	jobTags map[string]uint64
+}
+
+// fairShareUsages records usage of each queue by queue name
+var fairShareUsages = struct {
+	sync.Mutex
+	usages map[string]*fairShareUsage
+}{usages: make(map[string]*fairShareUsage)}
+
+func getFairShareUsage(queueName string) *fairShareUsage {
+	fairShareUsages.Lock()
+	defer fairShareUsages.Unlock()
+	usage, ok := fairShareUsages.usages[queueName]
+	if !ok {
+		usage = &fairShareUsage{
+			userNextTag: make(map[string]uint64),
+			jobTags:     make(map[string]uint64),
+		}
+		fairShareUsages.usages[queueName] = usage
+	}
+	return usage
+}
+
+// ReleaseJob releases the tag of completed job, the job may be completed before it is dequeued,
+// e.g. the job is stopped before submitted to cluster.
+func ReleaseJob(jobID string) {
+	fairShareUsages.Lock()
+	defer fairShareUsages.Unlock()
+	for _, usage := range fairShareUsages.usages {
+		usage.mutex.Lock()
+		delete(usage.jobTags, jobID)
+		usage.mutex.Unlock()
+	}
+}
+
+type fairSharePolicy struct {
+	// Arguments given for the sort policy
+	policyArguments api.Arguments
+
+	usage *fairShareUsage
+}
+
+// FairSharePolicyNew return fair-share sort policy
+func FairSharePolicyNew(arguments api.Arguments) (api.SortPolicy, error) {
+	return &fairSharePolicy{
+		policyArguments: arguments,
+		usage:           getFairShareUsage(arguments[api.ArgumentQueueName]),
+	}, nil
+}
+
+func (fp *fairSharePolicy) Name() string {
+	return FairSharePolicyName
+}
+
+func (fp *fairSharePolicy) OrderFn(l, r interface{}) int {
+	lv := l.(*api.PFJob)
+	rv := r.(*api.PFJob)
+
+	fp.usage.mutex.Lock()
+	defer fp.usage.mutex.Unlock()
+	// tag of released job is 0, so the job is dequeued and skipped soon
+	lTag := fp.usage.jobTags[lv.ID]
+	rTag := fp.usage.jobTags[rv.ID]
+
+	if lTag < rTag {
+		return -1
+	}
+
+	if lTag > rTag {
+		return 1
+	}
+
+	return 0
+}
+
+// OnJobEnqueued assign tag for the job
+func (fp *fairSharePolicy) OnJobEnqueued(job *api.PFJob) {
+	fp.usage.mutex.Lock()
+	defer fp.usage.mutex.Unlock()
+	fp.usage.assignJobTag(job)
+}
+
+// OnJobDequeued advance virtual time of queue, and release the tag of job
+func (fp *fairSharePolicy) OnJobDequeued(job *api.PFJob) {
+	fp.usage.mutex.Lock()
+	defer fp.usage.mutex.Unlock()
+	tag, ok := fp.usage.jobTags[job.ID]
+	if !ok {
+		return
+	}
+	if tag > fp.usage.virtualTime {
+		fp.usage.virtualTime = tag
+	}
+	delete(fp.usage.jobTags, job.ID)
+}
+
+// assignJobTag assign tag for job, tag of job enqueued again, e.g. after the queue is rebuilt, is kept
+func (u *fairShareUsage) assignJobTag(job *api.PFJob) {
+	if _, ok := u.jobTags[job.ID]; ok {
+		return
+	}
+	tag := u.userNextTag[job.UserName]
+	if tag < u.virtualTime {
+		tag = u.virtualTime
+	}
+	u.jobTags[job.ID] = tag
+	u.userNextTag[job.UserName] = tag + 1
+}
@@ -0,0 +1,120 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sortpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+)
+
+func TestQueueFairShare(t *testing.T) {
+	now := time.Now()
+	q := api.NewQueueInfo(model.Queue{
+		Model:            model.Model{ID: "q1"},
+		Name:             "q1",
+		SchedulingPolicy: []string{FairSharePolicyName},
+	})
+	assert.Equal(t, 1, len(q.SortPolicies))
+	jobQueue := api.NewJobQueue(q)
+
+	// user1 submits three jobs before user2
+	jobQueue.Insert(&api.PFJob{ID: "job-1", UserName: "user1", CreateTime: now})
+	jobQueue.Insert(&api.PFJob{ID: "job-2", UserName: "user1", CreateTime: now.Add(time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-3", UserName: "user1", CreateTime: now.Add(2 * time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-4", UserName: "user2", CreateTime: now.Add(3 * time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-5", UserName: "user2", CreateTime: now.Add(4 * time.Second)})
+
+	job, ok := jobQueue.GetJob()
+	assert.True(t, ok)
+	assert.Equal(t, "job-1", job.ID)
+	job, _ = jobQueue.GetJob()
+	assert.Equal(t, "job-4", job.ID)
+
+	// a new user joins queue, and gets its turn first as none of its jobs is dequeued
+	jobQueue.Insert(&api.PFJob{ID: "job-6", UserName: "user3", CreateTime: now.Add(5 * time.Second)})
+
+	var jobIDs []string
+	for {
+		job, ok = jobQueue.GetJob()
+		if !ok {
+			break
+		}
+		jobIDs = append(jobIDs, job.ID)
+	}
+	assert.Equal(t, []string{"job-6", "job-2", "job-5", "job-3"}, jobIDs)
+
+	// user1 and user2 come back, user2 goes first as user1 got the last turn
+	jobQueue.Insert(&api.PFJob{ID: "job-7", UserName: "user1", CreateTime: now.Add(6 * time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-8", UserName: "user1", CreateTime: now.Add(7 * time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-9", UserName: "user2", CreateTime: now.Add(8 * time.Second)})
+	jobIDs = nil
+	for {
+		job, ok = jobQueue.GetJob()
+		if !ok {
+			break
+		}
+		jobIDs = append(jobIDs, job.ID)
+	}
+	assert.Equal(t, []string{"job-9", "job-7", "job-8"}, jobIDs)
+}
+
+func TestQueueFairShareRebuild(t *testing.T) {
+	now := time.Now()
+	queue := model.Queue{
+		Model:            model.Model{ID: "q2"},
+		Name:             "q2",
+		SchedulingPolicy: []string{FairSharePolicyName},
+	}
+	jobQueue := api.NewJobQueue(api.NewQueueInfo(queue))
+	jobQueue.Insert(&api.PFJob{ID: "job-1", UserName: "user1", CreateTime: now})
+	jobQueue.Insert(&api.PFJob{ID: "job-2", UserName: "user1", CreateTime: now.Add(time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-3", UserName: "user1", CreateTime: now.Add(2 * time.Second)})
+	job, _ := jobQueue.GetJob()
+	assert.Equal(t, "job-1", job.ID)
+
+	// queue info is rebuilt, jobs in queue are enqueued again and keep their tags
+	jobQueue = api.NewJobQueue(api.NewQueueInfo(queue))
+	jobQueue.Insert(&api.PFJob{ID: "job-4", UserName: "user2", CreateTime: now.Add(3 * time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-3", UserName: "user1", CreateTime: now.Add(2 * time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-2", UserName: "user1", CreateTime: now.Add(time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-5", UserName: "user2", CreateTime: now.Add(4 * time.Second)})
+
+	var jobIDs []string
+	for {
+		job, ok := jobQueue.GetJob()
+		if !ok {
+			break
+		}
+		jobIDs = append(jobIDs, job.ID)
+	}
+	assert.Equal(t, []string{"job-4", "job-2", "job-5", "job-3"}, jobIDs)
+
+	// tag of job completed before dequeued is released
+	usage := getFairShareUsage(queue.Name)
+	jobQueue.Insert(&api.PFJob{ID: "job-6", UserName: "user1", CreateTime: now.Add(5 * time.Second)})
+	assert.Equal(t, 1, len(usage.jobTags))
+	ReleaseJob("job-6")
+	assert.Equal(t, 0, len(usage.jobTags))
+	job, _ = jobQueue.GetJob()
+	assert.Equal(t, "job-6", job.ID)
+	assert.Equal(t, 0, len(usage.jobTags))
+}
@@ -0,0 +1,55 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sortpolicy
+
+import (
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+)
+
+// FIFOPolicyName indicates the name of fifo sort policy, which orders jobs by create time strictly.
+const FIFOPolicyName = "fifo"
+
+type fifoPolicy struct {
+	// Arguments given for the sort policy
+	policyArguments api.Arguments
+}
+
+// FIFOPolicyNew return fifo sort policy
+func FIFOPolicyNew(arguments api.Arguments) (api.SortPolicy, error) {
+	return &fifoPolicy{
+		policyArguments: arguments,
+	}, nil
+}
+
+func (fp *fifoPolicy) Name() string {
+	return FIFOPolicyName
+}
+
+func (fp *fifoPolicy) OrderFn(l, r interface{}) int {
+	lv := l.(*api.PFJob)
+	rv := r.(*api.PFJob)
+
+	if lv.CreateTime.Before(rv.CreateTime) {
+		return -1
+	}
+
+	if lv.CreateTime.After(rv.CreateTime) {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,91 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sortpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+)
+
+func TestQueueFIFO(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name string
+		l    *api.PFJob
+		r    *api.PFJob
+		ans  int
+	}{
+		{
+			name: "left is created before right",
+			l:    &api.PFJob{CreateTime: now},
+			r:    &api.PFJob{CreateTime: now.Add(time.Second)},
+			ans:  -1,
+		},
+		{
+			name: "left is created after right",
+			l:    &api.PFJob{CreateTime: now.Add(time.Second)},
+			r:    &api.PFJob{CreateTime: now},
+			ans:  1,
+		},
+		{
+			name: "left is created at the same time as right",
+			l:    &api.PFJob{CreateTime: now},
+			r:    &api.PFJob{CreateTime: now},
+			ans:  0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sp, err := FIFOPolicyNew(api.Arguments{})
+			t.Logf("run %s", sp.Name())
+			assert.Equal(t, nil, err)
+			ans := sp.OrderFn(tc.l, tc.r)
+			assert.Equal(t, tc.ans, ans)
+		})
+	}
+}
+
+func TestQueueComposedPolicy(t *testing.T) {
+	now := time.Now()
+	q := api.NewQueueInfo(model.Queue{
+		Model:            model.Model{ID: "q1"},
+		Name:             "q1",
+		SchedulingPolicy: []string{PriorityPolicyName, FIFOPolicyName},
+	})
+	assert.Equal(t, 2, len(q.SortPolicies))
+
+	jobQueue := api.NewJobQueue(q)
+	jobQueue.Insert(&api.PFJob{ID: "job-1", Priority: 3, CreateTime: now.Add(2 * time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-2", Priority: 4, CreateTime: now.Add(3 * time.Second)})
+	jobQueue.Insert(&api.PFJob{ID: "job-3", Priority: 3, CreateTime: now.Add(time.Second)})
+
+	var jobIDs []string
+	for {
+		job, ok := jobQueue.GetJob()
+		if !ok {
+			break
+		}
+		jobIDs = append(jobIDs, job.ID)
+	}
+	assert.Equal(t, []string{"job-2", "job-3", "job-1"}, jobIDs)
+}
@@ -709,7 +709,8 @@ func (krc *KubeRuntimeClient) Patch(namespace, name string, fv pfschema.Framewor
 	if krc == nil {
 		return fmt.Errorf("dynamic client is nil")
 	}
-	patchType := types.StrategicMergePatchType
+	// custom resources, such as kubeflow jobs, do not support strategic merge patch
+	patchType := types.MergePatchType
 	patchOptions := v1.PatchOptions{}
 	gvrMap, err := krc.GetGVR(gvk)
 	if err != nil {
@@ -0,0 +1,492 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/resources"
+	pfschema "github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/utils"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+)
+
+const (
+	// LocalProcessKind is the kind of job which runs as a host process
+	LocalProcessKind = "Process"
+	// LocalProcessAPIVersion is the api version of local process job
+	LocalProcessAPIVersion = "local/v1"
+	// LocalJobLogFile is the name of log file in job working directory
+	LocalJobLogFile = "job.log"
+
+	// localStopGracePeriod is the time waited between SIGTERM and SIGKILL when stopping a process
+	localStopGracePeriod = 10 * time.Second
+)
+
+var (
+	// LocalProcessFwVersion framework version for local process job
+	LocalProcessFwVersion = pfschema.NewFrameworkVersion(LocalProcessKind, LocalProcessAPIVersion)
+	// DefaultLocalWorkDir is the default root directory of local runtime
+	DefaultLocalWorkDir = filepath.Join(os.TempDir(), "paddleflow", "local-runtime")
+)
+
+// LocalProcess describes a job which runs as a process on host
+type LocalProcess struct {
+	ID          string
+	Namespace   string
+	Command     string
+	Args        []string
+	Env         map[string]string
+	Annotations map[string]string
+	// WorkDir is the working directory of process, and the log file is stored in it
+	WorkDir string
+
+	Pid        int
+	Status     pfschema.JobStatus
+	Message    string
+	ExitCode   int
+	StartTime  time.Time
+	FinishTime time.Time
+
+	cmd      *exec.Cmd
+	stopping bool
+	done     chan struct{}
+}
+
+// LogPath return the path of process log file
+func (p *LocalProcess) LogPath() string {
+	return filepath.Join(p.WorkDir, LocalJobLogFile)
+}
+
+func (p *LocalProcess) runtimeInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"pid":     p.Pid,
+		"command": p.Command,
+		"workDir": p.WorkDir,
+	}
+}
+
+func (p *LocalProcess) runtimeStatus() map[string]interface{} {
+	status := map[string]interface{}{
+		"phase":     string(p.Status),
+		"startTime": p.StartTime.Format(time.RFC3339),
+	}
+	if !p.FinishTime.IsZero() {
+		status["finishTime"] = p.FinishTime.Format(time.RFC3339)
+		status["exitCode"] = p.ExitCode
+	}
+	return status
+}
+
+// LocalRuntimeClient for local process client
+type LocalRuntimeClient struct {
+	ClusterInfo *pfschema.Cluster
+	// WorkDir is the root directory of job working directories
+	WorkDir  string
+	NodeName string
+
+	mutex     sync.RWMutex
+	processes map[string]*LocalProcess
+	jobQueue  workqueue.RateLimitingInterface
+	taskQueue workqueue.RateLimitingInterface
+}
+
+func CreateLocalRuntimeClient(cluster *pfschema.Cluster) (*LocalRuntimeClient, error) {
+	if cluster == nil {
+		log.Errorf("cluster info is nil")
+		return nil, fmt.Errorf("cluster info is nil")
+	}
+	// the endpoint of local cluster is used as work dir when it is an absolute path
+	workDir := DefaultLocalWorkDir
+	if filepath.IsAbs(cluster.ClientOpt.Master) {
+		workDir = cluster.ClientOpt.Master
+	}
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		log.Errorf("create work dir %s for local runtime failed, err: %v", workDir, err)
+		return nil, err
+	}
+	nodeName, err := os.Hostname()
+	if err != nil {
+		log.Warnf("get hostname failed, err: %v", err)
+		nodeName = "localhost"
+	}
+	return &LocalRuntimeClient{
+		ClusterInfo: cluster,
+		WorkDir:     workDir,
+		NodeName:    nodeName,
+		processes:   make(map[string]*LocalProcess),
+	}, nil
+}
+
+func (lrc *LocalRuntimeClient) Cluster() string {
+	msg := ""
+	if lrc.ClusterInfo != nil {
+		msg = fmt.Sprintf("cluster %s with type %s", lrc.ClusterInfo.Name, lrc.ClusterInfo.Type)
+	}
+	return msg
+}
+
+func (lrc *LocalRuntimeClient) ClusterID() string {
+	clusterID := ""
+	if lrc.ClusterInfo != nil {
+		clusterID = lrc.ClusterInfo.ID
+	}
+	return clusterID
+}
+
+func (lrc *LocalRuntimeClient) ClusterName() string {
+	clusterName := ""
+	if lrc.ClusterInfo != nil {
+		clusterName = lrc.ClusterInfo.Name
+	}
+	return clusterName
+}
+
+func (lrc *LocalRuntimeClient) JobFrameworkVersion(jobType pfschema.JobType, fw pfschema.Framework) pfschema.FrameworkVersion {
+	switch jobType {
+	case pfschema.TypeSingle:
+		return LocalProcessFwVersion
+	default:
+		log.Warnf("on %s, job type %s framework %s is not supported", lrc.Cluster(), jobType, fw)
+		return pfschema.FrameworkVersion{}
+	}
+}
+
+func (lrc *LocalRuntimeClient) GetJobTypeFramework(fv pfschema.FrameworkVersion) (pfschema.JobType, pfschema.Framework) {
+	if fv == LocalProcessFwVersion {
+		return pfschema.TypeSingle, pfschema.FrameworkStandalone
+	}
+	return pfschema.JobType(""), pfschema.Framework("")
+}
+
+func (lrc *LocalRuntimeClient) RegisterListener(listenerType string, workQueue workqueue.RateLimitingInterface) error {
+	switch listenerType {
+	case pfschema.ListenerTypeJob:
+		lrc.jobQueue = workQueue
+	case pfschema.ListenerTypeTask:
+		lrc.taskQueue = workQueue
+	default:
+		return fmt.Errorf("listener type %s is not supported", listenerType)
+	}
+	return nil
+}
+
+func (lrc *LocalRuntimeClient) StartListener(listenerType string, stopCh <-chan struct{}) error {
+	switch listenerType {
+	case pfschema.ListenerTypeJob, pfschema.ListenerTypeTask:
+		// process events are pushed to work queue directly, nothing to start
+		log.Infof("on %s, start %s listener", lrc.Cluster(), listenerType)
+	default:
+		return fmt.Errorf("listener type %s is not supported", listenerType)
+	}
+	return nil
+}
+
+func (lrc *LocalRuntimeClient) Get(namespace string, name string, fv pfschema.FrameworkVersion) (interface{}, error) {
+	lrc.mutex.RLock()
+	defer lrc.mutex.RUnlock()
+	p, find := lrc.processes[name]
+	if !find {
+		return nil, k8serrors.NewNotFound(k8sschema.GroupResource{Resource: LocalProcessKind}, name)
+	}
+	return p, nil
+}
+
+// Create start a LocalProcess on host
+func (lrc *LocalRuntimeClient) Create(resource interface{}, fv pfschema.FrameworkVersion) error {
+	p, ok := resource.(*LocalProcess)
+	if !ok || p == nil {
+		return fmt.Errorf("on %s, resource %v is not a local process", lrc.Cluster(), resource)
+	}
+	lrc.mutex.Lock()
+	defer lrc.mutex.Unlock()
+	if _, find := lrc.processes[p.ID]; find {
+		return fmt.Errorf("on %s, process for job %s is already exist", lrc.Cluster(), p.ID)
+	}
+	if p.WorkDir == "" {
+		p.WorkDir = filepath.Join(lrc.WorkDir, p.Namespace, p.ID)
+	}
+	if err := os.MkdirAll(p.WorkDir, 0755); err != nil {
+		log.Errorf("create work dir %s for job %s failed, err: %v", p.WorkDir, p.ID, err)
+		return err
+	}
+	logFile, err := os.OpenFile(p.LogPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		log.Errorf("open log file for job %s failed, err: %v", p.ID, err)
+		return err
+	}
+
+	cmd := exec.Command("/bin/sh", append([]string{"-c", p.Command, p.ID}, p.Args...)...)
+	cmd.Dir = p.WorkDir
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Env = os.Environ()
+	for key, value := range p.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+	}
+	// run process in a new process group, so that its children can be stopped together
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err = cmd.Start(); err != nil {
+		logFile.Close()
+		log.Errorf("start process for job %s failed, err: %v", p.ID, err)
+		return err
+	}
+
+	p.cmd = cmd
+	p.done = make(chan struct{})
+	p.Pid = cmd.Process.Pid
+	p.StartTime = time.Now()
+	p.Status = pfschema.StatusJobRunning
+	p.Message = "job is running"
+	lrc.processes[p.ID] = p
+	log.Infof("on %s, start process %d for job %s", lrc.Cluster(), p.Pid, p.ID)
+
+	lrc.enqueueJob(p, pfschema.Create)
+	lrc.enqueueTask(p, pfschema.Create)
+	go lrc.waitProcess(p, logFile)
+	return nil
+}
+
+func (lrc *LocalRuntimeClient) waitProcess(p *LocalProcess, logFile *os.File) {
+	err := p.cmd.Wait()
+	logFile.Close()
+
+	lrc.mutex.Lock()
+	defer lrc.mutex.Unlock()
+	defer close(p.done)
+	p.FinishTime = time.Now()
+	p.ExitCode = p.cmd.ProcessState.ExitCode()
+	if p.stopping {
+		// process is stopped by Delete, and the delete event is sent already
+		log.Infof("on %s, process %d for job %s is stopped", lrc.Cluster(), p.Pid, p.ID)
+		return
+	}
+	if err == nil {
+		p.Status = pfschema.StatusJobSucceeded
+		p.Message = "job is succeeded"
+	} else {
+		p.Status = pfschema.StatusJobFailed
+		p.Message = fmt.Sprintf("job is failed, process exited with code: %d, err: %v", p.ExitCode, err)
+	}
+	log.Infof("on %s, process %d for job %s exited, status: %s", lrc.Cluster(), p.Pid, p.ID, p.Status)
+	lrc.enqueueJob(p, pfschema.Update)
+	lrc.enqueueTask(p, pfschema.Update)
+}
+
+func (lrc *LocalRuntimeClient) enqueueJob(p *LocalProcess, action pfschema.ActionType) {
+	if lrc.jobQueue == nil {
+		log.Warnf("on %s, job listener is not registered, skip %s event for job %s", lrc.Cluster(), action, p.ID)
+		return
+	}
+	lrc.jobQueue.Add(&api.JobSyncInfo{
+		ID:               p.ID,
+		Namespace:        p.Namespace,
+		Annotations:      p.Annotations,
+		FrameworkVersion: LocalProcessFwVersion,
+		Status:           p.Status,
+		RuntimeInfo:      p.runtimeInfo(),
+		RuntimeStatus:    p.runtimeStatus(),
+		Message:          p.Message,
+		Action:           action,
+	})
+}
+
+func (lrc *LocalRuntimeClient) enqueueTask(p *LocalProcess, action pfschema.ActionType) {
+	if lrc.taskQueue == nil {
+		return
+	}
+	var taskStatus pfschema.TaskStatus
+	switch p.Status {
+	case pfschema.StatusJobRunning:
+		taskStatus = pfschema.StatusTaskRunning
+	case pfschema.StatusJobSucceeded:
+		taskStatus = pfschema.StatusTaskSucceeded
+	default:
+		taskStatus = pfschema.StatusTaskFailed
+	}
+	lrc.taskQueue.Add(&api.TaskSyncInfo{
+		ID:         fmt.Sprintf("%s-%d", p.ID, p.Pid),
+		Name:       p.ID,
+		Namespace:  p.Namespace,
+		JobID:      p.ID,
+		NodeName:   lrc.NodeName,
+		MemberRole: pfschema.RoleWorker,
+		Status:     taskStatus,
+		Message:    p.Message,
+		PodStatus:  p.runtimeStatus(),
+		Action:     action,
+	})
+}
+
+// Delete stop the process if it is running, and remove it from local runtime.
+// The log file of process is kept in its work dir.
+func (lrc *LocalRuntimeClient) Delete(namespace string, name string, fv pfschema.FrameworkVersion) error {
+	lrc.mutex.Lock()
+	p, find := lrc.processes[name]
+	if !find {
+		lrc.mutex.Unlock()
+		return k8serrors.NewNotFound(k8sschema.GroupResource{Resource: LocalProcessKind}, name)
+	}
+	delete(lrc.processes, name)
+	running := p.FinishTime.IsZero()
+	if running {
+		p.stopping = true
+		p.Status = pfschema.StatusJobTerminated
+		p.Message = "job is terminated"
+	}
+	lrc.mutex.Unlock()
+
+	if running {
+		log.Infof("on %s, stop process %d for job %s", lrc.Cluster(), p.Pid, p.ID)
+		// send signal to the process group
+		if err := syscall.Kill(-p.Pid, syscall.SIGTERM); err != nil {
+			log.Warnf("send SIGTERM to process %d failed, err: %v", p.Pid, err)
+		}
+		select {
+		case <-p.done:
+		case <-time.After(localStopGracePeriod):
+			log.Warnf("process %d is not exited after %s, kill it", p.Pid, localStopGracePeriod)
+			_ = syscall.Kill(-p.Pid, syscall.SIGKILL)
+		}
+	}
+	lrc.enqueueJob(p, pfschema.Delete)
+	lrc.enqueueTask(p, pfschema.Delete)
+	return nil
+}
+
+func (lrc *LocalRuntimeClient) Patch(namespace, name string, fv pfschema.FrameworkVersion, data []byte) error {
+	return fmt.Errorf("patch is not supported on %s", lrc.Cluster())
+}
+
+func (lrc *LocalRuntimeClient) Update(resource interface{}, fv pfschema.FrameworkVersion) error {
+	return fmt.Errorf("update is not supported on %s", lrc.Cluster())
+}
+
+// ListNodeQuota return the resource of host, and the resource of running process is not subtracted
+func (lrc *LocalRuntimeClient) ListNodeQuota(ctx context.Context) (pfschema.QuotaSummary, []pfschema.NodeQuotaInfo, error) {
+	total := resources.EmptyResource()
+	total.SetResources(resources.ResCPU, int64(runtime.NumCPU())*1000)
+	if memory, err := hostMemory(); err == nil {
+		total.SetResources(resources.ResMemory, memory)
+	} else {
+		log.Warnf("on %s, get memory of host failed, err: %v", lrc.Cluster(), err)
+	}
+	nodeQuota := pfschema.NodeQuotaInfo{
+		NodeName:    lrc.NodeName,
+		Schedulable: true,
+		Total:       *total,
+		Idle:        *total.Clone(),
+	}
+	summary := pfschema.QuotaSummary{
+		TotalQuota: *total.Clone(),
+		IdleQuota:  *total.Clone(),
+	}
+	return summary, []pfschema.NodeQuotaInfo{nodeQuota}, nil
+}
+
+// hostMemory return total memory of host in bytes, which is read from /proc/meminfo
+func hostMemory() (int64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return kb * 1024, nil
+		}
+	}
+	return 0, fmt.Errorf("MemTotal is not found in /proc/meminfo")
+}
+
+// GetTaskLog read log of the process for job, the log is paged by lines
+func (lrc *LocalRuntimeClient) GetTaskLog(jobID, logFilePosition string, pageSize, pageNo int) ([]pfschema.TaskLogInfo, error) {
+	lrc.mutex.RLock()
+	p, find := lrc.processes[jobID]
+	lrc.mutex.RUnlock()
+	if !find {
+		return []pfschema.TaskLogInfo{}, nil
+	}
+	content, err := os.ReadFile(p.LogPath())
+	if err != nil {
+		log.Errorf("read log file of job %s failed, err: %v", jobID, err)
+		return []pfschema.TaskLogInfo{}, err
+	}
+	logContent := string(content)
+	length := len(strings.Split(strings.TrimRight(logContent, "\n"), "\n"))
+	if logContent == "" {
+		length = 0
+	}
+
+	startIndex, endIndex := -1, -1
+	hasNextPage, overFlag := false, false
+	if (pageNo-1)*pageSize+1 <= length {
+		switch logFilePosition {
+		case common.EndFilePosition:
+			startIndex = length - pageSize*pageNo
+			endIndex = length - (pageNo-1)*pageSize
+			if startIndex <= 0 {
+				startIndex = -1
+			} else {
+				hasNextPage = true
+			}
+			if endIndex == length {
+				endIndex = -1
+			}
+		default:
+			startIndex = (pageNo - 1) * pageSize
+			if pageNo*pageSize < length {
+				endIndex = pageNo * pageSize
+				hasNextPage = true
+			}
+		}
+	} else {
+		overFlag = true
+	}
+	return []pfschema.TaskLogInfo{
+		{
+			TaskID: fmt.Sprintf("%s-%d", p.ID, p.Pid),
+			Info: pfschema.LogInfo{
+				LogContent:  utils.SplitLog(logContent, startIndex, endIndex, overFlag),
+				HasNextPage: hasNextPage,
+			},
+		},
+	}, nil
+}
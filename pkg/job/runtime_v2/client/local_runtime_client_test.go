@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
+	pfschema "github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+)
+
+func newTestLocalClient(t *testing.T) *LocalRuntimeClient {
+	lrc, err := CreateLocalRuntimeClient(&pfschema.Cluster{
+		Name: "local-cluster",
+		ID:   "local-cluster-id",
+		Type: pfschema.LocalType,
+		ClientOpt: pfschema.ClientOptions{
+			Master: t.TempDir(),
+		},
+	})
+	assert.NoError(t, err)
+	return lrc
+}
+
+func nextJobSyncInfo(t *testing.T, queue workqueue.RateLimitingInterface) *api.JobSyncInfo {
+	obj, shutdown := queue.Get()
+	assert.False(t, shutdown)
+	queue.Done(obj)
+	return obj.(*api.JobSyncInfo)
+}
+
+func TestLocalRuntimeClient_Process(t *testing.T) {
+	testCases := []struct {
+		name          string
+		command       string
+		expectStatus  pfschema.JobStatus
+		expectLogLine string
+	}{
+		{
+			name:          "process succeeded",
+			command:       "echo hello $PF_TEST_ENV",
+			expectStatus:  pfschema.StatusJobSucceeded,
+			expectLogLine: "hello local\n",
+		},
+		{
+			name:          "process failed",
+			command:       "echo failed; exit 3",
+			expectStatus:  pfschema.StatusJobFailed,
+			expectLogLine: "failed\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lrc := newTestLocalClient(t)
+			jobQueue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			err := lrc.RegisterListener(pfschema.ListenerTypeJob, jobQueue)
+			assert.NoError(t, err)
+
+			p := &LocalProcess{
+				ID:        "job-local-1",
+				Namespace: "default",
+				Command:   tc.command,
+				Env:       map[string]string{"PF_TEST_ENV": "local"},
+			}
+			err = lrc.Create(p, LocalProcessFwVersion)
+			assert.NoError(t, err)
+
+			createInfo := nextJobSyncInfo(t, jobQueue)
+			assert.Equal(t, pfschema.Create, createInfo.Action)
+			assert.Equal(t, pfschema.StatusJobRunning, createInfo.Status)
+
+			updateInfo := nextJobSyncInfo(t, jobQueue)
+			assert.Equal(t, pfschema.Update, updateInfo.Action)
+			assert.Equal(t, tc.expectStatus, updateInfo.Status)
+
+			logs, err := lrc.GetTaskLog(p.ID, common.BeginFilePosition, 10, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(logs))
+			assert.Equal(t, tc.expectLogLine, logs[0].Info.LogContent)
+
+			err = lrc.Delete(p.Namespace, p.ID, LocalProcessFwVersion)
+			assert.NoError(t, err)
+			_, err = lrc.Get(p.Namespace, p.ID, LocalProcessFwVersion)
+			assert.True(t, k8serrors.IsNotFound(err))
+		})
+	}
+}
+
+func TestLocalRuntimeClient_StopProcess(t *testing.T) {
+	lrc := newTestLocalClient(t)
+	jobQueue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	err := lrc.RegisterListener(pfschema.ListenerTypeJob, jobQueue)
+	assert.NoError(t, err)
+
+	p := &LocalProcess{
+		ID:        "job-local-2",
+		Namespace: "default",
+		Command:   "sleep 60",
+	}
+	err = lrc.Create(p, LocalProcessFwVersion)
+	assert.NoError(t, err)
+	createInfo := nextJobSyncInfo(t, jobQueue)
+	assert.Equal(t, pfschema.StatusJobRunning, createInfo.Status)
+
+	startTime := time.Now()
+	err = lrc.Delete(p.Namespace, p.ID, LocalProcessFwVersion)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(startTime) < localStopGracePeriod)
+
+	deleteInfo := nextJobSyncInfo(t, jobQueue)
+	assert.Equal(t, pfschema.Delete, deleteInfo.Action)
+	assert.Equal(t, pfschema.StatusJobTerminated, deleteInfo.Status)
+}
+
+func TestLocalRuntimeClient_ListNodeQuota(t *testing.T) {
+	lrc := newTestLocalClient(t)
+	summary, nodes, err := lrc.ListNodeQuota(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(nodes))
+	assert.False(t, summary.TotalQuota.CPU() == 0)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	pfschema "github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/queue/sortpolicy"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/framework"
 	_ "github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/metrics"
@@ -197,6 +198,7 @@ func (j *JobSync) doDeleteAction(jobSyncInfo *api.JobSyncInfo) error {
 		log.Errorf("sync job status failed. jobID: %s, err: %s", jobSyncInfo.ID, err.Error())
 		return err
 	}
+	sortpolicy.ReleaseJob(jobSyncInfo.ID)
 	return nil
 }
 
@@ -216,6 +218,9 @@ func (j *JobSync) doUpdateAction(jobSyncInfo *api.JobSyncInfo) error {
 		log.Errorf("update job failed. jobID: %s, err: %s", jobSyncInfo.ID, err.Error())
 		return err
 	}
+	if pfschema.IsImmutableJobStatus(jobSyncInfo.Status) {
+		sortpolicy.ReleaseJob(jobSyncInfo.ID)
+	}
 	return nil
 }
 
@@ -95,7 +95,7 @@ func CreateRuntime(clusterInfo model.ClusterInfo) (RuntimeService, error) {
 	cluster := newClusterConfig(clusterInfo)
 	switch cluster.Type {
 	case schema.LocalType:
-		//runtimeSvc = NewLocalRuntime(cluster)
+		runtimeSvc = NewLocalRuntime(cluster)
 	case schema.KubernetesType:
 		runtimeSvc = NewKubeRuntime(cluster)
 	default:
@@ -33,12 +33,21 @@ var kubeJobMutex sync.RWMutex
 // kubeJobMaps store JobPlugin
 var kubeJobMaps = map[schema.FrameworkVersion]JobPlugin{}
 
+var localJobMutex sync.RWMutex
+
+// localJobMaps store JobPlugin for local runtime
+var localJobMaps = map[schema.FrameworkVersion]JobPlugin{}
+
 func RegisterJobPlugin(runtimeType string, frameworkVersion schema.FrameworkVersion, job JobPlugin) {
 	switch runtimeType {
 	case schema.KubernetesType:
 		kubeJobMutex.Lock()
 		defer kubeJobMutex.Unlock()
 		kubeJobMaps[frameworkVersion] = job
+	case schema.LocalType:
+		localJobMutex.Lock()
+		defer localJobMutex.Unlock()
+		localJobMaps[frameworkVersion] = job
 	default:
 		fmt.Printf("runtime type %s is not supported\n", runtimeType)
 	}
@@ -50,6 +59,10 @@ func CleanupJobPlugins(runtimeType string) {
 		kubeJobMutex.Lock()
 		defer kubeJobMutex.Unlock()
 		kubeJobMaps = map[schema.FrameworkVersion]JobPlugin{}
+	case schema.LocalType:
+		localJobMutex.Lock()
+		defer localJobMutex.Unlock()
+		localJobMaps = map[schema.FrameworkVersion]JobPlugin{}
 	default:
 		fmt.Printf("runtime type %s is not supported\n", runtimeType)
 	}
@@ -63,6 +76,10 @@ func GetJobPlugin(runtimeType string, frameworkVersion schema.FrameworkVersion)
 		kubeJobMutex.RLock()
 		defer kubeJobMutex.RUnlock()
 		jobPlugin, found = kubeJobMaps[frameworkVersion]
+	case schema.LocalType:
+		localJobMutex.RLock()
+		defer localJobMutex.RUnlock()
+		jobPlugin, found = localJobMaps[frameworkVersion]
 	default:
 		fmt.Printf("runtime type %s is not supported\n", runtimeType)
 	}
@@ -78,6 +95,12 @@ func ListJobPlugins(runtimeType string) map[schema.FrameworkVersion]JobPlugin {
 		for fv, jp := range kubeJobMaps {
 			jobPlugin[fv] = jp
 		}
+	case schema.LocalType:
+		localJobMutex.RLock()
+		defer localJobMutex.RUnlock()
+		for fv, jp := range localJobMaps {
+			jobPlugin[fv] = jp
+		}
 	default:
 		fmt.Printf("runtime type %s is not supported\n", runtimeType)
 	}
@@ -21,12 +21,14 @@ import (
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/framework"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/argoworkflow"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/mpi"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/mxnet"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/paddle"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/pytorch"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/ray"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/single"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/spark"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/tensorflow"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/xgboost"
 )
 
 func init() {
@@ -36,8 +38,12 @@ func init() {
 	framework.RegisterJobPlugin(pfschema.KubernetesType, mpi.KubeMPIFwVersion, mpi.New)
 	framework.RegisterJobPlugin(pfschema.KubernetesType, pytorch.KubePyTorchFwVersion, pytorch.New)
 	framework.RegisterJobPlugin(pfschema.KubernetesType, tensorflow.KubeTFFwVersion, tensorflow.New)
+	framework.RegisterJobPlugin(pfschema.KubernetesType, mxnet.KubeMXNetFwVersion, mxnet.New)
+	framework.RegisterJobPlugin(pfschema.KubernetesType, xgboost.KubeXGBoostFwVersion, xgboost.New)
 	framework.RegisterJobPlugin(pfschema.KubernetesType, spark.KubeSparkFwVersion, spark.New)
 	framework.RegisterJobPlugin(pfschema.KubernetesType, ray.KubeRayFwVersion, ray.New)
 	framework.RegisterJobPlugin(pfschema.KubernetesType, argoworkflow.KubeArgoWorkflowFwVersion, argoworkflow.New)
+	// Plugins for local Jobs
+	framework.RegisterJobPlugin(pfschema.LocalType, single.LocalSingleFwVersion, single.NewLocalJob)
 	// TODO: add more plugins
 }
@@ -0,0 +1,311 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mxnet
+
+import (
+	"context"
+	"fmt"
+
+	kubeflowv1 "github.com/kubeflow/common/pkg/apis/common/v1"
+	mxv1 "github.com/kubeflow/training-operator/pkg/apis/mxnet/v1"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/k8s"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/resources"
+	pfschema "github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/client"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/framework"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/util/kuberuntime"
+)
+
+var (
+	JobGVK             = k8s.MXNetJobGVK
+	KubeMXNetFwVersion = client.KubeFrameworkVersion(JobGVK)
+	// schedulerFlavour is the flavour of mxnet scheduler, which only coordinates servers and workers and needs no accelerator
+	schedulerFlavour = pfschema.Flavour{ResourceInfo: pfschema.ResourceInfo{CPU: "1", Mem: "1Gi"}}
+)
+
+// KubeMXJob is a struct that runs a mxnet job
+type KubeMXJob struct {
+	GVK              schema.GroupVersionKind
+	frameworkVersion pfschema.FrameworkVersion
+	runtimeClient    framework.RuntimeClientInterface
+	jobQueue         workqueue.RateLimitingInterface
+}
+
+func New(kubeClient framework.RuntimeClientInterface) framework.JobInterface {
+	return &KubeMXJob{
+		runtimeClient:    kubeClient,
+		GVK:              JobGVK,
+		frameworkVersion: KubeMXNetFwVersion,
+	}
+}
+
+func (mj *KubeMXJob) String(name string) string {
+	return fmt.Sprintf("%s job %s on %s", mj.GVK.String(), name, mj.runtimeClient.Cluster())
+}
+
+func (mj *KubeMXJob) Submit(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	mxJob := &mxv1.MXJob{}
+	if err := kuberuntime.CreateKubeJobFromYaml(mxJob, mj.GVK, job); err != nil {
+		log.Errorf("create %s failed, err %v", mj.String(jobName), err)
+		return err
+	}
+
+	var err error
+	// set metadata field
+	kuberuntime.BuildJobMetadata(&mxJob.ObjectMeta, job)
+	// set spec field
+	if job.IsCustomYaml {
+		// set custom MXJob Spec from user
+		err = mj.customMXJobSpec(&mxJob.Spec, job)
+	} else {
+		// set builtin MXJob Spec
+		err = mj.builtinMXJobSpec(&mxJob.Spec, job)
+	}
+	if err != nil {
+		log.Errorf("build %s spec failed, err %v", mj.String(jobName), err)
+		return err
+	}
+	log.Debugf("begin to create %s, job info: %v", mj.String(jobName), mxJob)
+	err = mj.runtimeClient.Create(mxJob, mj.frameworkVersion)
+	if err != nil {
+		log.Errorf("create %s failed, err %v", mj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+// builtinMXJobSpec set build-in MXJob spec
+func (mj *KubeMXJob) builtinMXJobSpec(mxJobSpec *mxv1.MXJobSpec, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Debugf("patch %s spec:%#v", mj.String(jobName), mxJobSpec)
+	mxJobSpec.JobMode = mxv1.MXTrain
+	// set MXReplicaSpecs
+	minResources := resources.EmptyResource()
+	var schedulerTask *pfschema.Member
+	for index, task := range job.Tasks {
+		// mxnet server for distributed training
+		replicaType := mxv1.MXReplicaTypeServer
+		// if role is worker, set it to mxnet worker
+		if task.Role == pfschema.RoleWorker || task.Role == pfschema.RolePWorker {
+			replicaType = mxv1.MXReplicaTypeWorker
+		}
+		if schedulerTask == nil || replicaType == mxv1.MXReplicaTypeServer {
+			schedulerTask = &job.Tasks[index]
+		}
+		if err := mj.buildReplicaSpec(mxJobSpec, replicaType, job.ID, &task, minResources); err != nil {
+			log.Errorf("build %s RepilcaSpec for %s failed, err: %v", replicaType, mj.String(jobName), err)
+			return err
+		}
+	}
+	if schedulerTask == nil {
+		return fmt.Errorf("tasks of %s is empty", mj.String(jobName))
+	}
+	// the scheduler only coordinates servers and workers, so it reuses the conf of server, or worker in collective mode,
+	// except the flavour, which would request accelerators of workers
+	scheduler := *schedulerTask
+	scheduler.Replicas = 1
+	scheduler.Flavour = schedulerFlavour
+	if err := mj.buildReplicaSpec(mxJobSpec, mxv1.MXReplicaTypeScheduler, job.ID, &scheduler, minResources); err != nil {
+		log.Errorf("build %s RepilcaSpec for %s failed, err: %v", mxv1.MXReplicaTypeScheduler, mj.String(jobName), err)
+		return err
+	}
+	// set RunPolicy
+	resourceList := k8s.NewResourceList(minResources)
+	return kuberuntime.KubeflowRunPolicy(&mxJobSpec.RunPolicy, &resourceList, job.Conf.GetQueueName(), job.Conf.GetPriority())
+}
+
+// buildReplicaSpec build the replica spec of replicaType, and add its resources to minResources
+func (mj *KubeMXJob) buildReplicaSpec(mxJobSpec *mxv1.MXJobSpec, replicaType kubeflowv1.ReplicaType, jobID string,
+	task *pfschema.Member, minResources *resources.Resource) error {
+	replicaSpec, ok := mxJobSpec.MXReplicaSpecs[replicaType]
+	if !ok || replicaSpec == nil {
+		return fmt.Errorf("replica type %s is not found in template", replicaType)
+	}
+	if err := kuberuntime.KubeflowReplicaSpec(replicaSpec, jobID, task); err != nil {
+		return err
+	}
+	// calculate job minResources
+	taskResources, err := resources.NewResourceFromMap(task.Flavour.ToMap())
+	if err != nil {
+		return fmt.Errorf("parse resources of %s failed, err: %v", replicaType, err)
+	}
+	taskResources.Multi(task.Replicas)
+	minResources.Add(taskResources)
+	return nil
+}
+
+// customMXJobSpec set custom MXJob Spec
+func (mj *KubeMXJob) customMXJobSpec(mxJobSpec *mxv1.MXJobSpec, job *api.PFJob) error {
+	if job == nil || mxJobSpec == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Debugf("patch %s spec:%#v", mj.String(jobName), mxJobSpec)
+	// patch metadata
+	for _, replicaType := range []kubeflowv1.ReplicaType{mxv1.MXReplicaTypeScheduler, mxv1.MXReplicaTypeServer, mxv1.MXReplicaTypeWorker} {
+		replicaSpec, find := mxJobSpec.MXReplicaSpecs[replicaType]
+		if find && replicaSpec != nil {
+			kuberuntime.BuildTaskMetadata(&replicaSpec.Template.ObjectMeta, job.ID, &pfschema.Conf{})
+		}
+	}
+	// TODO: patch mxnet job from user
+	// check RunPolicy
+	return kuberuntime.KubeflowRunPolicy(&mxJobSpec.RunPolicy, nil, job.Conf.GetQueueName(), job.Conf.GetPriority())
+}
+
+func (mj *KubeMXJob) Stop(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Infof("begin to stop %s", mj.String(jobName))
+	if err := mj.runtimeClient.Delete(job.Namespace, job.ID, mj.frameworkVersion); err != nil {
+		log.Errorf("stop %s failed, err: %v", mj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+func (mj *KubeMXJob) Update(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Infof("begin to update %s", mj.String(jobName))
+	if err := kuberuntime.UpdateKubeJob(job, mj.runtimeClient, mj.frameworkVersion); err != nil {
+		log.Errorf("update %s failed, err: %v", mj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+func (mj *KubeMXJob) Delete(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Infof("begin to delete %s ", mj.String(jobName))
+	if err := mj.runtimeClient.Delete(job.Namespace, job.ID, mj.frameworkVersion); err != nil {
+		log.Errorf("delete %s failed, err %v", mj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+func (mj *KubeMXJob) GetLog(ctx context.Context, jobLogRequest pfschema.JobLogRequest) (pfschema.JobLogInfo, error) {
+	// TODO: add get log logic
+	return pfschema.JobLogInfo{}, nil
+}
+
+func (mj *KubeMXJob) AddEventListener(ctx context.Context, listenerType string, jobQueue workqueue.RateLimitingInterface, listener interface{}) error {
+	var err error
+	switch listenerType {
+	case pfschema.ListenerTypeJob:
+		err = mj.addJobEventListener(ctx, jobQueue, listener)
+	default:
+		err = fmt.Errorf("listenerType %s is not supported", listenerType)
+	}
+	return err
+}
+
+func (mj *KubeMXJob) addJobEventListener(ctx context.Context, jobQueue workqueue.RateLimitingInterface, listener interface{}) error {
+	if jobQueue == nil || listener == nil {
+		return fmt.Errorf("add job event listener failed, err: listener is nil")
+	}
+	mj.jobQueue = jobQueue
+	informer := listener.(cache.SharedIndexInformer)
+	informer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: kuberuntime.ResponsibleForJob,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    mj.addJob,
+			UpdateFunc: mj.updateJob,
+			DeleteFunc: mj.deleteJob,
+		},
+	})
+	return nil
+}
+
+func (mj *KubeMXJob) addJob(obj interface{}) {
+	jobSyncInfo, err := kuberuntime.JobAddFunc(obj, mj.JobStatus)
+	if err != nil {
+		return
+	}
+	mj.jobQueue.Add(jobSyncInfo)
+}
+
+func (mj *KubeMXJob) updateJob(old, new interface{}) {
+	jobSyncInfo, err := kuberuntime.JobUpdateFunc(old, new, mj.JobStatus)
+	if err != nil {
+		return
+	}
+	mj.jobQueue.Add(jobSyncInfo)
+}
+
+func (mj *KubeMXJob) deleteJob(obj interface{}) {
+	jobSyncInfo, err := kuberuntime.JobDeleteFunc(obj, mj.JobStatus)
+	if err != nil {
+		return
+	}
+	mj.jobQueue.Add(jobSyncInfo)
+}
+
+// JobStatus get the statusInfo of MXNet job, including origin status, pf status and message
+func (mj *KubeMXJob) JobStatus(obj interface{}) (api.StatusInfo, error) {
+	unObj := obj.(*unstructured.Unstructured)
+	// convert to MXJob struct
+	job := &mxv1.MXJob{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unObj.Object, job); err != nil {
+		log.Errorf("convert unstructured object [%+v] to %s job failed. error: %s", obj, mj.GVK.String(), err)
+		return api.StatusInfo{}, err
+	}
+	// convert job status
+	condLen := len(job.Status.Conditions)
+	var jobCond kubeflowv1.JobCondition
+	if condLen >= 1 {
+		jobCond = job.Status.Conditions[condLen-1]
+	}
+	state, msg, err := mj.getJobStatus(jobCond)
+	if err != nil {
+		log.Errorf("get mxnet job status failed, err: %v", err)
+		return api.StatusInfo{}, err
+	}
+	log.Infof("mxnet job status: %s", state)
+	return api.StatusInfo{
+		OriginStatus: string(jobCond.Type),
+		Status:       state,
+		Message:      msg,
+	}, nil
+}
+
+func (mj *KubeMXJob) getJobStatus(jobCond kubeflowv1.JobCondition) (pfschema.JobStatus, string, error) {
+	return kuberuntime.GetKubeflowJobStatus(jobCond)
+}
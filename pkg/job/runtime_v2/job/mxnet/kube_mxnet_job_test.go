@@ -0,0 +1,175 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mxnet
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/k8s"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/client"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/driver"
+)
+
+func TestMXJob_CreateJob(t *testing.T) {
+	config.GlobalServerConfig = &config.ServerConfig{}
+	config.GlobalServerConfig.Job.SchedulerName = "testSchedulerName"
+	defaultJobYamlPath := "../../../../../config/server/default/job/job_template.yaml"
+	config.InitJobTemplate(defaultJobYamlPath)
+
+	var server = httptest.NewServer(k8s.DiscoveryHandlerFunc)
+	defer server.Close()
+	kubeRuntimeClient := client.NewFakeKubeRuntimeClient(server)
+	// mock db
+	driver.InitMockDB()
+	// create kubernetes resource with dynamic client
+	tests := []struct {
+		caseName         string
+		jobObj           *api.PFJob
+		wantErr          bool
+		wantReplicas     map[string]int64
+		wantMinResources map[string]interface{}
+	}{
+		{
+			caseName: "create mxnet job with ps mode",
+			jobObj: &api.PFJob{
+				Name:      "test-mxnet-job",
+				ID:        "job-test-mxnet-ps",
+				Namespace: "default",
+				JobType:   schema.TypeDistributed,
+				JobMode:   schema.EnvJobModePS,
+				Framework: schema.FrameworkMXNet,
+				Conf: schema.Conf{
+					Name:    "normal",
+					Command: "sleep 200",
+					Image:   "mockImage",
+					Env:     map[string]string{},
+				},
+				Tasks: []schema.Member{
+					{
+						Replicas: 2,
+						Role:     schema.RolePServer,
+						Conf: schema.Conf{
+							Flavour: schema.Flavour{Name: "", ResourceInfo: schema.ResourceInfo{CPU: "4", Mem: "4Gi"}},
+						},
+					},
+					{
+						Replicas: 3,
+						Role:     schema.RolePWorker,
+						Conf: schema.Conf{
+							Flavour: schema.Flavour{Name: "", ResourceInfo: schema.ResourceInfo{CPU: "4", Mem: "4Gi"}},
+						},
+					},
+				},
+			},
+			wantReplicas:     map[string]int64{"Scheduler": 1, "Server": 2, "Worker": 3},
+			wantMinResources: map[string]interface{}{"cpu": "21", "memory": "21Gi"},
+		},
+		{
+			caseName: "create mxnet job with collective mode",
+			jobObj: &api.PFJob{
+				Name:      "test-mxnet-job",
+				ID:        "job-test-mxnet-collective",
+				Namespace: "default",
+				JobType:   schema.TypeDistributed,
+				JobMode:   schema.EnvJobModeCollective,
+				Framework: schema.FrameworkMXNet,
+				Conf: schema.Conf{
+					Name:    "normal",
+					Command: "sleep 200",
+					Image:   "mockImage",
+					Env:     map[string]string{},
+				},
+				Tasks: []schema.Member{
+					{
+						Replicas: 2,
+						Role:     schema.RoleWorker,
+						Conf: schema.Conf{
+							Flavour: schema.Flavour{Name: "", ResourceInfo: schema.ResourceInfo{
+								CPU: "4", Mem: "4Gi", ScalarResources: schema.ScalarResourcesType{"nvidia.com/gpu": "1"},
+							}},
+						},
+					},
+				},
+			},
+			wantReplicas:     map[string]int64{"Scheduler": 1, "Worker": 2},
+			wantMinResources: map[string]interface{}{"cpu": "9", "memory": "9Gi", "nvidia.com/gpu": "2"},
+		},
+		{
+			caseName: "create mxnet job with server in collective template",
+			jobObj: &api.PFJob{
+				Name:      "test-mxnet-job",
+				ID:        "job-test-mxnet-failed",
+				Namespace: "default",
+				JobType:   schema.TypeDistributed,
+				JobMode:   schema.EnvJobModeCollective,
+				Framework: schema.FrameworkMXNet,
+				Conf: schema.Conf{
+					Name:    "normal",
+					Command: "sleep 200",
+					Image:   "mockImage",
+				},
+				Tasks: []schema.Member{
+					{
+						Replicas: 1,
+						Role:     schema.RolePServer,
+						Conf: schema.Conf{
+							Flavour: schema.Flavour{Name: "", ResourceInfo: schema.ResourceInfo{CPU: "4", Mem: "4Gi"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	mxJob := New(kubeRuntimeClient)
+	for _, test := range tests {
+		t.Run(test.caseName, func(t *testing.T) {
+			err := mxJob.Submit(context.TODO(), test.jobObj)
+			if test.wantErr {
+				assert.Error(t, err)
+				t.Logf("create job failed, err: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			jobObj, err := kubeRuntimeClient.Get(test.jobObj.Namespace, test.jobObj.ID, KubeMXNetFwVersion)
+			assert.NoError(t, err)
+			unObj := jobObj.(*unstructured.Unstructured)
+			replicaSpecs, _, _ := unstructured.NestedMap(unObj.Object, "spec", "mxReplicaSpecs")
+			assert.Equal(t, len(test.wantReplicas), len(replicaSpecs))
+			for replicaType, replicas := range test.wantReplicas {
+				value, _, _ := unstructured.NestedInt64(unObj.Object, "spec", "mxReplicaSpecs", replicaType, "replicas")
+				assert.Equal(t, replicas, value)
+			}
+			// scheduler uses a cpu flavour whatever the flavour of workers is
+			containers, _, _ := unstructured.NestedSlice(unObj.Object, "spec", "mxReplicaSpecs", "Scheduler", "template", "spec", "containers")
+			assert.Equal(t, 1, len(containers))
+			limits, _, _ := unstructured.NestedMap(containers[0].(map[string]interface{}), "resources", "limits")
+			assert.Equal(t, map[string]interface{}{"cpu": "1", "memory": "1Gi"}, limits)
+			minResources, _, _ := unstructured.NestedMap(unObj.Object, "spec", "runPolicy", "schedulingPolicy", "minResources")
+			assert.Equal(t, test.wantMinResources, minResources)
+		})
+	}
+}
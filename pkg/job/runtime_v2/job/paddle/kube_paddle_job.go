@@ -41,6 +41,16 @@ import (
 var (
 	JobGVK              = k8s.PaddleJobGVK
 	KubePaddleFwVersion = client.KubeFrameworkVersion(JobGVK)
+	// replicasPaths is the path of replicas field in PaddleJob for roles which can be scaled
+	replicasPaths = map[pfschema.MemberRole][]string{
+		pfschema.RoleWorker:  {"spec", "worker", "replicas"},
+		pfschema.RolePWorker: {"spec", "worker", "replicas"},
+	}
+)
+
+const (
+	// elasticLevel is the elastic level of paddle job, which allows workers to join or leave while job is running
+	elasticLevel = 1
 )
 
 // KubePaddleJob is an executor struct that runs a paddle job
@@ -209,10 +219,14 @@ func (pj *KubePaddleJob) builtinPaddleJob(pdj *paddlejobv1.PaddleJob, job *api.P
 			log.Errorf("parse resources for %s task failed, err: %v", pj.String(jobName), err)
 			return err
 		}
-		taskResources.Multi(task.Replicas)
+		taskResources.Multi(task.GetMinReplicas())
 		minResources.Add(taskResources)
 		// calculate min available
-		minAvailable += int32(task.Replicas)
+		minAvailable += int32(task.GetMinReplicas())
+		if task.IsElastic() {
+			level := elasticLevel
+			pdj.Spec.Elastic = &level
+		}
 	}
 	// set minAvailable and minResources for paddle job
 	if pdj.Spec.SchedulingPolicy != nil {
@@ -234,6 +248,12 @@ func (pj *KubePaddleJob) patchPaddleTask(resourceSpec *paddlejobv1.ResourceSpec,
 	if resourceSpec.Replicas <= 0 {
 		resourceSpec.Replicas = kuberuntime.DefaultReplicas
 	}
+	// set min and max replicas for elastic task
+	if task.IsElastic() {
+		minReplicas, maxReplicas := task.GetMinReplicas(), task.MaxReplicas
+		resourceSpec.Requests = &minReplicas
+		resourceSpec.Limits = &maxReplicas
+	}
 	// set metadata
 	if task.Name == "" {
 		task.Name = uuid.GenerateIDWithLength(jobID, 3)
@@ -266,6 +286,11 @@ func (pj *KubePaddleJob) Update(ctx context.Context, job *api.PFJob) error {
 		log.Errorf("update %s failed, err: %v", pj.String(jobName), err)
 		return err
 	}
+	// scale replicas of elastic job
+	if err := kuberuntime.UpdateKubeJobReplicas(job, pj.runtimeClient, pj.frameworkVersion, replicasPaths); err != nil {
+		log.Errorf("scale %s failed, err: %v", pj.String(jobName), err)
+		return err
+	}
 	return nil
 }
 
@@ -354,6 +379,14 @@ func (pj *KubePaddleJob) JobStatus(obj interface{}) (api.StatusInfo, error) {
 		log.Errorf("get PaddleJob status failed, err: %v", err)
 		return api.StatusInfo{}, err
 	}
+	if job.Spec.Elastic != nil && job.Spec.Worker != nil && state == pfschema.StatusJobRunning {
+		// record the replicas of elastic workers, the message changes when job is scaled
+		var active int32
+		if job.Status.Worker != nil {
+			active = int32(job.Status.Worker.Running)
+		}
+		msg = kuberuntime.ElasticReplicasMessage(msg, int32(job.Spec.Worker.Replicas), active)
+	}
 	log.Infof("Paddle job status: %s", state)
 	return api.StatusInfo{
 		OriginStatus: string(job.Status.Phase),
@@ -372,9 +405,12 @@ func (pj *KubePaddleJob) getJobStatus(jobStatus *paddlejobv1.PaddleJobStatus) (p
 	case paddlejobv1.Starting, paddlejobv1.Pending:
 		status = pfschema.StatusJobPending
 		msg = "paddle job is pending"
-	case paddlejobv1.Running, paddlejobv1.Restarting, paddlejobv1.Completing, paddlejobv1.Scaling:
+	case paddlejobv1.Running, paddlejobv1.Restarting, paddlejobv1.Completing:
 		status = pfschema.StatusJobRunning
 		msg = "paddle job is running"
+	case paddlejobv1.Scaling:
+		status = pfschema.StatusJobRunning
+		msg = "paddle job is scaling"
 	case paddlejobv1.Terminating, paddlejobv1.Aborting:
 		status = pfschema.StatusJobTerminating
 		msg = "paddle job is terminating"
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/k8s"
@@ -578,3 +579,78 @@ func TestPaddleJob_CreateJob(t *testing.T) {
 		})
 	}
 }
+
+func TestPaddleJob_Elastic(t *testing.T) {
+	config.GlobalServerConfig = &config.ServerConfig{}
+	config.GlobalServerConfig.Job.SchedulerName = "testSchedulerName"
+	defaultJobYamlPath := "../../../../../config/server/default/job/job_template.yaml"
+	config.InitJobTemplate(defaultJobYamlPath)
+
+	var server = httptest.NewServer(k8s.DiscoveryHandlerFunc)
+	defer server.Close()
+	kubeRuntimeClient := client.NewFakeKubeRuntimeClient(server)
+	driver.InitMockDB()
+
+	jobObj := &api.PFJob{
+		ID:        "job-elastic-paddle",
+		Namespace: "default",
+		JobType:   schema.TypeDistributed,
+		Framework: schema.FrameworkPaddle,
+		JobMode:   schema.EnvJobModeCollective,
+		UserName:  "root",
+		QueueID:   "mockQueueID",
+		Conf: schema.Conf{
+			Name:    "normal",
+			Command: "sleep 200",
+			Image:   "mockImage",
+		},
+		Tasks: []schema.Member{
+			{
+				ID:          "task-elastic-0001",
+				Replicas:    2,
+				MinReplicas: 1,
+				MaxReplicas: 4,
+				Role:        schema.RoleWorker,
+				Conf: schema.Conf{
+					Name:    "normal",
+					Command: "sleep 200",
+					Image:   "mockImage",
+					Flavour: schema.Flavour{Name: "cpu", ResourceInfo: schema.ResourceInfo{CPU: "2", Mem: "2"}},
+				},
+			},
+		},
+	}
+
+	paddleJob := New(kubeRuntimeClient)
+	err := paddleJob.Submit(context.TODO(), jobObj)
+	assert.NoError(t, err)
+	obj, err := kubeRuntimeClient.Get(jobObj.Namespace, jobObj.ID, KubePaddleFwVersion)
+	assert.NoError(t, err)
+	unObj := obj.(*unstructured.Unstructured)
+	elastic, _, _ := unstructured.NestedInt64(unObj.Object, "spec", "elastic")
+	requests, _, _ := unstructured.NestedInt64(unObj.Object, "spec", "worker", "requests")
+	limits, _, _ := unstructured.NestedInt64(unObj.Object, "spec", "worker", "limits")
+	minAvailable, _, _ := unstructured.NestedInt64(unObj.Object, "spec", "schedulingPolicy", "minAvailable")
+	assert.Equal(t, int64(elasticLevel), elastic)
+	assert.Equal(t, int64(1), requests)
+	assert.Equal(t, int64(4), limits)
+	assert.Equal(t, int64(1), minAvailable)
+
+	// scale workers
+	jobObj.UpdateReplicas(schema.RoleWorker, 3)
+	err = paddleJob.Update(context.TODO(), jobObj)
+	assert.NoError(t, err)
+	obj, err = kubeRuntimeClient.Get(jobObj.Namespace, jobObj.ID, KubePaddleFwVersion)
+	assert.NoError(t, err)
+	unObj = obj.(*unstructured.Unstructured)
+	replicas, _, _ := unstructured.NestedInt64(unObj.Object, "spec", "worker", "replicas")
+	assert.Equal(t, int64(3), replicas)
+
+	// scale event appears in job status
+	assert.NoError(t, unstructured.SetNestedField(unObj.Object, "Running", "status", "phase"))
+	assert.NoError(t, unstructured.SetNestedField(unObj.Object, int64(2), "status", "worker", "running"))
+	statusInfo, err := paddleJob.(*KubePaddleJob).JobStatus(unObj)
+	assert.NoError(t, err)
+	assert.Equal(t, schema.StatusJobRunning, statusInfo.Status)
+	assert.Equal(t, "paddle job is running; elastic job is scaled to 3 replicas, 2 replicas are active", statusInfo.Message)
+}
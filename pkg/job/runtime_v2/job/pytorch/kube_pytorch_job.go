@@ -41,6 +41,10 @@ import (
 var (
 	JobGVK               = k8s.PyTorchJobGVK
 	KubePyTorchFwVersion = client.KubeFrameworkVersion(JobGVK)
+	// replicasPaths is the path of replicas field in PyTorchJob for roles which can be scaled
+	replicasPaths = map[pfschema.MemberRole][]string{
+		pfschema.RoleWorker: {"spec", "pytorchReplicaSpecs", string(pytorchv1.PyTorchReplicaTypeWorker), "replicas"},
+	}
 )
 
 // KubePyTorchJob is a struct that runs a pytorch job
@@ -105,7 +109,6 @@ func (pj *KubePyTorchJob) builtinPyTorchJobSpec(torchJobSpec *pytorchv1.PyTorchJ
 	}
 	jobName := job.NamespacedName()
 	log.Debugf("patch %s spec:%#v", pj.String(jobName), torchJobSpec)
-	// TODO: set ElasticPolicy for PyTorchJob
 	// set PyTorchReplicaSpecs
 	minResources := resources.EmptyResource()
 	for _, task := range job.Tasks {
@@ -122,13 +125,17 @@ func (pj *KubePyTorchJob) builtinPyTorchJobSpec(torchJobSpec *pytorchv1.PyTorchJ
 			log.Errorf("build %s RepilcaSpec for %s failed, err: %v", replicaType, pj.String(jobName), err)
 			return err
 		}
+		// set ElasticPolicy for elastic worker
+		if task.IsElastic() && replicaType == pytorchv1.PyTorchReplicaTypeWorker {
+			torchJobSpec.ElasticPolicy = pj.buildElasticPolicy(torchJobSpec.ElasticPolicy, task)
+		}
 		// calculate job minResources
 		taskResources, err := resources.NewResourceFromMap(task.Flavour.ToMap())
 		if err != nil {
 			log.Errorf("parse resources for %s task failed, err: %v", pj.String(jobName), err)
 			return err
 		}
-		taskResources.Multi(task.Replicas)
+		taskResources.Multi(task.GetMinReplicas())
 		minResources.Add(taskResources)
 	}
 	// set RunPolicy
@@ -136,6 +143,22 @@ func (pj *KubePyTorchJob) builtinPyTorchJobSpec(torchJobSpec *pytorchv1.PyTorchJ
 	return kuberuntime.KubeflowRunPolicy(&torchJobSpec.RunPolicy, &resourceList, job.Conf.GetQueueName(), job.Conf.GetPriority())
 }
 
+// buildElasticPolicy set min and max replicas of ElasticPolicy, and use c10d as rendezvous backend by default
+func (pj *KubePyTorchJob) buildElasticPolicy(elasticPolicy *pytorchv1.ElasticPolicy, task pfschema.Member) *pytorchv1.ElasticPolicy {
+	if elasticPolicy == nil {
+		elasticPolicy = &pytorchv1.ElasticPolicy{}
+	}
+	minReplicas := int32(task.GetMinReplicas())
+	maxReplicas := int32(task.MaxReplicas)
+	elasticPolicy.MinReplicas = &minReplicas
+	elasticPolicy.MaxReplicas = &maxReplicas
+	if elasticPolicy.RDZVBackend == nil {
+		backend := pytorchv1.BackendC10D
+		elasticPolicy.RDZVBackend = &backend
+	}
+	return elasticPolicy
+}
+
 // customPyTorchJobSpec set custom PyTorchJob Spec
 func (pj *KubePyTorchJob) customPyTorchJobSpec(torchJobSpec *pytorchv1.PyTorchJobSpec, job *api.PFJob) error {
 	if job == nil || torchJobSpec == nil {
@@ -180,6 +203,11 @@ func (pj *KubePyTorchJob) Update(ctx context.Context, job *api.PFJob) error {
 		log.Errorf("update %s failed, err: %v", pj.String(jobName), err)
 		return err
 	}
+	// scale replicas of elastic job
+	if err := kuberuntime.UpdateKubeJobReplicas(job, pj.runtimeClient, pj.frameworkVersion, replicasPaths); err != nil {
+		log.Errorf("scale %s failed, err: %v", pj.String(jobName), err)
+		return err
+	}
 	return nil
 }
 
@@ -273,6 +301,10 @@ func (pj *KubePyTorchJob) JobStatus(obj interface{}) (api.StatusInfo, error) {
 		log.Errorf("get pytorch status failed, err: %v", err)
 		return api.StatusInfo{}, err
 	}
+	if job.Spec.ElasticPolicy != nil && state == pfschema.StatusJobRunning {
+		// record the replicas of elastic workers, the message changes when job is scaled
+		msg = kuberuntime.ElasticReplicasMessage(msg, getWorkerReplicas(job), activeWorkers(job))
+	}
 	log.Infof("pytorch job status: %s", state)
 	return api.StatusInfo{
 		OriginStatus: string(jobCond.Type),
@@ -281,6 +313,22 @@ func (pj *KubePyTorchJob) JobStatus(obj interface{}) (api.StatusInfo, error) {
 	}, nil
 }
 
+func getWorkerReplicas(job *pytorchv1.PyTorchJob) int32 {
+	worker, find := job.Spec.PyTorchReplicaSpecs[pytorchv1.PyTorchReplicaTypeWorker]
+	if !find || worker == nil || worker.Replicas == nil {
+		return 0
+	}
+	return *worker.Replicas
+}
+
+func activeWorkers(job *pytorchv1.PyTorchJob) int32 {
+	status, find := job.Status.ReplicaStatuses[pytorchv1.PyTorchReplicaTypeWorker]
+	if !find || status == nil {
+		return 0
+	}
+	return status.Active
+}
+
 func (pj *KubePyTorchJob) getJobStatus(jobCond kubeflowv1.JobCondition) (pfschema.JobStatus, string, error) {
 	return kuberuntime.GetKubeflowJobStatus(jobCond)
 }
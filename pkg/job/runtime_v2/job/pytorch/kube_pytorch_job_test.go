@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/k8s"
@@ -104,3 +105,73 @@ func TestPyTorchJob_CreateJob(t *testing.T) {
 		})
 	}
 }
+
+func TestPyTorchJob_Elastic(t *testing.T) {
+	config.GlobalServerConfig = &config.ServerConfig{}
+	config.GlobalServerConfig.Job.SchedulerName = "testSchedulerName"
+	defaultJobYamlPath := "../../../../../config/server/default/job/job_template.yaml"
+	config.InitJobTemplate(defaultJobYamlPath)
+
+	var server = httptest.NewServer(k8s.DiscoveryHandlerFunc)
+	defer server.Close()
+	kubeRuntimeClient := client.NewFakeKubeRuntimeClient(server)
+	driver.InitMockDB()
+
+	jobObj := &api.PFJob{
+		Name:      "test-elastic-pytorch-job",
+		ID:        "job-test-elastic-pytorch",
+		Namespace: "default",
+		JobType:   schema.TypeDistributed,
+		JobMode:   schema.EnvJobModePS,
+		Framework: schema.FrameworkPytorch,
+		Conf: schema.Conf{
+			Name:    "normal",
+			Command: "sleep 200",
+			Image:   "mockImage",
+		},
+		Tasks: []schema.Member{
+			{
+				Replicas: 1,
+				Role:     schema.RoleMaster,
+				Conf: schema.Conf{
+					Flavour: schema.Flavour{Name: "", ResourceInfo: schema.ResourceInfo{CPU: "4", Mem: "4Gi"}},
+				},
+			},
+			{
+				Replicas:    2,
+				MinReplicas: 1,
+				MaxReplicas: 4,
+				Role:        schema.RoleWorker,
+				Conf: schema.Conf{
+					Flavour: schema.Flavour{Name: "", ResourceInfo: schema.ResourceInfo{CPU: "4", Mem: "4Gi"}},
+				},
+			},
+		},
+	}
+
+	pytorchJob := New(kubeRuntimeClient)
+	err := pytorchJob.Submit(context.TODO(), jobObj)
+	assert.NoError(t, err)
+	obj, err := kubeRuntimeClient.Get(jobObj.Namespace, jobObj.ID, KubePyTorchFwVersion)
+	assert.NoError(t, err)
+	unObj := obj.(*unstructured.Unstructured)
+	minReplicas, _, _ := unstructured.NestedInt64(unObj.Object, "spec", "elasticPolicy", "minReplicas")
+	maxReplicas, _, _ := unstructured.NestedInt64(unObj.Object, "spec", "elasticPolicy", "maxReplicas")
+	assert.Equal(t, int64(1), minReplicas)
+	assert.Equal(t, int64(4), maxReplicas)
+
+	// scale workers
+	jobObj.UpdateReplicas(schema.RoleWorker, 3)
+	err = pytorchJob.Update(context.TODO(), jobObj)
+	assert.NoError(t, err)
+	obj, err = kubeRuntimeClient.Get(jobObj.Namespace, jobObj.ID, KubePyTorchFwVersion)
+	assert.NoError(t, err)
+	replicas, _, _ := unstructured.NestedInt64(obj.(*unstructured.Unstructured).Object,
+		"spec", "pytorchReplicaSpecs", "Worker", "replicas")
+	assert.Equal(t, int64(3), replicas)
+
+	// master cannot be scaled
+	jobObj.UpdateReplicas(schema.RoleMaster, 2)
+	err = pytorchJob.Update(context.TODO(), jobObj)
+	assert.Error(t, err)
+}
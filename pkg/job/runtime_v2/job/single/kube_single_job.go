@@ -326,6 +326,10 @@ func getJobMessage(jobStatus *v1.PodStatus) string {
 		return ""
 	}
 	errMessage := "job is failed, "
+	if jobStatus.Reason != "" {
+		// pod level reason, such as Evicted
+		errMessage += fmt.Sprintf("reason: %s, message: %s, ", jobStatus.Reason, jobStatus.Message)
+	}
 	for _, initConStatus := range jobStatus.InitContainerStatuses {
 		if initConStatus.State.Terminated != nil {
 			errMessage += fmt.Sprintf("init container: %s exited with code: %d, reason: %s, message: %s",
@@ -0,0 +1,154 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package single
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/workqueue"
+
+	pfschema "github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/client"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/framework"
+)
+
+var LocalSingleFwVersion = client.LocalProcessFwVersion
+
+// LocalSingleJob is an executor struct that runs a single job as a process on host
+type LocalSingleJob struct {
+	frameworkVersion pfschema.FrameworkVersion
+	runtimeClient    framework.RuntimeClientInterface
+}
+
+func NewLocalJob(runtimeClient framework.RuntimeClientInterface) framework.JobInterface {
+	return &LocalSingleJob{
+		runtimeClient:    runtimeClient,
+		frameworkVersion: LocalSingleFwVersion,
+	}
+}
+
+func (lj *LocalSingleJob) String(name string) string {
+	return fmt.Sprintf("local single job %s on %s", name, lj.runtimeClient.Cluster())
+}
+
+func (lj *LocalSingleJob) Submit(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Debugf("begin to create %s", lj.String(jobName))
+	if job.IsCustomYaml {
+		return fmt.Errorf("create %s failed, custom yaml is not supported by local runtime", lj.String(jobName))
+	}
+
+	process, err := lj.buildProcess(job)
+	if err != nil {
+		log.Errorf("build process for %s failed, err: %v", lj.String(jobName), err)
+		return err
+	}
+	if err = lj.runtimeClient.Create(process, lj.frameworkVersion); err != nil {
+		log.Errorf("create %s failed, err: %v", lj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+func (lj *LocalSingleJob) buildProcess(job *api.PFJob) (*client.LocalProcess, error) {
+	conf := job.Conf
+	if len(job.Tasks) == 1 {
+		conf = job.Tasks[0].Conf
+	}
+	if conf.Command == "" {
+		return nil, fmt.Errorf("command of job %s is empty", job.ID)
+	}
+	env := map[string]string{
+		pfschema.EnvJobNamespace: job.Namespace,
+	}
+	for key, value := range job.Conf.GetEnv() {
+		env[key] = value
+	}
+	for key, value := range conf.GetEnv() {
+		env[key] = value
+	}
+	return &client.LocalProcess{
+		ID:          job.ID,
+		Namespace:   job.Namespace,
+		Command:     conf.Command,
+		Args:        conf.Args,
+		Env:         env,
+		Annotations: job.Annotations,
+	}, nil
+}
+
+func (lj *LocalSingleJob) Stop(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Infof("begin to stop %s", lj.String(jobName))
+	if err := lj.runtimeClient.Delete(job.Namespace, job.ID, lj.frameworkVersion); err != nil {
+		log.Errorf("stop %s failed, err: %v", lj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+func (lj *LocalSingleJob) Update(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	// priority, labels and annotations take no effect on a running process
+	log.Infof("skip update %s, it is not supported by local runtime", lj.String(job.NamespacedName()))
+	return nil
+}
+
+func (lj *LocalSingleJob) Delete(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Infof("begin to delete %s", lj.String(jobName))
+	if err := lj.runtimeClient.Delete(job.Namespace, job.ID, lj.frameworkVersion); err != nil {
+		log.Errorf("delete %s failed, err: %v", lj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+func (lj *LocalSingleJob) GetLog(ctx context.Context, jobLogRequest pfschema.JobLogRequest) (pfschema.JobLogInfo, error) {
+	localClient, ok := lj.runtimeClient.(*client.LocalRuntimeClient)
+	if !ok {
+		return pfschema.JobLogInfo{}, fmt.Errorf("runtime client is not local runtime client")
+	}
+	taskLogs, err := localClient.GetTaskLog(jobLogRequest.JobID, jobLogRequest.LogFilePosition,
+		jobLogRequest.LogPageSize, jobLogRequest.LogPageNo)
+	if err != nil {
+		return pfschema.JobLogInfo{}, err
+	}
+	return pfschema.JobLogInfo{
+		JobID:    jobLogRequest.JobID,
+		TaskList: taskLogs,
+	}, nil
+}
+
+// AddEventListener is not needed by local single job, the events of process are sent by local runtime client
+func (lj *LocalSingleJob) AddEventListener(ctx context.Context, listenerType string, jobQueue workqueue.RateLimitingInterface, listener interface{}) error {
+	return nil
+}
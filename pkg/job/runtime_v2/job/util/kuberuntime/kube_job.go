@@ -67,16 +67,19 @@ func getDefaultTemplate(framework schema.Framework, jobType schema.JobType, jobM
 
 	//the footer comment of all type job as the follow:
 	//  single -> single-job, workflow -> workflow-job,
-	//  spark -> spark-job, ray -> ray-job
+	//  spark -> spark-job, ray -> ray-job, mpi -> mpi-job, xgboost -> xgboost-job
 	//  paddle with ps mode -> paddle-ps-job
 	//  paddle with collective mode -> paddle-collective-job
 	//  tensorflow with ps mode -> tensorflow-ps-job
 	//  pytorch with ps mode -> pytorch-ps-job
+	//  mxnet with ps mode -> mxnet-ps-job
+	//  mxnet with collective mode -> mxnet-collective-job
 	switch jobType {
 	case schema.TypeSingle, schema.TypeWorkflow:
 		jobTemplateName = fmt.Sprintf("%s-job", jobType)
 	case schema.TypeDistributed:
-		if framework == schema.FrameworkSpark || framework == schema.FrameworkRay || framework == schema.FrameworkMPI {
+		if framework == schema.FrameworkSpark || framework == schema.FrameworkRay || framework == schema.FrameworkMPI ||
+			framework == schema.FrameworkXGBoost {
 			jobTemplateName = fmt.Sprintf("%s-job", framework)
 		} else {
 			jobTemplateName = fmt.Sprintf("%s-%s-job", framework, strings.ToLower(jobMode))
@@ -731,6 +734,15 @@ func GetKubeflowJobStatus(jobCond kubeflowv1.JobCondition) (schema.JobStatus, st
 	return status, msg, nil
 }
 
+// ElasticReplicasMessage append the expected and active replicas of elastic job to message
+func ElasticReplicasMessage(msg string, replicas, active int32) string {
+	elasticMsg := fmt.Sprintf("elastic job is scaled to %d replicas, %d replicas are active", replicas, active)
+	if msg == "" {
+		return elasticMsg
+	}
+	return fmt.Sprintf("%s; %s", msg, elasticMsg)
+}
+
 // BuildPodTemplateSpec build PodTemplateSpec for built-in distributed job, such as PaddleJob, PyTorchJob, TFJob and so on
 func BuildPodTemplateSpec(podSpec *corev1.PodTemplateSpec, jobID string, task *schema.Member) error {
 	if podSpec == nil || task == nil {
@@ -800,7 +812,8 @@ func getPodGroupName(jobID string) string {
 	}
 	pgName := ""
 	switch job.Framework {
-	case schema.FrameworkPaddle, schema.FrameworkPytorch, schema.FrameworkTF, schema.FrameworkMXNet:
+	case schema.FrameworkPaddle, schema.FrameworkPytorch, schema.FrameworkTF, schema.FrameworkMXNet,
+		schema.FrameworkXGBoost:
 		pgName = jobID
 	case schema.FrameworkSpark:
 		pgName = fmt.Sprintf("spark-%s-pg", jobID)
@@ -906,3 +919,39 @@ func UpdateKubeJob(job *api.PFJob, runtimeClient framework.RuntimeClientInterfac
 	}
 	return nil
 }
+
+// UpdateKubeJobReplicas scale replicas of kubernetes job, replicasPaths is the path of replicas field for each role
+func UpdateKubeJobReplicas(job *api.PFJob, runtimeClient framework.RuntimeClientInterface, fv schema.FrameworkVersion,
+	replicasPaths map[schema.MemberRole][]string) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	if len(job.Replicas) == 0 {
+		return nil
+	}
+	jobmsg := fmt.Sprintf("%s job %s on %s", fv.String(), job.NamespacedName(), runtimeClient.Cluster())
+	patch := map[string]interface{}{}
+	for role, replicas := range job.Replicas {
+		fields, find := replicasPaths[role]
+		if !find {
+			err := fmt.Errorf("the replicas of role %s cannot be scaled for %s", role, jobmsg)
+			log.Errorln(err)
+			return err
+		}
+		if err := unstructured.SetNestedField(patch, int64(replicas), fields...); err != nil {
+			log.Errorf("set replicas of role %s for %s failed, err: %v", role, jobmsg, err)
+			return err
+		}
+	}
+	updateData, err := json.Marshal(patch)
+	if err != nil {
+		log.Errorf("scale %s failed, err: %v", jobmsg, err)
+		return err
+	}
+	log.Infof("begin to scale %s, data: %s", jobmsg, string(updateData))
+	if err = runtimeClient.Patch(job.Namespace, job.ID, fv, updateData); err != nil {
+		log.Errorf("scale %s failed, err: %v", jobmsg, err)
+		return err
+	}
+	return nil
+}
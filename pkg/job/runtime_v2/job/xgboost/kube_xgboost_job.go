@@ -0,0 +1,286 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xgboost
+
+import (
+	"context"
+	"fmt"
+
+	kubeflowv1 "github.com/kubeflow/common/pkg/apis/common/v1"
+	xgboostv1 "github.com/kubeflow/training-operator/pkg/apis/xgboost/v1"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/k8s"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/resources"
+	pfschema "github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/client"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/framework"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job/util/kuberuntime"
+)
+
+var (
+	JobGVK               = k8s.XGBoostJobGVK
+	KubeXGBoostFwVersion = client.KubeFrameworkVersion(JobGVK)
+)
+
+// KubeXGBoostJob is a struct that runs a xgboost job
+type KubeXGBoostJob struct {
+	GVK              schema.GroupVersionKind
+	frameworkVersion pfschema.FrameworkVersion
+	runtimeClient    framework.RuntimeClientInterface
+	jobQueue         workqueue.RateLimitingInterface
+}
+
+func New(kubeClient framework.RuntimeClientInterface) framework.JobInterface {
+	return &KubeXGBoostJob{
+		runtimeClient:    kubeClient,
+		GVK:              JobGVK,
+		frameworkVersion: KubeXGBoostFwVersion,
+	}
+}
+
+func (xj *KubeXGBoostJob) String(name string) string {
+	return fmt.Sprintf("%s job %s on %s", xj.GVK.String(), name, xj.runtimeClient.Cluster())
+}
+
+func (xj *KubeXGBoostJob) Submit(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	xgbJob := &xgboostv1.XGBoostJob{}
+	if err := kuberuntime.CreateKubeJobFromYaml(xgbJob, xj.GVK, job); err != nil {
+		log.Errorf("create %s failed, err %v", xj.String(jobName), err)
+		return err
+	}
+
+	var err error
+	// set metadata field
+	kuberuntime.BuildJobMetadata(&xgbJob.ObjectMeta, job)
+	// set spec field
+	if job.IsCustomYaml {
+		// set custom XGBoostJob Spec from user
+		err = xj.customXGBoostJobSpec(&xgbJob.Spec, job)
+	} else {
+		// set builtin XGBoostJob Spec
+		err = xj.builtinXGBoostJobSpec(&xgbJob.Spec, job)
+	}
+	if err != nil {
+		log.Errorf("build %s spec failed, err %v", xj.String(jobName), err)
+		return err
+	}
+	log.Debugf("begin to create %s, job info: %v", xj.String(jobName), xgbJob)
+	err = xj.runtimeClient.Create(xgbJob, xj.frameworkVersion)
+	if err != nil {
+		log.Errorf("create %s failed, err %v", xj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+// builtinXGBoostJobSpec set build-in XGBoostJob spec
+func (xj *KubeXGBoostJob) builtinXGBoostJobSpec(xgbJobSpec *xgboostv1.XGBoostJobSpec, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Debugf("patch %s spec:%#v", xj.String(jobName), xgbJobSpec)
+	// set XGBReplicaSpecs
+	minResources := resources.EmptyResource()
+	for _, task := range job.Tasks {
+		// xgboost master, which runs the rabit tracker
+		replicaType := xgboostv1.XGBoostReplicaTypeMaster
+		if task.Role == pfschema.RoleWorker || task.Role == pfschema.RolePWorker {
+			// xgboost worker
+			replicaType = xgboostv1.XGBoostReplicaTypeWorker
+		}
+		replicaSpec, ok := xgbJobSpec.XGBReplicaSpecs[replicaType]
+		if !ok || replicaSpec == nil {
+			return fmt.Errorf("replica type %s for %s is not supported", replicaType, xj.String(jobName))
+		}
+		if err := kuberuntime.KubeflowReplicaSpec(replicaSpec, job.ID, &task); err != nil {
+			log.Errorf("build %s RepilcaSpec for %s failed, err: %v", replicaType, xj.String(jobName), err)
+			return err
+		}
+		// calculate job minResources
+		taskResources, err := resources.NewResourceFromMap(task.Flavour.ToMap())
+		if err != nil {
+			log.Errorf("parse resources for %s task failed, err: %v", xj.String(jobName), err)
+			return err
+		}
+		taskResources.Multi(task.Replicas)
+		minResources.Add(taskResources)
+	}
+	// set RunPolicy
+	resourceList := k8s.NewResourceList(minResources)
+	return kuberuntime.KubeflowRunPolicy(&xgbJobSpec.RunPolicy, &resourceList, job.Conf.GetQueueName(), job.Conf.GetPriority())
+}
+
+// customXGBoostJobSpec set custom XGBoostJob Spec
+func (xj *KubeXGBoostJob) customXGBoostJobSpec(xgbJobSpec *xgboostv1.XGBoostJobSpec, job *api.PFJob) error {
+	if job == nil || xgbJobSpec == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Debugf("patch %s spec:%#v", xj.String(jobName), xgbJobSpec)
+	// patch metadata
+	master, find := xgbJobSpec.XGBReplicaSpecs[xgboostv1.XGBoostReplicaTypeMaster]
+	if find && master != nil {
+		kuberuntime.BuildTaskMetadata(&master.Template.ObjectMeta, job.ID, &pfschema.Conf{})
+	}
+	worker, find := xgbJobSpec.XGBReplicaSpecs[xgboostv1.XGBoostReplicaTypeWorker]
+	if find && worker != nil {
+		kuberuntime.BuildTaskMetadata(&worker.Template.ObjectMeta, job.ID, &pfschema.Conf{})
+	}
+	// TODO: patch xgboost job from user
+	// check RunPolicy
+	return kuberuntime.KubeflowRunPolicy(&xgbJobSpec.RunPolicy, nil, job.Conf.GetQueueName(), job.Conf.GetPriority())
+}
+
+func (xj *KubeXGBoostJob) Stop(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Infof("begin to stop %s", xj.String(jobName))
+	if err := xj.runtimeClient.Delete(job.Namespace, job.ID, xj.frameworkVersion); err != nil {
+		log.Errorf("stop %s failed, err: %v", xj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+func (xj *KubeXGBoostJob) Update(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Infof("begin to update %s", xj.String(jobName))
+	if err := kuberuntime.UpdateKubeJob(job, xj.runtimeClient, xj.frameworkVersion); err != nil {
+		log.Errorf("update %s failed, err: %v", xj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+func (xj *KubeXGBoostJob) Delete(ctx context.Context, job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("job is nil")
+	}
+	jobName := job.NamespacedName()
+	log.Infof("begin to delete %s ", xj.String(jobName))
+	if err := xj.runtimeClient.Delete(job.Namespace, job.ID, xj.frameworkVersion); err != nil {
+		log.Errorf("delete %s failed, err %v", xj.String(jobName), err)
+		return err
+	}
+	return nil
+}
+
+func (xj *KubeXGBoostJob) GetLog(ctx context.Context, jobLogRequest pfschema.JobLogRequest) (pfschema.JobLogInfo, error) {
+	// TODO: add get log logic
+	return pfschema.JobLogInfo{}, nil
+}
+
+func (xj *KubeXGBoostJob) AddEventListener(ctx context.Context, listenerType string, jobQueue workqueue.RateLimitingInterface, listener interface{}) error {
+	var err error
+	switch listenerType {
+	case pfschema.ListenerTypeJob:
+		err = xj.addJobEventListener(ctx, jobQueue, listener)
+	default:
+		err = fmt.Errorf("listenerType %s is not supported", listenerType)
+	}
+	return err
+}
+
+func (xj *KubeXGBoostJob) addJobEventListener(ctx context.Context, jobQueue workqueue.RateLimitingInterface, listener interface{}) error {
+	if jobQueue == nil || listener == nil {
+		return fmt.Errorf("add job event listener failed, err: listener is nil")
+	}
+	xj.jobQueue = jobQueue
+	informer := listener.(cache.SharedIndexInformer)
+	informer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: kuberuntime.ResponsibleForJob,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    xj.addJob,
+			UpdateFunc: xj.updateJob,
+			DeleteFunc: xj.deleteJob,
+		},
+	})
+	return nil
+}
+
+func (xj *KubeXGBoostJob) addJob(obj interface{}) {
+	jobSyncInfo, err := kuberuntime.JobAddFunc(obj, xj.JobStatus)
+	if err != nil {
+		return
+	}
+	xj.jobQueue.Add(jobSyncInfo)
+}
+
+func (xj *KubeXGBoostJob) updateJob(old, new interface{}) {
+	jobSyncInfo, err := kuberuntime.JobUpdateFunc(old, new, xj.JobStatus)
+	if err != nil {
+		return
+	}
+	xj.jobQueue.Add(jobSyncInfo)
+}
+
+func (xj *KubeXGBoostJob) deleteJob(obj interface{}) {
+	jobSyncInfo, err := kuberuntime.JobDeleteFunc(obj, xj.JobStatus)
+	if err != nil {
+		return
+	}
+	xj.jobQueue.Add(jobSyncInfo)
+}
+
+// JobStatus get the statusInfo of XGBoost job, including origin status, pf status and message
+func (xj *KubeXGBoostJob) JobStatus(obj interface{}) (api.StatusInfo, error) {
+	unObj := obj.(*unstructured.Unstructured)
+	// convert to XGBoostJob struct
+	job := &xgboostv1.XGBoostJob{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unObj.Object, job); err != nil {
+		log.Errorf("convert unstructured object [%+v] to %s job failed. error: %s", obj, xj.GVK.String(), err)
+		return api.StatusInfo{}, err
+	}
+	// convert job status
+	condLen := len(job.Status.Conditions)
+	var jobCond kubeflowv1.JobCondition
+	if condLen >= 1 {
+		jobCond = job.Status.Conditions[condLen-1]
+	}
+	state, msg, err := xj.getJobStatus(jobCond)
+	if err != nil {
+		log.Errorf("get xgboost job status failed, err: %v", err)
+		return api.StatusInfo{}, err
+	}
+	log.Infof("xgboost job status: %s", state)
+	return api.StatusInfo{
+		OriginStatus: string(jobCond.Type),
+		Status:       state,
+		Message:      msg,
+	}, nil
+}
+
+func (xj *KubeXGBoostJob) getJobStatus(jobCond kubeflowv1.JobCondition) (pfschema.JobStatus, string, error) {
+	return kuberuntime.GetKubeflowJobStatus(jobCond)
+}
@@ -727,7 +727,7 @@ func (kr *KubeRuntime) getPersistentVolumeClaim(namespace, name string, getOptio
 	return kr.clientset().CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, getOptions)
 }
 
-func (kr *KubeRuntime) listNodes(listOptions metav1.ListOptions) (*corev1.NodeList, error) {
+func (kr *KubeRuntime) ListNodes(listOptions metav1.ListOptions) (*corev1.NodeList, error) {
 	return kr.clientset().CoreV1().Nodes().List(context.TODO(), listOptions)
 }
 
@@ -739,6 +739,12 @@ func (kr *KubeRuntime) DeletePod(namespace, name string) error {
 	return kr.clientset().CoreV1().Pods(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
 }
 
+func (kr *KubeRuntime) PatchPod(namespace, name string, data []byte) error {
+	_, err := kr.clientset().CoreV1().Pods(namespace).
+		Patch(context.TODO(), name, types.JSONPatchType, data, metav1.PatchOptions{})
+	return err
+}
+
 func (kr *KubeRuntime) getNodeQuotaListImpl(subQuotaFn func(r *resources.Resource, pod *corev1.Pod) error) (
 	pfschema.QuotaSummary, []pfschema.NodeQuotaInfo, error) {
 	result := []pfschema.NodeQuotaInfo{}
@@ -746,7 +752,7 @@ func (kr *KubeRuntime) getNodeQuotaListImpl(subQuotaFn func(r *resources.Resourc
 		TotalQuota: *k8s.NewResource(corev1.ResourceList{}),
 		IdleQuota:  *k8s.NewResource(corev1.ResourceList{}),
 	}
-	nodes, _ := kr.listNodes(metav1.ListOptions{})
+	nodes, _ := kr.ListNodes(metav1.ListOptions{})
 	log.Infof("ListNodeQuota nodes Items len: %d", len(nodes.Items))
 
 	for _, node := range nodes.Items {
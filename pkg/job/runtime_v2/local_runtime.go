@@ -0,0 +1,176 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime_v2
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	pfschema "github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/api"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/client"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/controller"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/framework"
+	_ "github.com/PaddlePaddle/PaddleFlow/pkg/job/runtime_v2/job"
+)
+
+// LocalRuntime runs jobs as processes on the host of PaddleFlow server, only single job is supported
+type LocalRuntime struct {
+	cluster     pfschema.Cluster
+	localClient *client.LocalRuntimeClient
+}
+
+func NewLocalRuntime(cluster pfschema.Cluster) RuntimeService {
+	cluster.Type = pfschema.LocalType
+	return &LocalRuntime{
+		cluster: cluster,
+	}
+}
+
+func (lr *LocalRuntime) Name() string {
+	return fmt.Sprintf("local runtime for cluster: %s", lr.cluster.Name)
+}
+
+func (lr *LocalRuntime) String() string {
+	msg := "local runtime"
+	if lr.localClient != nil {
+		msg = lr.localClient.Cluster()
+	}
+	return msg
+}
+
+func (lr *LocalRuntime) Init() error {
+	localClient, err := client.CreateLocalRuntimeClient(&lr.cluster)
+	if err != nil {
+		log.Errorf("create local client failed, err: %v", err)
+		return err
+	}
+	lr.localClient = localClient
+	return nil
+}
+
+func (lr *LocalRuntime) Client() framework.RuntimeClientInterface {
+	return lr.localClient
+}
+
+func (lr *LocalRuntime) SyncController(stopCh <-chan struct{}) {
+	log.Infof("start job controller on %s", lr.String())
+	jobQueueSync := os.Getenv(pfschema.EnvEnableJobQueueSync)
+	if jobQueueSync == "false" {
+		log.Warnf("skip job syn controller on %s", lr.String())
+		return
+	}
+	jobController := controller.NewJobSync()
+	if err := jobController.Initialize(lr.localClient); err != nil {
+		log.Errorf("init job controller on %s failed, err: %v", lr.String(), err)
+		return
+	}
+	go jobController.Run(stopCh)
+}
+
+func (lr *LocalRuntime) SubmitJob(job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("submit job failed, job is nil")
+	}
+	log.Infof("submit job[%v] to cluster[%s] queue[%s]", job.ID, lr.cluster.ID, job.QueueID)
+	fwVersion := lr.localClient.JobFrameworkVersion(job.JobType, job.Framework)
+	err := lr.Job(fwVersion).Submit(context.TODO(), job)
+	if err != nil {
+		log.Warnf("create local job[%s] failed, err: %v", job.Name, err)
+		return err
+	}
+	log.Debugf("submit local job[%s] successful", job.ID)
+	return nil
+}
+
+func (lr *LocalRuntime) StopJob(job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("stop job failed, job is nil")
+	}
+	fwVersion := lr.localClient.JobFrameworkVersion(job.JobType, job.Framework)
+	return lr.Job(fwVersion).Stop(context.TODO(), job)
+}
+
+func (lr *LocalRuntime) UpdateJob(job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("update job failed, job is nil")
+	}
+	fwVersion := lr.localClient.JobFrameworkVersion(job.JobType, job.Framework)
+	return lr.Job(fwVersion).Update(context.TODO(), job)
+}
+
+func (lr *LocalRuntime) DeleteJob(job *api.PFJob) error {
+	if job == nil {
+		return fmt.Errorf("delete job failed, job is nil")
+	}
+	fwVersion := lr.localClient.JobFrameworkVersion(job.JobType, job.Framework)
+	return lr.Job(fwVersion).Delete(context.TODO(), job)
+}
+
+func (lr *LocalRuntime) GetLog(jobLogRequest pfschema.JobLogRequest, mixedLogRequest pfschema.MixedLogRequest) (pfschema.JobLogInfo, error) {
+	if jobLogRequest.JobID == "" {
+		return pfschema.JobLogInfo{}, fmt.Errorf("get log of %s is not supported on %s",
+			mixedLogRequest.ResourceType, lr.String())
+	}
+	fwVersion := lr.localClient.JobFrameworkVersion(pfschema.JobType(jobLogRequest.JobType), "")
+	return lr.Job(fwVersion).GetLog(context.TODO(), jobLogRequest)
+}
+
+func (lr *LocalRuntime) Job(fwVersion pfschema.FrameworkVersion) framework.JobInterface {
+	jobPlugin, found := framework.GetJobPlugin(lr.cluster.Type, fwVersion)
+	if !found {
+		log.Errorf("get job plugin on %s failed, err: %s job is not implemented", lr.String(), fwVersion)
+		return &framework.JobSample{}
+	}
+	return jobPlugin(lr.localClient)
+}
+
+// CreateQueue queue of local runtime is only stored in database, so there is nothing to do on cluster
+func (lr *LocalRuntime) CreateQueue(q *api.QueueInfo) error {
+	if q == nil {
+		return fmt.Errorf("create queue failed, queue is nil")
+	}
+	log.Infof("create queue %s on %s", q.Name, lr.String())
+	return nil
+}
+
+func (lr *LocalRuntime) DeleteQueue(q *api.QueueInfo) error {
+	if q == nil {
+		return fmt.Errorf("delete queue failed, queue is nil")
+	}
+	log.Infof("delete queue %s on %s", q.Name, lr.String())
+	return nil
+}
+
+func (lr *LocalRuntime) UpdateQueue(q *api.QueueInfo) error {
+	if q == nil {
+		return fmt.Errorf("update queue failed, queue is nil")
+	}
+	log.Infof("update queue %s on %s", q.Name, lr.String())
+	return nil
+}
+
+func (lr *LocalRuntime) Queue(fwVersion pfschema.FrameworkVersion) framework.QueueInterface {
+	return &framework.QueueSample{}
+}
+
+func (lr *LocalRuntime) ListNodeQuota() (pfschema.QuotaSummary, []pfschema.NodeQuotaInfo, error) {
+	return lr.localClient.ListNodeQuota(context.TODO())
+}
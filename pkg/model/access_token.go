@@ -0,0 +1,62 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccessToken is a long-lived token of user with limited scopes, which is revoked by deleting it
+type AccessToken struct {
+	Pk        int64          `json:"-" gorm:"primaryKey;autoIncrement"`
+	ID        string         `json:"tokenID" gorm:"type:varchar(60);uniqueIndex"`
+	Name      string         `json:"name" gorm:"type:varchar(128)"`
+	UserName  string         `json:"userName" gorm:"type:varchar(60);index"`
+	RawScopes string         `json:"-" gorm:"column:scopes;type:text"`
+	Scopes    []string       `json:"scopes" gorm:"-"`
+	ExpiresAt *time.Time     `json:"expireTime,omitempty"`
+	CreatedAt time.Time      `json:"createTime"`
+	UpdatedAt time.Time      `json:"updateTime,omitempty"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+func (AccessToken) TableName() string {
+	return "access_token"
+}
+
+// IsExpired returns true if token has expire time and it is passed
+func (token *AccessToken) IsExpired() bool {
+	return token.ExpiresAt != nil && token.ExpiresAt.Before(time.Now())
+}
+
+// BeforeSave joins scopes to save in one column
+func (token *AccessToken) BeforeSave(*gorm.DB) error {
+	token.RawScopes = strings.Join(token.Scopes, ",")
+	return nil
+}
+
+// AfterFind splits scopes saved
+func (token *AccessToken) AfterFind(*gorm.DB) error {
+	token.Scopes = nil
+	if token.RawScopes != "" {
+		token.Scopes = strings.Split(token.RawScopes, ",")
+	}
+	return nil
+}
@@ -31,7 +31,7 @@ const FlavourTableName = "flavour"
 type Flavour struct {
 	Model              `gorm:"embedded"  json:",inline"`
 	Pk                 int64                      `json:"-"           gorm:"primaryKey;autoIncrement"`
-	Name               string                     `json:"name"        gorm:"uniqueIndex"`
+	Name               string                     `json:"name"        gorm:"type:varchar(60);uniqueIndex"`
 	ClusterID          string                     `json:"-"   gorm:"column:cluster_id;default:''"`
 	ClusterName        string                     `json:"-" gorm:"column:cluster_name;->"`
 	CPU                string                     `json:"cpu"         gorm:"column:cpu"`
@@ -21,6 +21,9 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/secret"
+	fsCommon "github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
 )
 
 const (
@@ -47,22 +50,91 @@ func (FileSystem) TableName() string {
 // AfterFind is the callback methods doing after the find file system
 func (s *FileSystem) AfterFind(*gorm.DB) error {
 	if s.PropertiesJson != "" {
-		s.PropertiesMap = make(map[string]string)
-		if err := json.Unmarshal([]byte(s.PropertiesJson), &s.PropertiesMap); err != nil {
-			log.Errorf("json Unmarshal propertiesJson[%s] failed: %v", s.PropertiesJson, err)
+		propertiesMap, err := unmarshalProperties(s.PropertiesJson)
+		if err != nil {
+			log.Errorf("unmarshal properties of fs[%s] failed: %v", s.ID, err)
 			return err
 		}
+		s.PropertiesMap = propertiesMap
 	}
 	return nil
 }
 
 // BeforeSave is the callback methods for saving file system
 func (s *FileSystem) BeforeSave(*gorm.DB) error {
-	propertiesJson, err := json.Marshal(&s.PropertiesMap)
+	propertiesJson, err := marshalProperties(s.PropertiesMap)
 	if err != nil {
-		log.Errorf("json Marshal propertiesMap[%v] failed: %v", s.PropertiesMap, err)
+		log.Errorf("marshal properties of fs[%s] failed: %v", s.ID, err)
 		return err
 	}
-	s.PropertiesJson = string(propertiesJson)
+	s.PropertiesJson = propertiesJson
 	return nil
 }
+
+// RedactedProperties returns properties with secrets hidden, which is used in api responses
+func RedactedProperties(properties map[string]string) map[string]string {
+	if properties == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(properties))
+	for k, v := range properties {
+		if fsCommon.IsSecretProperty(k) {
+			v = secret.Redact(v)
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// PropertiesEncrypted returns whether secret properties in properties json are all encrypted
+func PropertiesEncrypted(propertiesJson string) (bool, error) {
+	properties := make(map[string]string)
+	if err := json.Unmarshal([]byte(propertiesJson), &properties); err != nil {
+		return false, err
+	}
+	for k, v := range properties {
+		if fsCommon.IsSecretProperty(k) && v != "" && !secret.IsEncrypted(v) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// marshalProperties encrypts secret properties such as secret key and password, other properties are kept in plaintext
+func marshalProperties(properties map[string]string) (string, error) {
+	var encrypted map[string]string
+	if properties != nil {
+		encrypted = make(map[string]string, len(properties))
+	}
+	for k, v := range properties {
+		if fsCommon.IsSecretProperty(k) {
+			var err error
+			if v, err = secret.Encrypt(v); err != nil {
+				return "", err
+			}
+		}
+		encrypted[k] = v
+	}
+	propertiesJson, err := json.Marshal(&encrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(propertiesJson), nil
+}
+
+func unmarshalProperties(propertiesJson string) (map[string]string, error) {
+	properties := make(map[string]string)
+	if err := json.Unmarshal([]byte(propertiesJson), &properties); err != nil {
+		return nil, err
+	}
+	for k, v := range properties {
+		if fsCommon.IsSecretProperty(k) {
+			plaintext, err := secret.Decrypt(v)
+			if err != nil {
+				return nil, err
+			}
+			properties[k] = plaintext
+		}
+	}
+	return properties, nil
+}
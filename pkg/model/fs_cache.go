@@ -38,6 +38,15 @@ type FSCache struct {
 	CreatedAt   time.Time      `json:"-"`
 	UpdatedAt   time.Time      `json:"-"`
 	DeletedAt   gorm.DeletedAt `json:"-"`
+
+	// progress of the latest warmup task on the node
+	WarmupID     string `json:"warmupID"     gorm:"type:varchar(36);column:warmup_id;default:''"`
+	WarmupStatus string `json:"warmupStatus" gorm:"type:varchar(32);column:warmup_status;default:''"`
+	WarmupTotal  int64  `json:"warmupTotal"  gorm:"type:bigint(20);column:warmup_total;default:0"`
+	WarmupDone   int64  `json:"warmupDone"   gorm:"type:bigint(20);column:warmup_done;default:0"`
+	WarmupFailed int64  `json:"warmupFailed" gorm:"type:bigint(20);column:warmup_failed;default:0"`
+	WarmupBytes  int64  `json:"warmupBytes"  gorm:"type:bigint(20);column:warmup_bytes;default:0"`
+	WarmupMsg    string `json:"warmupMsg"    gorm:"type:varchar(1024);column:warmup_msg;default:''"`
 }
 
 func (c *FSCache) TableName() string {
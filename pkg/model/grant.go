@@ -22,15 +22,19 @@ import (
 	"gorm.io/gorm"
 )
 
+// Grant 授予用户或用户组对资源的角色, UserName和GroupName有且只有一个不为空
 type Grant struct {
-	Pk           int64          `json:"-" gorm:"primaryKey;autoIncrement"`
-	ID           string         `json:"grantID" gorm:"uniqueIndex"`
-	UserName     string         `json:"userName"`
-	ResourceType string         `json:"resourceType"`
-	ResourceID   string         `json:"resourceID"`
-	CreatedAt    time.Time      `json:"createTime"`
-	UpdatedAt    time.Time      `json:"updateTime,omitempty"`
-	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+	Pk           int64  `json:"-" gorm:"primaryKey;autoIncrement"`
+	ID           string `json:"grantID" gorm:"type:varchar(60);uniqueIndex"`
+	UserName     string `json:"userName"`
+	GroupName    string `json:"groupName,omitempty" gorm:"type:varchar(60);not null;default:''"`
+	ResourceType string `json:"resourceType"`
+	ResourceID   string `json:"resourceID"`
+	// Role 旧版本的授权没有角色, 升级后为submitter
+	Role      string         `json:"role" gorm:"type:varchar(32);not null;default:'submitter'"`
+	CreatedAt time.Time      `json:"createTime"`
+	UpdatedAt time.Time      `json:"updateTime,omitempty"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 func (Grant) TableName() string {
@@ -0,0 +1,46 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+)
+
+// Group 用户组, 授予用户组的权限对组内所有用户生效
+type Group struct {
+	Pk          int64     `json:"-" gorm:"primaryKey;autoIncrement"`
+	Name        string    `json:"name" gorm:"type:varchar(60);uniqueIndex"`
+	Description string    `json:"description" gorm:"type:varchar(255)"`
+	CreatedAt   time.Time `json:"createTime"`
+	UpdatedAt   time.Time `json:"updateTime,omitempty"`
+}
+
+func (Group) TableName() string {
+	return "user_group"
+}
+
+// GroupMember 用户组成员
+type GroupMember struct {
+	Pk        int64     `json:"-" gorm:"primaryKey;autoIncrement"`
+	GroupName string    `json:"groupName" gorm:"type:varchar(60);uniqueIndex:idx_group_user"`
+	UserName  string    `json:"userName" gorm:"type:varchar(60);uniqueIndex:idx_group_user;index"`
+	CreatedAt time.Time `json:"createTime"`
+}
+
+func (GroupMember) TableName() string {
+	return "user_group_member"
+}
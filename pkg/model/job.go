@@ -32,8 +32,8 @@ type Job struct {
 	Pk                int64               `json:"-" gorm:"primaryKey;autoIncrement"`
 	ID                string              `json:"jobID" gorm:"type:varchar(60);index:idx_id,unique;NOT NULL"`
 	Name              string              `json:"jobName" gorm:"type:varchar(512);default:''"`
-	UserName          string              `json:"userName" gorm:"NOT NULL"`
-	QueueID           string              `json:"queueID" gorm:"NOT NULL"`
+	UserName          string              `json:"userName" gorm:"type:varchar(60);NOT NULL"`
+	QueueID           string              `json:"queueID" gorm:"type:varchar(60);NOT NULL"`
 	Type              string              `json:"type" gorm:"type:varchar(20);NOT NULL"`
 	ConfigJson        string              `json:"-" gorm:"column:config;type:text"`
 	Config            *schema.Conf        `json:"config" gorm:"-"`
@@ -52,8 +52,9 @@ type Job struct {
 	ParentJob         string              `json:"-" gorm:"type:varchar(60)"`
 	CreatedAt         time.Time           `json:"createTime"`
 	ActivatedAt       sql.NullTime        `json:"activateTime"`
+	Deadline          sql.NullTime        `json:"-" gorm:"index"`
 	UpdatedAt         time.Time           `json:"updateTime,omitempty"`
-	DeletedAt         string              `json:"-" gorm:"index:idx_id"`
+	DeletedAt         string              `json:"-" gorm:"type:varchar(64);index:idx_id"`
 }
 
 func (Job) TableName() string {
@@ -17,7 +17,6 @@ limitations under the License.
 package model
 
 import (
-	"encoding/json"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -46,22 +45,23 @@ func (Link) TableName() string {
 // AfterFind is the callback methods doing after the find link
 func (s *Link) AfterFind(*gorm.DB) error {
 	if s.PropertiesJson != "" {
-		s.PropertiesMap = make(map[string]string)
-		if err := json.Unmarshal([]byte(s.PropertiesJson), &s.PropertiesMap); err != nil {
-			log.Errorf("json Unmarshal propertiesJson[%s] failed: %v", s.PropertiesJson, err)
+		propertiesMap, err := unmarshalProperties(s.PropertiesJson)
+		if err != nil {
+			log.Errorf("unmarshal properties of link[%s] failed: %v", s.ID, err)
 			return err
 		}
+		s.PropertiesMap = propertiesMap
 	}
 	return nil
 }
 
 // BeforeSave is the callback methods for saving file system
 func (s *Link) BeforeSave(*gorm.DB) error {
-	propertiesJson, err := json.Marshal(&s.PropertiesMap)
+	propertiesJson, err := marshalProperties(s.PropertiesMap)
 	if err != nil {
-		log.Errorf("json Marshal propertiesMap[%v] failed: %v", s.PropertiesMap, err)
+		log.Errorf("marshal properties of link[%s] failed: %v", s.ID, err)
 		return err
 	}
-	s.PropertiesJson = string(propertiesJson)
+	s.PropertiesJson = propertiesJson
 	return nil
 }
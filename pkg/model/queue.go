@@ -28,7 +28,7 @@ import (
 type Queue struct {
 	Model           `gorm:"embedded"`
 	Pk              int64               `json:"-" gorm:"primaryKey;autoIncrement"`
-	Name            string              `json:"name" gorm:"uniqueIndex"`
+	Name            string              `json:"name" gorm:"type:varchar(255);uniqueIndex"`
 	Namespace       string              `json:"namespace" gorm:"column:"`
 	ClusterId       string              `json:"-" gorm:"column:cluster_id"`
 	ClusterName     string              `json:"clusterName" gorm:"column:cluster_name;->"`
@@ -23,7 +23,7 @@ import (
 )
 
 type UserInfo struct {
-	Name     string `gorm:"uniqueIndex" json:"name"`
+	Name     string `gorm:"type:varchar(60);uniqueIndex" json:"name"`
 	Password string `json:"-"`
 }
 
@@ -285,9 +285,210 @@ func (cc *conservativeCacheCalculator) CalculateSecondFingerprint() (fingerprint
 	return secondFingerprint, err
 }
 
+type PathToDigest struct {
+	Digest map[string]string `json:",omitempty"`
+}
+
+// 用于计算激进策略的第二层 fingerprint 的结构
+type aggressiveSecondCacheKey struct {
+	// 输入 artifact 的名字到其内容摘要的映射
+	InputArtifactsDigest map[string]string `json:",omitempty"`
+
+	// Fs 上的文件名与其内容摘要之间的映射关系
+	FsScopeDigest map[string]PathToDigest `json:",omitempty"`
+}
+
+// 激进策略：第二层 fingerprint 基于输入 artifact 及 fs_scope 的文件内容摘要计算，
+// 文件被拷贝、touch 或者重新上传时，只要内容不变，cache 依然能够命中
+type aggressiveCacheCalculator struct {
+	conservativeCacheCalculator
+	aggressiveSecondCacheKey *aggressiveSecondCacheKey
+}
+
+// 调用方应该保证在启用了 cache 功能的情况下才会调用NewAggressiveCacheCalculator
+func NewAggressiveCacheCalculator(job PaddleFlowJob, cacheConfig schema.Cache, logger *logrus.Entry,
+	mainFs *schema.FsMount, extraFs []schema.FsMount) (CacheCalculator, error) {
+	calculator := aggressiveCacheCalculator{
+		conservativeCacheCalculator: conservativeCacheCalculator{
+			job:         job,
+			cacheConfig: cacheConfig,
+			logger:      logger,
+			mainFS:      mainFs,
+			extraFS:     extraFs,
+		},
+	}
+	return &calculator, nil
+}
+
+func (ac *aggressiveCacheCalculator) generateFirstCacheKey() error {
+	if err := ac.conservativeCacheCalculator.generateFirstCacheKey(); err != nil {
+		return err
+	}
+
+	// 输入 artifact 的内容已经体现在第二层 fingerprint 中，因此第一层只保留其名字，不再关心其路径
+	inputArtifacts := map[string]string{}
+	for name := range ac.firstCacheKey.InputArtifacts {
+		inputArtifacts[name] = ""
+	}
+	ac.firstCacheKey.InputArtifacts = inputArtifacts
+	return nil
+}
+
+func (ac *aggressiveCacheCalculator) CalculateFirstFingerprint() (fingerprint string, err error) {
+	err = ac.generateFirstCacheKey()
+	if err != nil {
+		err = fmt.Errorf("Calculate FirstFingerprint failed due to generating FirstCacheKey: %s", err.Error())
+		ac.logger.Errorln(err.Error())
+		return "", err
+	}
+
+	firstFingerprint, err := calculateFingerprint(ac.firstCacheKey)
+	if err != nil {
+		err = fmt.Errorf("Calculate FirstFingerprint failed: %s", err.Error())
+		ac.logger.Errorln(err.Error())
+		return "", err
+	}
+
+	return firstFingerprint, err
+}
+
+func (ac *aggressiveCacheCalculator) getFsScopeDigest() (map[string]PathToDigest, error) {
+	// 注意， FsScope 的合法性需要由调用方保证
+	sd := map[string]PathToDigest{}
+	for _, scope := range ac.cacheConfig.FsScope {
+		ac.logger.Infof("begin to get the digest of scope: %v", scope)
+		fsHandler, err := handler.NewFsHandlerWithServer(scope.ID, ac.logger)
+		if err != nil {
+			errMsg := fmt.Errorf("init fsHandler failed: %s", err.Error())
+			ac.logger.Errorln(errMsg)
+			return nil, err
+		}
+
+		var pathToDigest PathToDigest
+		if _, ok := sd[scope.ID]; ok {
+			pathToDigest = sd[scope.ID]
+		} else {
+			pathToDigest = PathToDigest{Digest: map[string]string{}}
+		}
+
+		FsScope := strings.TrimSpace(scope.Path)
+		if FsScope == "" {
+			FsScope = "/"
+		}
+
+		for _, path := range strings.Split(FsScope, ",") {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+
+			digest, err := fsHandler.ContentDigest(path)
+			if err != nil {
+				err = fmt.Errorf("get the digest of fsScope file[%s] failed: %s", path, err.Error())
+				ac.logger.Errorln(err.Error())
+				return nil, err
+			}
+			pathToDigest.Digest[path] = digest
+		}
+
+		sd[scope.ID] = pathToDigest
+	}
+	return sd, nil
+}
+
+func (ac *aggressiveCacheCalculator) getInputArtifactDigest() (map[string]string, error) {
+	if ac.mainFS.ID == "" {
+		ac.logger.Info("there must be no input artifact because global fsId is empty")
+		return map[string]string{}, nil
+	}
+
+	fsHandler, err := handler.NewFsHandlerWithServer(ac.mainFS.ID, ac.logger)
+	if err != nil {
+		errMsg := fmt.Errorf("init fsHandler failed: %s", err.Error())
+		ac.logger.Errorln(errMsg)
+		return nil, err
+	}
+
+	inArtDigestMap := map[string]string{}
+	for name, paths := range ac.job.Artifacts.Input {
+		name = strings.TrimSpace(name)
+		digests := []string{}
+
+		for _, path := range strings.Split(paths, ",") {
+			path = strings.TrimSpace(path)
+
+			if name == "" || path == "" {
+				err := fmt.Errorf("the input artifact[%s] is illegal, name or path of it is empty", name)
+				ac.logger.Errorln(err.Error())
+				return map[string]string{}, err
+			}
+
+			digest, err := fsHandler.ContentDigest(path)
+			if err != nil {
+				err = fmt.Errorf("get the digest of inputArtfact[%s] failed: %s", name, err.Error())
+				return map[string]string{}, err
+			}
+			digests = append(digests, digest)
+		}
+		inArtDigestMap[name] = strings.Join(digests, ",")
+	}
+
+	return inArtDigestMap, nil
+}
+
+func (ac *aggressiveCacheCalculator) generateSecondCacheKey() error {
+	fsScopeDigest, err := ac.getFsScopeDigest()
+	if err != nil {
+		err := fmt.Errorf("generate SecondCacheKey failed: [%s]", err.Error())
+		ac.logger.Errorln(err.Error())
+		return err
+	}
+
+	inArt, err := ac.getInputArtifactDigest()
+	if err != nil {
+		err := fmt.Errorf("generate SecondCacheKey failed: [%s]", err.Error())
+		ac.logger.Errorln(err.Error())
+		return err
+	}
+
+	ac.aggressiveSecondCacheKey = &aggressiveSecondCacheKey{
+		InputArtifactsDigest: inArt,
+		FsScopeDigest:        fsScopeDigest,
+	}
+
+	logMsg := fmt.Sprintf("SecondCacheKey:\nInputArtDigest: %s, FsScopeDigest: %v", inArt, fsScopeDigest)
+	ac.logger.Debugf(logMsg)
+
+	return nil
+}
+
+func (ac *aggressiveCacheCalculator) CalculateSecondFingerprint() (fingerprint string, err error) {
+	err = ac.generateSecondCacheKey()
+	if err != nil {
+		err = fmt.Errorf("Calculate SecondFingerprint failed due to generating SecondCacheKey failed: %s", err.Error())
+		ac.logger.Errorln(err.Error())
+		return "", err
+	}
+
+	secondFingerprint, err := calculateFingerprint(ac.aggressiveSecondCacheKey)
+	if err != nil {
+		err = fmt.Errorf("Calculate SecondFingerprint failed: %s", err.Error())
+		ac.logger.Errorln(err.Error())
+		return "", err
+	}
+
+	return secondFingerprint, err
+}
+
 // 调用方应该保证在启用了 cache 功能的情况下才会调用NewCacheCalculator
 func NewCacheCalculator(job PaddleFlowJob, cacheConfig schema.Cache, logger *logrus.Entry,
 	mainFs *schema.FsMount, extraFs []schema.FsMount) (CacheCalculator, error) {
-	// TODO: 当支持多中 cache 策略时，做好分发的功能
-	return NewConservativeCacheCalculator(job, cacheConfig, logger, mainFs, extraFs)
+	switch cacheConfig.Strategy {
+	case "", common.CacheStrategyConservative:
+		return NewConservativeCacheCalculator(job, cacheConfig, logger, mainFs, extraFs)
+	case common.CacheStrategyAggressive:
+		return NewAggressiveCacheCalculator(job, cacheConfig, logger, mainFs, extraFs)
+	default:
+		return nil, fmt.Errorf("cache strategy[%s] is not supported", cacheConfig.Strategy)
+	}
 }
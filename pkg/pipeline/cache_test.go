@@ -19,8 +19,11 @@ package pipeline
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/handler"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
@@ -28,6 +31,7 @@ import (
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/client/fs"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
+	pplcommon "github.com/PaddlePaddle/PaddleFlow/pkg/pipeline/common"
 	"github.com/stretchr/testify/assert"
 	// . "github.com/PaddlePaddle/PaddleFlow/pkg/pipeline/common"
 )
@@ -466,3 +470,86 @@ func TestNewCacheCalculator(t *testing.T) {
 	_, ok = calculator.(*conservativeCacheCalculator)
 	assert.Equal(t, ok, true)
 }
+
+func mockerNewAggressiveCacheCalculator() (CacheCalculator, error) {
+	ServerConf := &config.ServerConfig{}
+	err := config.InitConfigFromYaml(ServerConf, "../../config/server/default/paddleserver.yaml")
+	config.GlobalServerConfig = ServerConf
+
+	step := mockStep()
+	cacheConfig := mockCacheConfig()
+	cacheConfig.Strategy = pplcommon.CacheStrategyAggressive
+	handler.NewFsHandlerWithServer = handler.MockerNewFsHandlerWithServer
+
+	job := step.job.(*PaddleFlowJob)
+	calculator, err := NewCacheCalculator(*job, cacheConfig, step.logger, step.mainFS,
+		step.getWorkFlowStep().ExtraFS)
+	return calculator, err
+}
+
+func TestAggressiveCacheCalculator(t *testing.T) {
+	calculator, err := mockerNewAggressiveCacheCalculator()
+	assert.Equal(t, err, nil)
+	ac, ok := calculator.(*aggressiveCacheCalculator)
+	assert.Equal(t, ok, true)
+
+	// 第一层 fingerprint 不依赖输入 artifact 的路径
+	fp, err := calculator.CalculateFirstFingerprint()
+	assert.Equal(t, err, nil)
+	for name := range ac.firstCacheKey.InputArtifacts {
+		assert.Equal(t, "", ac.firstCacheKey.InputArtifacts[name])
+		ac.job.Artifacts.Input[name] = "copied/" + name
+	}
+	fp2, err := calculator.CalculateFirstFingerprint()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, fp, fp2)
+
+	for _, path := range ac.job.Artifacts.Input {
+		err := CreatefileByFsClient(path, true)
+		assert.Equal(t, err, nil)
+	}
+	cacheConfig := mockCacheConfig()
+	for _, scope := range cacheConfig.FsScope {
+		for _, path := range strings.Split(scope.Path, ",") {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			err := CreatefileByFsClient(path, false)
+			assert.Equal(t, err, nil)
+		}
+	}
+
+	secondFp, err := calculator.CalculateSecondFingerprint()
+	assert.Equal(t, err, nil)
+	for name := range ac.job.Artifacts.Input {
+		_, ok := ac.aggressiveSecondCacheKey.InputArtifactsDigest[name]
+		assert.Equal(t, ok, true)
+	}
+
+	// touch 文件后，内容不变，第二层 fingerprint 不变
+	touchTime := time.Now().Add(time.Hour)
+	err = os.Chtimes("./mock_fs_handler/a.txt", touchTime, touchTime)
+	assert.Equal(t, err, nil)
+	secondFp2, err := calculator.CalculateSecondFingerprint()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, secondFp, secondFp2)
+
+	// 内容变化后，第二层 fingerprint 变化
+	err = ioutil.WriteFile("./mock_fs_handler/a.txt", []byte("new content"), 0644)
+	assert.Equal(t, err, nil)
+	secondFp3, err := calculator.CalculateSecondFingerprint()
+	assert.Equal(t, err, nil)
+	assert.NotEqual(t, secondFp, secondFp3)
+}
+
+func TestNewCacheCalculatorWithInvalidStrategy(t *testing.T) {
+	step := mockStep()
+	cacheConfig := mockCacheConfig()
+	cacheConfig.Strategy = "unknown"
+
+	job := step.job.(*PaddleFlowJob)
+	_, err := NewCacheCalculator(*job, cacheConfig, step.logger, step.mainFS,
+		step.getWorkFlowStep().ExtraFS)
+	assert.NotNil(t, err)
+}
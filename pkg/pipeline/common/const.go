@@ -21,10 +21,11 @@ type ViewType string
 
 const (
 	// 如果有增加新的系统变量，记得需要同步更新 SysParamNameList
-	SysParamNamePFRunID        = "PF_RUN_ID"
-	SysParamNamePFStepName     = "PF_STEP_NAME"
-	SysParamNamePFUserName     = "PF_USER_NAME"
-	SysParamNamePFLoopArgument = "PF_LOOP_ARGUMENT"
+	SysParamNamePFRunID         = "PF_RUN_ID"
+	SysParamNamePFStepName      = "PF_STEP_NAME"
+	SysParamNamePFUserName      = "PF_USER_NAME"
+	SysParamNamePFLoopArgument  = "PF_LOOP_ARGUMENT"
+	SysParamNamePFScheduledTime = "PF_SCHEDULED_TIME" // 周期调度发起的run对应的调度时间，非周期调度发起的run为空
 
 	PF_PARENT        = "PF_PARENT"
 	PF_LOOP_ARGUMENT = "PF_LOOP_ARGUMENT"
@@ -34,6 +35,8 @@ const (
 	WfExtraInfoKeyFsName     = "FsName"
 	WfExtraInfoKeyFsID       = "FsID"
 
+	WfExtraInfoKeyScheduledTime = "ScheduledTime"
+
 	ParamTypeString = "string"
 	ParamTypeFloat  = "float"
 	ParamTypePath   = "path"
@@ -85,4 +88,5 @@ var SysParamNameList []string = []string{
 	SysParamNamePFStepName,
 	SysParamNamePFUserName,
 	SysParamNamePFLoopArgument,
+	SysParamNamePFScheduledTime,
 }
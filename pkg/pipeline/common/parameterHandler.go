@@ -258,7 +258,7 @@ func (s *ComponentParamChecker) checkReference(comp schema.Component) error {
 			if len(step.Artifacts.Output) > 0 || len(step.Command) > 0 || len(step.Condition) > 0 ||
 				len(step.DockerEnv) > 0 || len(step.Env) > 0 || step.LoopArgument != nil ||
 				len(step.Cache.FsScope) > 0 || len(step.Cache.MaxExpiredTime) > 0 || step.Cache.Enable ||
-				len(step.ExtraFS) > 0 {
+				len(step.ExtraFS) > 0 || step.Retry.Limit > 0 || step.Timeout > 0 {
 				return fmt.Errorf("reference step can only have deps, parameters, input artifacts, reference")
 			}
 
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -41,18 +42,43 @@ var (
 	StatusRuntimeTerminated  RuntimeStatus = schema.StatusJobTerminated
 	StatusRuntimeCancelled   RuntimeStatus = schema.StatusJobCancelled
 	StatusRuntimeSkipped     RuntimeStatus = schema.StatusJobSkipped
+	StatusRuntimeTimeout     RuntimeStatus = schema.StatusJobTimeout
 )
 
 func isRuntimeFinallyStatus(status RuntimeStatus) bool {
 	if status == StatusRuntimeCancelled || status == StatusRuntimeFailed ||
 		status == StatusRuntimeSucceeded || status == StatusRuntimeSkipped ||
-		status == StatusRuntimeTerminated {
+		status == StatusRuntimeTerminated || status == StatusRuntimeTimeout {
 		return true
 	}
 
 	return false
 }
 
+// timeoutAfter: 计算从 startTime 开始运行 timeout 秒后，距离现在还剩余的时间，startTime 为空或者无法解析时从当前开始计时
+func timeoutAfter(timeout int, startTime string) time.Duration {
+	start := time.Now()
+	if startTime != "" {
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", startTime, time.Local); err == nil {
+			start = t
+		}
+	}
+	return time.Until(start.Add(time.Duration(timeout) * time.Second))
+}
+
+// waitTimeout: 超时后调用 onTimeout，如果在此之前收到了终止信号，则直接退出
+func waitTimeout(d time.Duration, onTimeout func(), ctx context.Context, failureOpitonsCtx context.Context) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		onTimeout()
+	case <-ctx.Done():
+	case <-failureOpitonsCtx.Done():
+	}
+}
+
 // 管理并发信息
 type parallelismManager struct {
 	ch chan struct{}
@@ -83,6 +109,7 @@ type componentRuntime interface {
 	isCancelled() bool
 	isSkipped() bool
 	isTerminated() bool
+	isTimeout() bool
 	updateStatus(RuntimeStatus) error
 
 	getComponent() schema.Component
@@ -112,6 +139,9 @@ type runConfig struct {
 	// 由 ApiServer 或者 Parser 动态生成的信息
 	runID  string
 	logger *logrus.Entry
+
+	// 周期调度发起的 run 对应的调度时间
+	scheduledTime string
 	*parallelismManager
 
 	// 用于与 APIServer 同步信息
@@ -238,6 +268,10 @@ func (crt *baseComponentRuntime) isTerminated() bool {
 	return crt.status == StatusRuntimeTerminated
 }
 
+func (crt *baseComponentRuntime) isTimeout() bool {
+	return crt.status == StatusRuntimeTimeout
+}
+
 // 判断当次运行是否已经处于终态
 func (crt *baseComponentRuntime) isDone() bool {
 	return crt.done
@@ -301,9 +335,10 @@ func (crt *baseComponentRuntime) getPFLoopArgument() (value interface{}, err err
 // 获取系统变量
 func (crt *baseComponentRuntime) setSysParams() error {
 	crt.sysParams = map[string]string{
-		SysParamNamePFRunID:    crt.runID,
-		SysParamNamePFStepName: crt.component.GetName(),
-		SysParamNamePFUserName: crt.userName,
+		SysParamNamePFRunID:         crt.runID,
+		SysParamNamePFStepName:      crt.component.GetName(),
+		SysParamNamePFUserName:      crt.userName,
+		SysParamNamePFScheduledTime: crt.scheduledTime,
 	}
 
 	pfLoopArugment, err := crt.getPFLoopArgument()
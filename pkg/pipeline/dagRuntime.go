@@ -63,6 +63,9 @@ type DagRuntime struct {
 
 	failureOptionsCtxAndCancels map[string]CtxAndCancel
 	hasFailureOptionsTriggered  bool
+
+	// 为 true 时表示 dag 已经超时，此时所有子节点结束后，dag 的状态为 timeout
+	timedOut bool
 }
 
 func generateDagID(runID string) string {
@@ -345,6 +348,7 @@ func (drt *DagRuntime) Start() {
 	// 监听子节点已经父节点传递过来的事件或者信号
 	go drt.Listen()
 	go drt.Stop()
+	drt.watchTimeout(drt.startTime)
 
 	// 开始调度子节点
 	drt.scheduleSubComponent()
@@ -535,7 +539,7 @@ func (drt *DagRuntime) Resume(dagView *schema.DagView) {
 					}
 				}
 
-				if runtime.isFailed() {
+				if runtime.isFailed() || runtime.isTimeout() {
 					failedCp[name] = append(failedCp[name], view.GetSeq())
 				}
 			} else {
@@ -602,6 +606,7 @@ func (drt *DagRuntime) Resume(dagView *schema.DagView) {
 	// 在最后才进入listen状态，为了避免在resume A 子节点的过程中，监听到了B 发过来的事件，有一次调度了子节点A
 	go drt.Listen()
 	go drt.Stop()
+	drt.watchTimeout(drt.startTime)
 
 }
 
@@ -645,6 +650,7 @@ func (drt *DagRuntime) Restart(dagView *schema.DagView) {
 	// 3、处理完所有的view 后 才开始 监听信号, 主要是为了在还没有处理完 view 中新，便接受到了事件， 导致在 view 中存在的节点再次被调度
 	go drt.Listen()
 	go drt.Stop()
+	drt.watchTimeout("")
 
 	// 4、这里做一次调度的原因是，避免 3 中没有发起任何任务，导致永远监听不到信息，导致任务 hang 住的情况出现
 	drt.scheduleSubComponent()
@@ -890,7 +896,7 @@ func (drt *DagRuntime) processEventFromSubComponent(event WorkflowEvent) error {
 		status, ok := event.Extra[common.WfEventKeyStatus]
 		if ok {
 			subRuntimeStatus := status.(schema.JobStatus)
-			isFailed := subRuntimeStatus == StatusRuntimeFailed
+			isFailed := subRuntimeStatus == StatusRuntimeFailed || subRuntimeStatus == StatusRuntimeTimeout
 			isUnexpectedTerminated := subRuntimeStatus == StatusRuntimeTerminated && drt.status != StatusRuntimeTerminating
 
 			if isFailed || isUnexpectedTerminated {
@@ -1165,7 +1171,7 @@ func (drt *DagRuntime) updateStatusAccordingSubComponentRuntimeStatus() string {
 		}
 
 		for index := range cps {
-			if cps[index].isFailed() {
+			if cps[index].isFailed() || cps[index].isTimeout() {
 				faieldComponentNames = append(faieldComponentNames, cps[index].getName())
 			} else if cps[index].isTerminated() {
 				terminatedComponentNames = append(terminatedComponentNames, cps[index].getName())
@@ -1193,7 +1199,11 @@ func (drt *DagRuntime) updateStatusAccordingSubComponentRuntimeStatus() string {
 
 	var msg string
 	var err error
-	if len(faieldComponentNames) != 0 {
+	if drt.timedOut {
+		err = drt.updateStatus(StatusRuntimeTimeout)
+		msg = fmt.Sprintf("update dag[%s]'s status to [%s] due to timeout after %d seconds",
+			drt.name, StatusRuntimeTimeout, drt.getworkflowSouceDag().Timeout)
+	} else if len(faieldComponentNames) != 0 {
 		err = drt.updateStatus(StatusRuntimeFailed)
 		msg = fmt.Sprintf("update dag[%s]'s status to [%s] due to subSteps or subDags[%s] faield",
 			drt.name, StatusRuntimeFailed, strings.Join(faieldComponentNames, ","))
@@ -1277,6 +1287,47 @@ func (drt *DagRuntime) newView(msg string) schema.DagView {
 	}
 }
 
+// watchTimeout: 如果 dag 设置了 timeout，则在超时后终止 dag，startTime 为 dag 开始运行的时间
+func (drt *DagRuntime) watchTimeout(startTime string) {
+	timeout := drt.getworkflowSouceDag().Timeout
+	if timeout <= 0 {
+		return
+	}
+
+	go waitTimeout(timeoutAfter(timeout, startTime), drt.processTimeout, drt.ctx, drt.failureOpitonsCtx)
+}
+
+// processTimeout: 终止所有子节点，待所有子节点处于终态后，dag 的状态将会被置为 timeout
+func (drt *DagRuntime) processTimeout() {
+	defer drt.processSubComponentLock.Unlock()
+	drt.processSubComponentLock.Lock()
+
+	if drt.done {
+		return
+	}
+
+	drt.timedOut = true
+	msg := fmt.Sprintf("dag[%s] timeout after %d seconds", drt.name, drt.getworkflowSouceDag().Timeout)
+	drt.logger.Infof(msg)
+
+	err := drt.updateStatus(StatusRuntimeTerminating)
+	if err != nil {
+		drt.logger.Errorf(err.Error())
+	}
+
+	// 已经调度的子节点会监听 failureOptionsCtx 信号并终止运行，还没有调度的子节点直接置为 cancelled
+	for name := range drt.subComponentRumtimes {
+		drt.getfailureOptionsCtxAndCF(name).cancel()
+	}
+	drt.cancellAllNotReadySubComponent(msg)
+
+	if statusMsg := drt.updateStatusAccordingSubComponentRuntimeStatus(); statusMsg != "" {
+		msg = statusMsg
+	}
+	view := drt.newView(msg)
+	drt.syncToApiServerAndParent(WfEventDagUpdate, &view, msg)
+}
+
 // stopByCtx: 在监测到底 ctx 的信号后，开始终止逻辑
 func (drt *DagRuntime) stopByCtx() {
 	// 对于已经调度了节点，其本身也会监听 ctx 信号, 执行终止相关的逻辑，因此，此处只需要处理还未被调度的节点
@@ -446,6 +446,26 @@ func TestUpdateStatusAccordingSubComponentRuntimeStatus(t *testing.T) {
 
 	drt.updateStatusAccordingSubComponentRuntimeStatus()
 	assert.Equal(t, drt.status, StatusRuntimeSucceeded)
+
+	// 子节点超时，dag 的状态为 failed
+	drt.done = false
+	drt3.done = false
+	drt.updateStatus(StatusRuntimeRunning)
+	drt3.updateStatus(StatusRuntimeTimeout)
+
+	drt.updateStatusAccordingSubComponentRuntimeStatus()
+	assert.Equal(t, drt.status, StatusRuntimeFailed)
+
+	// dag 本身超时，dag 的状态为 timeout
+	drt.done = false
+	drt3.done = false
+	drt.timedOut = true
+	drt.updateStatus(StatusRuntimeTerminating)
+	drt3.updateStatus(StatusRuntimeTerminated)
+
+	drt.updateStatusAccordingSubComponentRuntimeStatus()
+	assert.Equal(t, drt.status, StatusRuntimeTimeout)
+	assert.True(t, drt.done)
 }
 
 func TestDagRunRestart(t *testing.T) {
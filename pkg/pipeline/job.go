@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
+
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/common"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/job"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
@@ -280,6 +282,9 @@ func (pfj *PaddleFlowJob) Watch() {
 				"jobid":     pfj.ID,
 				"message":   jobInstance.Message,
 			}
+			if jobInstance.Status == schema.StatusJobFailed {
+				extra[common.WfEventKeyReason] = pfj.failedReason()
+			}
 			wfe := NewWorkflowEvent(WfEventJobUpdate, jobInstance.Message, extra)
 			pfj.eventChannel <- *wfe
 			pfj.Status = jobInstance.Status
@@ -294,6 +299,21 @@ func (pfj *PaddleFlowJob) Watch() {
 	}
 }
 
+// failedReason 根据 job 的 task 状态判断其失败的原因，被驱逐的 pod 的 reason 为 Evicted
+func (pfj *PaddleFlowJob) failedReason() string {
+	tasks, err := storage.Job.ListByJobID(pfj.ID)
+	if err != nil {
+		logger.Logger().Errorf("list tasks of job[%s] failed: %s", pfj.ID, err.Error())
+		return schema.RetryOnFailed
+	}
+	for _, task := range tasks {
+		if podStatus, ok := task.ExtRuntimeStatus.(v1.PodStatus); ok && podStatus.Reason == "Evicted" {
+			return schema.RetryOnEvicted
+		}
+	}
+	return schema.RetryOnFailed
+}
+
 func (pfj *PaddleFlowJob) Succeeded() bool {
 	return pfj.Status == schema.StatusJobSucceeded
 }
@@ -6,9 +6,14 @@ import (
 
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 
 	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/controller/job"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/logger"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/schema"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/driver"
 )
 
 func TestStopJob(t *testing.T) {
@@ -29,3 +34,27 @@ func TestStopJob(t *testing.T) {
 
 	pfj.Stop()
 }
+
+func TestJobFailedReason(t *testing.T) {
+	driver.InitMockDB()
+	pfj := NewPaddleFlowJob("abc", "abc:qe", "root", make(chan<- WorkflowEvent), nil, nil)
+	pfj.ID = "job-evicted"
+
+	// 1、task 不是被驱逐的
+	err := storage.Job.UpdateTask(&model.JobTask{
+		ID:               "task-001",
+		JobID:            pfj.ID,
+		ExtRuntimeStatus: v1.PodStatus{Phase: v1.PodFailed, Message: "pod Evicted by user"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, schema.RetryOnFailed, pfj.failedReason())
+
+	// 2、task 的 reason 为 Evicted
+	err = storage.Job.UpdateTask(&model.JobTask{
+		ID:               "task-002",
+		JobID:            pfj.ID,
+		ExtRuntimeStatus: v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, schema.RetryOnEvicted, pfj.failedReason())
+}
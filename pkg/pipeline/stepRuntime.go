@@ -38,6 +38,13 @@ type StepRuntime struct {
 	CacheRunID        string
 	CacheJobID        string
 
+	// 根据 retry 策略重试前，已经失败的 job 的记录
+	attempts []schema.JobAttempt
+	// 为 true 时表示失败的 job 正在等待重试，此时没有处于运行状态的 job
+	retrying bool
+	// 为 true 时表示 step 已经超时，此时 job 结束后，step 的状态为 timeout
+	timedOut bool
+
 	// 需要避免在终止的同时在 创建 job 的情况，导致数据不一致
 	processJobLock sync.Mutex
 }
@@ -190,6 +197,7 @@ func (srt *StepRuntime) Start() {
 	// 监听channel, 及时除了时间
 	go srt.Listen()
 	go srt.Stop()
+	srt.watchTimeout("")
 	srt.Execute()
 }
 
@@ -226,6 +234,7 @@ func (srt *StepRuntime) Resume(view *schema.JobView) {
 		srt.receiveEventChildren, srt.runConfig.mainFS, srt.getWorkFlowStep().ExtraFS)
 
 	srt.pk = view.PK
+	srt.attempts = append(view.Attempts, []schema.JobAttempt{}...)
 	err := srt.updateStatus(view.Status)
 	if err != nil {
 		errMsg := fmt.Sprintf("set the sysparams for dag[%s] failed: %s", srt.name, err.Error())
@@ -261,6 +270,7 @@ func (srt *StepRuntime) Resume(view *schema.JobView) {
 	go srt.Listen()
 	go srt.Stop()
 	go srt.job.Watch()
+	srt.watchTimeout(view.StartTime)
 	return
 }
 
@@ -520,6 +530,11 @@ func (srt *StepRuntime) checkCached() (cacheFound bool, err error) {
 }
 
 func (srt *StepRuntime) logCache() error {
+	strategy := srt.getWorkFlowStep().Cache.Strategy
+	if strategy == "" {
+		strategy = CacheStrategyConservative
+	}
+
 	// 写cache记录到数据库
 	req := schema.LogRunCacheRequest{
 		FirstFp:     srt.firstFingerprint,
@@ -532,7 +547,7 @@ func (srt *StepRuntime) logCache() error {
 		FsName:      srt.runConfig.mainFS.Name,
 		UserName:    srt.userName,
 		ExpiredTime: srt.getWorkFlowStep().Cache.MaxExpiredTime,
-		Strategy:    CacheStrategyConservative,
+		Strategy:    strategy,
 	}
 
 	// logcache失败，不影响job正常结束，但是把cache失败添加日志
@@ -717,6 +732,21 @@ func (srt *StepRuntime) Execute() {
 }
 
 func (srt *StepRuntime) stopWithMsg(msg string) {
+	if srt.retrying {
+		// 此时失败的 job 正在等待重试，没有需要停止的 job，因此直接将状态置为 terminated，并通过事件进行同步即可
+		srt.retrying = false
+		err := srt.updateStatus(StatusRuntimeTerminated)
+		if err != nil {
+			srt.logger.Errorf(err.Error())
+		}
+
+		logMsg := fmt.Sprintf("step[%s] is terminated while waiting for retry: %s", srt.name, msg)
+		srt.logger.Infof(logMsg)
+		view := srt.newJobView(logMsg)
+		srt.syncToApiServerAndParent(WfEventJobUpdate, &view, logMsg)
+		return
+	}
+
 	if srt.job.JobID() == "" {
 		// 此时说明还没有创建job，因此直接将状态置为 failed，并通过事件进行同步即可
 		var msg string
@@ -763,6 +793,13 @@ func (srt *StepRuntime) stopWithMsg(msg string) {
 
 // 步骤监控
 func (srt *StepRuntime) processEventFromJob(event WorkflowEvent) {
+	defer srt.processJobLock.Unlock()
+	srt.processJobLock.Lock()
+
+	if srt.done {
+		return
+	}
+
 	logMsg := fmt.Sprintf("receive event from job[%s] of step[%s]: \n%v",
 		srt.job.(*PaddleFlowJob).ID, srt.name, event)
 	srt.logger.Infof(logMsg)
@@ -789,15 +826,171 @@ func (srt *StepRuntime) processEventFromJob(event WorkflowEvent) {
 			srt.logger.Infof(logMsg)
 		}
 
-		err := srt.updateStatus(extra["status"].(RuntimeStatus))
+		status := extra["status"].(RuntimeStatus)
+		msg := event.Message
+		if srt.timedOut && isRuntimeFinallyStatus(status) && status != StatusRuntimeSucceeded {
+			status = StatusRuntimeTimeout
+			msg = fmt.Sprintf("step[%s] timeout after %d seconds: %s", srt.name, srt.getWorkFlowStep().Timeout, msg)
+		}
+
+		reason, _ := extra[common.WfEventKeyReason].(string)
+		if srt.needRetry(status, reason) {
+			srt.retryJob(event.Message)
+			return
+		}
+
+		err := srt.updateStatus(status)
 		if err != nil {
 			srt.logger.Errorf(err.Error())
 		}
-		view := srt.newJobView(event.Message)
-		srt.syncToApiServerAndParent(WfEventJobUpdate, &view, event.Message)
+		view := srt.newJobView(msg)
+		srt.syncToApiServerAndParent(WfEventJobUpdate, &view, msg)
 	}
 }
 
+// watchTimeout: 如果 step 设置了 timeout，则在超时后终止 step， startTime 为 step 开始运行的时间
+func (srt *StepRuntime) watchTimeout(startTime string) {
+	timeout := srt.getWorkFlowStep().Timeout
+	if timeout <= 0 {
+		return
+	}
+
+	go waitTimeout(timeoutAfter(timeout, startTime), srt.processTimeout, srt.ctx, srt.failureOpitonsCtx)
+}
+
+// processTimeout: 终止超时的 step 对应的 job，job 终止后，step 的状态将会被置为 timeout
+func (srt *StepRuntime) processTimeout() {
+	// 检查状态到终止 job 的整个过程都持有 processJobLock，期间 job 的事件需要等待超时处理完成
+	defer srt.processJobLock.Unlock()
+	srt.processJobLock.Lock()
+
+	if srt.done {
+		return
+	}
+
+	srt.timedOut = true
+	msg := fmt.Sprintf("step[%s] timeout after %d seconds", srt.name, srt.getWorkFlowStep().Timeout)
+	srt.logger.Infof(msg)
+
+	// job 还没有创建或者正在等待重试时，没有需要终止的 job，直接将状态置为 timeout 即可
+	if srt.job.JobID() == "" || srt.retrying {
+		srt.retrying = false
+		srt.processStartAbnormalStatus(msg, StatusRuntimeTimeout)
+		return
+	}
+
+	if err := srt.job.Stop(); err != nil {
+		// job 可能恰好已经结束，此时 job 的状态依然会通过 watch 同步
+		srt.logger.Errorf("stop job[%s] of step[%s] for timeout failed: %s", srt.job.JobID(), srt.name, err.Error())
+	}
+
+	view := srt.newJobView(msg)
+	srt.syncToApiServerAndParent(WfEventJobUpdate, &view, msg)
+}
+
+// needRetry 判断 job 失败后是否需要根据 step 的 retry 策略重新提交 job
+// reason 为 job 失败的原因，由 PaddleFlowJob 根据 task 的状态给出，为空时视为 RetryOnFailed
+func (srt *StepRuntime) needRetry(status RuntimeStatus, reason string) bool {
+	if status != StatusRuntimeFailed || srt.done {
+		return false
+	}
+
+	if srt.ctx.Err() != nil || srt.failureOpitonsCtx.Err() != nil {
+		return false
+	}
+
+	attempt := len(srt.attempts) + 1
+	if n := len(srt.attempts); n > 0 && srt.attempts[n-1].JobID == srt.job.JobID() {
+		// resume 后再次收到了已经记录过的 job 的失败事件
+		attempt = n
+	}
+	if reason == "" {
+		reason = schema.RetryOnFailed
+	}
+	return srt.getWorkFlowStep().Retry.ShouldRetry(attempt, reason)
+}
+
+// retryJob 记录失败的 job，并在等待 backoff 时间后重新提交一个新的 job，调用方需持有 processJobLock
+func (srt *StepRuntime) retryJob(msg string) {
+	job := srt.job.Job()
+	if n := len(srt.attempts); n == 0 || srt.attempts[n-1].JobID != job.ID {
+		srt.attempts = append(srt.attempts, schema.JobAttempt{
+			JobID:     job.ID,
+			Status:    schema.StatusJobFailed,
+			Message:   msg,
+			StartTime: job.StartTime,
+			EndTime:   time.Now().Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	// 等待重试期间，step 仍然处于 running 状态
+	if err := srt.updateStatus(StatusRuntimeRunning); err != nil {
+		srt.logger.Errorf(err.Error())
+	}
+	srt.retrying = true
+
+	retry := srt.getWorkFlowStep().Retry
+	attempt := len(srt.attempts)
+	backoff := retry.BackoffSeconds(attempt)
+	retryMsg := fmt.Sprintf("job[%s] of step[%s] failed, retry it after %d seconds, attempt [%d/%d]: %s",
+		job.ID, srt.name, backoff, attempt, retry.Limit, msg)
+	srt.logger.Infof(retryMsg)
+
+	view := srt.newJobView(retryMsg)
+	srt.syncToApiServerAndParent(WfEventJobUpdate, &view, retryMsg)
+
+	go srt.resubmitJob(time.Duration(backoff) * time.Second)
+}
+
+func (srt *StepRuntime) resubmitJob(backoff time.Duration) {
+	select {
+	case <-time.After(backoff):
+	case <-srt.ctx.Done():
+	case <-srt.failureOpitonsCtx.Done():
+	}
+
+	defer srt.processJobLock.Unlock()
+	srt.processJobLock.Lock()
+
+	defer srt.catchPanic()
+
+	// 终止信号由 Stop 协程处理
+	if srt.done || !srt.retrying || srt.ctx.Err() != nil || srt.failureOpitonsCtx.Err() != nil {
+		return
+	}
+	srt.retrying = false
+
+	// 新的 job 沿用失败的 job 替换后的 command, parameters, env 以及 artifacts
+	failedJob := srt.job.Job()
+	step := srt.getWorkFlowStep()
+	jobName := fmt.Sprintf("%s-retry-%d", generateJobName(srt.runID, step.GetName(), srt.loopSeq), len(srt.attempts))
+	newJob := NewPaddleFlowJob(jobName, step.DockerEnv, srt.userName, srt.receiveEventChildren,
+		srt.runConfig.mainFS, step.ExtraFS)
+	newJob.Update(failedJob.Command, failedJob.Parameters, failedJob.Env, &failedJob.Artifacts)
+	srt.job = newJob
+
+	_, err := srt.job.Start()
+	if err != nil {
+		errMsg := fmt.Sprintf("retry job for step[%s] with runid[%s] failed: [%s]", srt.name, srt.runID, err.Error())
+		srt.logger.Errorf(errMsg)
+		srt.processStartAbnormalStatus(errMsg, StatusRuntimeFailed)
+		return
+	}
+
+	if step.Cache.Enable {
+		// cache 记录需要指向最新的 job
+		if err := srt.logCache(); err != nil {
+			srt.logger.Errorf(err.Error())
+		}
+	}
+
+	logMsg := fmt.Sprintf("step[%s] of runid[%s]: retry with jobID[%s], attempt [%d/%d]",
+		srt.name, srt.runID, srt.job.JobID(), len(srt.attempts), step.Retry.Limit)
+	srt.logger.Infof(logMsg)
+	view := srt.newJobView(logMsg)
+	srt.syncToApiServerAndParent(WfEventJobUpdate, &view, logMsg)
+}
+
 func (srt *StepRuntime) newJobView(msg string) schema.JobView {
 	step := srt.getWorkFlowStep()
 	params := map[string]string{}
@@ -831,6 +1024,7 @@ func (srt *StepRuntime) newJobView(msg string) schema.JobView {
 		LoopSeq:     srt.loopSeq,
 		Artifacts:   *newArt,
 		ExtraFS:     srt.getWorkFlowStep().ExtraFS,
+		Attempts:    append(srt.attempts, []schema.JobAttempt{}...),
 	}
 
 	return view
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -103,7 +104,7 @@ func TestUpdateJobForFingerPrint(t *testing.T) {
 	dr.setSysParams()
 
 	ds := NewDependencySolver(dr)
-	sysNum := 4 // 系统变量数量
+	sysNum := 5 // 系统变量数量
 	for _, stepName := range sortedSteps {
 		err := ds.ResolveBeforeRun(dr.getworkflowSouceDag().EntryPoints[stepName])
 		assert.Nil(t, err)
@@ -652,6 +653,200 @@ func TestProcessEventFromJob(t *testing.T) {
 
 }
 
+func TestProcessEventFromJobWithRetry(t *testing.T) {
+	handler.NewFsHandlerWithServer = handler.MockerNewFsHandlerWithServer
+	testCase := loadcase(runYamlPath)
+	wfs, err := schema.GetWorkflowSource([]byte(testCase))
+	assert.Nil(t, err)
+
+	rf := mockRunConfigForComponentRuntime()
+	rf.WorkflowSource = &wfs
+	rf.callbacks = mockCbs
+
+	extra := GetExtra()
+	wfptr, err := NewMockWorkflow(wfs, rf.runID, map[string]interface{}{}, extra, rf.callbacks)
+	assert.Nil(t, err)
+
+	wfs = wfptr.Source
+
+	var views []schema.JobView
+	var viewsLock sync.Mutex
+	rf.callbacks.UpdateRuntimeCb = func(id string, event interface{}) (int64, bool) {
+		return 123, true
+	}
+
+	eventChan := make(chan WorkflowEvent)
+	go func(eventChan chan WorkflowEvent) {
+		for {
+			ep := <-eventChan
+			if view, ok := ep.Extra[apicommon.WfEventKeyView].(*schema.JobView); ok {
+				viewsLock.Lock()
+				views = append(views, *view)
+				viewsLock.Unlock()
+			}
+		}
+	}(eventChan)
+
+	failctx, _ := context.WithCancel(context.Background())
+	st := wfs.EntryPoints.EntryPoints["data-preprocess"].(*schema.WorkflowSourceStep)
+	st.Retry = schema.RetryPolicy{Limit: 1, RetryOn: []string{schema.RetryOnEvicted}}
+	srt := NewStepRuntime("a.entrypoint.data-preprocess", "a.entrypoint.data-preprocess", st, 0, context.Background(), failctx,
+		eventChan, rf, "dag-11")
+	srt.setSysParams()
+	srt.job.(*PaddleFlowJob).ID = "job-001"
+
+	started := false
+	patch := gomonkey.ApplyMethod(reflect.TypeOf(srt.job), "Start", func(_ *PaddleFlowJob) (string, error) {
+		started = true
+		return "job-002", nil
+	})
+	defer patch.Reset()
+
+	// 1、失败原因不在 retry_on 中，不会重试
+	assert.False(t, srt.needRetry(StatusRuntimeFailed, schema.RetryOnFailed))
+	assert.False(t, srt.needRetry(StatusRuntimeFailed, ""))
+
+	// 2、message 中包含 Evicted 但 reason 不是 evicted 时，不会重试
+	srt.increase()
+	event := NewWorkflowEvent(WfEventJobUpdate, "job is failed, user log: Evicted", map[string]interface{}{
+		apicommon.WfEventKeyStatus: StatusRuntimeFailed,
+		apicommon.WfEventKeyReason: schema.RetryOnFailed,
+	})
+	assert.False(t, srt.needRetry(StatusRuntimeFailed, event.Extra[apicommon.WfEventKeyReason].(string)))
+
+	// 3、被驱逐的 job 会重新提交
+	event = NewWorkflowEvent(WfEventJobUpdate, "job is failed", map[string]interface{}{
+		apicommon.WfEventKeyStatus: StatusRuntimeFailed,
+		apicommon.WfEventKeyReason: schema.RetryOnEvicted,
+	})
+	srt.processEventFromJob(*event)
+	time.Sleep(time.Millisecond * 100)
+
+	// 重新提交 job 的协程持有 processJobLock
+	srt.processJobLock.Lock()
+	assert.True(t, started)
+	assert.False(t, srt.done)
+	assert.Equal(t, StatusRuntimeRunning, srt.status)
+	assert.Equal(t, 1, len(srt.attempts))
+	assert.Equal(t, "job-001", srt.attempts[0].JobID)
+	assert.NotEqual(t, "job-001", srt.job.JobID())
+	srt.processJobLock.Unlock()
+	viewsLock.Lock()
+	assert.Equal(t, 1, len(views[len(views)-1].Attempts))
+	viewsLock.Unlock()
+
+	// 4、超过重试次数后，step 失败
+	srt.processEventFromJob(*event)
+	time.Sleep(time.Millisecond * 100)
+	assert.True(t, srt.done)
+	assert.Equal(t, StatusRuntimeFailed, srt.status)
+}
+
+func TestStepProcessTimeout(t *testing.T) {
+	handler.NewFsHandlerWithServer = handler.MockerNewFsHandlerWithServer
+	testCase := loadcase(runYamlPath)
+	wfs, err := schema.GetWorkflowSource([]byte(testCase))
+	assert.Nil(t, err)
+
+	rf := mockRunConfigForComponentRuntime()
+	rf.WorkflowSource = &wfs
+	rf.callbacks = mockCbs
+
+	extra := GetExtra()
+	wfptr, err := NewMockWorkflow(wfs, rf.runID, map[string]interface{}{}, extra, rf.callbacks)
+	assert.Nil(t, err)
+
+	wfs = wfptr.Source
+	rf.callbacks.UpdateRuntimeCb = func(id string, event interface{}) (int64, bool) {
+		return 123, true
+	}
+
+	eventChan := make(chan WorkflowEvent)
+	go func(eventChan chan WorkflowEvent) {
+		for {
+			<-eventChan
+		}
+	}(eventChan)
+
+	failctx, _ := context.WithCancel(context.Background())
+	st := wfs.EntryPoints.EntryPoints["data-preprocess"].(*schema.WorkflowSourceStep)
+	st.Timeout = 10
+
+	// 1、job 还没有创建时超时，step 直接置为 timeout
+	srt := NewStepRuntime("a.entrypoint.data-preprocess", "a.entrypoint.data-preprocess", st, 0, context.Background(), failctx,
+		eventChan, rf, "dag-11")
+	srt.increase()
+	srt.processTimeout()
+	assert.True(t, srt.done)
+	assert.Equal(t, StatusRuntimeTimeout, srt.status)
+
+	// 2、job 运行中超时，job 被终止后，step 的状态为 timeout
+	srt = NewStepRuntime("a.entrypoint.data-preprocess", "a.entrypoint.data-preprocess", st, 0, context.Background(), failctx,
+		eventChan, rf, "dag-11")
+	srt.job.(*PaddleFlowJob).ID = "job-001"
+	srt.increase()
+	srt.updateStatus(StatusRuntimeRunning)
+
+	stopped := false
+	patch := gomonkey.ApplyMethod(reflect.TypeOf(srt.job), "Stop", func(_ *PaddleFlowJob) error {
+		stopped = true
+		return nil
+	})
+	defer patch.Reset()
+
+	srt.processTimeout()
+	assert.True(t, stopped)
+	assert.False(t, srt.done)
+
+	event := NewWorkflowEvent(WfEventJobUpdate, "job is terminated", map[string]interface{}{
+		apicommon.WfEventKeyStatus: StatusRuntimeTerminated,
+	})
+	srt.processEventFromJob(*event)
+	assert.True(t, srt.done)
+	assert.Equal(t, StatusRuntimeTimeout, srt.status)
+	patch.Reset()
+
+	// 3、终止 job 期间收到的 job 结束事件，需要等待超时处理完成之后再处理
+	var statuses []RuntimeStatus
+	var statusesLock sync.Mutex
+	recordChan := make(chan WorkflowEvent)
+	go func(recordChan chan WorkflowEvent) {
+		for {
+			ep := <-recordChan
+			statusesLock.Lock()
+			statuses = append(statuses, ep.Extra[apicommon.WfEventKeyStatus].(RuntimeStatus))
+			statusesLock.Unlock()
+		}
+	}(recordChan)
+
+	srt = NewStepRuntime("a.entrypoint.data-preprocess", "a.entrypoint.data-preprocess", st, 0, context.Background(), failctx,
+		recordChan, rf, "dag-11")
+	srt.job.(*PaddleFlowJob).ID = "job-002"
+	srt.increase()
+	srt.updateStatus(StatusRuntimeRunning)
+
+	eventDone := make(chan struct{})
+	patch = gomonkey.ApplyMethod(reflect.TypeOf(srt.job), "Stop", func(_ *PaddleFlowJob) error {
+		go func() {
+			srt.processEventFromJob(*event)
+			close(eventDone)
+		}()
+		time.Sleep(time.Millisecond * 50)
+		return nil
+	})
+	defer patch.Reset()
+
+	srt.processTimeout()
+	<-eventDone
+	time.Sleep(time.Millisecond * 50)
+
+	assert.True(t, srt.done)
+	assert.Equal(t, StatusRuntimeTimeout, srt.status)
+	statusesLock.Lock()
+	assert.Equal(t, []RuntimeStatus{StatusRuntimeRunning, StatusRuntimeTimeout}, statuses)
+	statusesLock.Unlock()
+}
+
 func TestStart(t *testing.T) {
 	handler.NewFsHandlerWithServer = handler.MockerNewFsHandlerWithServer
 	testCase := loadcase(runYamlPath)
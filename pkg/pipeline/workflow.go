@@ -624,6 +624,9 @@ func (bwf *BaseWorkflow) checkCache() error {
 	if err != nil {
 		return fmt.Errorf("MaxExpiredTime[%s] of cache not correct", bwf.Source.Cache.MaxExpiredTime)
 	}
+	if err := checkCacheStrategy(&bwf.Source.Cache); err != nil {
+		return err
+	}
 
 	// FsScope 由于涉及到 Fs权限校验，FsID填充等操作，不便在此进行，在此前已经完成校验
 
@@ -648,6 +651,9 @@ func (bwf *BaseWorkflow) checkStepCache(components map[string]schema.Component)
 				if err != nil {
 					return fmt.Errorf("MaxExpiredTime[%s] of cache in step[%s] not correct", step.Cache.MaxExpiredTime, name)
 				}
+				if err := checkCacheStrategy(&step.Cache); err != nil {
+					return fmt.Errorf("check cache of step[%s] failed: %s", name, err.Error())
+				}
 			}
 		} else {
 			return fmt.Errorf("component not step or dag")
@@ -656,6 +662,19 @@ func (bwf *BaseWorkflow) checkStepCache(components map[string]schema.Component)
 	return nil
 }
 
+// 校验 cache 策略，如果没传，默认为保守策略
+func checkCacheStrategy(cache *schema.Cache) error {
+	switch cache.Strategy {
+	case "":
+		cache.Strategy = CacheStrategyConservative
+	case CacheStrategyConservative, CacheStrategyAggressive:
+	default:
+		return fmt.Errorf("strategy[%s] of cache should be [%s] or [%s]", cache.Strategy,
+			CacheStrategyConservative, CacheStrategyAggressive)
+	}
+	return nil
+}
+
 func (bwf *BaseWorkflow) checkParams() error {
 	for paramName, paramVal := range bwf.Params {
 		if err := bwf.replaceRunParam(paramName, paramVal); err != nil {
@@ -1168,6 +1187,7 @@ func (wf *Workflow) NewWorkflowRuntime() error {
 	logger.LoggerForRun(wf.RunID).Debugf("initializing [%d] parallelism jobs", wf.Source.Parallelism)
 	runConf := NewRunConfig(&wf.Source, &wf.Source.FsOptions.MainFS, wf.Extra[WfExtraInfoKeyFSUserName], wf.RunID,
 		logger.LoggerForRun(wf.RunID), wf.callbacks, wf.Extra[WfExtraInfoKeySource])
+	runConf.scheduledTime = wf.Extra[WfExtraInfoKeyScheduledTime]
 	wf.runtime = NewWorkflowRuntime(runConf)
 
 	return nil
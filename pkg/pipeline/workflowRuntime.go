@@ -43,6 +43,9 @@ type WorkflowRuntime struct {
 	pk                    int64
 	startTime             string
 
+	// 为 true 时表示 run 已经超时，run 结束后的状态为 timeout
+	timedOut bool
+
 	// 主要用于避免在调度节点的同时遇到终止任务的情况
 	scheduleLock sync.Mutex
 }
@@ -116,6 +119,7 @@ func (wfr *WorkflowRuntime) Start() {
 		wfr.callback("begin to running, update status to running")
 
 		go wfr.Listen()
+		wfr.watchTimeout()
 		wfr.entryPoints.Start()
 	}
 }
@@ -130,6 +134,9 @@ func (wfr *WorkflowRuntime) Resume(entryPointView *schema.DagView, postProcessVi
 	wfr.status = runStatus
 
 	wfr.startTime = entryPointView.StartTime
+	if runStatus == common.StatusRunRunning {
+		wfr.watchTimeout()
+	}
 
 	// 1、如果 ep 未处于终态， 则需要重启ep
 	if !isRuntimeFinallyStatus(entryPointView.Status) {
@@ -206,6 +213,7 @@ func (wfr *WorkflowRuntime) Restart(entryPointView *schema.DagView,
 	msg := fmt.Sprintf("restart run[%s], and update status to [%s]", wfr.runID, wfr.status)
 	wfr.logger.Infof(msg)
 	wfr.callback(msg)
+	wfr.watchTimeout()
 
 	if entryPointView.Status != StatusRuntimeSucceeded {
 		wfr.entryPoints.Restart(entryPointView)
@@ -271,6 +279,32 @@ func (wfr *WorkflowRuntime) Stop(force bool) error {
 	return nil
 }
 
+// watchTimeout: 如果 run 设置了 timeout，则在超时后终止 entryPoints，postProcess 节点仍然会被执行
+func (wfr *WorkflowRuntime) watchTimeout() {
+	timeout := wfr.WorkflowSource.Timeout
+	if timeout <= 0 {
+		return
+	}
+
+	go waitTimeout(timeoutAfter(timeout, wfr.startTime), wfr.processTimeout, wfr.entryPointsCtx, wfr.postProcessPointsCtx)
+}
+
+func (wfr *WorkflowRuntime) processTimeout() {
+	wfr.scheduleLock.Lock()
+	if wfr.IsCompleted() || wfr.status == common.StatusRunTerminating {
+		wfr.scheduleLock.Unlock()
+		return
+	}
+	wfr.timedOut = true
+	wfr.scheduleLock.Unlock()
+
+	wfr.logger.Infof("run[%s] timeout after %d seconds, begin to stop it", wfr.runID, wfr.WorkflowSource.Timeout)
+	// 与文档保持一致，超时后 postProcess 仍然会被执行
+	if err := wfr.Stop(false); err != nil {
+		wfr.logger.Errorf("stop run[%s] for timeout failed: %s", wfr.runID, err.Error())
+	}
+}
+
 func (wfr *WorkflowRuntime) Status() string {
 	return wfr.status
 }
@@ -292,7 +326,8 @@ func (wfr *WorkflowRuntime) Listen() {
 func (wfr *WorkflowRuntime) IsCompleted() bool {
 	return wfr.status == common.StatusRunSucceeded ||
 		wfr.status == common.StatusRunFailed ||
-		wfr.status == common.StatusRunTerminated
+		wfr.status == common.StatusRunTerminated ||
+		wfr.status == common.StatusRunTimeout
 }
 
 func (wfr *WorkflowRuntime) schedulePostProcess() {
@@ -380,14 +415,16 @@ func (wfr *WorkflowRuntime) updateStatusAccordingComponentStatus() {
 		}
 	}
 
-	hasFailedComponent := wfr.entryPoints.isFailed() ||
-		(wfr.postProcess != nil && wfr.postProcess.isFailed())
+	hasFailedComponent := wfr.entryPoints.isFailed() || wfr.entryPoints.isTimeout() ||
+		(wfr.postProcess != nil && (wfr.postProcess.isFailed() || wfr.postProcess.isTimeout()))
 	hasTerminatedComponent := wfr.entryPoints.isTerminated() ||
 		(wfr.postProcess != nil && wfr.postProcess.isTerminated())
 	hasCancelledComponent := wfr.entryPoints.isCancelled() ||
 		(wfr.postProcess != nil && wfr.postProcess.isCancelled())
 
-	if hasFailedComponent {
+	if wfr.timedOut {
+		wfr.status = common.StatusRunTimeout
+	} else if hasFailedComponent {
 		wfr.status = common.StatusRunFailed
 	} else if hasTerminatedComponent || hasCancelledComponent {
 		if wfr.status == common.StatusRunTerminating {
@@ -156,6 +156,35 @@ func TestStopRun(t *testing.T) {
 
 }
 
+// 测试 run 超时后，entryPoints 被终止，postProcess 不会被取消
+func TestRunTimeout(t *testing.T) {
+	wfr, err := mockWorkflowRuntime()
+	assert.Nil(t, err)
+
+	var drt *DagRuntime
+	patch := gomonkey.ApplyMethod(reflect.TypeOf(drt), "Start", func(drt *DagRuntime) {
+		drt.updateStatus(StatusRuntimeRunning)
+		return
+	})
+	defer patch.Reset()
+
+	wfr.Start()
+	go wfr.Listen()
+	time.Sleep(time.Millisecond * 100)
+
+	wfr.processTimeout()
+	time.Sleep(time.Millisecond * 100)
+
+	assert.True(t, wfr.timedOut)
+	assert.Equal(t, common.StatusRunTerminating, wfr.status)
+	assert.Nil(t, wfr.postProcess)
+
+	// 已经在终止中的 run 不会重复处理超时
+	wfr.timedOut = false
+	wfr.processTimeout()
+	assert.False(t, wfr.timedOut)
+}
+
 func TestRestartEntry(t *testing.T) {
 	wfr, err := mockWorkflowRuntime()
 	assert.Nil(t, err)
@@ -459,6 +459,19 @@ func TestValidateWorkflowCache(t *testing.T) {
 	err = mockValidate(&bwf)
 	assert.NotNil(t, err)
 	assert.Equal(t, "MaxExpiredTime[notInt] of cache in step[data-preprocess] not correct", err.Error())
+
+	// cache Strategy 未设置时，默认为保守策略
+	bwf.Source.EntryPoints.EntryPoints["data-preprocess"].(*schema.WorkflowSourceStep).Cache.MaxExpiredTime = ""
+	err = mockValidate(&bwf)
+	assert.Nil(t, err)
+	assert.Equal(t, pplcommon.CacheStrategyConservative, bwf.Source.Cache.Strategy)
+	assert.Equal(t, pplcommon.CacheStrategyConservative, bwf.Source.EntryPoints.EntryPoints["data-preprocess"].(*schema.WorkflowSourceStep).Cache.Strategy)
+
+	// 节点cache Strategy 设置失败
+	bwf.Source.EntryPoints.EntryPoints["data-preprocess"].(*schema.WorkflowSourceStep).Cache.Strategy = "unknown"
+	err = mockValidate(&bwf)
+	assert.NotNil(t, err)
+	assert.Equal(t, "check cache of step[data-preprocess] failed: strategy[unknown] of cache should be [conservative] or [aggressive]", err.Error())
 }
 
 // 测试不使用Fs时，workflow校验逻辑
@@ -48,7 +48,8 @@ func (as *AuthStore) CreateUser(ctx *logger.RequestContext, user *model.User) er
 
 func (as *AuthStore) UpdateUser(ctx *logger.RequestContext, userName, password string) error {
 	ctx.Logging().Debugf("model update user's password, userName:%v.", userName)
-	err := as.db.Model(&model.User{}).Where("name = ?", userName).UpdateColumn("password", password).Error
+	// updated_at is updated too, which invalidates login tokens issued before
+	err := as.db.Model(&model.User{}).Where("name = ?", userName).Update("password", password).Error
 	if err != nil {
 		ctx.Logging().Errorf("model update password failed . userName:%v, error:%s ",
 			userName, err)
@@ -120,43 +121,59 @@ func (as *AuthStore) CreateGrant(ctx *logger.RequestContext, grant *model.Grant)
 	return nil
 }
 
-func (as *AuthStore) DeleteGrant(ctx *logger.RequestContext, userName, resourceType, resourceID string) error {
-	ctx.Logging().Debugf("model begin delete grant. userName:%s, resourceID:%s ", userName, resourceID)
-	tx := as.db.Unscoped().Table("grant").Where("user_name = ? and resource_type = ? and resource_id = ?", userName, resourceType, resourceID).Delete(&model.Grant{})
+func (as *AuthStore) DeleteGrant(ctx *logger.RequestContext, userName, groupName, resourceType, resourceID string) error {
+	ctx.Logging().Debugf("model begin delete grant. userName:%s, groupName:%s, resourceID:%s ", userName, groupName, resourceID)
+	tx := as.db.Unscoped().Table("grant").Where("user_name = ? and group_name = ? and resource_type = ? and resource_id = ?",
+		userName, groupName, resourceType, resourceID).Delete(&model.Grant{})
 	if tx.Error != nil {
-		ctx.Logging().Errorf("delete grant failed. userName:%v, resourceID:%s. error:%s",
-			userName, resourceID, tx.Error.Error())
+		ctx.Logging().Errorf("delete grant failed. userName:%v, groupName:%s, resourceID:%s. error:%s",
+			userName, groupName, resourceID, tx.Error.Error())
 		return tx.Error
 	}
 	return nil
 }
 
-func (as *AuthStore) GetGrant(ctx *logger.RequestContext, userName, resourceType, resourceID string) (*model.Grant, error) {
-	ctx.Logging().Debugf("model begin get grant. userName:%s, resourceID:%s ", userName, resourceID)
+func (as *AuthStore) GetGrant(ctx *logger.RequestContext, userName, groupName, resourceType, resourceID string) (*model.Grant, error) {
+	ctx.Logging().Debugf("model begin get grant. userName:%s, groupName:%s, resourceID:%s ", userName, groupName, resourceID)
 	var grant model.Grant
-	tx := as.db.Model(&model.Grant{}).Where("user_name = ? and resource_id = ? and resource_type = ?", userName, resourceID, resourceType).First(&grant)
+	tx := as.db.Model(&model.Grant{}).Where("user_name = ? and group_name = ? and resource_id = ? and resource_type = ?",
+		userName, groupName, resourceID, resourceType).First(&grant)
 	if tx.Error != nil {
-		ctx.Logging().Errorf("model get grant failed. userName:%v, resourceID:%s. error:%s.",
-			userName, resourceID, tx.Error.Error())
+		ctx.Logging().Errorf("model get grant failed. userName:%v, groupName:%s, resourceID:%s. error:%s.",
+			userName, groupName, resourceID, tx.Error.Error())
 		return nil, tx.Error
 	}
 	return &grant, nil
 }
 
-func (as *AuthStore) HasAccessToResource(ctx *logger.RequestContext, resourceType string, resourceID string) bool {
+// ListRolesOfResource returns roles of resource granted to the user and groups of the user
+func (as *AuthStore) ListRolesOfResource(ctx *logger.RequestContext, userName, resourceType, resourceID string) ([]string, error) {
+	var roles []string
+	groups := as.db.Model(&model.GroupMember{}).Select("group_name").Where("user_name = ?", userName)
+	tx := as.db.Model(&model.Grant{}).Where("resource_type = ? and resource_id = ?", resourceType, resourceID).
+		Where(as.db.Where("user_name = ?", userName).Or("group_name IN (?)", groups)).Pluck("role", &roles)
+	if tx.Error != nil {
+		ctx.Logging().Errorf("list roles of resourceID[%s] resourceType[%s] failed. error:%s",
+			resourceID, resourceType, tx.Error.Error())
+		return nil, tx.Error
+	}
+	return roles, nil
+}
+
+// HasPermission returns true if the user of ctx is root, or any role granted to the user or groups of the user allows the action
+func (as *AuthStore) HasPermission(ctx *logger.RequestContext, resourceType, resourceID, action string) bool {
 	if common.IsRootUser(ctx.UserName) {
 		return true
 	}
-
-	var num int64
-	tx := as.db.Model(&model.Grant{}).Where("user_name = ? and resource_type = ? and resource_id = ?",
-		ctx.UserName, resourceType, resourceID).Count(&num)
-	if tx.Error != nil {
-		ctx.Logging().Errorf("deny access to resourceID[%s] resourceType[%s].", resourceID, resourceType)
+	roles, err := as.ListRolesOfResource(ctx, ctx.UserName, resourceType, resourceID)
+	if err != nil {
+		ctx.Logging().Errorf("deny %s of resourceID[%s] resourceType[%s].", action, resourceID, resourceType)
 		return false
 	}
-	if num > 0 {
-		return true
+	for _, role := range roles {
+		if common.RoleAllows(role, action) {
+			return true
+		}
 	}
 	return false
 }
@@ -181,8 +198,8 @@ func (as *AuthStore) DeleteGrantByResourceID(ctx *logger.RequestContext, resourc
 	return nil
 }
 
-func (as *AuthStore) ListGrant(ctx *logger.RequestContext, pk int64, maxKeys int, userName string) ([]model.Grant, error) {
-	ctx.Logging().Debugf("model begin list grants by userName. userName:%s. ", userName)
+func (as *AuthStore) ListGrant(ctx *logger.RequestContext, pk int64, maxKeys int, userName, groupName string) ([]model.Grant, error) {
+	ctx.Logging().Debugf("model begin list grants. userName:%s, groupName:%s. ", userName, groupName)
 	query := as.db.Model(&model.Grant{})
 	query.Where("pk > ?", pk)
 	if maxKeys > 0 {
@@ -191,11 +208,14 @@ func (as *AuthStore) ListGrant(ctx *logger.RequestContext, pk int64, maxKeys int
 	if userName != "" {
 		query.Where("user_name = ?", userName)
 	}
+	if groupName != "" {
+		query.Where("group_name = ?", groupName)
+	}
 	var grants []model.Grant
 
 	if err := query.Find(&grants).Error; err != nil {
-		ctx.Logging().Errorf("model list grant failed. userName:[%s]. error:%s.",
-			userName, err.Error())
+		ctx.Logging().Errorf("model list grant failed. userName:[%s], groupName:[%s]. error:%s.",
+			userName, groupName, err.Error())
 		return nil, err
 	}
 	return grants, nil
@@ -211,3 +231,207 @@ func (as *AuthStore) GetLastGrant(ctx *logger.RequestContext) (model.Grant, erro
 	}
 	return grant, nil
 }
+
+// ============================================================= table access_token ============================================================= //
+
+func (as *AuthStore) CreateAccessToken(ctx *logger.RequestContext, token *model.AccessToken) error {
+	ctx.Logging().Debugf("model begin create access token. userName:%s, name:%s", token.UserName, token.Name)
+	token.ID = uuid.GenerateID(common.PrefixToken)
+	tx := as.db.Model(&model.AccessToken{}).Create(token)
+	if tx.Error != nil {
+		ctx.Logging().Errorf("create access token failed. userName:%s, error:%s",
+			token.UserName, tx.Error.Error())
+		return tx.Error
+	}
+	return nil
+}
+
+func (as *AuthStore) GetAccessToken(ctx *logger.RequestContext, tokenID string) (model.AccessToken, error) {
+	ctx.Logging().Debugf("model begin get access token. tokenID:%s", tokenID)
+	var token model.AccessToken
+	tx := as.db.Model(&model.AccessToken{}).Where("id = ?", tokenID).First(&token)
+	if tx.Error != nil {
+		ctx.Logging().Errorf("get access token failed. tokenID:%s, error:%s", tokenID, tx.Error.Error())
+		return model.AccessToken{}, tx.Error
+	}
+	return token, nil
+}
+
+func (as *AuthStore) DeleteAccessToken(ctx *logger.RequestContext, tokenID string) error {
+	ctx.Logging().Debugf("model begin delete access token. tokenID:%s", tokenID)
+	tx := as.db.Model(&model.AccessToken{}).Where("id = ?", tokenID).Delete(&model.AccessToken{})
+	if tx.Error != nil {
+		ctx.Logging().Errorf("delete access token failed. tokenID:%s, error:%s", tokenID, tx.Error.Error())
+		return tx.Error
+	}
+	return nil
+}
+
+func (as *AuthStore) DeleteAccessTokenByUserName(ctx *logger.RequestContext, userName string) error {
+	ctx.Logging().Debugf("model begin delete access token by userName. userName:%s", userName)
+	err := as.db.Model(&model.AccessToken{}).Where("user_name = ?", userName).Delete(&model.AccessToken{}).Error
+	if err != nil {
+		ctx.Logging().Errorf("delete access token by userName failed. userName:%s, error:%s", userName, err.Error())
+		return err
+	}
+	return nil
+}
+
+func (as *AuthStore) ListAccessToken(ctx *logger.RequestContext, pk int64, maxKeys int, userName string) ([]model.AccessToken, error) {
+	ctx.Logging().Debugf("model begin list access tokens. userName:%s", userName)
+	query := as.db.Model(&model.AccessToken{}).Where("pk > ?", pk)
+	if userName != "" {
+		query = query.Where("user_name = ?", userName)
+	}
+	if maxKeys > 0 {
+		query = query.Limit(maxKeys)
+	}
+	var tokens []model.AccessToken
+	if err := query.Order("pk").Find(&tokens).Error; err != nil {
+		ctx.Logging().Errorf("list access token failed. userName:%s, error:%s", userName, err.Error())
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (as *AuthStore) GetLastAccessToken(ctx *logger.RequestContext, userName string) (model.AccessToken, error) {
+	ctx.Logging().Debugf("get last access token. userName:%s", userName)
+	token := model.AccessToken{}
+	query := as.db.Model(&model.AccessToken{})
+	if userName != "" {
+		query = query.Where("user_name = ?", userName)
+	}
+	if err := query.Last(&token).Error; err != nil {
+		ctx.Logging().Errorf("get last access token failed. error:%s", err.Error())
+		return model.AccessToken{}, err
+	}
+	return token, nil
+}
+
+// ============================================================= table user_group ============================================================= //
+
+func (as *AuthStore) CreateGroup(ctx *logger.RequestContext, group *model.Group) error {
+	ctx.Logging().Debugf("model begin create group. name:%s", group.Name)
+	tx := as.db.Model(&model.Group{}).Create(group)
+	if tx.Error != nil {
+		ctx.Logging().Errorf("create group failed. name:%s, error:%s", group.Name, tx.Error.Error())
+		return tx.Error
+	}
+	return nil
+}
+
+func (as *AuthStore) GetGroup(ctx *logger.RequestContext, groupName string) (model.Group, error) {
+	ctx.Logging().Debugf("model begin get group. name:%s", groupName)
+	var group model.Group
+	tx := as.db.Model(&model.Group{}).Where("name = ?", groupName).First(&group)
+	if tx.Error != nil {
+		ctx.Logging().Errorf("get group failed. name:%s, error:%s", groupName, tx.Error.Error())
+		return model.Group{}, tx.Error
+	}
+	return group, nil
+}
+
+// DeleteGroup deletes group with its members and grants
+func (as *AuthStore) DeleteGroup(ctx *logger.RequestContext, groupName string) error {
+	ctx.Logging().Debugf("model begin delete group. name:%s", groupName)
+	return as.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_name = ?", groupName).Delete(&model.GroupMember{}).Error; err != nil {
+			ctx.Logging().Errorf("delete members of group failed. name:%s, error:%s", groupName, err.Error())
+			return err
+		}
+		if err := tx.Unscoped().Where("group_name = ?", groupName).Delete(&model.Grant{}).Error; err != nil {
+			ctx.Logging().Errorf("delete grants of group failed. name:%s, error:%s", groupName, err.Error())
+			return err
+		}
+		if err := tx.Where("name = ?", groupName).Delete(&model.Group{}).Error; err != nil {
+			ctx.Logging().Errorf("delete group failed. name:%s, error:%s", groupName, err.Error())
+			return err
+		}
+		return nil
+	})
+}
+
+// ListGroup lists groups, only groups of the user are listed if userName is not empty
+func (as *AuthStore) ListGroup(ctx *logger.RequestContext, pk int64, maxKeys int, userName string) ([]model.Group, error) {
+	ctx.Logging().Debugf("model begin list groups. userName:%s", userName)
+	query := as.db.Model(&model.Group{}).Where("pk > ?", pk)
+	if userName != "" {
+		query = query.Where("name IN (?)",
+			as.db.Model(&model.GroupMember{}).Select("group_name").Where("user_name = ?", userName))
+	}
+	if maxKeys > 0 {
+		query = query.Limit(maxKeys)
+	}
+	var groups []model.Group
+	if err := query.Order("pk").Find(&groups).Error; err != nil {
+		ctx.Logging().Errorf("list group failed. userName:%s, error:%s", userName, err.Error())
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (as *AuthStore) GetLastGroup(ctx *logger.RequestContext, userName string) (model.Group, error) {
+	ctx.Logging().Debugf("get last group. userName:%s", userName)
+	group := model.Group{}
+	query := as.db.Model(&model.Group{})
+	if userName != "" {
+		query = query.Where("name IN (?)",
+			as.db.Model(&model.GroupMember{}).Select("group_name").Where("user_name = ?", userName))
+	}
+	if err := query.Last(&group).Error; err != nil {
+		ctx.Logging().Errorf("get last group failed. error:%s", err.Error())
+		return model.Group{}, err
+	}
+	return group, nil
+}
+
+func (as *AuthStore) AddGroupMember(ctx *logger.RequestContext, member *model.GroupMember) error {
+	ctx.Logging().Debugf("model begin add group member. group:%s, user:%s", member.GroupName, member.UserName)
+	tx := as.db.Model(&model.GroupMember{}).Create(member)
+	if tx.Error != nil {
+		ctx.Logging().Errorf("add group member failed. group:%s, user:%s, error:%s",
+			member.GroupName, member.UserName, tx.Error.Error())
+		return tx.Error
+	}
+	return nil
+}
+
+func (as *AuthStore) GetGroupMember(ctx *logger.RequestContext, groupName, userName string) (model.GroupMember, error) {
+	ctx.Logging().Debugf("model begin get group member. group:%s, user:%s", groupName, userName)
+	var member model.GroupMember
+	tx := as.db.Model(&model.GroupMember{}).Where("group_name = ? and user_name = ?", groupName, userName).First(&member)
+	if tx.Error != nil {
+		ctx.Logging().Errorf("get group member failed. group:%s, user:%s, error:%s", groupName, userName, tx.Error.Error())
+		return model.GroupMember{}, tx.Error
+	}
+	return member, nil
+}
+
+func (as *AuthStore) RemoveGroupMember(ctx *logger.RequestContext, groupName, userName string) error {
+	ctx.Logging().Debugf("model begin remove group member. group:%s, user:%s", groupName, userName)
+	tx := as.db.Where("group_name = ? and user_name = ?", groupName, userName).Delete(&model.GroupMember{})
+	if tx.Error != nil {
+		ctx.Logging().Errorf("remove group member failed. group:%s, user:%s, error:%s", groupName, userName, tx.Error.Error())
+		return tx.Error
+	}
+	return nil
+}
+
+func (as *AuthStore) ListGroupMember(ctx *logger.RequestContext, groupName string) ([]model.GroupMember, error) {
+	ctx.Logging().Debugf("model begin list group members. group:%s", groupName)
+	var members []model.GroupMember
+	if err := as.db.Model(&model.GroupMember{}).Where("group_name = ?", groupName).Order("pk").Find(&members).Error; err != nil {
+		ctx.Logging().Errorf("list group members failed. group:%s, error:%s", groupName, err.Error())
+		return nil, err
+	}
+	return members, nil
+}
+
+func (as *AuthStore) DeleteGroupMemberByUserName(ctx *logger.RequestContext, userName string) error {
+	ctx.Logging().Debugf("model begin delete group member by userName. userName:%s", userName)
+	if err := as.db.Where("user_name = ?", userName).Delete(&model.GroupMember{}).Error; err != nil {
+		ctx.Logging().Errorf("delete group member by userName failed. userName:%s, error:%s", userName, err.Error())
+		return err
+	}
+	return nil
+}
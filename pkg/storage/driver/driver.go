@@ -23,50 +23,66 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 
-	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/models"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/common/config"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
 	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage/migration"
 )
 
 const (
-	Mysql  = "mysql"
-	Sqlite = "sqlite"
+	Mysql    = "mysql"
+	Postgres = "postgres"
+	Sqlite   = "sqlite"
 	// data init for sqllite
-	dsn              = "file:paddleflow.db?cache=shared&mode=rwc"
-	rootUserName     = "root"
-	rootUserPassword = "$2a$10$1qdSQN5wMl3FtXoxw7mKpuxBqIuP0eYXTBM9CBn5H4KubM/g5Hrb6%"
+	dsn = "file:paddleflow.db?cache=shared&mode=rwc"
 )
 
 func InitStorage(conf *config.StorageConfig, logLevel string) error {
-	driver := strings.ToLower(conf.Driver)
-	gormConf := getGormConf(logLevel)
-	switch driver {
-	case Mysql:
-		storage.DB = initMysqlDB(conf, gormConf)
-	default:
-		// 若配置文件没有设置，则默认使用SQLLite
-		storage.DB = initSQLiteDB(gormConf)
-	}
-
+	storage.DB = OpenDB(conf, logLevel)
 	if storage.DB == nil {
 		panic(fmt.Errorf("Init database DB error\n"))
 	}
 	if err := setSqlDBConns(conf); err != nil {
 		return err
 	}
+	// 本地SQLite总是自动迁移，MySQL和PostgreSQL需配置migrateOnStart或执行migrate子命令
+	if isSQLite(conf.Driver) || conf.MigrateOnStart {
+		if _, err := migration.New(storage.DB).Up(""); err != nil {
+			log.Errorf("migrate database failed, error: %v", err)
+			return err
+		}
+	}
 
 	log.Debugf("InitStorage success.dbConf:%v", conf)
 	storage.InitStores(storage.DB)
 	return nil
 }
 
+// OpenDB opens database of the driver, SQLite is used if driver is not set
+func OpenDB(conf *config.StorageConfig, logLevel string) *gorm.DB {
+	gormConf := getGormConf(logLevel)
+	switch strings.ToLower(conf.Driver) {
+	case Mysql:
+		return initMysqlDB(conf, gormConf)
+	case Postgres:
+		return initPostgresDB(conf, gormConf)
+	default:
+		// 若配置文件没有设置，则默认使用SQLLite
+		return initSQLiteDB(gormConf)
+	}
+}
+
+func isSQLite(driver string) bool {
+	driver = strings.ToLower(driver)
+	return driver != Mysql && driver != Postgres
+}
+
 func InitCache(logLevel string) error {
 	gormConf := getGormConf(logLevel)
 	gormConf.Logger.LogMode(logger.Info)
@@ -166,56 +182,6 @@ func initSQLiteDB(gormConf *gorm.Config) *gorm.DB {
 		log.Fatalf("initSQLiteDB error[%s]", err.Error())
 		return nil
 	}
-
-	if err := createDatabaseTables(db); err != nil {
-		log.Fatalf("initSQLiteDB createDatabaseTables error[%s]", err.Error())
-		return nil
-	}
-	// init root user to db, can not be modified by config file currently
-	rootUser := model.User{
-		UserInfo: model.UserInfo{
-			Name:     rootUserName,
-			Password: rootUserPassword,
-		},
-	}
-
-	tx := db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "name"}},
-		DoUpdates: clause.AssignmentColumns([]string{"password"}),
-	}).Create(&rootUser)
-	if tx.Error != nil {
-		log.Fatalf("init sqllite db error[%s]", tx.Error)
-		return nil
-	}
-	// init flavour to db
-	flavours := []model.Flavour{
-		{
-			Name: "flavour1",
-			CPU:  "1",
-			Mem:  "1Gi",
-		},
-		{
-			Name:               "flavour2",
-			CPU:                "4",
-			Mem:                "8Gi",
-			RawScalarResources: `{"nvidia.com/gpu": "1"}`,
-		},
-		{
-			Name:               "flavour3",
-			CPU:                "4",
-			Mem:                "8Gi",
-			RawScalarResources: `{"nvidia.com/gpu": "2"}`,
-		},
-	}
-	tx = db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "name"}},
-		DoUpdates: clause.AssignmentColumns([]string{"cpu", "mem", "scalar_resources"}),
-	}).CreateInBatches(&flavours, 4)
-	if tx.Error != nil {
-		log.Fatalf("init sqllite db error[%s]", tx.Error)
-		return nil
-	}
-
 	log.Debugf("init sqlite DB success")
 	return db
 }
@@ -232,28 +198,29 @@ func initMysqlDB(dbConf *config.StorageConfig, gormConf *gorm.Config) *gorm.DB {
 	return db
 }
 
+func initPostgresDB(dbConf *config.StorageConfig, gormConf *gorm.Config) *gorm.DB {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=Local",
+		dbConf.Host, dbConf.Port, dbConf.User, dbConf.Password, dbConf.Database, postgresSSLMode(dbConf))
+	if dbConf.ConnectTimeoutInSeconds > 0 {
+		dsn = fmt.Sprintf("%s connect_timeout=%d", dsn, dbConf.ConnectTimeoutInSeconds)
+	}
+	db, err := gorm.Open(postgres.Open(dsn), gormConf)
+	if err != nil {
+		log.Fatalf("initPostgresDB error[%s]", err.Error())
+		return nil
+	}
+	log.Debugf("init postgres DB success")
+	return db
+}
+
+func postgresSSLMode(dbConf *config.StorageConfig) string {
+	if dbConf.SSLMode == "" {
+		return "disable"
+	}
+	return dbConf.SSLMode
+}
+
+// createDatabaseTables creates tables without initial data for mock db
 func createDatabaseTables(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&model.Pipeline{},
-		&model.PipelineVersion{},
-		&models.Schedule{},
-		&models.RunCache{},
-		&model.ArtifactEvent{},
-		&model.User{},
-		&models.Run{},
-		&models.RunJob{},
-		&models.RunDag{},
-		&model.Queue{},
-		&model.Flavour{},
-		&model.Grant{},
-		&model.Job{},
-		&model.JobTask{},
-		&model.JobLabel{},
-		&model.ClusterInfo{},
-		&model.Image{},
-		&model.FileSystem{},
-		&model.Link{},
-		&model.FSCacheConfig{},
-		&model.FSCache{},
-	)
+	return db.AutoMigrate(migration.Tables()...)
 }
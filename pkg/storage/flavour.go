@@ -32,7 +32,7 @@ var (
 	flavourSelectColumn = `flavour.pk as pk, flavour.id as id, flavour.name as name, flavour.cpu as cpu, flavour.mem as mem, 
 flavour.scalar_resources as scalar_resources, flavour.cluster_id as cluster_id, cluster_info.name as cluster_name,
 flavour.created_at as created_at, flavour.updated_at as updated_at, flavour.deleted_at as deleted_at`
-	flavourJoinCluster = "left join `cluster_info` on `cluster_info`.id = `flavour`.cluster_id"
+	flavourJoinCluster = "left join cluster_info on cluster_info.id = flavour.cluster_id"
 )
 
 type FlavourStore struct {
@@ -92,9 +92,9 @@ func (fs *FlavourStore) ListFlavour(pk int64, maxKeys int, clusterID, queryKey s
 	query := fs.db.Table(model.FlavourTableName).Where("flavour.pk > ?", pk).Select(flavourSelectColumn).Joins(flavourJoinCluster)
 
 	if clusterID != "" {
-		query.Where("`flavour`.`cluster_id` = ? or `flavour`.`cluster_id` = ''", clusterID)
+		query.Where("flavour.cluster_id = ? or flavour.cluster_id = ''", clusterID)
 	} else {
-		query.Where("`flavour`.`cluster_id` = ''")
+		query.Where("flavour.cluster_id = ''")
 	}
 
 	if !strings.EqualFold(queryKey, "") {
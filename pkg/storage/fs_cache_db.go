@@ -24,6 +24,9 @@ import (
 	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
 )
 
+var fsCacheWarmupColumns = []string{"warmup_id", "warmup_status", "warmup_total", "warmup_done",
+	"warmup_failed", "warmup_bytes", "warmup_msg"}
+
 func newDBFSCache(db *gorm.DB) FsCacheStoreInterface {
 	n := new(DBFSCache)
 	n.db = db
@@ -84,3 +87,10 @@ func (f *DBFSCache) Update(value *model.FSCache) (int64, error) {
 	result := f.db.Where(&model.FSCache{FsID: value.FsID, CacheID: value.CacheID}).Updates(value)
 	return result.RowsAffected, result.Error
 }
+
+// UpdateWarmup updates warmup progress of fs cache, zero values included
+func (f *DBFSCache) UpdateWarmup(value *model.FSCache) (int64, error) {
+	result := f.db.Model(&model.FSCache{}).Where(&model.FSCache{FsID: value.FsID, CacheID: value.CacheID}).
+		Select(fsCacheWarmupColumns).Updates(value)
+	return result.RowsAffected, result.Error
+}
@@ -163,3 +163,20 @@ func (mem *MemFSCache) Update(value *model.FSCache) (int64, error) {
 	}
 	return 1, nil
 }
+
+func (mem *MemFSCache) UpdateWarmup(value *model.FSCache) (int64, error) {
+	mem.fsCacheMap.Lock()
+	defer mem.fsCacheMap.Unlock()
+	cache, ok := mem.fsCacheMap.value[value.FsID][value.CacheID]
+	if !ok {
+		return 0, nil
+	}
+	cache.WarmupID = value.WarmupID
+	cache.WarmupStatus = value.WarmupStatus
+	cache.WarmupTotal = value.WarmupTotal
+	cache.WarmupDone = value.WarmupDone
+	cache.WarmupFailed = value.WarmupFailed
+	cache.WarmupBytes = value.WarmupBytes
+	cache.WarmupMsg = value.WarmupMsg
+	return 1, nil
+}
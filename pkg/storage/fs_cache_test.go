@@ -53,6 +53,24 @@ func TestDBFSCache(t *testing.T) {
 	fscacheList, err := dbfs.List("", "")
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(fscacheList))
+
+	// test update warmup, zero values are updated
+	fsCache1.WarmupID = "warmup1"
+	fsCache1.WarmupStatus = "running"
+	fsCache1.WarmupDone = 10
+	n, err := dbfs.UpdateWarmup(fsCache1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	fsCache1.WarmupID = "warmup2"
+	fsCache1.WarmupStatus = "pending"
+	fsCache1.WarmupDone = 0
+	_, err = dbfs.UpdateWarmup(fsCache1)
+	assert.Nil(t, err)
+	fsc, err = dbfs.Get("fsid", "cacheID1")
+	assert.Nil(t, err)
+	assert.Equal(t, "warmup2", fsc.WarmupID)
+	assert.Equal(t, int64(0), fsc.WarmupDone)
+	assert.Equal(t, 111, fsc.UsedSize)
 }
 
 func TestMemFSCache(t *testing.T) {
@@ -17,6 +17,8 @@ limitations under the License.
 package storage
 
 import (
+	"time"
+
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 
@@ -153,6 +155,7 @@ type FsCacheStoreInterface interface {
 	List(fsID, cacheID string) ([]model.FSCache, error)
 	ListNodes(fsID []string) ([]string, error)
 	Update(value *model.FSCache) (int64, error)
+	UpdateWarmup(value *model.FSCache) (int64, error)
 }
 
 type AuthStoreInterface interface {
@@ -165,13 +168,32 @@ type AuthStoreInterface interface {
 	GetLastUser(ctx *logger.RequestContext) (model.User, error)
 	// grant
 	CreateGrant(ctx *logger.RequestContext, grant *model.Grant) error
-	DeleteGrant(ctx *logger.RequestContext, userName, resourceType, resourceID string) error
-	GetGrant(ctx *logger.RequestContext, userName, resourceType, resourceID string) (*model.Grant, error)
-	HasAccessToResource(ctx *logger.RequestContext, resourceType string, resourceID string) bool
+	DeleteGrant(ctx *logger.RequestContext, userName, groupName, resourceType, resourceID string) error
+	GetGrant(ctx *logger.RequestContext, userName, groupName, resourceType, resourceID string) (*model.Grant, error)
+	ListRolesOfResource(ctx *logger.RequestContext, userName, resourceType, resourceID string) ([]string, error)
+	HasPermission(ctx *logger.RequestContext, resourceType, resourceID, action string) bool
 	DeleteGrantByUserName(ctx *logger.RequestContext, userName string) error
 	DeleteGrantByResourceID(ctx *logger.RequestContext, resourceID string) error
-	ListGrant(ctx *logger.RequestContext, pk int64, maxKeys int, userName string) ([]model.Grant, error)
+	ListGrant(ctx *logger.RequestContext, pk int64, maxKeys int, userName, groupName string) ([]model.Grant, error)
 	GetLastGrant(ctx *logger.RequestContext) (model.Grant, error)
+	// access token
+	CreateAccessToken(ctx *logger.RequestContext, token *model.AccessToken) error
+	GetAccessToken(ctx *logger.RequestContext, tokenID string) (model.AccessToken, error)
+	DeleteAccessToken(ctx *logger.RequestContext, tokenID string) error
+	DeleteAccessTokenByUserName(ctx *logger.RequestContext, userName string) error
+	ListAccessToken(ctx *logger.RequestContext, pk int64, maxKeys int, userName string) ([]model.AccessToken, error)
+	GetLastAccessToken(ctx *logger.RequestContext, userName string) (model.AccessToken, error)
+	// group
+	CreateGroup(ctx *logger.RequestContext, group *model.Group) error
+	GetGroup(ctx *logger.RequestContext, groupName string) (model.Group, error)
+	DeleteGroup(ctx *logger.RequestContext, groupName string) error
+	ListGroup(ctx *logger.RequestContext, pk int64, maxKeys int, userName string) ([]model.Group, error)
+	GetLastGroup(ctx *logger.RequestContext, userName string) (model.Group, error)
+	AddGroupMember(ctx *logger.RequestContext, member *model.GroupMember) error
+	GetGroupMember(ctx *logger.RequestContext, groupName, userName string) (model.GroupMember, error)
+	RemoveGroupMember(ctx *logger.RequestContext, groupName, userName string) error
+	ListGroupMember(ctx *logger.RequestContext, groupName string) ([]model.GroupMember, error)
+	DeleteGroupMemberByUserName(ctx *logger.RequestContext, userName string) error
 }
 
 type JobStoreInterface interface {
@@ -183,11 +205,13 @@ type JobStoreInterface interface {
 	DeleteJob(jobID string) error
 	UpdateJobStatus(jobId, errMessage string, newStatus schema.JobStatus) error
 	UpdateJobConfig(jobId string, conf *schema.Conf) error
+	UpdateJobMembers(jobID string, members []schema.Member) error
 	UpdateJob(jobID string, status schema.JobStatus, runtimeInfo, runtimeStatus interface{}, message string) (schema.JobStatus, error)
 	ListQueueJob(queueID string, status []schema.JobStatus) []model.Job
 	ListQueueInitJob(queueID string) []model.Job
 	ListJobsByQueueIDsAndStatus(queueIDs []string, status schema.JobStatus) []model.Job
 	ListJobByStatus(status schema.JobStatus) []model.Job
+	ListTimeoutJobs(now time.Time) []model.Job
 	GetJobsByRunID(runID string, jobID string) ([]model.Job, error)
 	ListJobByUpdateTime(updateTime string) ([]model.Job, error)
 	ListJobByParentID(parentID string) ([]model.Job, error)
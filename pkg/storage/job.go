@@ -17,6 +17,7 @@ limitations under the License.
 package storage
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -125,14 +126,32 @@ func (js *JobStore) UpdateJobConfig(jobId string, conf *schema.Conf) error {
 	if err != nil {
 		return err
 	}
+	job, err := js.GetJobByID(jobId)
+	if err != nil {
+		return errors.JobIDNotFoundError(jobId)
+	}
 	log.Infof("update job config [%v]", conf)
-	tx := js.db.Model(&model.Job{}).Where("id = ?", jobId).Where("deleted_at = ''").UpdateColumn("config", confJSON)
+	tx := js.db.Model(&model.Job{}).Where("id = ?", jobId).Where("deleted_at = ''").UpdateColumns(map[string]interface{}{
+		"config":   confJSON,
+		"deadline": jobDeadline(job.ActivatedAt, conf),
+	})
 	if tx.Error != nil {
 		return tx.Error
 	}
 	return nil
 }
 
+// jobDeadline returns the time when job activated at activatedAt runs out of max run time
+func jobDeadline(activatedAt sql.NullTime, conf *schema.Conf) sql.NullTime {
+	if !activatedAt.Valid || conf == nil || conf.GetMaxRunTime() <= 0 {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{
+		Time:  activatedAt.Time.Add(time.Duration(conf.GetMaxRunTime()) * time.Second),
+		Valid: true,
+	}
+}
+
 func jobStatusTransition(jobID string, preStatus, newStatus schema.JobStatus, msg string) (schema.JobStatus, string) {
 	if schema.IsImmutableJobStatus(preStatus) {
 		return preStatus, ""
@@ -150,6 +169,20 @@ func jobStatusTransition(jobID string, preStatus, newStatus schema.JobStatus, ms
 	return newStatus, msg
 }
 
+// UpdateJobMembers update members of job, such as replicas of elastic member
+func (js *JobStore) UpdateJobMembers(jobID string, members []schema.Member) error {
+	membersJSON, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+	log.Infof("update job %s members [%v]", jobID, members)
+	tx := js.db.Model(&model.Job{}).Where("id = ?", jobID).Where("deleted_at = ''").UpdateColumn("members", string(membersJSON))
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
 func (js *JobStore) UpdateJob(jobID string, status schema.JobStatus, runtimeInfo, runtimeStatus interface{}, message string) (schema.JobStatus, error) {
 	job, err := js.GetUnscopedJobByID(jobID)
 	if err != nil {
@@ -181,6 +214,7 @@ func (js *JobStore) UpdateJob(jobID string, status schema.JobStatus, runtimeInfo
 		})
 		updatedJob.ActivatedAt.Time = time.Now()
 		updatedJob.ActivatedAt.Valid = true
+		updatedJob.Deadline = jobDeadline(updatedJob.ActivatedAt, job.Config)
 	}
 	log.Debugf("update for job %s, updated content [%+v]", jobID, updatedJob)
 	tx := js.db.Table("job").Where("id = ?", jobID).Where("deleted_at = ''").Updates(&updatedJob)
@@ -236,6 +270,19 @@ func (js *JobStore) ListJobByStatus(status schema.JobStatus) []model.Job {
 	return jobs
 }
 
+// ListTimeoutJobs list running jobs which run out of max run time at now
+func (js *JobStore) ListTimeoutJobs(now time.Time) []model.Job {
+	db := js.db.Table("job").Where("status = ?", schema.StatusJobRunning).Where("deadline <= ?", now).
+		Where("deleted_at = ''")
+
+	var jobs []model.Job
+	if err := db.Find(&jobs).Error; err != nil {
+		log.Errorf("list timeout jobs failed, error:%s", err.Error())
+		return []model.Job{}
+	}
+	return jobs
+}
+
 func (js *JobStore) GetJobsByRunID(runID string, jobID string) ([]model.Job, error) {
 	var jobList []model.Job
 	query := js.db.Table("job").Where("id like ?", "job-"+runID+"-%").Where("deleted_at = ''")
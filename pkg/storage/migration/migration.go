@@ -0,0 +1,212 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const HistoryTableName = "schema_migration"
+
+// Migration changes schema or data of database from the previous version to Version, and Down reverts it.
+// migrations are written with gorm, so that the same migration can be applied to mysql, postgres and sqlite.
+type Migration struct {
+	// Version is unique and increasing, such as 202211010000
+	Version     string
+	Description string
+	Up          func(tx *gorm.DB) error
+	Down        func(tx *gorm.DB) error
+}
+
+// History records a migration applied to database
+type History struct {
+	Version     string    `gorm:"type:varchar(32);primaryKey"`
+	Description string    `gorm:"type:varchar(255)"`
+	AppliedAt   time.Time `gorm:"not null"`
+}
+
+func (History) TableName() string {
+	return HistoryTableName
+}
+
+// Status is state of a migration in database
+type Status struct {
+	Version     string
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+}
+
+// New returns a migrator with migrations of paddleflow
+func New(db *gorm.DB) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: Migrations(),
+	}
+}
+
+// NewWithMigrations returns a migrator with migrations given, which must be sorted by version
+func NewWithMigrations(db *gorm.DB, migrations []*Migration) (*Migrator, error) {
+	for i, m := range migrations {
+		if m.Version == "" || m.Up == nil {
+			return nil, fmt.Errorf("migration[%d] has no version or up function", i)
+		}
+		if i > 0 && migrations[i-1].Version >= m.Version {
+			return nil, fmt.Errorf("version of migration %s is not greater than %s", m.Version, migrations[i-1].Version)
+		}
+	}
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies migrations not applied whose version is not greater than target, all of them if target is empty.
+// it returns number of migrations applied.
+func (m *Migrator) Up(target string) (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	m.warnUnknown(applied)
+
+	count := 0
+	for _, migration := range m.migrations {
+		if target != "" && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		log.Infof("applying migration %s: %s", migration.Version, migration.Description)
+		// ddl of mysql is committed implicitly, failed migration should be reverted manually in this case
+		err = m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&History{
+				Version:     migration.Version,
+				Description: migration.Description,
+				AppliedAt:   time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return count, fmt.Errorf("apply migration %s failed: %v", migration.Version, err)
+		}
+		count++
+	}
+	log.Infof("%d migrations are applied", count)
+	return count, nil
+}
+
+// Down reverts the last steps migrations applied, it returns number of migrations reverted.
+func (m *Migrator) Down(steps int) (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	m.warnUnknown(applied)
+
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return count, fmt.Errorf("migration %s can not be reverted", migration.Version)
+		}
+		log.Infof("reverting migration %s: %s", migration.Version, migration.Description)
+		err = m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&History{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return count, fmt.Errorf("revert migration %s failed: %v", migration.Version, err)
+		}
+		count++
+	}
+	log.Infof("%d migrations are reverted", count)
+	return count, nil
+}
+
+// Status returns states of all migrations, sorted by version
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{
+			Version:     migration.Version,
+			Description: migration.Description,
+		}
+		if history, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = history.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// applied returns migrations applied by version, history table is created if not exist
+func (m *Migrator) applied() (map[string]History, error) {
+	if err := m.db.AutoMigrate(&History{}); err != nil {
+		return nil, fmt.Errorf("create table %s failed: %v", HistoryTableName, err)
+	}
+	var histories []History
+	if err := m.db.Order("version").Find(&histories).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[string]History, len(histories))
+	for _, history := range histories {
+		applied[history.Version] = history
+	}
+	return applied, nil
+}
+
+// warnUnknown warns migrations applied by newer server, which are kept as they are
+func (m *Migrator) warnUnknown(applied map[string]History) {
+	known := make(map[string]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+	var unknown []string
+	for version := range applied {
+		if !known[version] {
+			unknown = append(unknown, version)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		log.Warningf("migrations %v are applied by newer version of server", unknown)
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/secret"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{SingularTable: true},
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	assert.Nil(t, err)
+	sqlDB, err := db.DB()
+	assert.Nil(t, err)
+	// each connection of memory database is a different database
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+func TestMigrator(t *testing.T) {
+	db := newTestDB(t)
+	migrator := New(db)
+
+	count, err := migrator.Up("")
+	assert.Nil(t, err)
+	assert.Equal(t, len(Migrations()), count)
+	for _, table := range Tables() {
+		assert.True(t, db.Migrator().HasTable(table))
+	}
+	var user model.User
+	assert.Nil(t, db.Where("name = ?", rootUserName).First(&user).Error)
+	var flavourCount int64
+	assert.Nil(t, db.Model(&model.Flavour{}).Count(&flavourCount).Error)
+	assert.Equal(t, int64(3), flavourCount)
+
+	// applied migrations are skipped
+	count, err = migrator.Up("")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	statuses, err := migrator.Status()
+	assert.Nil(t, err)
+	for _, status := range statuses {
+		assert.True(t, status.Applied)
+		assert.False(t, status.AppliedAt.IsZero())
+	}
+
+	// reverting encryption of secrets changes nothing
+	count, err = migrator.Down(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = migrator.Down(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.False(t, db.Migrator().HasColumn(&model.Job{}, "Deadline"))
+
+	count, err = migrator.Down(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.False(t, db.Migrator().HasTable(&model.Group{}))
+	assert.False(t, db.Migrator().HasTable(&model.GroupMember{}))
+	assert.False(t, db.Migrator().HasColumn(&model.Grant{}, "Role"))
+
+	count, err = migrator.Down(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.False(t, db.Migrator().HasTable(&model.AccessToken{}))
+
+	count, err = migrator.Down(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.Nil(t, db.Model(&model.Flavour{}).Count(&flavourCount).Error)
+	assert.Equal(t, int64(0), flavourCount)
+	statuses, err = migrator.Status()
+	assert.Nil(t, err)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[1].Applied)
+	assert.False(t, statuses[2].Applied)
+	assert.False(t, statuses[3].Applied)
+	assert.False(t, statuses[4].Applied)
+	assert.False(t, statuses[5].Applied)
+
+	count, err = migrator.Down(10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	for _, table := range Tables() {
+		assert.False(t, db.Migrator().HasTable(table))
+	}
+}
+
+func TestMigratorUpToTarget(t *testing.T) {
+	db := newTestDB(t)
+	var applied []string
+	newMigration := func(version string, fail bool) *Migration {
+		return &Migration{
+			Version: version,
+			Up: func(tx *gorm.DB) error {
+				if fail {
+					return fmt.Errorf("mock error")
+				}
+				applied = append(applied, version)
+				return nil
+			},
+		}
+	}
+
+	_, err := NewWithMigrations(db, []*Migration{newMigration("2", false), newMigration("1", false)})
+	assert.NotNil(t, err)
+
+	migrator, err := NewWithMigrations(db, []*Migration{
+		newMigration("1", false),
+		newMigration("2", false),
+		newMigration("3", true),
+	})
+	assert.Nil(t, err)
+	count, err := migrator.Up("2")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{"1", "2"}, applied)
+
+	// failed migration is not recorded
+	count, err = migrator.Up("")
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, count)
+	statuses, err := migrator.Status()
+	assert.Nil(t, err)
+	assert.False(t, statuses[2].Applied)
+
+	// migration without down function can not be reverted
+	_, err = migrator.Down(1)
+	assert.NotNil(t, err)
+}
+
+func TestMigrateGrantRole(t *testing.T) {
+	db := newTestDB(t)
+	migrator := New(db)
+	_, err := migrator.Up("202212010000")
+	assert.Nil(t, err)
+	// role and group are added by migration 202212010001
+	assert.False(t, db.Migrator().HasColumn(&model.Grant{}, "Role"))
+	assert.False(t, db.Migrator().HasColumn(&model.Grant{}, "GroupName"))
+	assert.Nil(t, db.Exec("INSERT INTO `grant` (id, user_name, resource_type, resource_id) VALUES (?, ?, ?, ?)",
+		"grant-1", "user1", "queue", "queue1").Error)
+
+	_, err = migrator.Up("")
+	assert.Nil(t, err)
+	var grant model.Grant
+	assert.Nil(t, db.Where("id = ?", "grant-1").First(&grant).Error)
+	assert.Equal(t, "submitter", grant.Role)
+	assert.Equal(t, "", grant.GroupName)
+}
+
+func TestMigrateJobDeadline(t *testing.T) {
+	db := newTestDB(t)
+	migrator := New(db)
+	_, err := migrator.Up("202212010001")
+	assert.Nil(t, err)
+	activatedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, job := range []struct {
+		id     string
+		status string
+		config string
+	}{
+		{"job-1", "running", `{"maxRunTime":60}`},
+		{"job-2", "running", `{}`},
+		{"job-3", "succeeded", `{"maxRunTime":60}`},
+	} {
+		assert.Nil(t, db.Exec("INSERT INTO `job` (id, user_name, queue_id, type, config, status, activated_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			job.id, "user1", "queue1", "vcjob", job.config, job.status, activatedAt).Error)
+	}
+
+	_, err = migrator.Up("")
+	assert.Nil(t, err)
+	var jobs []model.Job
+	assert.Nil(t, db.Order("id").Find(&jobs).Error)
+	assert.Equal(t, 3, len(jobs))
+	assert.True(t, jobs[0].Deadline.Valid)
+	assert.True(t, activatedAt.Add(time.Minute).Equal(jobs[0].Deadline.Time))
+	assert.False(t, jobs[1].Deadline.Valid)
+	assert.False(t, jobs[2].Deadline.Valid)
+}
+
+func TestMigrateEncryptSecrets(t *testing.T) {
+	db := newTestDB(t)
+	migrator := New(db)
+	_, err := migrator.Up("202301010000")
+	assert.Nil(t, err)
+	assert.Nil(t, db.Exec("INSERT INTO `cluster_info` (id, name, credential) VALUES (?, ?, ?)",
+		"cluster-1", "cluster1", "kubeconfig").Error)
+
+	keyFile := "./mock-secret-key"
+	defer os.Remove(keyFile)
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("k"), 32))
+	assert.Nil(t, os.WriteFile(keyFile, []byte(key), 0600))
+	assert.Nil(t, secret.Init(secret.Config{Provider: secret.LocalProvider, KeyFile: keyFile}))
+	defer secret.Init(secret.Config{})
+
+	_, err = migrator.Up("")
+	assert.Nil(t, err)
+	var raws []string
+	assert.Nil(t, db.Table("cluster_info").Where("id = ?", "cluster-1").Pluck("credential", &raws).Error)
+	assert.True(t, secret.IsEncrypted(raws[0]))
+	var cluster model.ClusterInfo
+	assert.Nil(t, db.Where("id = ?", "cluster-1").First(&cluster).Error)
+	assert.Equal(t, "kubeconfig", cluster.Credential)
+}
+
+// sqliteStatements converts paddleflow.sql of former versions for mysql to sqlite statements
+func sqliteStatements(t *testing.T, file string) []string {
+	data, err := os.ReadFile(file)
+	assert.Nil(t, err)
+	var (
+		statements []string
+		table      string
+		columns    []string
+		indexes    []string
+	)
+	comment := regexp.MustCompile(` COMMENT '[^']*'`)
+	index := regexp.MustCompile("^(UNIQUE )?(?:KEY|INDEX)\\s*`?(\\w*)`?\\s*(\\(.*\\))$")
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(strings.TrimSpace(comment.ReplaceAllString(line, "")), ",")
+		switch {
+		case line == "" || strings.HasPrefix(line, "--") || strings.HasPrefix(line, "USE ") ||
+			strings.HasPrefix(line, "CREATE DATABASE") || strings.HasPrefix(line, "TRUNCATE"):
+		case strings.HasPrefix(line, "CREATE TABLE"):
+			table = strings.Trim(strings.Fields(line)[5], "`")
+			columns, indexes = nil, nil
+		case table == "":
+			statements = append(statements, line)
+		case strings.HasPrefix(line, ")"):
+			statements = append(statements, fmt.Sprintf("CREATE TABLE `%s` (%s)", table, strings.Join(columns, ", ")))
+			statements = append(statements, indexes...)
+			table = ""
+		case strings.HasPrefix(line, "PRIMARY KEY"):
+		case index.MatchString(line):
+			matches := index.FindStringSubmatch(line)
+			name := matches[2]
+			if name == "" {
+				name = strings.Trim(strings.Split(strings.Trim(matches[3], "()"), ",")[0], "`")
+			}
+			indexes = append(indexes, fmt.Sprintf("CREATE %sINDEX `%s_%s` ON `%s` %s", matches[1], table, name, table, matches[3]))
+		default:
+			if strings.HasPrefix(line, "`pk`") {
+				line = "`pk` integer PRIMARY KEY AUTOINCREMENT"
+			}
+			line = strings.ReplaceAll(line, " ON UPDATE CURRENT_TIMESTAMP(3)", "")
+			line = strings.ReplaceAll(line, "CURRENT_TIMESTAMP(3)", "CURRENT_TIMESTAMP")
+			columns = append(columns, line)
+		}
+	}
+	return statements
+}
+
+func TestMigrateDatabaseOfPaddleflowSQL(t *testing.T) {
+	db := newTestDB(t)
+	for _, statement := range sqliteStatements(t, "testcase/paddleflow.sql") {
+		assert.Nil(t, db.Exec(statement).Error, statement)
+	}
+	assert.Nil(t, db.Exec("INSERT INTO job (id, user_name, queue_id, type, config, status) VALUES (?, ?, ?, ?, ?, ?)",
+		"job-1", "user1", "queue-1", "vcjob", "{}", "pending").Error)
+
+	count, err := New(db).Up("")
+	assert.Nil(t, err)
+	assert.Equal(t, len(Migrations()), count)
+
+	// columns created by paddleflow.sql are not changed
+	columnTypes, err := db.Migrator().ColumnTypes(&model.Job{})
+	assert.Nil(t, err)
+	for _, columnType := range columnTypes {
+		switch columnType.Name() {
+		case "id", "user_name", "queue_id":
+			assert.Equal(t, "varchar", strings.ToLower(columnType.DatabaseTypeName()), columnType.Name())
+			length, _ := columnType.Length()
+			assert.Equal(t, int64(60), length, columnType.Name())
+		}
+	}
+	assert.True(t, db.Migrator().HasIndex(&model.Job{}, "status_queue_deleted"))
+	var job model.Job
+	assert.Nil(t, db.Where("id = ?", "job-1").First(&job).Error)
+	assert.Equal(t, "queue-1", job.QueueID)
+
+	// initial data of paddleflow.sql is kept
+	var userCount, flavourCount int64
+	assert.Nil(t, db.Model(&model.User{}).Where("name = ?", rootUserName).Count(&userCount).Error)
+	assert.Equal(t, int64(1), userCount)
+	assert.Nil(t, db.Model(&model.Flavour{}).Count(&flavourCount).Error)
+	assert.Equal(t, int64(3), flavourCount)
+	for _, table := range Tables() {
+		assert.True(t, db.Migrator().HasTable(table))
+	}
+}
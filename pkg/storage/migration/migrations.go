@@ -0,0 +1,296 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"encoding/json"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/apiserver/models"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/secret"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/storage"
+)
+
+const (
+	rootUserName = "root"
+	// initial password of root is 'paddleflow'
+	rootUserPassword = "$2a$10$1qdSQN5wMl3FtXoxw7mKpuxBqIuP0eYXTBM9CBn5H4KubM/g5Hrb6%"
+
+	mysqlTableOptions = "ENGINE=InnoDB DEFAULT CHARACTER SET utf8 COLLATE utf8_bin"
+)
+
+// Migrations returns all migrations of paddleflow, new migration should be appended with a greater version,
+// and migrations released should never be changed.
+func Migrations() []*Migration {
+	return []*Migration{
+		{
+			Version:     "202211010000",
+			Description: "create tables",
+			Up:          createTables,
+			Down:        dropTables,
+		},
+		{
+			Version:     "202211010001",
+			Description: "init root user and default flavours",
+			Up:          initData,
+			Down:        deleteInitData,
+		},
+		{
+			Version:     "202212010000",
+			Description: "create table access_token",
+			Up: func(tx *gorm.DB) error {
+				return autoMigrate(tx, &accessToken{})
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&accessToken{})
+			},
+		},
+		{
+			Version:     "202212010001",
+			Description: "add role and group to grant, create tables user_group and user_group_member",
+			Up:          addGrantRoleAndGroup,
+			Down:        dropGrantRoleAndGroup,
+		},
+		{
+			Version:     "202301010000",
+			Description: "add deadline to job",
+			Up:          addJobDeadline,
+			Down:        dropJobDeadline,
+		},
+		{
+			Version:     "202301010001",
+			Description: "encrypt secrets stored in plaintext",
+			Up:          encryptSecrets,
+			// secrets are kept encrypted, reverting allows encrypting again after secret key provider is configured
+			Down: func(tx *gorm.DB) error {
+				return nil
+			},
+		},
+	}
+}
+
+// Tables returns models of all tables, in order of creation. Migrations never use the models,
+// tables of a migration are defined in its schema file.
+func Tables() []interface{} {
+	return []interface{}{
+		&model.Pipeline{},
+		&model.PipelineVersion{},
+		&models.Schedule{},
+		&models.RunCache{},
+		&model.ArtifactEvent{},
+		&model.User{},
+		&models.Run{},
+		&models.RunJob{},
+		&models.RunDag{},
+		&model.Queue{},
+		&model.Flavour{},
+		&model.Grant{},
+		&model.Job{},
+		&model.JobTask{},
+		&model.JobLabel{},
+		&model.ClusterInfo{},
+		&model.Image{},
+		&model.FileSystem{},
+		&model.Link{},
+		&model.FSCacheConfig{},
+		&model.FSCache{},
+		&model.AccessToken{},
+		&model.Group{},
+		&model.GroupMember{},
+	}
+}
+
+// initialTables returns tables created by the first migration
+func initialTables() []interface{} {
+	return []interface{}{
+		&initialPipeline{},
+		&initialPipelineVersion{},
+		&initialSchedule{},
+		&initialRunCache{},
+		&initialArtifactEvent{},
+		&initialUser{},
+		&initialRun{},
+		&initialRunJob{},
+		&initialRunDag{},
+		&initialQueue{},
+		&initialFlavour{},
+		&initialGrant{},
+		&initialJob{},
+		&initialJobTask{},
+		&initialJobLabel{},
+		&initialClusterInfo{},
+		&initialImage{},
+		&initialFileSystem{},
+		&initialLink{},
+		&initialFSCacheConfig{},
+		&initialFSCache{},
+	}
+}
+
+// createTables creates tables, and adds columns and indexes missing for tables created by paddleflow.sql of former versions
+func createTables(tx *gorm.DB) error {
+	return autoMigrate(tx, initialTables()...)
+}
+
+func dropTables(tx *gorm.DB) error {
+	tables := initialTables()
+	for i, j := 0, len(tables)-1; i < j; i, j = i+1, j-1 {
+		tables[i], tables[j] = tables[j], tables[i]
+	}
+	return tx.Migrator().DropTable(tables...)
+}
+
+func autoMigrate(tx *gorm.DB, tables ...interface{}) error {
+	if tx.Dialector.Name() == "mysql" {
+		tx = tx.Set("gorm:table_options", mysqlTableOptions)
+	}
+	return tx.AutoMigrate(tables...)
+}
+
+// addGrantRoleAndGroup adds columns role and group_name to grant, role of existing grants is submitter by default
+func addGrantRoleAndGroup(tx *gorm.DB) error {
+	for _, column := range []string{"GroupName", "Role"} {
+		if !tx.Migrator().HasColumn(&grantRoleAndGroup{}, column) {
+			if err := tx.Migrator().AddColumn(&grantRoleAndGroup{}, column); err != nil {
+				return err
+			}
+		}
+	}
+	return autoMigrate(tx, &group{}, &groupMember{})
+}
+
+func dropGrantRoleAndGroup(tx *gorm.DB) error {
+	if err := tx.Migrator().DropTable(&groupMember{}, &group{}); err != nil {
+		return err
+	}
+	for _, column := range []string{"Role", "GroupName"} {
+		if tx.Migrator().HasColumn(&grantRoleAndGroup{}, column) {
+			if err := tx.Migrator().DropColumn(&grantRoleAndGroup{}, column); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addJobDeadline adds column deadline to job, which is used to find jobs running out of max run time
+func addJobDeadline(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&jobDeadline{}, "Deadline") {
+		return nil
+	}
+	if err := tx.Migrator().AddColumn(&jobDeadline{}, "Deadline"); err != nil {
+		return err
+	}
+	if err := tx.Migrator().CreateIndex(&jobDeadline{}, "Deadline"); err != nil {
+		return err
+	}
+	// fill deadline of jobs already running
+	var jobs []runningJob
+	if err := tx.Where("status = ?", "running").Where("activated_at IS NOT NULL").Where("deleted_at = ''").
+		Find(&jobs).Error; err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		var conf maxRunTimeConf
+		if err := json.Unmarshal([]byte(job.Config), &conf); err != nil || conf.MaxRunTime <= 0 {
+			continue
+		}
+		if err := tx.Model(&runningJob{}).Where("pk = ?", job.Pk).
+			Update("deadline", conf.deadline(job.ActivatedAt.Time)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropJobDeadline(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&jobDeadline{}, "Deadline") {
+		return nil
+	}
+	if tx.Migrator().HasIndex(&jobDeadline{}, "Deadline") {
+		if err := tx.Migrator().DropIndex(&jobDeadline{}, "Deadline"); err != nil {
+			return err
+		}
+	}
+	return tx.Migrator().DropColumn(&jobDeadline{}, "Deadline")
+}
+
+// encryptSecrets encrypts cluster credentials and secret properties of file systems and links stored in plaintext,
+// secret key provider must be initialized before migration, and secrets are kept in plaintext if it is not configured
+func encryptSecrets(tx *gorm.DB) error {
+	if !secret.Enabled() {
+		log.Warningf("secret key provider is not configured, skip encrypting secrets")
+		return nil
+	}
+	return storage.EncryptSecrets(tx)
+}
+
+func defaultFlavours() []initialFlavour {
+	return []initialFlavour{
+		{
+			Name: "flavour1",
+			CPU:  "1",
+			Mem:  "1Gi",
+		},
+		{
+			Name:            "flavour2",
+			CPU:             "4",
+			Mem:             "8Gi",
+			ScalarResources: `{"nvidia.com/gpu": "1"}`,
+		},
+		{
+			Name:            "flavour3",
+			CPU:             "4",
+			Mem:             "8Gi",
+			ScalarResources: `{"nvidia.com/gpu": "2"}`,
+		},
+	}
+}
+
+// initData creates root user and default flavours, which are kept if exist
+func initData(tx *gorm.DB) error {
+	rootUser := initialUser{
+		Name:     rootUserName,
+		Password: rootUserPassword,
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&rootUser).Error; err != nil {
+		return err
+	}
+	flavours := defaultFlavours()
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&flavours).Error
+}
+
+func deleteInitData(tx *gorm.DB) error {
+	var names []string
+	for _, flavour := range defaultFlavours() {
+		names = append(names, flavour.Name)
+	}
+	if err := tx.Unscoped().Where("name IN ?", names).Delete(&initialFlavour{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("name = ?", rootUserName).Delete(&initialUser{}).Error
+}
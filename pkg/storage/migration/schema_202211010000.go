@@ -0,0 +1,479 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Tables created by migration 202211010000. The schema is frozen here instead of using models of package model,
+// so that changes of models never change what this migration does. Column types are the same as paddleflow.sql
+// of former versions, otherwise migrating a database created by paddleflow.sql would alter its columns,
+// e.g. a string column without type is longtext in mysql, which can not be used in index.
+
+// mediumText is mediumtext in mysql and sqlite, and text in postgres which has no mediumtext
+type mediumText string
+
+func (mediumText) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "postgres" {
+		return "text"
+	}
+	return "mediumtext"
+}
+
+type initialPipeline struct {
+	Pk        int64  `gorm:"primaryKey;autoIncrement"`
+	ID        string `gorm:"type:varchar(60);not null;index"`
+	Name      string `gorm:"type:varchar(128);not null;index:idx_fs_name"`
+	Desc      string `gorm:"type:varchar(256);not null"`
+	UserName  string `gorm:"type:varchar(60);not null;index:idx_fs_name"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+func (initialPipeline) TableName() string {
+	return "pipeline"
+}
+
+type initialPipelineVersion struct {
+	Pk           int64  `gorm:"primaryKey;autoIncrement"`
+	ID           string `gorm:"type:varchar(60);not null"`
+	PipelineID   string `gorm:"type:varchar(60);not null"`
+	FsID         string `gorm:"type:varchar(200);not null"`
+	FsName       string `gorm:"type:varchar(60);not null"`
+	YamlPath     string `gorm:"type:text;not null"`
+	PipelineYaml string `gorm:"type:text;not null"`
+	PipelineMd5  string `gorm:"type:varchar(32);not null"`
+	UserName     string `gorm:"type:varchar(60);not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt
+}
+
+func (initialPipelineVersion) TableName() string {
+	return "pipeline_version"
+}
+
+type initialSchedule struct {
+	Pk                int64  `gorm:"primaryKey;autoIncrement"`
+	ID                string `gorm:"type:varchar(60);not null"`
+	Name              string `gorm:"type:varchar(60);not null"`
+	Desc              string `gorm:"type:varchar(256);not null"`
+	PipelineID        string `gorm:"type:varchar(60);not null"`
+	PipelineVersionID string `gorm:"type:varchar(60);not null"`
+	UserName          string `gorm:"type:varchar(60);not null"`
+	Crontab           string `gorm:"type:varchar(60);not null"`
+	FsConfig          string `gorm:"type:varchar(1024);not null"`
+	Options           string `gorm:"type:text"`
+	Message           string `gorm:"type:text"`
+	Status            string `gorm:"type:varchar(32)"`
+	StartAt           sql.NullTime
+	EndAt             sql.NullTime
+	NextRunAt         sql.NullTime
+	BackfillNextAt    sql.NullTime
+	BackfillEndAt     sql.NullTime
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt
+}
+
+func (initialSchedule) TableName() string {
+	return "schedule"
+}
+
+type initialRunCache struct {
+	Pk          int64  `gorm:"primaryKey;autoIncrement"`
+	ID          string `gorm:"type:varchar(60);not null;index"`
+	JobID       string `gorm:"type:varchar(60);not null"`
+	FirstFp     string `gorm:"type:varchar(256)"`
+	SecondFp    string `gorm:"type:varchar(256)"`
+	Source      string `gorm:"type:varchar(256);not null"`
+	FsID        string `gorm:"type:varchar(200);not null"`
+	RunID       string `gorm:"type:varchar(60);not null"`
+	FsName      string `gorm:"type:varchar(60);not null"`
+	UserName    string `gorm:"type:varchar(60);not null"`
+	ExpiredTime string `gorm:"type:varchar(64);not null;default:'-1'"`
+	Strategy    string `gorm:"type:varchar(16);not null;default:'conservative'"`
+	Custom      string `gorm:"type:text"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+func (initialRunCache) TableName() string {
+	return "run_cache"
+}
+
+type initialArtifactEvent struct {
+	Pk           int64  `gorm:"primaryKey;autoIncrement"`
+	Md5          string `gorm:"type:varchar(32);not null"`
+	RunID        string `gorm:"type:varchar(60);not null"`
+	FsID         string `gorm:"type:varchar(200);not null"`
+	UserName     string `gorm:"type:varchar(60);not null"`
+	FsName       string `gorm:"type:varchar(60);not null"`
+	ArtifactPath string `gorm:"type:varchar(256);not null"`
+	Step         string `gorm:"type:varchar(256);not null"`
+	JobID        string `gorm:"type:varchar(60);not null"`
+	ArtifactName string `gorm:"type:varchar(32);not null"`
+	Type         string `gorm:"type:varchar(16);not null"`
+	Meta         string `gorm:"type:text"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (initialArtifactEvent) TableName() string {
+	return "artifact_event"
+}
+
+type initialUser struct {
+	Pk        int64  `gorm:"primaryKey;autoIncrement"`
+	Name      string `gorm:"type:varchar(60);not null;uniqueIndex"`
+	Password  string `gorm:"type:varchar(256);not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+func (initialUser) TableName() string {
+	return "user"
+}
+
+type initialRun struct {
+	Pk                 int64  `gorm:"primaryKey;autoIncrement"`
+	ID                 string `gorm:"type:varchar(60);not null"`
+	Name               string `gorm:"type:varchar(128);not null"`
+	Source             string `gorm:"type:varchar(256);not null"`
+	UserName           string `gorm:"type:varchar(60);not null"`
+	FsID               string `gorm:"type:varchar(200);not null"`
+	FsName             string `gorm:"type:varchar(60);not null"`
+	Description        string `gorm:"type:text;not null"`
+	ParametersJson     string `gorm:"type:text;not null"`
+	RunYaml            string `gorm:"type:text;not null"`
+	DockerEnv          string `gorm:"type:varchar(128);not null"`
+	Disabled           string `gorm:"type:text;not null"`
+	FailureOptionsJson string `gorm:"type:text;not null"`
+	ScheduleID         string `gorm:"type:varchar(60);not null"`
+	Message            string `gorm:"type:text;not null"`
+	Status             string `gorm:"type:varchar(32)"`
+	RunOptionsJson     string `gorm:"type:text;not null"`
+	RunCachedIDs       string `gorm:"column:run_cached_ids;type:text;not null"`
+	ScheduledAt        sql.NullTime
+	CreatedAt          time.Time
+	ActivatedAt        sql.NullTime
+	UpdatedAt          time.Time
+	DeletedAt          gorm.DeletedAt
+}
+
+func (initialRun) TableName() string {
+	return "run"
+}
+
+type initialRunJob struct {
+	Pk             int64  `gorm:"primaryKey;autoIncrement"`
+	ID             string `gorm:"type:varchar(60);not null"`
+	RunID          string `gorm:"type:varchar(60);not null"`
+	ParentDagID    string `gorm:"type:varchar(60);not null"`
+	Name           string `gorm:"type:varchar(60);not null"`
+	StepName       string `gorm:"type:varchar(60);not null"`
+	Command        string `gorm:"type:text"`
+	ParametersJson string `gorm:"type:text"`
+	ArtifactsJson  string `gorm:"type:text"`
+	EnvJson        string `gorm:"type:text"`
+	DockerEnv      string `gorm:"type:varchar(128)"`
+	LoopSeq        int    `gorm:"type:int;not null"`
+	Status         string `gorm:"type:varchar(32)"`
+	Message        string `gorm:"type:text"`
+	CacheJson      string `gorm:"type:text"`
+	CacheRunID     string `gorm:"type:varchar(60)"`
+	CacheJobID     string `gorm:"type:varchar(60)"`
+	ExtraFSJson    string `gorm:"column:extra_fs_json;type:text"`
+	AttemptsJson   string `gorm:"type:text"`
+	CreatedAt      time.Time
+	ActivatedAt    sql.NullTime
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (initialRunJob) TableName() string {
+	return "run_job"
+}
+
+type initialRunDag struct {
+	Pk             int64  `gorm:"primaryKey;autoIncrement"`
+	ID             string `gorm:"type:varchar(60);not null"`
+	RunID          string `gorm:"type:varchar(60);not null"`
+	ParentDagID    string `gorm:"type:varchar(60);not null"`
+	Name           string `gorm:"type:varchar(60);not null"`
+	DagName        string `gorm:"type:varchar(60);not null"`
+	ParametersJson string `gorm:"type:text"`
+	ArtifactsJson  string `gorm:"type:text"`
+	LoopSeq        int    `gorm:"type:int;not null"`
+	Status         string `gorm:"type:varchar(32)"`
+	Message        string `gorm:"type:text"`
+	CreatedAt      time.Time
+	ActivatedAt    sql.NullTime
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (initialRunDag) TableName() string {
+	return "run_dag"
+}
+
+type initialQueue struct {
+	Pk               int64  `gorm:"primaryKey;autoIncrement"`
+	ID               string `gorm:"type:varchar(60);not null"`
+	Name             string `gorm:"type:varchar(255);not null;uniqueIndex"`
+	Namespace        string `gorm:"type:varchar(64);not null"`
+	ClusterID        string `gorm:"column:cluster_id;type:varchar(60);not null;default:''"`
+	QuotaType        string `gorm:"type:varchar(255)"`
+	MinResources     string `gorm:"type:text"`
+	MaxResources     string `gorm:"type:text"`
+	Location         string `gorm:"type:text"`
+	Status           string `gorm:"type:varchar(20)"`
+	SchedulingPolicy string `gorm:"type:varchar(2048)"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        gorm.DeletedAt `gorm:"index"`
+}
+
+func (initialQueue) TableName() string {
+	return "queue"
+}
+
+type initialFlavour struct {
+	Pk              int64  `gorm:"primaryKey;autoIncrement"`
+	ID              string `gorm:"type:varchar(60);not null"`
+	Name            string `gorm:"type:varchar(60);not null;uniqueIndex"`
+	ClusterID       string `gorm:"column:cluster_id;type:varchar(60);default:''"`
+	CPU             string `gorm:"column:cpu;type:varchar(20);not null"`
+	Mem             string `gorm:"type:varchar(20);not null"`
+	ScalarResources string `gorm:"type:varchar(255)"`
+	UserName        string `gorm:"type:varchar(60)"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
+}
+
+func (initialFlavour) TableName() string {
+	return "flavour"
+}
+
+type initialGrant struct {
+	Pk           int64  `gorm:"primaryKey;autoIncrement"`
+	ID           string `gorm:"type:varchar(60);not null;uniqueIndex"`
+	UserName     string `gorm:"type:varchar(128);not null"`
+	ResourceType string `gorm:"type:varchar(36);not null"`
+	ResourceID   string `gorm:"type:varchar(36);not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (initialGrant) TableName() string {
+	return "grant"
+}
+
+type initialJob struct {
+	Pk                int64      `gorm:"primaryKey;autoIncrement"`
+	ID                string     `gorm:"type:varchar(60);not null;index:idx_id,unique"`
+	Name              string     `gorm:"type:varchar(512);default:''"`
+	UserName          string     `gorm:"type:varchar(60);not null"`
+	QueueID           string     `gorm:"type:varchar(60);not null;index:status_queue_deleted,priority:1"`
+	Type              string     `gorm:"type:varchar(20);not null"`
+	Config            mediumText `gorm:"not null"`
+	RuntimeInfo       mediumText
+	RuntimeStatus     mediumText
+	Status            string `gorm:"type:varchar(32);not null;index:status_queue_deleted,priority:2"`
+	Message           string `gorm:"type:text"`
+	Resource          string `gorm:"type:text"`
+	Framework         string `gorm:"type:varchar(30)"`
+	Members           mediumText
+	ExtensionTemplate mediumText
+	ParentJob         string `gorm:"type:varchar(60)"`
+	CreatedAt         time.Time
+	ActivatedAt       sql.NullTime
+	UpdatedAt         time.Time
+	DeletedAt         string `gorm:"type:varchar(64);default:'';index:idx_id;index:status_queue_deleted,priority:3"`
+}
+
+func (initialJob) TableName() string {
+	return "job"
+}
+
+type initialJobTask struct {
+	Pk               int64  `gorm:"primaryKey;autoIncrement"`
+	ID               string `gorm:"type:varchar(64);not null;uniqueIndex"`
+	JobID            string `gorm:"type:varchar(60);not null"`
+	Namespace        string `gorm:"type:varchar(64);not null"`
+	Name             string `gorm:"type:varchar(512);not null"`
+	NodeName         string `gorm:"type:varchar(512)"`
+	MemberRole       string `gorm:"type:varchar(64)"`
+	Status           string `gorm:"type:varchar(32)"`
+	Message          string `gorm:"type:text"`
+	LogURL           string `gorm:"column:log_url;type:varchar(4096)"`
+	ExtRuntimeStatus mediumText
+	CreatedAt        time.Time
+	StartedAt        sql.NullTime
+	UpdatedAt        time.Time
+	DeletedAt        sql.NullTime
+}
+
+func (initialJobTask) TableName() string {
+	return "job_task"
+}
+
+type initialJobLabel struct {
+	Pk        int64  `gorm:"primaryKey;autoIncrement"`
+	ID        string `gorm:"type:varchar(36);not null;uniqueIndex"`
+	Label     string `gorm:"type:varchar(255);not null"`
+	JobID     string `gorm:"type:varchar(60);not null"`
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (initialJobLabel) TableName() string {
+	return "job_label"
+}
+
+type initialClusterInfo struct {
+	Pk            int64  `gorm:"primaryKey;autoIncrement"`
+	ID            string `gorm:"type:varchar(60);not null"`
+	Name          string `gorm:"type:varchar(255);not null"`
+	Description   string `gorm:"type:varchar(2048);not null;default:''"`
+	Endpoint      string `gorm:"type:varchar(255);not null;default:''"`
+	Source        string `gorm:"type:varchar(64);not null;default:'OnPremise'"`
+	ClusterType   string `gorm:"type:varchar(32);not null;default:''"`
+	Version       string `gorm:"type:varchar(32)"`
+	Status        string `gorm:"type:varchar(32);not null;default:'online'"`
+	Credential    string `gorm:"type:text"`
+	Setting       string `gorm:"type:text"`
+	NamespaceList string `gorm:"type:text"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     string `gorm:"type:char(32);not null;default:''"`
+}
+
+func (initialClusterInfo) TableName() string {
+	return "cluster_info"
+}
+
+type initialImage struct {
+	Pk        int64  `gorm:"primaryKey;autoIncrement"`
+	ID        string `gorm:"type:varchar(128);not null;uniqueIndex"`
+	ImageID   string `gorm:"type:varchar(64)"`
+	FsID      string `gorm:"type:varchar(200);not null"`
+	Source    string `gorm:"type:varchar(256);not null"`
+	Md5       string `gorm:"type:varchar(60)"`
+	Url       string `gorm:"type:varchar(256)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (initialImage) TableName() string {
+	return "image"
+}
+
+type initialFileSystem struct {
+	Pk                      int64  `gorm:"primaryKey;autoIncrement"`
+	ID                      string `gorm:"type:varchar(200);not null"`
+	Name                    string `gorm:"type:varchar(200);not null"`
+	ServerAddress           string `gorm:"type:varchar(1024);not null"`
+	Type                    string `gorm:"type:varchar(50);not null"`
+	SubPath                 string `gorm:"column:subpath;type:varchar(1024);not null"`
+	UserName                string `gorm:"type:varchar(256);not null"`
+	IndependentMountProcess bool   `gorm:"not null;default:false"`
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+	Properties              string `gorm:"type:text"`
+}
+
+func (initialFileSystem) TableName() string {
+	return "filesystem"
+}
+
+type initialLink struct {
+	Pk            int64  `gorm:"primaryKey;autoIncrement"`
+	ID            string `gorm:"type:varchar(36);not null"`
+	FsID          string `gorm:"type:varchar(200);not null"`
+	FsPath        string `gorm:"type:varchar(1024);not null"`
+	ServerAddress string `gorm:"type:varchar(1024);not null"`
+	Type          string `gorm:"type:varchar(50);not null"`
+	SubPath       string `gorm:"column:subpath;type:varchar(1024);not null"`
+	UserName      string `gorm:"type:varchar(256)"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Properties    string `gorm:"type:text"`
+}
+
+func (initialLink) TableName() string {
+	return "link"
+}
+
+type initialFSCacheConfig struct {
+	PK                  int64  `gorm:"primaryKey;autoIncrement"`
+	FsID                string `gorm:"type:varchar(200);not null"`
+	CacheDir            string `gorm:"type:varchar(4096);not null"`
+	Quota               int64  `gorm:"not null"`
+	BlockSize           int    `gorm:"type:int;not null"`
+	MetaDriver          string `gorm:"type:varchar(32);not null"`
+	Debug               bool   `gorm:"not null"`
+	CleanCache          bool   `gorm:"not null;default:false"`
+	Resource            string `gorm:"type:text"`
+	ExtraConfig         string `gorm:"type:text"`
+	NodeAffinity        string `gorm:"type:text"`
+	NodeTainttoleration string `gorm:"column:node_tainttoleration;type:text"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeletedAt           gorm.DeletedAt
+}
+
+func (initialFSCacheConfig) TableName() string {
+	return "fs_cache_config"
+}
+
+type initialFSCache struct {
+	PK           int64  `gorm:"primaryKey;autoIncrement"`
+	CacheID      string `gorm:"type:varchar(36);not null"`
+	CacheHashID  string `gorm:"type:varchar(36)"`
+	FsID         string `gorm:"type:varchar(200);not null"`
+	ClusterID    string `gorm:"column:cluster_id;type:varchar(60);default:''"`
+	CacheDir     string `gorm:"type:varchar(4096);not null"`
+	NodeName     string `gorm:"column:nodename;type:varchar(255);not null"`
+	UsedSize     int64  `gorm:"column:usedsize;not null"`
+	WarmupID     string `gorm:"type:varchar(36);default:''"`
+	WarmupStatus string `gorm:"type:varchar(32);default:''"`
+	WarmupTotal  int64  `gorm:"default:0"`
+	WarmupDone   int64  `gorm:"default:0"`
+	WarmupFailed int64  `gorm:"default:0"`
+	WarmupBytes  int64  `gorm:"default:0"`
+	WarmupMsg    string `gorm:"type:varchar(1024);default:''"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt
+}
+
+func (initialFSCache) TableName() string {
+	return "fs_cache"
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tables and columns created by migrations 202212010000 and 202212010001, frozen as schemas of 202211010000.
+
+type accessToken struct {
+	Pk        int64  `gorm:"primaryKey;autoIncrement"`
+	ID        string `gorm:"type:varchar(60);uniqueIndex"`
+	Name      string `gorm:"type:varchar(128)"`
+	UserName  string `gorm:"type:varchar(60);index"`
+	Scopes    string `gorm:"type:text"`
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (accessToken) TableName() string {
+	return "access_token"
+}
+
+// grantRoleAndGroup holds columns added to table grant
+type grantRoleAndGroup struct {
+	GroupName string `gorm:"type:varchar(60);not null;default:''"`
+	Role      string `gorm:"type:varchar(32);not null;default:'submitter'"`
+}
+
+func (grantRoleAndGroup) TableName() string {
+	return "grant"
+}
+
+type group struct {
+	Pk          int64  `gorm:"primaryKey;autoIncrement"`
+	Name        string `gorm:"type:varchar(60);uniqueIndex"`
+	Description string `gorm:"type:varchar(255)"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (group) TableName() string {
+	return "user_group"
+}
+
+type groupMember struct {
+	Pk        int64  `gorm:"primaryKey;autoIncrement"`
+	GroupName string `gorm:"type:varchar(60);uniqueIndex:idx_group_user"`
+	UserName  string `gorm:"type:varchar(60);uniqueIndex:idx_group_user;index"`
+	CreatedAt time.Time
+}
+
+func (groupMember) TableName() string {
+	return "user_group_member"
+}
@@ -0,0 +1,53 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Columns created by migration 202301010000, frozen as schemas of 202301010000.
+
+// jobDeadline holds column added to table job
+type jobDeadline struct {
+	Deadline sql.NullTime `gorm:"index"`
+}
+
+func (jobDeadline) TableName() string {
+	return "job"
+}
+
+// runningJob holds columns of running job to fill deadline
+type runningJob struct {
+	Pk          int64
+	Config      string
+	ActivatedAt sql.NullTime
+}
+
+func (runningJob) TableName() string {
+	return "job"
+}
+
+// maxRunTimeConf is the max run time in config of job, in seconds
+type maxRunTimeConf struct {
+	MaxRunTime int64 `json:"maxRunTime"`
+}
+
+func (c maxRunTimeConf) deadline(activatedAt time.Time) time.Time {
+	return activatedAt.Add(time.Duration(c.MaxRunTime) * time.Second)
+}
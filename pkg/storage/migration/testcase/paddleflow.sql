@@ -197,6 +197,7 @@ CREATE TABLE IF NOT EXISTS `run_job` (
     `cache_run_id` varchar(60),
     `cache_job_id` varchar(60),
     `extra_fs_json` text,
+    `attempts_json` text,
     `created_at` datetime(3) DEFAULT NULL,
     `activated_at` datetime(3) DEFAULT NULL,
     `updated_at` datetime(3) DEFAULT NULL,
@@ -290,6 +291,8 @@ CREATE TABLE IF NOT EXISTS `schedule` (
     `start_at` datetime(3) DEFAULT NULL,
     `end_at` datetime(3) DEFAULT NULL,
     `next_run_at` datetime(3) DEFAULT NULL,
+    `backfill_next_at` datetime(3) DEFAULT NULL,
+    `backfill_end_at` datetime(3) DEFAULT NULL,
     `created_at` datetime(3) DEFAULT NULL,
     `updated_at` datetime(3) DEFAULT NULL,
     `deleted_at` datetime(3) DEFAULT NULL,
@@ -403,6 +406,13 @@ CREATE TABLE IF NOT EXISTS `fs_cache` (
     `cache_dir` varchar(4096) NOT NULL COMMENT 'cache dir, e.g. /var/pfs_cache',
     `nodename` varchar(255) NOT NULL COMMENT 'node name',
     `usedsize` bigint(20) NOT NULL COMMENT 'cache used size on cache dir',
+    `warmup_id` varchar(36) DEFAULT '' COMMENT 'latest warmup task id',
+    `warmup_status` varchar(32) DEFAULT '' COMMENT 'warmup status, e.g. pending, running, succeeded, failed',
+    `warmup_total` bigint(20) DEFAULT 0 COMMENT 'number of paths to warm up',
+    `warmup_done` bigint(20) DEFAULT 0 COMMENT 'number of paths warmed up',
+    `warmup_failed` bigint(20) DEFAULT 0 COMMENT 'number of paths failed to warm up',
+    `warmup_bytes` bigint(20) DEFAULT 0 COMMENT 'bytes cached by warmup',
+    `warmup_msg` varchar(1024) DEFAULT '' COMMENT 'warmup error message',
     `created_at` datetime NOT NULL COMMENT 'create time',
     `updated_at` datetime NOT NULL COMMENT 'update time',
     `deleted_at` datetime(3) DEFAULT NULL  COMMENT 'delete time',
@@ -33,7 +33,7 @@ import (
 )
 
 const (
-	queueJoinCluster  = "join `cluster_info` on `cluster_info`.id = queue.cluster_id"
+	queueJoinCluster  = "join cluster_info on cluster_info.id = queue.cluster_id"
 	queueSelectColumn = `queue.pk as pk, queue.id as id, queue.name as name, queue.namespace as namespace, queue.cluster_id as cluster_id,
 cluster_info.name as cluster_name, queue.quota_type as quota_type, queue.max_resources as max_resources, queue.min_resources as min_resources, queue.location as location,
 queue.scheduling_policy as scheduling_policy, queue.status as status, queue.created_at as created_at, queue.updated_at as updated_at, queue.deleted_at as deleted_at`
@@ -192,8 +192,11 @@ func (qs *QueueStore) ListQueue(pk int64, maxKeys int, queueName string, userNam
 	var tx *gorm.DB
 	tx = qs.db.Table("queue").Select(queueSelectColumn).Joins(queueJoinCluster).Where("queue.pk > ?", pk)
 	if !common.IsRootUser(userName) {
-		tx = tx.Joins("join `grant` on `grant`.resource_id = queue.name").Where(
-			"`grant`.user_name = ?", userName)
+		// queues granted to the user or groups of the user
+		groups := qs.db.Model(&model.GroupMember{}).Select("group_name").Where("user_name = ?", userName)
+		granted := qs.db.Model(&model.Grant{}).Select("resource_id").Where("resource_type = ?", common.ResourceTypeQueue).
+			Where(qs.db.Where("user_name = ?", userName).Or("group_name IN (?)", groups))
+		tx = tx.Where("queue.name IN (?)", granted)
 	}
 	if !strings.EqualFold(queueName, "") {
 		tx = tx.Where("queue.name = ?", queueName)
@@ -175,7 +175,7 @@ func TestListQueue(t *testing.T) {
 	if err := Auth.CreateGrant(ctx, grantModel); err != nil {
 		t.Error(err)
 	}
-	grants, err := Auth.ListGrant(ctx, 0, 0, mockUserName)
+	grants, err := Auth.ListGrant(ctx, 0, 0, mockUserName, "")
 	if err != nil {
 		t.Error(err)
 	}
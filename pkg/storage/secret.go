@@ -0,0 +1,129 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/secret"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+)
+
+// EncryptSecrets encrypts cluster credentials and secret properties of file systems and links,
+// which are stored in plaintext by former versions. values encrypted already are skipped, so it can be run repeatedly.
+// only columns of secrets are updated, update time of rows is kept.
+func EncryptSecrets(db *gorm.DB) error {
+	if !secret.Enabled() {
+		return fmt.Errorf("secret key provider is not configured")
+	}
+	clusters, err := encryptClusterCredentials(db)
+	if err != nil {
+		return fmt.Errorf("encrypt cluster credentials failed: %v", err)
+	}
+	filesystems, err := encryptFileSystemProperties(db)
+	if err != nil {
+		return fmt.Errorf("encrypt file system properties failed: %v", err)
+	}
+	links, err := encryptLinkProperties(db)
+	if err != nil {
+		return fmt.Errorf("encrypt link properties failed: %v", err)
+	}
+	log.Infof("secrets of %d clusters, %d file systems and %d links are encrypted", clusters, filesystems, links)
+	return nil
+}
+
+func encryptClusterCredentials(db *gorm.DB) (int, error) {
+	var clusters []model.ClusterInfo
+	// soft deleted clusters are encrypted too
+	if err := db.Table("cluster_info").Find(&clusters).Error; err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, cluster := range clusters {
+		if cluster.RawCredential == "" || secret.IsEncrypted(cluster.RawCredential) {
+			continue
+		}
+		credential, err := secret.Encrypt(cluster.Credential)
+		if err != nil {
+			return count, err
+		}
+		if err = db.Table("cluster_info").Where("pk = ?", cluster.Pk).
+			UpdateColumn("credential", credential).Error; err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func encryptFileSystemProperties(db *gorm.DB) (int, error) {
+	var filesystems []model.FileSystem
+	if err := db.Model(&model.FileSystem{}).Find(&filesystems).Error; err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := range filesystems {
+		fs := &filesystems[i]
+		if fs.PropertiesJson == "" {
+			continue
+		}
+		encrypted, err := model.PropertiesEncrypted(fs.PropertiesJson)
+		if err != nil || encrypted {
+			continue
+		}
+		// BeforeSave encrypts secret properties into properties json
+		if err = fs.BeforeSave(db); err != nil {
+			return count, err
+		}
+		if err = db.Model(&model.FileSystem{}).Where("id = ?", fs.ID).
+			UpdateColumn("properties", fs.PropertiesJson).Error; err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func encryptLinkProperties(db *gorm.DB) (int, error) {
+	var links []model.Link
+	if err := db.Model(&model.Link{}).Find(&links).Error; err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := range links {
+		link := &links[i]
+		if link.PropertiesJson == "" {
+			continue
+		}
+		encrypted, err := model.PropertiesEncrypted(link.PropertiesJson)
+		if err != nil || encrypted {
+			continue
+		}
+		if err = link.BeforeSave(db); err != nil {
+			return count, err
+		}
+		if err = db.Model(&model.Link{}).Where("id = ?", link.ID).
+			UpdateColumn("properties", link.PropertiesJson).Error; err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
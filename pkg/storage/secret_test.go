@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2022 PaddlePaddle Authors. All Rights Reserve.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PaddlePaddle/PaddleFlow/pkg/common/secret"
+	fsCommon "github.com/PaddlePaddle/PaddleFlow/pkg/fs/common"
+	"github.com/PaddlePaddle/PaddleFlow/pkg/model"
+)
+
+func TestEncryptSecrets(t *testing.T) {
+	initMockDB()
+	assert.Nil(t, secret.Init(secret.Config{}))
+	assert.NotNil(t, EncryptSecrets(DB))
+
+	// rows stored in plaintext by former versions
+	cluster := model.ClusterInfo{Name: "cluster-secret", Credential: "kubeconfig"}
+	assert.Nil(t, Cluster.CreateCluster(&cluster))
+	fs := model.FileSystem{
+		Model: model.Model{ID: "fs-root-secret"},
+		Name:  "secret",
+		PropertiesMap: map[string]string{
+			fsCommon.AccessKey: "ak",
+			fsCommon.SecretKey: "sk",
+		},
+	}
+	assert.Nil(t, DB.Create(&fs).Error)
+	link := model.Link{
+		Model:         model.Model{ID: "link-secret"},
+		FsID:          fs.ID,
+		PropertiesMap: map[string]string{fsCommon.Password: "password"},
+	}
+	assert.Nil(t, DB.Create(&link).Error)
+
+	keyFile := "./mock-secret-key"
+	defer os.Remove(keyFile)
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("k"), 32))
+	assert.Nil(t, ioutil.WriteFile(keyFile, []byte(key), 0600))
+	assert.Nil(t, secret.Init(secret.Config{Provider: secret.LocalProvider, KeyFile: keyFile}))
+	defer secret.Init(secret.Config{})
+
+	assert.Nil(t, EncryptSecrets(DB))
+	// run again with secrets encrypted already
+	assert.Nil(t, EncryptSecrets(DB))
+
+	var raws []string
+	assert.Nil(t, DB.Table("cluster_info").Where("pk = ?", cluster.Pk).Pluck("credential", &raws).Error)
+	assert.True(t, secret.IsEncrypted(raws[0]))
+	clusterInfo, err := Cluster.GetClusterByName(cluster.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, "kubeconfig", clusterInfo.Credential)
+
+	raws = nil
+	assert.Nil(t, DB.Model(&model.FileSystem{}).Where("id = ?", fs.ID).Pluck("properties", &raws).Error)
+	encrypted, err := model.PropertiesEncrypted(raws[0])
+	assert.Nil(t, err)
+	assert.True(t, encrypted)
+	assert.Contains(t, raws[0], `"accessKey":"ak"`)
+	fsModel, err := Filesystem.GetFileSystemWithFsID(fs.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "sk", fsModel.PropertiesMap[fsCommon.SecretKey])
+
+	raws = nil
+	assert.Nil(t, DB.Model(&model.Link{}).Where("id = ?", link.ID).Pluck("properties", &raws).Error)
+	encrypted, err = model.PropertiesEncrypted(raws[0])
+	assert.Nil(t, err)
+	assert.True(t, encrypted)
+
+	// secrets saved after encryption enabled are encrypted by hooks
+	clusterInfo.Credential = "new-kubeconfig"
+	assert.Nil(t, Cluster.UpdateCluster(clusterInfo.ID, &clusterInfo))
+	raws = nil
+	assert.Nil(t, DB.Table("cluster_info").Where("pk = ?", cluster.Pk).Pluck("credential", &raws).Error)
+	assert.True(t, secret.IsEncrypted(raws[0]))
+	clusterInfo, err = Cluster.GetClusterByName(cluster.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, "new-kubeconfig", clusterInfo.Credential)
+	assert.Equal(t, secret.Redacted, clusterInfo.Redacted().Credential)
+}